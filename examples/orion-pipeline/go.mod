@@ -8,7 +8,6 @@ require (
 )
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/mattn/go-pointer v0.0.1 // indirect
 	github.com/tinyzimmer/go-glib v0.0.25 // indirect
 	github.com/tinyzimmer/go-gst v0.2.33 // indirect
@@ -35,6 +35,11 @@ type Config struct {
 
 	// Statistics
 	StatsInterval time.Duration
+	MetricsAddr   string // Prometheus/OpenMetrics endpoint ("" = disabled)
+
+	// Tracing (optional, at most one exporter)
+	TraceEndpoint string
+	TraceFile     string
 
 	// Logging
 	Debug bool
@@ -101,6 +106,11 @@ func parseFlags() Config {
 	// Stats flags
 	var statsIntervalSec int
 	flag.IntVar(&statsIntervalSec, "stats-interval", 5, "Statistics reporting interval (seconds)")
+	flag.StringVar(&config.MetricsAddr, "metrics-addr", "", "Serve FrameSupplier metrics on this address at /metrics (e.g. :9090)")
+
+	// Tracing flags (optional)
+	flag.StringVar(&config.TraceEndpoint, "trace-otlp", "", "Export frame traces to an OTLP/HTTP collector (e.g. http://localhost:4318/v1/traces)")
+	flag.StringVar(&config.TraceFile, "trace-file", "", "Export frame traces as OTLP JSON lines to a file")
 
 	// Debug flag
 	flag.BoolVar(&config.Debug, "debug", false, "Enable debug logging")
@@ -152,8 +162,21 @@ func parseFlags() Config {
 }
 
 func runPipeline(ctx context.Context, config Config, logger *slog.Logger) error {
+	// 0. Create tracer (optional: capture → distribution → worker spans)
+	tracer, err := newTracer(config)
+	if err != nil {
+		return fmt.Errorf("failed to create tracer: %w", err)
+	}
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := tracer.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
 	// 1. Create stream provider (RTSP only for now)
-	logger.Info("Creating RTSP stream provider", "url", config.RTSPUrl)
+	logger.Info("Creating RTSP stream provider", "url", streamcapture.RedactURL(config.RTSPUrl))
 	stream, err := streamcapture.NewRTSPStream(
 		streamcapture.RTSPConfig{
 			URL:          config.RTSPUrl,
@@ -161,6 +184,7 @@ func runPipeline(ctx context.Context, config Config, logger *slog.Logger) error
 			TargetFPS:    config.FPS,
 			SourceStream: "pipeline-example",
 		},
+		streamcapture.WithTracer(tracer),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create stream provider: %w", err)
@@ -180,7 +204,11 @@ func runPipeline(ctx context.Context, config Config, logger *slog.Logger) error
 	}
 
 	// 3. Create FrameSupplier
-	supplier := framesupplier.New()
+	var supplierOpts []framesupplier.Option
+	if tracer != nil {
+		supplierOpts = append(supplierOpts, framesupplier.WithSpanRecorder(spanBridge{tracer: tracer}))
+	}
+	supplier := framesupplier.New(supplierOpts...)
 
 	// 3. Create and start mock workers
 	workers := make([]*MockWorker, len(config.WorkerProfiles))
@@ -216,8 +244,11 @@ func runPipeline(ctx context.Context, config Config, logger *slog.Logger) error
 	// 6. Start producer goroutine (stream → supplier)
 	go produceFrames(ctx, frameChan, supplier, frameSaver, logger)
 
-	// 7. Start statistics reporter
+	// 7. Start statistics reporter (and metrics endpoint, if enabled)
 	go reportStats(ctx, config, stream, supplier, workers, frameSaver, logger)
+	if config.MetricsAddr != "" {
+		go serveMetrics(ctx, config.MetricsAddr, supplier, logger)
+	}
 
 	// 8. Wait for context cancellation
 	<-ctx.Done()
@@ -256,7 +287,9 @@ func produceFrames(ctx context.Context, frameChan <-chan streamcapture.Frame, su
 				Width:     streamFrame.Width,
 				Height:    streamFrame.Height,
 				Timestamp: streamFrame.Timestamp,
+				StreamID:  streamFrame.SourceStream,
 				// Seq will be assigned by FrameSupplier distribution loop
+				TraceParent: streamFrame.TraceParent,
 			}
 
 			// Publish to FrameSupplier (non-blocking)
@@ -298,6 +331,10 @@ func printBanner(config Config) {
 	}
 
 	fmt.Printf("  Stats Interval:  %v\n", config.StatsInterval)
+	if config.MetricsAddr != "" {
+		fmt.Printf("  Metrics:         http://%s/metrics\n", config.MetricsAddr)
+	}
+	fmt.Printf("  Tracing:         %s\n", describeTracing(config))
 	fmt.Println()
 	fmt.Println("Pipeline:")
 	fmt.Println("  stream-capture → FrameSupplier → Workers")
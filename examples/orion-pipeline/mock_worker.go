@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync/atomic"
@@ -42,39 +43,50 @@ func NewMockWorker(id string, latency time.Duration, logger *slog.Logger) *MockW
 
 // Run starts the worker's consumption loop.
 //
-// It subscribes to the FrameSupplier and processes frames until context cancellation.
+// It subscribes to the FrameSupplier and processes frames until context
+// cancellation or supplier shutdown.
 func (w *MockWorker) Run(ctx context.Context, supplier framesupplier.Supplier) error {
 	w.logger.Info("Worker started", "latency", w.latency)
 
 	// Subscribe to FrameSupplier
-	readFunc := supplier.Subscribe(w.id)
-	defer supplier.Unsubscribe(w.id)
+	sub, err := supplier.OpenSubscription(w.id)
+	if err != nil {
+		return fmt.Errorf("subscribe %s: %w", w.id, err)
+	}
+	defer sub.Close()
 
 	for {
-		select {
-		case <-ctx.Done():
+		// Read from worker mailbox (unblocks on ctx cancellation)
+		frame, err := sub.Read(ctx)
+		if errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+			w.logger.Info("Supplier stopped, worker exiting")
+			return nil
+		}
+		if err != nil {
 			w.logger.Info("Worker stopping gracefully")
-			return ctx.Err()
-		default:
-			// Blocking read from worker mailbox
-			frame := readFunc()
-
-			// Process frame (decode + simulate inference)
-			start := time.Now()
-			if err := w.processFrame(frame); err != nil {
-				w.logger.Error("Frame processing failed", "error", err)
-				continue
-			}
-			elapsed := time.Since(start)
-
-			// Update statistics
-			w.processed.Add(1)
-			w.totalLatencyMs.Add(uint64(elapsed.Milliseconds()))
-
-			w.logger.Debug("Frame processed",
-				"seq", frame.Seq,
-				"elapsed_ms", elapsed.Milliseconds())
+			return err
+		}
+
+		// Process frame (decode + simulate inference), traced as a child of the frame
+		span := supplier.StartSpan("worker."+w.id, frame)
+		start := time.Now()
+		if err := w.processFrame(frame); err != nil {
+			span.SetAttribute("error", err.Error())
+			span.End()
+			w.logger.Error("Frame processing failed", "error", err)
+			continue
 		}
+		elapsed := time.Since(start)
+		span.End()
+
+		// Update statistics
+		w.processed.Add(1)
+		w.totalLatencyMs.Add(uint64(elapsed.Milliseconds()))
+
+		w.logger.Debug("Frame processed",
+			"seq", frame.Seq,
+			"elapsed_ms", elapsed.Milliseconds(),
+			"trace_parent", span.TraceParent)
 	}
 }
 
@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier/metrics"
 	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
 )
 
-// reportStats periodically prints statistics from all pipeline components
+// reportStats prints pipeline statistics every StatsInterval.
+//
+// Supplier figures come from supplier.Watch (per-interval deltas), so the
+// display shows current rates instead of diffing lifetime counters.
 func reportStats(
 	ctx context.Context,
 	config Config,
@@ -20,19 +25,30 @@ func reportStats(
 	frameSaver *FrameSaver,
 	logger *slog.Logger,
 ) {
-	ticker := time.NewTicker(config.StatsInterval)
-	defer ticker.Stop()
-
 	startTime := time.Now()
 
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			uptime := time.Since(startTime)
-			printLiveStats(uptime, stream, supplier, workers, frameSaver)
-		}
+	for delta := range supplier.Watch(ctx, config.StatsInterval) {
+		uptime := time.Since(startTime)
+		printLiveStats(uptime, stream, delta, workers, frameSaver)
+	}
+}
+
+// serveMetrics exposes supplier metrics (Prometheus/OpenMetrics) on addr until ctx is done
+func serveMetrics(ctx context.Context, addr string, supplier framesupplier.Supplier, logger *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler(supplier))
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	logger.Info("Serving metrics", "addr", addr, "path", "/metrics")
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		logger.Error("Metrics server failed", "error", err)
 	}
 }
 
@@ -40,15 +56,15 @@ func reportStats(
 func printLiveStats(
 	uptime time.Duration,
 	stream streamcapture.StreamProvider,
-	supplier framesupplier.Supplier,
+	delta framesupplier.StatsDelta,
 	workers []*MockWorker,
 	frameSaver *FrameSaver,
 ) {
 	// Stream stats
 	streamStats := stream.Stats()
 
-	// FrameSupplier stats
-	supplierStats := supplier.Stats()
+	// FrameSupplier stats (snapshot at delta.At)
+	supplierStats := delta.Stats
 
 	// Worker stats
 	workerStats := make([]WorkerStats, len(workers))
@@ -90,9 +106,16 @@ func printLiveStats(
 
 	// FrameSupplier Stats
 	fmt.Println("│")
-	fmt.Println("│ FrameSupplier:")
-	fmt.Printf("│   Inbox Drops:        %6d\n", supplierStats.InboxDrops)
+	fmt.Printf("│ FrameSupplier (last %v):\n", delta.Interval.Round(time.Second))
+	fmt.Printf("│   Published:          %6d frames (%.2f fps)\n",
+		delta.Published,
+		float64(delta.Published)/delta.Interval.Seconds())
+	fmt.Printf("│   Inbox Drops:        %6d (%d total)\n", delta.InboxDrops, supplierStats.InboxDrops)
+	fmt.Printf("│   Distribution p99:   %6dµs\n", supplierStats.DistributionLatency.P99.Microseconds())
 	fmt.Printf("│   Active Workers:     %6d\n", len(supplierStats.Workers))
+	if len(delta.Added) > 0 || len(delta.Removed) > 0 {
+		fmt.Printf("│   Subscribed/Left:    %v / %v\n", delta.Added, delta.Removed)
+	}
 
 	// Detect idle workers
 	idleWorkers := detectIdleWorkers(supplierStats)
@@ -116,23 +139,27 @@ func printLiveStats(
 		fmt.Printf("│   Distribution:       Sequential (<%d workers)\n", 8)
 	}
 
-	// Worker Stats
+	// Worker Stats (interval deltas, lifetime processed)
 	fmt.Println("│")
 	fmt.Println("│ Workers:")
 	for _, ws := range workerStats {
-		// Get mailbox stats from supplier
-		mailboxStats, exists := supplierStats.Workers[ws.ID]
-		var mailboxDrops uint64
-		if exists {
-			mailboxDrops = mailboxStats.TotalDrops
-		}
+		wd := delta.Workers[ws.ID]
+		mailboxStats := supplierStats.Workers[ws.ID]
 
-		fmt.Printf("│   %-15s: %4d processed, %3d drops (%.1f%%), avg=%3dms\n",
+		restarted := ""
+		if wd.Restarted {
+			restarted = " (restarted)"
+		}
+		fmt.Printf("│   %-15s: %5.2f fps, %3d drops (%.1f%%), %4d processed, avg=%3dms, age p50/p99=%d/%dms%s\n",
 			ws.ID,
+			wd.ConsumeRate,
+			wd.Drops,
+			wd.DropRate*100,
 			ws.Processed,
-			mailboxDrops,
-			dropRateFromCounts(ws.Processed, mailboxDrops),
-			ws.AvgLatency.Milliseconds())
+			ws.AvgLatency.Milliseconds(),
+			mailboxStats.FrameAge.P50.Milliseconds(),
+			mailboxStats.FrameAge.P99.Milliseconds(),
+			restarted)
 	}
 
 	fmt.Println("╰─────────────────────────────────────────────────────────────────╯")
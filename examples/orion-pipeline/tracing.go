@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
+)
+
+// newTracer creates the span exporter selected by flags (nil = tracing disabled)
+//
+// --trace-otlp posts to an OpenTelemetry collector, --trace-file writes OTLP
+// JSON lines. When both are set the collector wins.
+func newTracer(config Config) (*trace.Tracer, error) {
+	var exporter trace.Exporter
+	switch {
+	case config.TraceEndpoint != "":
+		exp, err := trace.NewOTLPExporter(trace.OTLPConfig{
+			Endpoint:    config.TraceEndpoint,
+			ServiceName: "orion-pipeline",
+		})
+		if err != nil {
+			return nil, err
+		}
+		exporter = exp
+	case config.TraceFile != "":
+		exp, err := trace.NewFileExporter(config.TraceFile, "orion-pipeline")
+		if err != nil {
+			return nil, err
+		}
+		exporter = exp
+	default:
+		return nil, nil
+	}
+
+	return trace.NewTracer(exporter, trace.TracerConfig{}), nil
+}
+
+// spanBridge records FrameSupplier spans on a stream-capture Tracer
+//
+// FrameSupplier is dependency-free and reports spans as traceparent strings;
+// the bridge converts them to trace.Span so capture, distribution and worker
+// spans end up in the same export.
+type spanBridge struct {
+	tracer *trace.Tracer
+}
+
+// RecordSpan implements framesupplier.SpanRecorder (non-blocking)
+func (b spanBridge) RecordSpan(span framesupplier.Span) {
+	sc, err := trace.ParseTraceparent(span.TraceParent)
+	if err != nil {
+		return
+	}
+
+	out := trace.Span{
+		Name:    span.Name,
+		Context: sc,
+		Start:   span.StartTime,
+		End:     span.EndTime,
+	}
+	if parent, err := trace.ParseTraceparent(span.Parent); err == nil {
+		out.Parent = parent.SpanID
+	}
+
+	keys := make([]string, 0, len(span.Attributes))
+	for k := range span.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		out.Attributes = append(out.Attributes, trace.String(k, span.Attributes[k]))
+	}
+	if msg, ok := span.Attributes["error"]; ok {
+		out.Error = msg
+	}
+
+	b.tracer.Record(out)
+}
+
+// describeTracing returns the banner line for the tracing configuration
+func describeTracing(config Config) string {
+	switch {
+	case config.TraceEndpoint != "":
+		return fmt.Sprintf("OTLP (%s)", config.TraceEndpoint)
+	case config.TraceFile != "":
+		return fmt.Sprintf("file (%s)", config.TraceFile)
+	default:
+		return "disabled"
+	}
+}
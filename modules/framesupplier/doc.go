@@ -65,6 +65,33 @@
 //	    publishResult(result)
 //	}
 //
+// Workers that need cancellation or timeouts use a Subscription instead:
+//
+//	sub, err := supplier.OpenSubscription("PersonDetector")
+//	if err != nil {
+//	    return err  // ErrWorkerExists or ErrSubscriptionClosed
+//	}
+//	defer sub.Close()
+//
+//	for {
+//	    frame, err := sub.Read(ctx)  // Returns on frame, ctx done, or Close/Stop
+//	    if err != nil {
+//	        return err  // ctx.Err() or ErrSubscriptionClosed
+//	    }
+//	    publishResult(runInference(frame))
+//	}
+//
+// Read does not leak goroutines on cancellation; TryRead polls without
+// blocking and ReadTimeout bounds a single read.
+//
+// Worker IDs are unique. Subscribing an ID that is already subscribed is
+// rejected: OpenSubscription returns ErrWorkerExists, readFunc returns nil
+// and that worker's deferred Unsubscribe is a no-op (the live worker keeps
+// its subscription). A supervisor restarting a stuck worker uses
+// WithTakeover, which closes the old subscription and wakes its reader.
+// WorkerStats.Generation increments on every accepted Subscribe of an ID,
+// so a restart is visible in Stats.
+//
 // # Monitoring
 //
 // Check operational health with Stats():
@@ -85,6 +112,58 @@
 //	    log.Info("Worker drops", "id", workerID, "rate", dropRate)
 //	}
 //
+// Drops say how much a worker skipped, not how fresh its input is. Each
+// worker also carries HDR-style histograms (p50/p95/p99, ≤6.25% error):
+//
+//	age := workerStats.FrameAge             // Frame.Timestamp → readFunc return
+//	period := workerStats.ConsumeInterval   // time between consumes
+//	log.Info("Worker latency", "id", workerID, "age_p99", age.P99, "period_p50", period.P50)
+//
+// With JIT mailboxes FrameAge.P99 stays close to one inference period even
+// at high drop rates: latency over completeness, measured.
+//
+// Stats also counts Published frames and the DistributionLatency histogram
+// (Publish → fan-out done, µs range when healthy).
+//
+// Dashboards that want rates instead of lifetime counters use Watch, which
+// delivers per-interval deltas (published, drops, per-worker consume and drop
+// rates, added/removed/restarted workers):
+//
+//	for delta := range supplier.Watch(ctx, 5*time.Second) {
+//	    log.Info("Interval", "published", delta.Published, "inbox_drops", delta.InboxDrops)
+//	}
+//
+// Each tick takes a full Stats() snapshot (the delta carries it for gauges),
+// so Watch costs what polling Stats() at the same interval costs; it removes
+// the diffing, not the allocation.
+//
+// Package framesupplier/metrics serves the same counters to Prometheus
+// (text 0.0.4 or OpenMetrics, stdlib only):
+//
+//	http.Handle("/metrics", metrics.Handler(supplier))
+//
+// # Health Policies
+//
+// Workers declare an SLA class and optional thresholds at Subscribe; the
+// supplier evaluates them every second and keeps a verdict per worker
+// (WorkerStats.Health, WorkerStats.Violations):
+//
+//	supplier := framesupplier.New(framesupplier.WithHealthHandler(func(ev framesupplier.HealthEvent) {
+//	    if ev.Status == framesupplier.Unhealthy && !ev.Resolved {
+//	        restartWorker(ev.WorkerID) // e.g. re-Subscribe with WithTakeover
+//	    }
+//	}))
+//
+//	readFunc := supplier.Subscribe("PersonDetector",
+//	    framesupplier.WithSLA(framesupplier.SLACritical),
+//	    framesupplier.WithHealthPolicy(framesupplier.HealthPolicy{MaxDropRate: 0.05}))
+//
+// Thresholds: idle timeout (class default: Critical 10s, Normal 30s,
+// BestEffort 2min), max consecutive drops, max drop rate over a sliding
+// window. Violations make Critical and Normal workers Unhealthy and
+// BestEffort workers Degraded. Events fire on transitions only (violation
+// started or resolved), from a dedicated goroutine.
+//
 // # Drop Semantics
 //
 // Drops are EXPECTED and HEALTHY in JIT architecture:
@@ -96,6 +175,96 @@
 //
 // Drops are NOT errors. They indicate JIT semantics working correctly.
 //
+// # Multi-Stream Routing
+//
+// One supplier can serve several cameras. Frames carry Frame.StreamID
+// ("" = default stream); each stream has its own inbox (overwrite only
+// within the stream) and its own Seq counter. Workers choose streams at
+// Subscribe:
+//
+//	supplier.Publish(&framesupplier.Frame{StreamID: "room-1", Data: data})
+//
+//	room1 := supplier.Subscribe("FallDetector-1", framesupplier.WithStreams("room-1"))
+//	wing := supplier.Subscribe("PersonCounter", framesupplier.WithStreams("room-1", "room-2"))
+//	all := supplier.Subscribe("Recorder") // no option = all streams
+//
+// A multi-stream worker holds the latest frame of each stream and receives
+// them oldest stream first, so a busy camera never starves a quiet one.
+// Stats().Streams reports published frames, inbox drops, last Seq and
+// subscriber count per stream.
+//
+// # Replica Groups
+//
+// A slow model can run as N replicas sharing one mailbox (frame-buffer tee).
+// Each frame goes to exactly one idle replica (work-stealing), so the group
+// consumes up to N× the single-worker rate:
+//
+//	for i := 0; i < 3; i++ {
+//		id := fmt.Sprintf("YOLO-%d", i)
+//		readFunc := supplier.Subscribe(id, framesupplier.WithReplicaGroup("yolo"))
+//		go runWorker(readFunc)
+//	}
+//
+// JIT semantics are unchanged: while all replicas are busy, a newer frame
+// replaces the unconsumed one and counts as a group drop. Stats().Groups
+// reports the group's drops and consumption; each replica also appears in
+// Stats().Workers with its own LastConsumedAt, Consumed and IsIdle.
+// Unsubscribe stops one replica; the group disappears with its last replica.
+//
+// # Sampling
+//
+// Workers that deliberately run slower than the source can thin their
+// frames at distribution time instead of relying on mailbox overwrite:
+//
+//	vlm := supplier.Subscribe("VLM", framesupplier.WithMaxRate(0.1))                  // ≤ 1 frame per 10s
+//	pose := supplier.Subscribe("Pose", framesupplier.WithEveryNth(3))                  // Seq 1, 4, 7, ...
+//	audit := supplier.Subscribe("Audit", framesupplier.WithSampleInterval(time.Minute)) // aligned to :00
+//
+// Policies apply per stream and combine (a frame must pass all). Frames
+// withheld by policy are counted in WorkerStats.Skipped; TotalDrops keeps
+// meaning "worker too slow", so drop-rate alerts stay meaningful.
+//
+// # Tracing
+//
+// Frames carry a W3C trace context in Frame.TraceParent (set by the
+// publisher, e.g. from stream-capture's Frame.TraceParent). With
+// WithSpanRecorder the supplier records one "framesupplier.distribute" span
+// per frame and re-parents the frame under it; workers record their own
+// spans with StartSpan:
+//
+//	supplier := framesupplier.New(framesupplier.WithSpanRecorder(recorder))
+//
+//	frame := readFunc()
+//	span := supplier.StartSpan("worker.PersonDetector", frame)
+//	result := runInference(frame)
+//	span.End()
+//	result.TraceParent = span.TraceParent // results join the frame's trace
+//
+// The module stays dependency-free: spans are plain data, and the
+// SpanRecorder adapts them to an OpenTelemetry exporter. RecordSpan must
+// not block. Without a recorder TraceParent is passed through unchanged.
+//
+// # Out-of-Process Workers
+//
+// Package framesupplier/transport serves workers running in other processes
+// (e.g. Python ONNX detectors) over a Unix domain socket with a
+// length-prefixed binary protocol (SUBSCRIBE, FRAME, ACK):
+//
+//	srv := transport.NewServer(supplier)
+//	go srv.ListenAndServe("/run/orion/frames.sock")
+//
+// Each connection is an ordinary Subscription: the remote worker has one frame
+// in flight, the Go-side mailbox overwrites while it is busy, and its drops,
+// health and latency appear in Stats like any in-process worker. Frames are
+// copied once into the socket. See examples/python_worker.py for a Python
+// client.
+//
+// On Linux, transport.WithSharedMemory keeps frames zero-copy for local
+// workers: each distributed frame is written once into a seqlock slot ring
+// (e.g. /dev/shm/orion-frames) shared by all readers, and the socket carries
+// only a FRAME_REF (slot index + seqlock sequence). Slots stay pinned until
+// the worker's ACK; oversized frames fall back to inline FRAME messages.
+//
 // # Zero-Copy Contract
 //
 // Frame.Data is shared by reference (not copied). IMMUTABILITY CONTRACT:
@@ -147,7 +316,6 @@
 //
 // # Future Extensions (Out of Scope)
 //
-// Phase 2: Multi-stream support (streamID in Frame)
 // Phase 3: Priority-based distribution (skip low-priority under load)
 //
 // These are NOT in current scope (YAGNI), but API designed for extensibility.
@@ -0,0 +1,224 @@
+#!/usr/bin/env python3
+"""Remote FrameSupplier worker over the Unix socket transport (stdlib only).
+
+Replaces the prototype's stdin/msgpack bridge (PythonPersonDetector): the Go
+side keeps a mailbox per worker, so while this process runs inference the
+supplier overwrites stale frames and counts them as drops in Stats().
+
+Protocol: see modules/framesupplier/transport/server.go (package doc).
+Shared-memory ring layout (--shm): see modules/framesupplier/transport/shm.go.
+
+Usage:
+    python3 python_worker.py /run/orion/frames.sock PersonDetector --sla critical
+    python3 python_worker.py /run/orion/frames.sock PoseDetector --shm
+
+Go side:
+    srv := transport.NewServer(supplier)
+    go srv.ListenAndServe("/run/orion/frames.sock")
+"""
+
+import argparse
+import mmap
+import socket
+import struct
+import sys
+import time
+
+PROTOCOL_VERSION = 1
+MAX_MESSAGE = 64 << 20  # Bound of a message length prefix (Go client default)
+
+MSG_SUBSCRIBE = 0x01
+MSG_SUBSCRIBED = 0x02
+MSG_FRAME = 0x03
+MSG_ACK = 0x04
+MSG_ERROR = 0x05
+MSG_FRAME_REF = 0x06
+
+FLAG_TAKEOVER = 0x01
+FLAG_SHARED_MEMORY = 0x02
+
+SHM_MAGIC = b"FSSHM\x00\x00\x01"
+SHM_HEADER_SIZE = 64
+SHM_SLOT_HEADER_SIZE = 64
+
+SLA_CLASSES = {"normal": 0, "critical": 1, "best-effort": 2}
+ERROR_CODES = {1: "protocol", 2: "worker-exists", 3: "closed", 4: "replaced"}
+
+
+class RemoteError(Exception):
+    """Session ended by the server (ERROR message)."""
+
+    def __init__(self, code, message):
+        super().__init__(f"{ERROR_CODES.get(code, code)}: {message}")
+        self.code = code
+
+
+class Frame:
+    def __init__(self, seq, timestamp_ns, width, height, stream_id, trace_parent, data):
+        self.seq = seq
+        self.timestamp_ns = timestamp_ns  # 0 = unset
+        self.width = width
+        self.height = height
+        self.stream_id = stream_id
+        self.trace_parent = trace_parent
+        self.data = data  # bytes (inline) or memoryview into the ring (--shm)
+
+
+def _str(s):
+    b = s.encode()
+    return struct.pack(">H", len(b)) + b
+
+
+class ShmRing:
+    """Read-only view of the supplier's shared-memory ring (seqlock slots)."""
+
+    def __init__(self, path):
+        with open(path, "rb") as f:
+            self.mm = mmap.mmap(f.fileno(), 0, prot=mmap.PROT_READ)
+        if self.mm[:8] != SHM_MAGIC:
+            raise ValueError(f"{path} is not a frame ring")
+        self.slots, self.slot_size = struct.unpack_from("<IQ", self.mm, 12)
+
+    def slot_offset(self, slot):
+        return SHM_HEADER_SIZE + slot * (SHM_SLOT_HEADER_SIZE + self.slot_size)
+
+    def valid(self, slot, seq):
+        """True while the slot still holds the write identified by seq."""
+        (cur,) = struct.unpack_from("<Q", self.mm, self.slot_offset(slot))
+        return seq % 2 == 0 and cur == seq
+
+    def data(self, slot, length):
+        off = self.slot_offset(slot) + SHM_SLOT_HEADER_SIZE
+        return memoryview(self.mm)[off:off + length]  # Zero-copy (np.frombuffer works)
+
+    def close(self):
+        self.mm.close()
+
+
+class Client:
+    """One subscription; next() ACKs the previous frame, like a Go readFunc.
+
+    With shm=True, frame.data is a memoryview into the ring, valid until the
+    next call to next() (the ACK unpins the slot).
+    """
+
+    def __init__(self, path, worker_id, group="", streams=(), sla="normal",
+                 max_rate=0.0, every_nth=0, sample_interval_ms=0, takeover=False,
+                 shm=False):
+        self.sock = socket.socket(socket.AF_UNIX, socket.SOCK_STREAM)
+        self.sock.connect(path)
+        self.inflight = None
+        self.ring = None
+
+        flags = (FLAG_TAKEOVER if takeover else 0) | (FLAG_SHARED_MEMORY if shm else 0)
+        payload = struct.pack(">B", PROTOCOL_VERSION) + _str(worker_id) + _str(group)
+        payload += struct.pack(">BBdII", flags, SLA_CLASSES[sla],
+                               max_rate, every_nth, sample_interval_ms)
+        payload += struct.pack(">H", len(streams)) + b"".join(_str(s) for s in streams)
+        self._send(MSG_SUBSCRIBE, payload)
+
+        typ, body = self._recv()
+        if typ == MSG_ERROR:
+            raise self._error(body)
+        if typ != MSG_SUBSCRIBED:
+            raise RemoteError(1, f"unexpected message type {typ:#x}")
+        (self.generation,) = struct.unpack_from(">Q", body)
+        if len(body) > 8:  # Shared memory granted: shm_path, slots, slot_size
+            shm_path, _ = self._read_str(body, 8)
+            self.ring = ShmRing(shm_path)
+
+    def next(self):
+        """ACK the previous frame and block until the next (freshest) one."""
+        if self.inflight is not None:
+            self._send(MSG_ACK, struct.pack(">Q", self.inflight))
+            self.inflight = None
+
+        typ, body = self._recv()
+        if typ == MSG_ERROR:
+            raise self._error(body)
+        if typ not in (MSG_FRAME, MSG_FRAME_REF):
+            raise RemoteError(1, f"unexpected message type {typ:#x}")
+
+        seq, ts, width, height = struct.unpack_from(">QqII", body)
+        off = 24
+        stream_id, off = self._read_str(body, off)
+        trace_parent, off = self._read_str(body, off)
+        self.inflight = seq
+
+        if typ == MSG_FRAME:
+            data = body[off:]
+        else:
+            slot, slot_seq, length = struct.unpack_from(">IQI", body, off)
+            if not self.ring.valid(slot, slot_seq):
+                raise RemoteError(1, f"shared-memory slot {slot} rewritten")
+            data = self.ring.data(slot, length)
+        return Frame(seq, ts, width, height, stream_id, trace_parent, data)
+
+    def close(self):
+        self.sock.close()
+        if self.ring is not None:
+            self.ring.close()
+
+    def _send(self, typ, payload):
+        self.sock.sendall(struct.pack(">IB", len(payload) + 1, typ) + payload)
+
+    def _recv(self):
+        (length,) = struct.unpack(">I", self._read_exact(4))
+        if length == 0 or length > MAX_MESSAGE:
+            raise RemoteError(1, f"message length {length} out of bounds")
+        body = self._read_exact(length)
+        return body[0], body[1:]
+
+    def _read_exact(self, n):
+        buf = bytearray()
+        while len(buf) < n:
+            chunk = self.sock.recv(n - len(buf))
+            if not chunk:
+                raise ConnectionError("connection closed by supplier")
+            buf += chunk
+        return bytes(buf)
+
+    @staticmethod
+    def _read_str(body, off):
+        (n,) = struct.unpack_from(">H", body, off)
+        return body[off + 2:off + 2 + n].decode(), off + 2 + n
+
+    def _error(self, body):
+        code = body[0]
+        msg, _ = self._read_str(body, 1)
+        return RemoteError(code, msg)
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__.splitlines()[0])
+    parser.add_argument("socket")
+    parser.add_argument("worker_id")
+    parser.add_argument("--sla", choices=SLA_CLASSES, default="normal")
+    parser.add_argument("--stream", action="append", default=[])
+    parser.add_argument("--shm", action="store_true",
+                        help="Receive frames through the shared-memory ring")
+    parser.add_argument("--inference-ms", type=float, default=50.0,
+                        help="Simulated inference time")
+    args = parser.parse_args()
+
+    client = Client(args.socket, args.worker_id, streams=args.stream, sla=args.sla,
+                    shm=args.shm)
+    print(f"subscribed {args.worker_id} (generation {client.generation})", file=sys.stderr)
+    try:
+        while True:
+            frame = client.next()
+            age_ms = (time.time_ns() - frame.timestamp_ns) / 1e6 if frame.timestamp_ns else 0
+            time.sleep(args.inference_ms / 1000)  # Run ONNX inference here
+            print(f"seq={frame.seq} stream={frame.stream_id or '-'} "
+                  f"{frame.width}x{frame.height} {len(frame.data)}B age={age_ms:.1f}ms")
+            del frame  # Release the memoryview before the next ACK
+    except RemoteError as e:
+        print(f"session ended: {e}", file=sys.stderr)
+    except KeyboardInterrupt:
+        pass
+    finally:
+        client.close()
+
+
+if __name__ == "__main__":
+    main()
@@ -51,45 +51,44 @@ func WorkerClient(supplier framesupplier.Supplier, workerID string) {
 // CriticalWorkerClient shows a life-critical worker (PersonDetector for fall detection).
 //
 // Difference: Cannot tolerate drops, needs alerting on high drop rate.
+// The supplier evaluates the thresholds; violations reach the supplier's
+// HealthHandler (see HealthAlertHandler).
 func CriticalWorkerClient(supplier framesupplier.Supplier, workerID string) {
-	readFunc := supplier.Subscribe(workerID)
+	readFunc := supplier.Subscribe(workerID,
+		framesupplier.WithSLA(framesupplier.SLACritical),
+		framesupplier.WithHealthPolicy(framesupplier.HealthPolicy{
+			MaxDropRate: 0.05, // 5% drop rate over 10s = alert
+		}))
 	defer supplier.Unsubscribe(workerID)
 
-	// Monitor drops every 10 frames
-	frameCount := 0
-	alertThreshold := 0.05 // 5% drop rate = alert
-
 	for {
 		frame := readFunc()
 		if frame == nil {
 			break
 		}
 
-		frameCount++
-
-		// Check drops periodically
-		if frameCount%10 == 0 {
-			stats := supplier.Stats()
-			if workerStats, ok := stats.Workers[workerID]; ok {
-				dropRate := float64(workerStats.TotalDrops) / float64(workerStats.LastConsumedSeq)
-				if dropRate > alertThreshold {
-					log.Printf("[%s] CRITICAL: Drop rate %.1f%% exceeds threshold %.1f%%",
-						workerID, dropRate*100, alertThreshold*100)
-					// In real system: trigger alert to EdgeExpert
-				}
-			}
-		}
-
 		// Process frame
 		runInference(workerID, frame)
 	}
 }
 
+// HealthAlertHandler logs health violations (pass to framesupplier.WithHealthHandler).
+//
+// In real system: trigger alert to EdgeExpert, restart Unhealthy workers.
+func HealthAlertHandler(ev framesupplier.HealthEvent) {
+	if ev.Resolved {
+		log.Printf("[%s] %s recovered (%s)", ev.WorkerID, ev.Violation, ev.Class)
+		return
+	}
+	log.Printf("[%s] %s: %s value=%.2f threshold=%.2f (%s)",
+		ev.WorkerID, ev.Status, ev.Violation, ev.Value, ev.Threshold, ev.Class)
+}
+
 // BestEffortWorkerClient shows a low-priority worker (VLM, analytics, etc).
 //
-// Difference: Tolerates drops, no alerting needed.
+// Difference: Tolerates drops, no alerting needed (violations only Degraded).
 func BestEffortWorkerClient(supplier framesupplier.Supplier, workerID string) {
-	readFunc := supplier.Subscribe(workerID)
+	readFunc := supplier.Subscribe(workerID, framesupplier.WithSLA(framesupplier.SLABestEffort))
 	defer supplier.Unsubscribe(workerID)
 
 	for {
@@ -104,15 +103,13 @@ func BestEffortWorkerClient(supplier framesupplier.Supplier, workerID string) {
 	}
 }
 
-// MultiStreamWorkerClient shows future multi-stream scenario (Phase 2).
+// MultiStreamWorkerClient shows a worker bound to specific streams (cameras).
 //
-// Note: Current design doesn't have streamID, but API should accommodate future extension.
-func MultiStreamWorkerClient(supplier framesupplier.Supplier, workerID string, streamID string) {
-	// Future API (Phase 2):
-	// readFunc := supplier.SubscribeToStream(workerID, streamID)
-
-	// For now: Subscribe gets all streams, worker filters
-	readFunc := supplier.Subscribe(workerID)
+// Context: One supplier receives frames from N cameras (Frame.StreamID).
+// The worker only receives the streams it subscribed to, getting the latest
+// frame of each stream in turn (a busy camera never starves a quiet one).
+func MultiStreamWorkerClient(supplier framesupplier.Supplier, workerID string, streamIDs ...string) {
+	readFunc := supplier.Subscribe(workerID, framesupplier.WithStreams(streamIDs...))
 	defer supplier.Unsubscribe(workerID)
 
 	for {
@@ -121,11 +118,8 @@ func MultiStreamWorkerClient(supplier framesupplier.Supplier, workerID string, s
 			break
 		}
 
-		// TODO Phase 2: frame.StreamID for filtering
-		// if frame.StreamID != streamID {
-		//     continue
-		// }
-
+		// frame.Seq is per stream: track drops per frame.StreamID
+		log.Printf("[%s] Frame from %s seq=%d", workerID, frame.StreamID, frame.Seq)
 		runInference(workerID, frame)
 	}
 }
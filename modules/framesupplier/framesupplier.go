@@ -14,6 +14,7 @@ package framesupplier
 
 import (
 	"context"
+	"time"
 
 	"github.com/e7canasta/orion-care-sensor/modules/framesupplier/internal"
 )
@@ -55,7 +56,8 @@ type Supplier interface {
 	//
 	// Semantics:
 	//   - Non-blocking: always returns immediately (~1µs)
-	//   - Overwrite policy: new frame replaces old unconsumed frame (JIT)
+	//   - Per-stream inbox: frame.StreamID selects the inbox ("" = default stream)
+	//   - Overwrite policy: new frame replaces old unconsumed frame of the same stream (JIT)
 	//   - Drop tracking: increments InboxDrops if previous frame unconsumed
 	//
 	// Thread-safety: safe for concurrent calls (typically 1 publisher)
@@ -69,15 +71,32 @@ type Supplier interface {
 
 	// Subscribe registers a worker and returns a blocking read function.
 	//
+	// Routing:
+	//   - No options: frames of all streams
+	//   - WithStreams("cam-1"): one stream
+	//   - WithStreams("cam-1", "cam-2"): a set of streams
+	//   - WithReplicaGroup("yolo"): share one mailbox with other replicas,
+	//     each frame goes to exactly one idle replica
+	//
+	// Sampling (frames withheld by policy count as Skipped, not drops):
+	//   - WithMaxRate(0.1): at most one frame per 10 seconds per stream
+	//   - WithEveryNth(5): frames with Seq 1, 6, 11, ...
+	//   - WithSampleInterval(time.Second): first frame of each aligned second
+	//
 	// Returned function:
 	//   - Blocks until frame available (efficient, no busy-wait)
 	//   - Returns nil on graceful shutdown (Unsubscribe or Stop)
 	//   - Thread-safety: safe to call from single worker goroutine
 	//
 	// Semantics:
-	//   - Mailbox pattern: single-slot buffer, overwrite on publish
+	//   - Mailbox pattern: single-slot buffer per stream, overwrite on publish
 	//   - Drop tracking: increments worker's TotalDrops on overwrite
 	//   - Worker must call Unsubscribe when done (defer pattern recommended)
+	//   - Worker IDs are unique: a duplicate ID is rejected (readFunc returns
+	//     nil immediately) unless WithTakeover replaces the previous worker.
+	//     The rejected worker's deferred Unsubscribe is a no-op, so it does
+	//     not remove the live worker (OpenSubscription reports the rejection
+	//     as an error instead)
 	//
 	// Example:
 	//   readFunc := supplier.Subscribe("PersonDetector")
@@ -89,7 +108,36 @@ type Supplier interface {
 	//   }
 	//
 	// See: ADR-001 (sync.Cond), ARCHITECTURE.md (Worker Slot Mailbox)
-	Subscribe(workerID string) func() *Frame
+	Subscribe(workerID string, opts ...SubscribeOption) func() *Frame
+
+	// OpenSubscription registers a worker and returns a Subscription handle.
+	//
+	// Same routing, sampling and mailbox semantics as Subscribe, but reads
+	// compose with cancellation:
+	//   - Read(ctx): blocks until frame, ctx done, or close
+	//   - ReadTimeout(d): Read bounded by d
+	//   - TryRead(): non-blocking poll (ErrNoFrame if empty)
+	//   - Close(): unsubscribes this subscription only
+	//   - Stats(): this worker's WorkerStats
+	//
+	// Registration errors:
+	//   - ErrWorkerExists: worker ID already subscribed. With WithTakeover the
+	//     previous subscription's reads return ErrSubscriptionReplaced
+	//     instead (WorkerStats.Generation + 1)
+	//   - ErrSubscriptionClosed: supplier stopping or stopped
+	//
+	// No goroutine is left behind by a canceled or timed-out Read.
+	//
+	// Example:
+	//   sub, err := supplier.OpenSubscription("PersonDetector")
+	//   if err != nil { return err } // duplicate ID or supplier stopped
+	//   defer sub.Close()
+	//   for {
+	//       frame, err := sub.Read(ctx)
+	//       if err != nil { return err } // ctx done or subscription closed
+	//       process(frame)
+	//   }
+	OpenSubscription(workerID string, opts ...SubscribeOption) (*Subscription, error)
 
 	// Unsubscribe removes a worker and signals its readFunc to return nil.
 	//
@@ -97,6 +145,8 @@ type Supplier interface {
 	//   - Safe to call even if workerID not subscribed (idempotent)
 	//   - Wakes worker if blocked in readFunc (returns nil)
 	//   - After Unsubscribe, workerID's stats removed from Stats()
+	//   - Replica: only this replica exits, the group keeps receiving frames
+	//     until its last replica unsubscribes
 	//
 	// Thread-safety: safe for concurrent calls.
 	Unsubscribe(workerID string)
@@ -112,6 +162,44 @@ type Supplier interface {
 	//
 	// See: ARCHITECTURE.md (Operational Monitoring)
 	Stats() SupplierStats
+
+	// Watch delivers stats deltas every interval (default 1s if <= 0).
+	//
+	// Each StatsDelta carries the counters accumulated since the previous
+	// delivered delta (published, drops, per-stream and per-worker consumes,
+	// rates) plus the full snapshot, so dashboards stop diffing Stats()
+	// themselves. A slow reader gets fewer, longer deltas (never double
+	// counted). The channel is closed when ctx is done or after Stop.
+	//
+	// Cost: one Stats() snapshot per interval plus the delta maps (not
+	// cheaper than polling Stats() at the same interval, just pre-diffed).
+	//
+	// Example:
+	//   for delta := range supplier.Watch(ctx, 5*time.Second) {
+	//       for id, w := range delta.Workers {
+	//           log.Printf("%s: %.1f fps, %.1f%% drops", id, w.ConsumeRate, w.DropRate*100)
+	//       }
+	//   }
+	//
+	// Thread-safety: safe for concurrent calls (one goroutine per watcher).
+	Watch(ctx context.Context, interval time.Duration) <-chan StatsDelta
+
+	// StartSpan starts a span for work done on frame (typically inference).
+	//
+	// The span is a child of frame.TraceParent, so it joins the frame's
+	// trace (capture → distribution → worker). Call End() when done; pass
+	// span.TraceParent on with results to extend the trace downstream.
+	//
+	// Without WithSpanRecorder the span is not recorded, but its
+	// TraceParent is still valid. Works with untraced frames (new trace).
+	//
+	// Example:
+	//   span := supplier.StartSpan("worker.PersonDetector", frame)
+	//   result := runInference(frame)
+	//   span.SetAttribute("detections", strconv.Itoa(len(result)))
+	//   span.End()
+	//   result.TraceParent = span.TraceParent
+	StartSpan(name string, frame *Frame) *Span
 }
 
 // SupplierStats is re-exported from internal package to avoid import cycles.
@@ -122,7 +210,171 @@ type SupplierStats = internal.SupplierStats
 // See internal/types.go for full documentation.
 type WorkerStats = internal.WorkerStats
 
-// New creates a new Supplier instance with default configuration.
+// StreamStats is re-exported from internal package to avoid import cycles.
+// See internal/types.go for full documentation.
+type StreamStats = internal.StreamStats
+
+// LatencyStats is re-exported from internal package to avoid import cycles.
+// See internal/types.go for full documentation.
+type LatencyStats = internal.LatencyStats
+
+// StatsDelta is re-exported from internal package to avoid import cycles.
+// See internal/watch.go for full documentation.
+type StatsDelta = internal.StatsDelta
+
+// StreamDelta is re-exported from internal package to avoid import cycles.
+// See internal/watch.go for full documentation.
+type StreamDelta = internal.StreamDelta
+
+// WorkerDelta is re-exported from internal package to avoid import cycles.
+// See internal/watch.go for full documentation.
+type WorkerDelta = internal.WorkerDelta
+
+// Subscription is re-exported from internal package to avoid import cycles.
+// See internal/subscription.go for full documentation.
+type Subscription = internal.Subscription
+
+// Errors returned by OpenSubscription and Subscription reads.
+var (
+	// ErrSubscriptionClosed is returned after Close, Unsubscribe or Stop
+	// (and by OpenSubscription during/after Stop).
+	ErrSubscriptionClosed = internal.ErrSubscriptionClosed
+
+	// ErrSubscriptionReplaced is returned after another subscription took
+	// over the worker ID (WithTakeover). Wraps ErrSubscriptionClosed.
+	ErrSubscriptionReplaced = internal.ErrSubscriptionReplaced
+
+	// ErrWorkerExists is returned by OpenSubscription when the worker ID
+	// is already subscribed.
+	ErrWorkerExists = internal.ErrWorkerExists
+
+	// ErrNoFrame is returned by TryRead when no frame is pending.
+	ErrNoFrame = internal.ErrNoFrame
+)
+
+// GroupStats is re-exported from internal package to avoid import cycles.
+// See internal/types.go for full documentation.
+type GroupStats = internal.GroupStats
+
+// SubscribeOption is re-exported from internal package to avoid import cycles.
+// See internal/types.go for full documentation.
+type SubscribeOption = internal.SubscribeOption
+
+// WithStreams limits a subscription to frames of the given stream IDs.
+//
+// Without WithStreams a worker receives all streams. Multiple calls add up.
+//
+// Example:
+//
+//	readFunc := supplier.Subscribe("PersonDetector-room1", framesupplier.WithStreams("room-1"))
+func WithStreams(streamIDs ...string) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.Streams = append(o.Streams, streamIDs...)
+	}
+}
+
+// WithMaxRate limits a subscription to at most hz frames per second per stream.
+//
+// Intended for workers that deliberately run slower than the source (e.g. a
+// VLM at 0.1 Hz): withheld frames count as WorkerStats.Skipped instead of
+// inflating TotalDrops. The cadence follows Frame.Timestamp (capture time).
+// hz ≤ 0 means unlimited.
+func WithMaxRate(hz float64) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.MaxRate = hz
+	}
+}
+
+// WithEveryNth delivers every nth frame of each stream (Seq 1, n+1, 2n+1, ...).
+//
+// Decimation uses Frame.Seq, so workers with the same n see the same frames.
+// n ≤ 1 delivers every frame.
+func WithEveryNth(n int) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.EveryNth = n
+	}
+}
+
+// WithSampleInterval delivers the first frame of each wall-clock aligned window.
+//
+// Windows are aligned to the epoch (10s → :00, :10, :20, ...) using
+// Frame.Timestamp, so workers and cameras sample the same instants.
+// d ≤ 0 disables sampling.
+func WithSampleInterval(d time.Duration) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.SampleInterval = d
+	}
+}
+
+// WithTakeover replaces an existing subscription with the same worker ID.
+//
+// Intended for supervisors restarting a crashed or stuck worker: the
+// previous subscription is closed (a blocked reader wakes up, readFunc
+// returns nil, Read returns ErrSubscriptionReplaced) and the new one starts
+// with fresh stats and the next WorkerStats.Generation.
+//
+// Without WithTakeover a duplicate worker ID is rejected.
+//
+// Example:
+//
+//	readFunc := supplier.Subscribe("PersonDetector", framesupplier.WithTakeover())
+func WithTakeover() SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.Takeover = true
+	}
+}
+
+// WithReplicaGroup subscribes the worker as a replica of group.
+//
+// Replicas of a group share one mailbox (frame-buffer tee): each frame is
+// delivered to exactly one idle replica, so N replicas of a slow model
+// consume up to N× its single-worker rate. While all replicas are busy,
+// newer frames still replace unconsumed ones (JIT drop-old).
+//
+// The first replica's WithStreams filter and sampling options define the
+// group's streams and sampling policy.
+//
+// Example:
+//
+//	for i := 0; i < 3; i++ {
+//		id := fmt.Sprintf("YOLO-%d", i)
+//		go runWorker(supplier.Subscribe(id, framesupplier.WithReplicaGroup("yolo")))
+//	}
+func WithReplicaGroup(group string) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.Group = group
+	}
+}
+
+// Span is re-exported from internal package to avoid import cycles.
+// See internal/span.go for full documentation.
+type Span = internal.Span
+
+// SpanRecorder is re-exported from internal package to avoid import cycles.
+// See internal/span.go for full documentation.
+type SpanRecorder = internal.SpanRecorder
+
+// DistributeSpanName is the name of the span recorded per distributed frame.
+const DistributeSpanName = internal.DistributeSpanName
+
+// Option configures a Supplier at construction time (see New).
+type Option func(*internal.Options)
+
+// WithSpanRecorder enables frame tracing.
+//
+// Per distributed frame the supplier records a "framesupplier.distribute"
+// span (Publish → fan-out) as child of frame.TraceParent, and re-parents
+// the frame under it. Worker spans (StartSpan) are recorded on r as well.
+//
+// r MUST NOT block (called from the distribution loop).
+// Default: nil (no spans, TraceParent passed through unchanged).
+func WithSpanRecorder(r SpanRecorder) Option {
+	return func(o *internal.Options) {
+		o.SpanRecorder = r
+	}
+}
+
+// New creates a new Supplier instance (default configuration without options).
 //
 // Lifecycle:
 //  1. supplier := framesupplier.New()
@@ -132,6 +384,10 @@ type WorkerStats = internal.WorkerStats
 //  4. supplier.Stop()  // Graceful shutdown
 //
 // Returns: Supplier interface (implementation is internal).
-func New() Supplier {
-	return internal.NewSupplier()
+func New(opts ...Option) Supplier {
+	var o internal.Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return internal.NewSupplier(o)
 }
@@ -2,6 +2,11 @@ package framesupplier_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -828,3 +833,1178 @@ func TestStartStopIdempotency(t *testing.T) {
 
 	t.Logf("✅ Start/Stop idempotency validated")
 }
+
+// --- Test 9: Trace Context Propagation ---
+
+// spanCollector is a SpanRecorder that keeps spans in memory.
+type spanCollector struct {
+	mu    sync.Mutex
+	spans []framesupplier.Span
+}
+
+func (c *spanCollector) RecordSpan(span framesupplier.Span) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = append(c.spans, span)
+}
+
+func (c *spanCollector) byName(name string) (framesupplier.Span, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.spans {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return framesupplier.Span{}, false
+}
+
+// TestTracePropagation validates frame trace context through distribution to workers.
+//
+// Contract:
+//   - Distribution span is a child of the published TraceParent (same trace)
+//   - Worker receives frame re-parented under the distribution span
+//   - StartSpan span is a child of the received frame's TraceParent
+func TestTracePropagation(t *testing.T) {
+	const captureTP = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+	collector := &spanCollector{}
+	supplier := framesupplier.New(framesupplier.WithSpanRecorder(collector))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	readFunc := supplier.Subscribe("PersonDetector")
+	defer supplier.Unsubscribe("PersonDetector")
+
+	supplier.Publish(&framesupplier.Frame{Data: []byte("test"), TraceParent: captureTP})
+
+	received := make(chan *framesupplier.Frame, 1)
+	go func() { received <- readFunc() }()
+
+	var frame *framesupplier.Frame
+	select {
+	case frame = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("Worker didn't receive frame")
+	}
+
+	if !strings.Contains(frame.TraceParent, traceID) || frame.TraceParent == captureTP {
+		t.Errorf("frame.TraceParent = %q, want distribution span in trace %s", frame.TraceParent, traceID)
+	}
+
+	span := supplier.StartSpan("worker.PersonDetector", frame)
+	span.SetAttribute("detections", "2")
+	span.End()
+	span.End() // Idempotent
+
+	if !strings.Contains(span.TraceParent, traceID) || span.Parent != frame.TraceParent {
+		t.Errorf("worker span = %s (parent %s), want child of %s", span.TraceParent, span.Parent, frame.TraceParent)
+	}
+
+	// Distribution span is recorded after fan-out (may trail the worker wake-up)
+	deadline := time.Now().Add(time.Second)
+	var distribute framesupplier.Span
+	for {
+		var ok bool
+		if distribute, ok = collector.byName(framesupplier.DistributeSpanName); ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if distribute.Parent != captureTP || distribute.TraceParent != frame.TraceParent {
+		t.Errorf("distribute span = %+v, want parent %s and TraceParent %s", distribute, captureTP, frame.TraceParent)
+	}
+	if distribute.Attributes["frame.seq"] != "1" || distribute.EndTime.Before(distribute.StartTime) {
+		t.Errorf("distribute span attributes/times = %v %v..%v", distribute.Attributes, distribute.StartTime, distribute.EndTime)
+	}
+
+	worker, ok := collector.byName("worker.PersonDetector")
+	if !ok {
+		t.Fatal("worker span not recorded")
+	}
+	if worker.Attributes["detections"] != "2" || worker.Attributes["frame.seq"] != "1" {
+		t.Errorf("worker span attributes = %v", worker.Attributes)
+	}
+
+	collector.mu.Lock()
+	if len(collector.spans) != 2 {
+		t.Errorf("recorded %d spans, want 2 (End is idempotent)", len(collector.spans))
+	}
+	collector.mu.Unlock()
+
+	t.Logf("✅ Trace context propagated capture → distribute → worker")
+}
+
+// TestTracePassThrough validates untraced suppliers leave TraceParent unchanged.
+//
+// Contract:
+//   - Without SpanRecorder: frame.TraceParent reaches workers as published
+//   - StartSpan still returns a valid TraceParent (untraced frame = new trace)
+func TestTracePassThrough(t *testing.T) {
+	const captureTP = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	readFunc := supplier.Subscribe("TestWorker")
+	defer supplier.Unsubscribe("TestWorker")
+
+	supplier.Publish(&framesupplier.Frame{Data: []byte("test"), TraceParent: captureTP})
+	if frame := readFunc(); frame.TraceParent != captureTP {
+		t.Errorf("frame.TraceParent = %q, want %q unchanged", frame.TraceParent, captureTP)
+	}
+
+	span := supplier.StartSpan("worker.Test", &framesupplier.Frame{})
+	if span.Parent != "" || len(span.TraceParent) != 55 || !strings.HasPrefix(span.TraceParent, "00-") {
+		t.Errorf("StartSpan() on untraced frame = %q (parent %q), want new root trace", span.TraceParent, span.Parent)
+	}
+	span.End() // No recorder: no-op
+}
+
+// --- Test 10: Multi-Stream Routing ---
+
+// readWithTimeout calls readFunc, failing the test if no frame arrives in time.
+func readWithTimeout(t *testing.T, readFunc func() *framesupplier.Frame) *framesupplier.Frame {
+	t.Helper()
+
+	received := make(chan *framesupplier.Frame, 1)
+	go func() { received <- readFunc() }()
+
+	select {
+	case frame := <-received:
+		if frame == nil {
+			t.Fatal("readFunc() returned nil (unexpected shutdown)")
+		}
+		return frame
+	case <-time.After(time.Second):
+		t.Fatal("Worker didn't receive frame")
+		return nil
+	}
+}
+
+// spanFunc adapts a function to SpanRecorder.
+type spanFunc func(framesupplier.Span)
+
+func (f spanFunc) RecordSpan(span framesupplier.Span) { f(span) }
+
+// TestDistributeSpanCoversBatches validates the distribution span ends after fan-out.
+//
+// Contract:
+//   - >8 workers (batched fan-out): the span is recorded only once every
+//     batch has published to its slots
+//   - Distribution loop does not wait for batches (fire-and-forget kept)
+func TestDistributeSpanCoversBatches(t *testing.T) {
+	const workers = 40
+
+	var supplier framesupplier.Supplier
+	drops := make(chan uint64, 2)
+	supplier = framesupplier.New(framesupplier.WithSpanRecorder(spanFunc(func(span framesupplier.Span) {
+		if span.Name != framesupplier.DistributeSpanName || span.Attributes["frame.seq"] != "2" {
+			return
+		}
+		// Frame 1 was never consumed: frame 2 overwrites it in every slot
+		var total uint64
+		for _, w := range supplier.Stats().Workers {
+			total += w.TotalDrops
+		}
+		drops <- total
+	})))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	for i := 0; i < workers; i++ {
+		id := fmt.Sprintf("worker-%d", i)
+		supplier.Subscribe(id)
+		defer supplier.Unsubscribe(id)
+	}
+
+	supplier.Publish(&framesupplier.Frame{Data: []byte("frame-1")})
+	time.Sleep(50 * time.Millisecond) // Frame 1 distributed to every slot
+	supplier.Publish(&framesupplier.Frame{Data: []byte("frame-2")})
+
+	select {
+	case total := <-drops:
+		if total != workers {
+			t.Errorf("TotalDrops at span end = %d, want %d (span ended before fan-out)", total, workers)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("distribution span for frame 2 not recorded")
+	}
+
+	t.Logf("✅ Distribution span recorded after all %d slots were published", workers)
+}
+
+// TestMultiStreamRouting validates per-stream subscriptions and sequence counters.
+//
+// Contract:
+//   - WithStreams("a"): worker receives stream a only
+//   - WithStreams("a", "b"): worker receives both streams
+//   - No options: worker receives all streams
+//   - Seq is assigned per stream (each stream starts at 1)
+//   - Stats.Streams reports per-stream counters and subscriber counts
+func TestMultiStreamRouting(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	roomOnly := supplier.Subscribe("Room1Detector", framesupplier.WithStreams("room-1"))
+	defer supplier.Unsubscribe("Room1Detector")
+	both := supplier.Subscribe("SharedDetector", framesupplier.WithStreams("room-1", "room-2"))
+	defer supplier.Unsubscribe("SharedDetector")
+	all := supplier.Subscribe("Recorder")
+	defer supplier.Unsubscribe("Recorder")
+
+	// room-2 first, then room-1 twice (distribution completes between publishes)
+	publish := func(stream string) {
+		supplier.Publish(&framesupplier.Frame{Data: []byte(stream), StreamID: stream})
+		time.Sleep(5 * time.Millisecond)
+	}
+	publish("room-2")
+	publish("room-1")
+
+	// room-1-only worker never sees room-2
+	if frame := readWithTimeout(t, roomOnly); frame.StreamID != "room-1" || frame.Seq != 1 {
+		t.Errorf("Room1Detector got stream=%q seq=%d, want room-1 seq=1", frame.StreamID, frame.Seq)
+	}
+
+	// Multi-stream workers get the latest frame of each stream, oldest stream first
+	for _, read := range []func() *framesupplier.Frame{both, all} {
+		first := readWithTimeout(t, read)
+		second := readWithTimeout(t, read)
+		if first.StreamID != "room-2" || second.StreamID != "room-1" {
+			t.Errorf("multi-stream order = %q, %q (want room-2, room-1)", first.StreamID, second.StreamID)
+		}
+		if first.Seq != 1 || second.Seq != 1 {
+			t.Errorf("Seq = %d, %d (want 1, 1: counters are per stream)", first.Seq, second.Seq)
+		}
+	}
+
+	publish("room-1")
+	if frame := readWithTimeout(t, roomOnly); frame.Seq != 2 {
+		t.Errorf("Room1Detector second frame seq=%d, want 2", frame.Seq)
+	}
+
+	stats := supplier.Stats()
+	room1, room2 := stats.Streams["room-1"], stats.Streams["room-2"]
+	if room1.Published != 2 || room1.LastSeq != 2 || room1.Workers != 3 {
+		t.Errorf("Streams[room-1] = %+v, want Published=2 LastSeq=2 Workers=3", room1)
+	}
+	if room2.Published != 1 || room2.LastSeq != 1 || room2.Workers != 2 {
+		t.Errorf("Streams[room-2] = %+v, want Published=1 LastSeq=1 Workers=2", room2)
+	}
+	if got := stats.Workers["SharedDetector"].Streams; len(got) != 2 || got[0] != "room-1" || got[1] != "room-2" {
+		t.Errorf("Workers[SharedDetector].Streams = %v, want [room-1 room-2]", got)
+	}
+	if got := stats.Workers["Recorder"].Streams; got != nil {
+		t.Errorf("Workers[Recorder].Streams = %v, want nil (all streams)", got)
+	}
+
+	t.Logf("✅ Multi-stream routing validated")
+}
+
+// TestMultiStreamMailboxPerStream validates that streams never overwrite each other.
+//
+// Contract:
+//   - A slow multi-stream worker holds 1 frame per stream (latest of each)
+//   - Overwrites (drops) only happen within a stream
+func TestMultiStreamMailboxPerStream(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	readFunc := supplier.Subscribe("SlowWorker")
+	defer supplier.Unsubscribe("SlowWorker")
+
+	// Busy camera publishes 5 frames, quiet camera 1 (worker doesn't consume)
+	supplier.Publish(&framesupplier.Frame{Data: []byte("quiet"), StreamID: "quiet"})
+	time.Sleep(5 * time.Millisecond)
+	for i := 0; i < 5; i++ {
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}, StreamID: "busy"})
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	quiet := readWithTimeout(t, readFunc)
+	busy := readWithTimeout(t, readFunc)
+	if quiet.StreamID != "quiet" {
+		t.Errorf("first frame from %q, want quiet (not overwritten by busy stream)", quiet.StreamID)
+	}
+	if busy.StreamID != "busy" || busy.Seq != 5 {
+		t.Errorf("second frame stream=%q seq=%d, want latest busy frame (seq 5)", busy.StreamID, busy.Seq)
+	}
+
+	if drops := supplier.Stats().Workers["SlowWorker"].TotalDrops; drops != 4 {
+		t.Errorf("TotalDrops=%d, want 4 (busy frames 1-4 overwritten)", drops)
+	}
+
+	t.Logf("✅ Per-stream worker mailbox validated")
+}
+
+// --- Test 11: Replica Groups ---
+
+// TestReplicaGroupExactlyOnce validates frame-buffer tee delivery.
+//
+// Contract:
+//   - Each frame is delivered to at most one replica (never duplicated)
+//   - Every published frame is either consumed or counted as a group drop
+//   - Stats.Groups and per-replica Stats.Workers add up
+func TestReplicaGroupExactlyOnce(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	const replicas = 3
+	const frames = 50
+
+	var mu sync.Mutex
+	seen := make(map[uint64]string)
+	var wg sync.WaitGroup
+	for i := 0; i < replicas; i++ {
+		id := fmt.Sprintf("YOLO-%d", i)
+		readFunc := supplier.Subscribe(id, framesupplier.WithReplicaGroup("yolo"))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				frame := readFunc()
+				if frame == nil {
+					return
+				}
+				mu.Lock()
+				if other, dup := seen[frame.Seq]; dup {
+					t.Errorf("frame seq=%d delivered to %s and %s", frame.Seq, other, id)
+				}
+				seen[frame.Seq] = id
+				mu.Unlock()
+				time.Sleep(3 * time.Millisecond) // Simulate inference
+			}
+		}()
+	}
+
+	for i := 0; i < frames; i++ {
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}})
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond) // Let replicas drain the mailbox
+
+	stats := supplier.Stats()
+	group, ok := stats.Groups["yolo"]
+	if !ok {
+		t.Fatal("Stats().Groups missing group yolo")
+	}
+	if len(group.Replicas) != replicas || group.Replicas[0] != "YOLO-0" {
+		t.Errorf("Groups[yolo].Replicas = %v, want 3 sorted replicas", group.Replicas)
+	}
+
+	var perReplica uint64
+	for i := 0; i < replicas; i++ {
+		ws := stats.Workers[fmt.Sprintf("YOLO-%d", i)]
+		if ws.ReplicaGroup != "yolo" {
+			t.Errorf("Workers[YOLO-%d].ReplicaGroup = %q, want yolo", i, ws.ReplicaGroup)
+		}
+		perReplica += ws.Consumed
+	}
+
+	mu.Lock()
+	consumed := uint64(len(seen))
+	mu.Unlock()
+	if group.Consumed != consumed || perReplica != consumed {
+		t.Errorf("Consumed: group=%d replicas=%d received=%d (want equal)", group.Consumed, perReplica, consumed)
+	}
+	if consumed+group.TotalDrops != frames {
+		t.Errorf("consumed(%d) + drops(%d) = %d, want %d", consumed, group.TotalDrops, consumed+group.TotalDrops, frames)
+	}
+
+	for i := 0; i < replicas; i++ {
+		supplier.Unsubscribe(fmt.Sprintf("YOLO-%d", i))
+	}
+	wg.Wait()
+
+	if _, ok := supplier.Stats().Groups["yolo"]; ok {
+		t.Error("group yolo still present after last replica unsubscribed")
+	}
+
+	t.Logf("✅ Replica group delivered %d/%d frames exactly once (%d drops)", consumed, frames, group.TotalDrops)
+}
+
+// TestReplicaGroupWorkStealing validates that idle replicas take frames from busy ones.
+//
+// Contract:
+//   - While one replica is busy, the next frame goes to an idle replica
+//   - Unsubscribe stops only that replica (readFunc returns nil)
+//   - Remaining replicas keep receiving frames
+func TestReplicaGroupWorkStealing(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	readA := supplier.Subscribe("VLM-A", framesupplier.WithReplicaGroup("vlm"))
+	readB := supplier.Subscribe("VLM-B", framesupplier.WithReplicaGroup("vlm"))
+	defer supplier.Unsubscribe("VLM-B")
+
+	// A takes frame 1 and stays busy (doesn't read again)
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}})
+	if frame := readWithTimeout(t, readA); frame.Seq != 1 {
+		t.Errorf("VLM-A got seq=%d, want 1", frame.Seq)
+	}
+
+	// Frame 2 goes to idle B
+	supplier.Publish(&framesupplier.Frame{Data: []byte{2}})
+	if frame := readWithTimeout(t, readB); frame.Seq != 2 {
+		t.Errorf("VLM-B got seq=%d, want 2", frame.Seq)
+	}
+
+	// B waits for the next frame; A leaves the group
+	received := make(chan *framesupplier.Frame, 1)
+	go func() { received <- readB() }()
+	time.Sleep(5 * time.Millisecond)
+
+	supplier.Unsubscribe("VLM-A")
+	if frame := readA(); frame != nil {
+		t.Errorf("VLM-A readFunc() after Unsubscribe = seq %d, want nil", frame.Seq)
+	}
+	select {
+	case frame := <-received:
+		t.Fatalf("VLM-B woke up on VLM-A's Unsubscribe (frame=%v)", frame)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	supplier.Publish(&framesupplier.Frame{Data: []byte{3}})
+	select {
+	case frame := <-received:
+		if frame == nil || frame.Seq != 3 {
+			t.Errorf("VLM-B got %v, want seq=3", frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("VLM-B didn't receive frame after VLM-A left")
+	}
+
+	stats := supplier.Stats()
+	if _, ok := stats.Workers["VLM-A"]; ok {
+		t.Error("Workers[VLM-A] still present after Unsubscribe")
+	}
+	if b := stats.Workers["VLM-B"]; b.Consumed != 2 || b.LastConsumedSeq != 3 || b.IsIdle {
+		t.Errorf("Workers[VLM-B] = %+v, want Consumed=2 LastConsumedSeq=3", b)
+	}
+	if g := stats.Groups["vlm"]; len(g.Replicas) != 1 || g.Consumed != 3 || g.TotalDrops != 0 {
+		t.Errorf("Groups[vlm] = %+v, want 1 replica, Consumed=3, no drops", g)
+	}
+
+	t.Logf("✅ Replica work-stealing validated")
+}
+
+// --- Test 12: Sampling Policies ---
+
+// TestSubscriptionSampling validates rate limiting and decimation.
+//
+// Contract:
+//   - WithEveryNth(3): Seq 1, 4, 7 (Seq-based, per stream)
+//   - WithMaxRate(10): ≤1 frame per 100ms of capture time, no drift
+//   - WithSampleInterval(200ms): first frame of each aligned window
+//   - Withheld frames count as Skipped, never as TotalDrops
+func TestSubscriptionSampling(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	subs := map[string][]framesupplier.SubscribeOption{
+		"All":      nil,
+		"EveryNth": {framesupplier.WithEveryNth(3)},
+		"MaxRate":  {framesupplier.WithMaxRate(10)},
+		"Interval": {framesupplier.WithSampleInterval(200 * time.Millisecond)},
+	}
+
+	var mu sync.Mutex
+	received := make(map[string][]uint64)
+	var wg sync.WaitGroup
+	for id, opts := range subs {
+		readFunc := supplier.Subscribe(id, opts...)
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			for frame := readFunc(); frame != nil; frame = readFunc() {
+				mu.Lock()
+				received[id] = append(received[id], frame.Seq)
+				mu.Unlock()
+			}
+		}(id)
+	}
+
+	// 30fps capture timestamps (0, 33, ..., 297ms), aligned to a 200ms window
+	base := time.Unix(1700000000, 0)
+	for i := 0; i < 10; i++ {
+		supplier.Publish(&framesupplier.Frame{
+			Data:      []byte{byte(i)},
+			Timestamp: base.Add(time.Duration(i) * 33 * time.Millisecond),
+		})
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	stats := supplier.Stats()
+	for id := range subs {
+		supplier.Unsubscribe(id)
+	}
+	wg.Wait()
+
+	want := map[string][]uint64{
+		"All":      {1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+		"EveryNth": {1, 4, 7, 10},
+		"MaxRate":  {1, 5, 8}, // 0ms, 132ms (≥100), 231ms (≥200); 297ms < 300
+		"Interval": {1, 8},    // windows [0,200) and [200,400)
+	}
+	for id, seqs := range want {
+		if got := received[id]; fmt.Sprint(got) != fmt.Sprint(seqs) {
+			t.Errorf("%s received %v, want %v", id, got, seqs)
+		}
+		ws := stats.Workers[id]
+		if ws.TotalDrops != 0 {
+			t.Errorf("%s TotalDrops=%d, want 0 (policy skips are not drops)", id, ws.TotalDrops)
+		}
+		if wantSkipped := uint64(10 - len(seqs)); ws.Skipped != wantSkipped {
+			t.Errorf("%s Skipped=%d, want %d", id, ws.Skipped, wantSkipped)
+		}
+	}
+
+	t.Logf("✅ Subscription sampling validated")
+}
+
+// --- Test 13: Subscription Handle ---
+
+// openSubscription opens a subscription that must be accepted.
+func openSubscription(t *testing.T, supplier framesupplier.Supplier, workerID string, opts ...framesupplier.SubscribeOption) *framesupplier.Subscription {
+	t.Helper()
+	sub, err := supplier.OpenSubscription(workerID, opts...)
+	if err != nil {
+		t.Fatalf("OpenSubscription(%q) failed: %v", workerID, err)
+	}
+	return sub
+}
+
+// TestSubscriptionContextRead validates context-aware reads.
+//
+// Contract:
+//   - TryRead: ErrNoFrame when empty, frame when pending (non-blocking)
+//   - Read(ctx): returns ctx.Err() promptly on cancellation
+//   - ReadTimeout: returns context.DeadlineExceeded
+//   - Canceled/timed-out reads leave no goroutines behind
+//   - Stats: same snapshot as Supplier.Stats().Workers[id]
+func TestSubscriptionContextRead(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	sub := openSubscription(t, supplier, "Detector")
+	defer sub.Close()
+
+	if _, err := sub.TryRead(); !errors.Is(err, framesupplier.ErrNoFrame) {
+		t.Errorf("TryRead() on empty mailbox err=%v, want ErrNoFrame", err)
+	}
+
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}})
+	time.Sleep(5 * time.Millisecond)
+	if frame, err := sub.TryRead(); err != nil || frame.Seq != 1 {
+		t.Errorf("TryRead() = %v, %v; want seq=1", frame, err)
+	}
+
+	// Read unblocks on cancellation
+	readCtx, readCancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := sub.Read(readCtx)
+		errCh <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	readCancel()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Read() after cancel err=%v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() didn't return after ctx cancel")
+	}
+
+	// Many timed-out reads must not accumulate goroutines
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		if _, err := sub.ReadTimeout(time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("ReadTimeout() err=%v, want context.DeadlineExceeded", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > before+2 {
+		t.Errorf("goroutines grew from %d to %d after 100 timed-out reads", before, after)
+	}
+
+	// Frame still delivered after timeouts
+	supplier.Publish(&framesupplier.Frame{Data: []byte{2}})
+	if frame, err := sub.ReadTimeout(time.Second); err != nil || frame.Seq != 2 {
+		t.Errorf("ReadTimeout() = %v, %v; want seq=2", frame, err)
+	}
+
+	if got, want := sub.Stats(), supplier.Stats().Workers["Detector"]; got.Consumed != 2 || got.LastConsumedSeq != want.LastConsumedSeq {
+		t.Errorf("sub.Stats() = %+v, want Consumed=2 matching Supplier.Stats() %+v", got, want)
+	}
+
+	t.Logf("✅ Context-aware subscription reads validated")
+}
+
+// TestSubscriptionClose validates Close semantics and readFunc compatibility.
+//
+// Contract:
+//   - Close wakes a blocked Read with ErrSubscriptionClosed
+//   - Close of a stale subscription leaves a newer one with the same ID alone
+//   - Stop closes subscriptions (Read returns ErrSubscriptionClosed)
+//   - Replica subscriptions support the same API
+func TestSubscriptionClose(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+
+	// Close wakes blocked Read
+	sub := openSubscription(t, supplier, "Worker")
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := sub.Read(context.Background())
+		errCh <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	sub.Close()
+	sub.Close() // Idempotent
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+			t.Errorf("Read() after Close err=%v, want ErrSubscriptionClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() didn't return after Close")
+	}
+
+	// Stale Close doesn't affect a newer subscription with the same ID
+	newer := openSubscription(t, supplier, "Worker")
+	sub.Close()
+	if _, ok := supplier.Stats().Workers["Worker"]; !ok {
+		t.Error("stale Close removed the newer subscription")
+	}
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}})
+	if _, err := newer.ReadTimeout(time.Second); err != nil {
+		t.Errorf("newer.ReadTimeout() err=%v, want frame", err)
+	}
+
+	// Replica subscription: TryRead + Close
+	replica := openSubscription(t, supplier, "YOLO-0", framesupplier.WithReplicaGroup("yolo"))
+	supplier.Publish(&framesupplier.Frame{Data: []byte{2}})
+	time.Sleep(5 * time.Millisecond)
+	if frame, err := replica.TryRead(); err != nil || frame.Seq != 2 {
+		t.Errorf("replica.TryRead() = %v, %v; want seq=2", frame, err)
+	}
+	if stat := replica.Stats(); stat.ReplicaGroup != "yolo" || stat.Consumed != 1 {
+		t.Errorf("replica.Stats() = %+v, want ReplicaGroup=yolo Consumed=1", stat)
+	}
+	replica.Close()
+	if _, ok := supplier.Stats().Groups["yolo"]; ok {
+		t.Error("group yolo still present after its only replica closed")
+	}
+
+	// Stop closes remaining subscriptions
+	supplier.Stop()
+	if _, err := newer.Read(context.Background()); !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+		t.Errorf("Read() after Stop err=%v, want ErrSubscriptionClosed", err)
+	}
+	if _, err := supplier.OpenSubscription("Late"); !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+		t.Errorf("OpenSubscription() after Stop err=%v, want ErrSubscriptionClosed", err)
+	}
+
+	t.Logf("✅ Subscription Close semantics validated")
+}
+
+// --- Test 14: Duplicate Worker IDs ---
+
+// TestDuplicateWorkerID validates duplicate rejection, takeover and generations.
+//
+// Contract:
+//   - Duplicate Subscribe is rejected (readFunc returns nil, original untouched)
+//   - A rejected duplicate's Unsubscribe does not remove the live worker
+//   - Duplicate OpenSubscription returns ErrWorkerExists at registration
+//   - WithTakeover closes the previous subscription and wakes its reader
+//   - Generation increments per accepted subscription (also across Unsubscribe)
+//   - Plain workers and replicas share the worker ID namespace
+func TestDuplicateWorkerID(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	original := openSubscription(t, supplier, "Detector")
+	if gen := supplier.Stats().Workers["Detector"].Generation; gen != 1 {
+		t.Errorf("Generation=%d, want 1", gen)
+	}
+
+	// Duplicate rejected: readFunc returns nil, original keeps receiving
+	if frame := supplier.Subscribe("Detector")(); frame != nil {
+		t.Errorf("duplicate readFunc() = seq %d, want nil", frame.Seq)
+	}
+	supplier.Unsubscribe("Detector") // The rejected worker's deferred cleanup
+	if stat, ok := supplier.Stats().Workers["Detector"]; !ok || stat.Generation != 1 {
+		t.Fatalf("rejected duplicate's Unsubscribe removed the live worker (stats %+v, present=%v)", stat, ok)
+	}
+	if _, err := supplier.OpenSubscription("Detector", framesupplier.WithReplicaGroup("g")); !errors.Is(err, framesupplier.ErrWorkerExists) {
+		t.Errorf("duplicate replica OpenSubscription() err=%v, want ErrWorkerExists", err)
+	}
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}})
+	if frame, err := original.ReadTimeout(time.Second); err != nil || frame.Seq != 1 {
+		t.Errorf("original.ReadTimeout() = %v, %v; want seq=1 (untouched by duplicates)", frame, err)
+	}
+
+	// Takeover wakes the blocked original
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := original.Read(context.Background())
+		errCh <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+
+	restarted := openSubscription(t, supplier, "Detector", framesupplier.WithTakeover())
+	defer restarted.Close()
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, framesupplier.ErrSubscriptionReplaced) || !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+			t.Errorf("original Read() err=%v, want ErrSubscriptionReplaced (wrapping ErrSubscriptionClosed)", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("original reader not woken by takeover")
+	}
+
+	stat := supplier.Stats().Workers["Detector"]
+	if stat.Generation != 2 || restarted.Generation() != 2 || stat.Consumed != 0 {
+		t.Errorf("after takeover: %+v, want Generation=2 with fresh stats", stat)
+	}
+
+	// Takeover across kinds: replica replaces plain worker
+	replica := openSubscription(t, supplier, "Detector", framesupplier.WithReplicaGroup("g"), framesupplier.WithTakeover())
+	if _, err := restarted.TryRead(); !errors.Is(err, framesupplier.ErrSubscriptionReplaced) {
+		t.Errorf("plain TryRead() after replica takeover err=%v, want ErrSubscriptionReplaced", err)
+	}
+	if stat := supplier.Stats().Workers["Detector"]; stat.ReplicaGroup != "g" || stat.Generation != 3 {
+		t.Errorf("after replica takeover: %+v, want ReplicaGroup=g Generation=3", stat)
+	}
+
+	// Generation survives Unsubscribe
+	supplier.Unsubscribe("Detector")
+	if _, err := replica.TryRead(); !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+		t.Errorf("replica TryRead() after Unsubscribe err=%v, want ErrSubscriptionClosed", err)
+	}
+	again := openSubscription(t, supplier, "Detector")
+	defer again.Close()
+	if again.Generation() != 4 {
+		t.Errorf("Generation after re-subscribe = %d, want 4", again.Generation())
+	}
+
+	t.Logf("✅ Duplicate worker ID protection validated")
+}
+
+// --- Test 15: SLA Classes and Health Policies ---
+
+// healthEventLog records health events (HealthHandler).
+type healthEventLog struct {
+	mu     sync.Mutex
+	events []framesupplier.HealthEvent
+}
+
+func (l *healthEventLog) handle(ev framesupplier.HealthEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, ev)
+}
+
+// wait returns the first event matching worker, violation and resolved (polls up to 1s).
+func (l *healthEventLog) wait(t *testing.T, workerID string, v framesupplier.Violation, resolved bool) framesupplier.HealthEvent {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		for _, ev := range l.events {
+			if ev.WorkerID == workerID && ev.Violation == v && ev.Resolved == resolved {
+				l.mu.Unlock()
+				return ev
+			}
+		}
+		l.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("no %s event for %s (resolved=%v)", v, workerID, resolved)
+	return framesupplier.HealthEvent{}
+}
+
+// TestHealthIdleBySLAClass validates idle detection with per-worker thresholds.
+//
+// Contract:
+//   - Idle violation fires after the worker's IdleTimeout (not the global 30s)
+//   - Critical/Normal → Unhealthy, BestEffort → Degraded
+//   - Consuming again resolves the violation (Healthy)
+func TestHealthIdleBySLAClass(t *testing.T) {
+	events := &healthEventLog{}
+	supplier := framesupplier.New(
+		framesupplier.WithHealthHandler(events.handle),
+		framesupplier.WithHealthCheckInterval(5*time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	policy := framesupplier.WithHealthPolicy(framesupplier.HealthPolicy{IdleTimeout: 20 * time.Millisecond})
+	critical := supplier.Subscribe("PersonDetector", framesupplier.WithSLA(framesupplier.SLACritical), policy)
+	defer supplier.Unsubscribe("PersonDetector")
+	supplier.Subscribe("VLM", framesupplier.WithSLA(framesupplier.SLABestEffort), policy)
+	defer supplier.Unsubscribe("VLM")
+
+	ev := events.wait(t, "PersonDetector", framesupplier.ViolationIdle, false)
+	if ev.Status != framesupplier.Unhealthy || ev.Class != framesupplier.SLACritical {
+		t.Errorf("critical idle event = %+v, want Unhealthy", ev)
+	}
+	ev = events.wait(t, "VLM", framesupplier.ViolationIdle, false)
+	if ev.Status != framesupplier.Degraded {
+		t.Errorf("best-effort idle event = %+v, want Degraded", ev)
+	}
+
+	stat := supplier.Stats().Workers["PersonDetector"]
+	if !stat.IsIdle || stat.Health != framesupplier.Unhealthy || len(stat.Violations) != 1 || stat.Violations[0] != framesupplier.ViolationIdle {
+		t.Errorf("Stats = %+v, want IsIdle, Unhealthy, [idle]", stat)
+	}
+
+	// Consuming resolves the violation
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}})
+	readWithTimeout(t, critical)
+	ev = events.wait(t, "PersonDetector", framesupplier.ViolationIdle, true)
+	if ev.Status != framesupplier.Healthy {
+		t.Errorf("resolved event = %+v, want Healthy", ev)
+	}
+
+	// Default class keeps the 30s threshold
+	supplier.Subscribe("Recorder")
+	defer supplier.Unsubscribe("Recorder")
+	if stat := supplier.Stats().Workers["Recorder"]; stat.SLA != framesupplier.SLANormal || stat.IsIdle {
+		t.Errorf("Recorder stats = %+v, want SLANormal, not idle", stat)
+	}
+
+	t.Logf("✅ SLA class idle detection validated")
+}
+
+// TestHealthDropThresholds validates drop streak and drop rate thresholds.
+//
+// Contract:
+//   - ConsecutiveDrops > MaxConsecutiveDrops → violation
+//   - Drop rate over window > MaxDropRate → violation
+//   - Frames skipped by sampling policy never count as drops
+func TestHealthDropThresholds(t *testing.T) {
+	events := &healthEventLog{}
+	supplier := framesupplier.New(
+		framesupplier.WithHealthHandler(events.handle),
+		framesupplier.WithHealthCheckInterval(5*time.Millisecond),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	policy := framesupplier.HealthPolicy{
+		IdleTimeout:         time.Hour,
+		MaxConsecutiveDrops: 2,
+		MaxDropRate:         0.5,
+		DropRateWindow:      time.Second,
+	}
+	supplier.Subscribe("Stuck", framesupplier.WithHealthPolicy(policy))
+	defer supplier.Unsubscribe("Stuck")
+	sampled := openSubscription(t, supplier, "Sampled", framesupplier.WithHealthPolicy(policy), framesupplier.WithEveryNth(2))
+	defer sampled.Close()
+
+	time.Sleep(20 * time.Millisecond) // Baseline samples before traffic
+
+	// Stuck never consumes (4 frames → 3 drops); Sampled consumes all it gets
+	for i := 0; i < 4; i++ {
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}})
+		time.Sleep(5 * time.Millisecond)
+		sampled.TryRead()
+	}
+
+	ev := events.wait(t, "Stuck", framesupplier.ViolationConsecutiveDrops, false)
+	if ev.Value != 3 || ev.Threshold != 2 {
+		t.Errorf("consecutive drops event = %+v, want value 3 threshold 2", ev)
+	}
+	ev = events.wait(t, "Stuck", framesupplier.ViolationDropRate, false)
+	if ev.Value <= 0.5 {
+		t.Errorf("drop rate event value=%.2f, want > 0.5", ev.Value)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	stat := supplier.Stats().Workers["Sampled"]
+	if stat.Health != framesupplier.Healthy || stat.TotalDrops != 0 || stat.Skipped != 2 {
+		t.Errorf("Sampled stats = %+v, want Healthy, 0 drops, 2 skipped", stat)
+	}
+
+	t.Logf("✅ Health drop thresholds validated")
+}
+
+// --- Test 16: Latency Histograms ---
+
+// TestLatencyHistograms validates frame age and consume interval tracking.
+//
+// Contract:
+//   - FrameAge measures Frame.Timestamp → readFunc return (quantiles ≤6.25% error)
+//   - ConsumeInterval measures time between consumes (first consume is baseline)
+//   - Frames without Timestamp are not counted in FrameAge
+//   - Replicas keep their own histograms
+func TestLatencyHistograms(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	readFunc := supplier.Subscribe("Detector")
+	defer supplier.Unsubscribe("Detector")
+
+	if stat := supplier.Stats().Workers["Detector"]; stat.FrameAge.Count != 0 || stat.ConsumeInterval.Count != 0 {
+		t.Errorf("histograms before consume = %+v / %+v, want empty", stat.FrameAge, stat.ConsumeInterval)
+	}
+
+	// Ages 100ms..1s (captured in the past), consumes ~10ms apart
+	for i := 1; i <= 10; i++ {
+		age := time.Duration(i) * 100 * time.Millisecond
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}, Timestamp: time.Now().Add(-age)})
+		readWithTimeout(t, readFunc)
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Untimestamped frame: interval only
+	supplier.Publish(&framesupplier.Frame{Data: []byte{0}})
+	readWithTimeout(t, readFunc)
+
+	stat := supplier.Stats().Workers["Detector"]
+	age := stat.FrameAge
+	if age.Count != 10 {
+		t.Fatalf("FrameAge.Count = %d, want 10", age.Count)
+	}
+	within := func(got, want time.Duration, tol float64) bool {
+		return float64(got) >= float64(want)*(1-tol) && float64(got) <= float64(want)*(1+tol)
+	}
+	// Nearest-rank p50 of 10 samples = 5th (≈500ms); age includes distribution latency
+	if !within(age.P50, 500*time.Millisecond, 0.1) || !within(age.P99, time.Second, 0.1) {
+		t.Errorf("FrameAge = %+v, want P50≈500ms P99≈1s", age)
+	}
+	if age.Min < 100*time.Millisecond || age.Max < time.Second || age.Min > age.P50 || age.P95 > age.Max {
+		t.Errorf("FrameAge min/max/order = %+v", age)
+	}
+
+	interval := stat.ConsumeInterval
+	if interval.Count != 10 || interval.P50 < 10*time.Millisecond || interval.P50 > 100*time.Millisecond {
+		t.Errorf("ConsumeInterval = %+v, want 10 samples with P50 ≥ 10ms", interval)
+	}
+
+	// Replica histograms are per replica
+	replica := openSubscription(t, supplier, "YOLO-0", framesupplier.WithReplicaGroup("yolo"))
+	defer replica.Close()
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}, Timestamp: time.Now().Add(-50 * time.Millisecond)})
+	if _, err := replica.ReadTimeout(time.Second); err != nil {
+		t.Fatalf("replica.ReadTimeout() err=%v", err)
+	}
+	if got := replica.Stats().FrameAge; got.Count != 1 || !within(got.P50, 50*time.Millisecond, 0.1) {
+		t.Errorf("replica FrameAge = %+v, want 1 sample ≈50ms", got)
+	}
+
+	t.Logf("✅ Latency histograms validated (age p50=%v p99=%v, interval p50=%v)", age.P50, age.P99, interval.P50)
+}
+
+// --- Test 17: Stats Stream (Watch) ---
+
+// TestWatchDeltas validates Watch delivers per-interval deltas.
+//
+// Contract:
+//   - Stats counts Published and records DistributionLatency per frame
+//   - Each delta counts only the interval's frames (no double counting)
+//   - Added/Removed report subscription changes, Restarted a new generation
+//   - The channel closes when ctx is done
+func TestWatchDeltas(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	sub := openSubscription(t, supplier, "Detector")
+	defer sub.Close()
+
+	watchCtx, stopWatch := context.WithCancel(ctx)
+	defer stopWatch()
+	deltas := supplier.Watch(watchCtx, 50*time.Millisecond)
+
+	next := func() framesupplier.StatsDelta {
+		t.Helper()
+		select {
+		case d, ok := <-deltas:
+			if !ok {
+				t.Fatal("Watch channel closed")
+			}
+			return d
+		case <-time.After(time.Second):
+			t.Fatal("no delta within 1s")
+		}
+		return framesupplier.StatsDelta{}
+	}
+	// collect sums deltas until want frames were published (the ticker may
+	// split them across intervals).
+	collect := func(want uint64) (published, consumed uint64, last framesupplier.StatsDelta) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for published < want && time.Now().Before(deadline) {
+			last = next()
+			published += last.Published
+			consumed += last.Workers["Detector"].Consumed
+		}
+		return published, consumed, last
+	}
+
+	for i := 0; i < 5; i++ {
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}, Timestamp: time.Now()})
+		if _, err := sub.ReadTimeout(time.Second); err != nil {
+			t.Fatalf("ReadTimeout() err=%v", err)
+		}
+	}
+	published, consumed, _ := collect(5)
+	if published != 5 || consumed != 5 {
+		t.Fatalf("first deltas published=%d consumed=%d, want 5/5", published, consumed)
+	}
+
+	// Next interval counts only new frames
+	for i := 0; i < 3; i++ {
+		supplier.Publish(&framesupplier.Frame{Data: []byte{byte(i)}})
+		if _, err := sub.ReadTimeout(time.Second); err != nil {
+			t.Fatalf("ReadTimeout() err=%v", err)
+		}
+	}
+	published, consumed, last := collect(3)
+	if published != 3 || consumed != 3 {
+		t.Errorf("second deltas published=%d consumed=%d, want 3/3", published, consumed)
+	}
+	if last.Interval <= 0 || last.Stats.Published != 8 {
+		t.Errorf("delta Interval=%v Stats.Published=%d, want >0 and 8", last.Interval, last.Stats.Published)
+	}
+
+	stats := supplier.Stats()
+	if stats.Published != 8 || stats.DistributionLatency.Count != 8 {
+		t.Errorf("Published=%d DistributionLatency.Count=%d, want 8/8", stats.Published, stats.DistributionLatency.Count)
+	}
+
+	// Subscription changes: new worker, restarted worker
+	other := openSubscription(t, supplier, "Tracker")
+	defer other.Close()
+	restarted := openSubscription(t, supplier, "Detector", framesupplier.WithTakeover())
+	defer restarted.Close()
+
+	var added, wasRestarted bool
+	deadline := time.Now().Add(time.Second)
+	for !(added && wasRestarted) && time.Now().Before(deadline) {
+		d := next()
+		for _, id := range d.Added {
+			added = added || id == "Tracker"
+		}
+		wasRestarted = wasRestarted || d.Workers["Detector"].Restarted
+	}
+	if !added || !wasRestarted {
+		t.Errorf("Added Tracker=%v, Detector Restarted=%v, want both", added, wasRestarted)
+	}
+
+	other.Close()
+	var removed bool
+	deadline = time.Now().Add(time.Second)
+	for !removed && time.Now().Before(deadline) {
+		for _, id := range next().Removed {
+			removed = removed || id == "Tracker"
+		}
+	}
+	if !removed {
+		t.Error("Tracker not reported in Removed")
+	}
+
+	// Closed on ctx done
+	stopWatch()
+	closed := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-deltas:
+			if !ok {
+				t.Logf("✅ Watch deltas validated (distribution p99=%v)", stats.DistributionLatency.P99)
+				return
+			}
+		case <-closed:
+			t.Fatal("Watch channel not closed after ctx cancel")
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package framesupplier
+
+import (
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier/internal"
+)
+
+// SLAClass is re-exported from internal package to avoid import cycles.
+// See internal/health.go for full documentation.
+type SLAClass = internal.SLAClass
+
+// SLA classes (see WithSLA).
+const (
+	// SLANormal is the default class (idle after 30s, violations → Unhealthy).
+	SLANormal = internal.SLANormal
+
+	// SLACritical is for life-critical workers such as PersonDetector
+	// (idle after 10s, violations → Unhealthy).
+	SLACritical = internal.SLACritical
+
+	// SLABestEffort is for low-priority workers such as VLM
+	// (idle after 2min, violations → Degraded only).
+	SLABestEffort = internal.SLABestEffort
+)
+
+// HealthStatus is re-exported from internal package to avoid import cycles.
+// See internal/health.go for full documentation.
+type HealthStatus = internal.HealthStatus
+
+// Health verdicts (WorkerStats.Health, HealthEvent.Status).
+const (
+	Healthy   = internal.Healthy
+	Degraded  = internal.Degraded
+	Unhealthy = internal.Unhealthy
+)
+
+// Violation is re-exported from internal package to avoid import cycles.
+// See internal/health.go for full documentation.
+type Violation = internal.Violation
+
+// Health violations (WorkerStats.Violations, HealthEvent.Violation).
+const (
+	ViolationIdle             = internal.ViolationIdle
+	ViolationConsecutiveDrops = internal.ViolationConsecutiveDrops
+	ViolationDropRate         = internal.ViolationDropRate
+)
+
+// HealthPolicy is re-exported from internal package to avoid import cycles.
+// See internal/health.go for full documentation.
+type HealthPolicy = internal.HealthPolicy
+
+// HealthEvent is re-exported from internal package to avoid import cycles.
+// See internal/health.go for full documentation.
+type HealthEvent = internal.HealthEvent
+
+// WithSLA sets the worker's SLA class (default SLANormal).
+//
+// The class picks the default idle timeout and the verdict severity:
+// violations make Normal and Critical workers Unhealthy, BestEffort
+// workers only Degraded.
+//
+// Example:
+//
+//	readFunc := supplier.Subscribe("PersonDetector", framesupplier.WithSLA(framesupplier.SLACritical))
+func WithSLA(class SLAClass) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.SLA = class
+	}
+}
+
+// WithHealthPolicy sets the worker's health thresholds.
+//
+// Zero fields keep the SLA class default (IdleTimeout) or stay disabled
+// (MaxConsecutiveDrops, MaxDropRate). Drops count frames the worker was too
+// slow for; frames skipped by WithMaxRate/WithEveryNth/WithSampleInterval
+// never count against the drop thresholds.
+//
+// Example:
+//
+//	supplier.Subscribe("PersonDetector",
+//		framesupplier.WithSLA(framesupplier.SLACritical),
+//		framesupplier.WithHealthPolicy(framesupplier.HealthPolicy{
+//			MaxConsecutiveDrops: 30,   // 1s @ 30fps
+//			MaxDropRate:         0.05, // 5% over 10s
+//		}))
+func WithHealthPolicy(p HealthPolicy) SubscribeOption {
+	return func(o *internal.SubscribeOptions) {
+		o.Health = p
+	}
+}
+
+// WithHealthHandler receives health violation events.
+//
+// The supplier evaluates every worker once per health check interval and
+// calls fn when a violation starts (Resolved=false) or ends (Resolved=true).
+// fn runs on the health goroutine (never on the distribution path); a slow
+// fn delays the next evaluation only.
+//
+// Default: nil (verdicts available in Stats only).
+func WithHealthHandler(fn func(HealthEvent)) Option {
+	return func(o *internal.Options) {
+		o.HealthHandler = fn
+	}
+}
+
+// WithHealthCheckInterval sets how often worker health is evaluated.
+//
+// Default: 1s. Idle and drop-streak violations are detected at most one
+// interval late.
+func WithHealthCheckInterval(d time.Duration) Option {
+	return func(o *internal.Options) {
+		o.HealthInterval = d
+	}
+}
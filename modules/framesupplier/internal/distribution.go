@@ -22,15 +22,20 @@ import (
 // See: ADR-003 (Batching with Threshold=8), ARCHITECTURE.md (Algorithm 3)
 const publishBatchSize = 8
 
-// distributeToWorkers fans out a frame to all worker slots with threshold-based batching.
+// distributeToWorkers fans out a frame to the worker slots subscribed to its stream,
+// with threshold-based batching.
 //
 // Algorithm (ADR-003):
-//  1. Assign global sequence number to frame (atomic increment)
-//  2. Snapshot worker slots (sync.Map → slice)
+//  1. Assign per-stream sequence number to frame (atomic increment)
+//  2. Snapshot worker slots subscribed to the stream whose sampling policy
+//     admits the frame (sync.Map → slice, policy skips counted per slot)
 //  3. Decision tree:
 //     - If ≤8 workers: Sequential for-loop (0 goroutines spawned)
 //     - If >8 workers: Parallel batching (spawn ⌈N/8⌉ goroutines)
 //  4. Fire-and-forget: No wg.Wait (ordering guaranteed by physics)
+//  5. Call done once every slot has been published to: inline on the
+//     sequential path, from the last batch to finish on the parallel path
+//     (so the distribute span and latency cover the whole fan-out)
 //
 // Ordering Guarantee (Physical Invariant):
 //   - Distribution latency: ~100µs @ 64 workers
@@ -47,22 +52,34 @@ const publishBatchSize = 8
 //   - Parallel (>8w): ~20µs spawn + max(batch) (e.g., 64w = 30µs total)
 //
 // See: ADR-003 (Batching), ARCHITECTURE.md (Algorithm 3, Fire-and-forget rationale)
-func (s *supplier) distributeToWorkers(frame *Frame) {
-	// Assign global sequence number (monotonically increasing)
-	frame.Seq = atomic.AddUint64(&s.publishSeq, 1)
+func (s *supplier) distributeToWorkers(inbox *streamInbox, frame *Frame, done func()) {
+	// Assign per-stream sequence number (monotonically increasing within the stream)
+	frame.Seq = atomic.AddUint64(&inbox.seq, 1)
 
-	// Snapshot worker slots (sync.Map → slice)
+	// Snapshot subscribed worker slots (sync.Map → slice)
 	// Reason: sync.Map.Range is safe but not suitable for nested iteration
 	var slots []*WorkerSlot
 	s.slots.Range(func(key, value interface{}) bool {
-		slots = append(slots, value.(*WorkerSlot))
+		slot := value.(*WorkerSlot)
+		if slot.accepts(frame.StreamID) && slot.sampler.admit(frame) {
+			slots = append(slots, slot)
+		}
 		return true // Continue iteration
 	})
+	// Replica groups: one shared slot per group (frame goes to one replica)
+	s.groups.Range(func(key, value interface{}) bool {
+		slot := value.(*WorkerSlot)
+		if slot.accepts(frame.StreamID) && slot.sampler.admit(frame) {
+			slots = append(slots, slot)
+		}
+		return true
+	})
 
 	workerCount := len(slots)
 
 	// Fast path: No workers registered (no-op)
 	if workerCount == 0 {
+		done()
 		return
 	}
 
@@ -71,12 +88,15 @@ func (s *supplier) distributeToWorkers(frame *Frame) {
 		for _, slot := range slots {
 			s.publishToSlot(slot, frame)
 		}
+		done()
 		return
 	}
 
 	// Parallel path: >8 workers (fire-and-forget batching)
 	// Spawn ⌈workerCount / publishBatchSize⌉ goroutines
 	// Each goroutine processes publishBatchSize slots (last batch may be smaller)
+	// The last batch to finish calls done (no wait in distributionLoop)
+	remaining := int32((workerCount + publishBatchSize - 1) / publishBatchSize)
 	for i := 0; i < workerCount; i += publishBatchSize {
 		end := i + publishBatchSize
 		if end > workerCount {
@@ -91,6 +111,9 @@ func (s *supplier) distributeToWorkers(frame *Frame) {
 			for _, slot := range b {
 				s.publishToSlot(slot, frame)
 			}
+			if atomic.AddInt32(&remaining, -1) == 0 {
+				done()
+			}
 		}(batch)
 	}
 
@@ -34,8 +34,22 @@ type Frame struct {
 	// Timestamp when frame was captured (source time, not processing time)
 	Timestamp time.Time
 
-	// Seq is a global sequence number assigned by Supplier during distribution.
-	// Monotonically increasing. Used for drop detection and ordering verification.
+	// StreamID identifies the source stream (camera) of the frame.
+	// Set by the publisher; "" is the default stream (single-camera pipelines).
+	// Each stream has its own inbox and sequence counter; workers can
+	// subscribe to specific streams (WithStreams).
+	StreamID string
+
+	// Seq is a per-stream sequence number assigned by Supplier during distribution.
+	// Monotonically increasing within a stream. Used for drop detection and ordering verification.
 	// Set by distributeToWorkers(), not by publisher.
 	Seq uint64
+
+	// TraceParent is the W3C trace context of the frame ("00-<trace-id>-<span-id>-01").
+	// Set by the publisher (stream-capture Frame.TraceParent); "" = untraced.
+	// With a SpanRecorder, the supplier replaces it with its distribution span
+	// before fan-out, so worker spans (StartSpan) nest under distribution.
+	TraceParent string
+
+	publishedAt time.Time // Set by Publish (distribution latency and span start)
 }
@@ -0,0 +1,351 @@
+package internal
+
+import (
+	"time"
+)
+
+// SLAClass is a worker's service level (drives default thresholds and verdict severity).
+//
+// Classes (see ARCHITECTURE.md, Idle Detection):
+//   - SLANormal: default (idle after 30s, violations → Unhealthy)
+//   - SLACritical: life-critical, e.g. PersonDetector (idle after 10s, violations → Unhealthy)
+//   - SLABestEffort: low priority, e.g. VLM (idle after 2min, violations → Degraded only)
+type SLAClass int
+
+const (
+	SLANormal SLAClass = iota
+	SLACritical
+	SLABestEffort
+)
+
+// String returns the class name ("normal", "critical", "best-effort").
+func (c SLAClass) String() string {
+	switch c {
+	case SLACritical:
+		return "critical"
+	case SLABestEffort:
+		return "best-effort"
+	default:
+		return "normal"
+	}
+}
+
+// HealthStatus is a worker's health verdict.
+type HealthStatus int
+
+const (
+	Healthy   HealthStatus = iota // No threshold violated
+	Degraded                      // BestEffort worker violating a threshold (informational)
+	Unhealthy                     // Normal/Critical worker violating a threshold (supervisor action)
+)
+
+// String returns the status name ("healthy", "degraded", "unhealthy").
+func (h HealthStatus) String() string {
+	switch h {
+	case Degraded:
+		return "degraded"
+	case Unhealthy:
+		return "unhealthy"
+	default:
+		return "healthy"
+	}
+}
+
+// Violation identifies a violated health threshold.
+type Violation int
+
+const (
+	ViolationIdle             Violation = iota + 1 // No consume within IdleTimeout
+	ViolationConsecutiveDrops                      // ConsecutiveDrops > MaxConsecutiveDrops
+	ViolationDropRate                              // Drop rate over DropRateWindow > MaxDropRate
+)
+
+// String returns the violation name ("idle", "consecutive-drops", "drop-rate").
+func (v Violation) String() string {
+	switch v {
+	case ViolationIdle:
+		return "idle"
+	case ViolationConsecutiveDrops:
+		return "consecutive-drops"
+	case ViolationDropRate:
+		return "drop-rate"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthPolicy holds per-worker health thresholds.
+//
+// Zero fields take the SLA class default (IdleTimeout) or are disabled
+// (drop thresholds). Frames skipped by a sampling policy never count as drops.
+type HealthPolicy struct {
+	// IdleTimeout marks the worker idle without a consume for this long
+	// (0 = class default: Critical 10s, Normal 30s, BestEffort 2min).
+	IdleTimeout time.Duration
+
+	// MaxConsecutiveDrops is the longest tolerated drop streak (0 = no limit).
+	MaxConsecutiveDrops uint64
+
+	// MaxDropRate is the tolerated fraction of dropped frames (drops /
+	// (drops + consumed)) over DropRateWindow, e.g. 0.05 (0 = no limit).
+	MaxDropRate float64
+
+	// DropRateWindow is the sliding window for MaxDropRate (0 = 10s).
+	DropRateWindow time.Duration
+}
+
+// Health defaults.
+const (
+	// defaultHealthInterval is the evaluation period of healthLoop.
+	defaultHealthInterval = time.Second
+
+	// defaultDropRateWindow is the MaxDropRate window when unset.
+	defaultDropRateWindow = 10 * time.Second
+)
+
+// resolvePolicy fills zero fields of p with class defaults.
+func resolvePolicy(class SLAClass, p HealthPolicy) HealthPolicy {
+	if p.IdleTimeout <= 0 {
+		switch class {
+		case SLACritical:
+			p.IdleTimeout = 10 * time.Second
+		case SLABestEffort:
+			p.IdleTimeout = 2 * time.Minute
+		default:
+			p.IdleTimeout = idleThreshold
+		}
+	}
+	if p.DropRateWindow <= 0 {
+		p.DropRateWindow = defaultDropRateWindow
+	}
+	return p
+}
+
+// HealthEvent reports a violation starting or ending (delivered to the HealthHandler).
+type HealthEvent struct {
+	// WorkerID and Class identify the worker.
+	WorkerID string
+	Class    SLAClass
+
+	// Violation is the threshold crossed.
+	Violation Violation
+
+	// Resolved is false when the violation starts, true when it ends.
+	Resolved bool
+
+	// Status is the worker's verdict after this event.
+	Status HealthStatus
+
+	// Value and Threshold quantify the violation (seconds idle, drops, or
+	// drop rate 0..1, matching Violation).
+	Value     float64
+	Threshold float64
+
+	// At is the evaluation time.
+	At time.Time
+}
+
+// healthInput is the counter snapshot a worker is evaluated on.
+//
+// Replicas pass their own lastConsumedAt and the group's counters.
+type healthInput struct {
+	lastConsumedAt   time.Time
+	consumed         uint64
+	consecutiveDrops uint64
+	totalDrops       uint64
+}
+
+// dropSample is one evaluation's counters (drop rate window).
+type dropSample struct {
+	at       time.Time
+	drops    uint64
+	consumed uint64
+}
+
+// workerHealth is a subscription's SLA class, policy and last verdict.
+//
+// Thread-safety: protected by the owning slot's mu.
+type workerHealth struct {
+	class  SLAClass
+	policy HealthPolicy // Resolved (class defaults applied)
+
+	status     HealthStatus
+	violations []Violation  // Active violations (evaluation order)
+	samples    []dropSample // Samples within DropRateWindow (oldest first)
+}
+
+// newWorkerHealth resolves opts' SLA class and policy.
+func newWorkerHealth(opts SubscribeOptions) *workerHealth {
+	return &workerHealth{
+		class:  opts.SLA,
+		policy: resolvePolicy(opts.SLA, opts.Health),
+	}
+}
+
+// isIdle reports whether lastConsumedAt is older than the worker's IdleTimeout.
+func (h *workerHealth) isIdle(now, lastConsumedAt time.Time) bool {
+	return now.Sub(lastConsumedAt) > h.policy.IdleTimeout
+}
+
+// evaluate checks thresholds against in and returns transition events.
+//
+// Algorithm:
+//  1. Append counter sample, drop samples older than DropRateWindow
+//  2. Check idle, consecutive drops, drop rate (window delta)
+//  3. Emit an event per violation that started or resolved
+//  4. Verdict: any violation → Unhealthy (BestEffort: Degraded)
+//
+// Thread-safety: Caller holds the owning slot's mu.
+func (h *workerHealth) evaluate(workerID string, now time.Time, in healthInput) []HealthEvent {
+	p := h.policy
+
+	// Sliding window of counters (keep one sample at/before window start)
+	h.samples = append(h.samples, dropSample{at: now, drops: in.totalDrops, consumed: in.consumed})
+	for len(h.samples) > 1 && now.Sub(h.samples[1].at) >= p.DropRateWindow {
+		h.samples = h.samples[1:]
+	}
+
+	type check struct {
+		violation        Violation
+		value, threshold float64
+		violated         bool
+	}
+	checks := []check{{
+		violation: ViolationIdle,
+		value:     now.Sub(in.lastConsumedAt).Seconds(),
+		threshold: p.IdleTimeout.Seconds(),
+		violated:  h.isIdle(now, in.lastConsumedAt),
+	}}
+	if p.MaxConsecutiveDrops > 0 {
+		checks = append(checks, check{
+			violation: ViolationConsecutiveDrops,
+			value:     float64(in.consecutiveDrops),
+			threshold: float64(p.MaxConsecutiveDrops),
+			violated:  in.consecutiveDrops > p.MaxConsecutiveDrops,
+		})
+	}
+	if p.MaxDropRate > 0 {
+		oldest := h.samples[0]
+		drops := in.totalDrops - oldest.drops
+		delivered := drops + in.consumed - oldest.consumed
+		var rate float64
+		if delivered > 0 {
+			rate = float64(drops) / float64(delivered)
+		}
+		checks = append(checks, check{
+			violation: ViolationDropRate,
+			value:     rate,
+			threshold: p.MaxDropRate,
+			violated:  rate > p.MaxDropRate,
+		})
+	}
+
+	// Active violations and verdict
+	var violations []Violation
+	for _, c := range checks {
+		if c.violated {
+			violations = append(violations, c.violation)
+		}
+	}
+	status := Healthy
+	if len(violations) > 0 {
+		status = Unhealthy
+		if h.class == SLABestEffort {
+			status = Degraded
+		}
+	}
+
+	// Transitions (started or resolved)
+	var events []HealthEvent
+	for _, c := range checks {
+		if c.violated == containsViolation(h.violations, c.violation) {
+			continue // No change
+		}
+		events = append(events, HealthEvent{
+			WorkerID:  workerID,
+			Class:     h.class,
+			Violation: c.violation,
+			Resolved:  !c.violated,
+			Status:    status,
+			Value:     c.value,
+			Threshold: c.threshold,
+			At:        now,
+		})
+	}
+
+	h.violations = violations
+	h.status = status
+	return events
+}
+
+// containsViolation reports whether v is in vs.
+func containsViolation(vs []Violation, v Violation) bool {
+	for _, x := range vs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// healthLoop evaluates worker health every healthInterval until shutdown.
+//
+// Runs in its own goroutine (started by Start, stopped by Stop), so health
+// evaluation never delays distribution. The HealthHandler is called from
+// this goroutine, outside slot locks.
+func (s *supplier) healthLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.healthInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.evaluateHealth(now)
+		}
+	}
+}
+
+// evaluateHealth evaluates all workers once and delivers transition events.
+func (s *supplier) evaluateHealth(now time.Time) {
+	var events []HealthEvent
+
+	s.slots.Range(func(key, value interface{}) bool {
+		slot := value.(*WorkerSlot)
+		slot.mu.Lock()
+		events = append(events, slot.health.evaluate(key.(string), now, healthInput{
+			lastConsumedAt:   slot.lastConsumedAt,
+			consumed:         slot.consumed,
+			consecutiveDrops: slot.consecutiveDrops,
+			totalDrops:       slot.totalDrops,
+		})...)
+		slot.mu.Unlock()
+		return true
+	})
+
+	// Replicas: own idle state, group drop counters
+	s.groups.Range(func(key, value interface{}) bool {
+		slot := value.(*WorkerSlot)
+		slot.mu.Lock()
+		for workerID, r := range slot.replicas {
+			events = append(events, r.health.evaluate(workerID, now, healthInput{
+				lastConsumedAt:   r.lastConsumedAt,
+				consumed:         slot.consumed,
+				consecutiveDrops: slot.consecutiveDrops,
+				totalDrops:       slot.totalDrops,
+			})...)
+		}
+		slot.mu.Unlock()
+		return true
+	})
+
+	if s.healthHandler == nil {
+		return
+	}
+	for _, ev := range events {
+		s.healthHandler(ev)
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"math/bits"
+	"time"
+)
+
+// Histogram layout (HDR-style log-linear buckets, microsecond resolution).
+//
+// Values < 16µs get one bucket each; above, every power of two is split in
+// 16 linear sub-buckets → relative error ≤ 6.25% at any magnitude, fixed
+// memory (histBuckets counters), O(1) record.
+//
+// Range: 1µs .. 2^32µs (~71 min); larger values land in the last bucket.
+const (
+	histSubBits    = 4
+	histSubBuckets = 1 << histSubBits // 16 linear sub-buckets per power of two
+	histMaxExp     = 28               // Values up to 2^(histMaxExp+histSubBits) µs
+	histBuckets    = (histMaxExp + 2) * histSubBuckets
+)
+
+// histogram records durations for quantile estimation (p50/p95/p99).
+//
+// Thread-safety: NOT safe for concurrent use (protected by the owning slot's mu,
+// or supplier.distMu for the distribution latency).
+type histogram struct {
+	counts   [histBuckets]uint64
+	count    uint64
+	sum      time.Duration
+	min, max time.Duration
+}
+
+// histIndex maps a value in µs to its bucket.
+func histIndex(us uint64) int {
+	if us < histSubBuckets {
+		return int(us)
+	}
+	exp := bits.Len64(us) - histSubBits - 1 // Shift keeping the top histSubBits+1 bits
+	if exp > histMaxExp {
+		return histBuckets - 1
+	}
+	return (exp+1)*histSubBuckets + int(us>>uint(exp)&(histSubBuckets-1))
+}
+
+// histValue returns the midpoint (µs) of bucket i (inverse of histIndex).
+func histValue(i int) uint64 {
+	if i < histSubBuckets {
+		return uint64(i)
+	}
+	exp := uint(i/histSubBuckets - 1)
+	lower := uint64(histSubBuckets+i%histSubBuckets) << exp
+	return lower + (uint64(1)<<exp)/2
+}
+
+// record adds one observation (negative durations count as 0).
+func (h *histogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[histIndex(uint64(d/time.Microsecond))]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// quantile returns the estimated q-quantile (0 < q ≤ 1), clamped to [min, max].
+func (h *histogram) quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+	rank := uint64(q*float64(h.count) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			v := time.Duration(histValue(i)) * time.Microsecond
+			if v < h.min {
+				return h.min
+			}
+			if v > h.max {
+				return h.max
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+// snapshot summarizes the histogram (zero LatencyStats when empty).
+func (h *histogram) snapshot() LatencyStats {
+	if h == nil || h.count == 0 {
+		return LatencyStats{}
+	}
+	return LatencyStats{
+		Count: h.count,
+		Min:   h.min,
+		Mean:  h.sum / time.Duration(h.count),
+		P50:   h.quantile(0.50),
+		P95:   h.quantile(0.95),
+		P99:   h.quantile(0.99),
+		Max:   h.max,
+	}
+}
+
+// consumeLatency tracks a subscription's consume-time histograms.
+//
+// Allocated on first consume (idle subscriptions cost nothing).
+//
+// Thread-safety: protected by the owning slot's mu.
+type consumeLatency struct {
+	age         histogram // Frame.Timestamp → readFunc return
+	interval    histogram // Time between consecutive consumes
+	lastConsume time.Time // Previous consume (interval baseline)
+}
+
+// recordConsume records frame consumed at now.
+//
+// Frames without Timestamp are not counted in the age histogram; the first
+// consume only sets the interval baseline.
+func (l *consumeLatency) recordConsume(now time.Time, frame *Frame) {
+	if !frame.Timestamp.IsZero() {
+		l.age.record(now.Sub(frame.Timestamp))
+	}
+	if !l.lastConsume.IsZero() {
+		l.interval.record(now.Sub(l.lastConsume))
+	}
+	l.lastConsume = now
+}
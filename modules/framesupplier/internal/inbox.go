@@ -2,44 +2,84 @@ package internal
 
 import (
 	"sync/atomic"
+	"time"
 )
 
+// streamInbox is the per-stream inbox mailbox (one per Frame.StreamID).
+//
+// Architecture (ADR-001, ADR-004):
+//   - Single-slot buffer per stream (frame *Frame)
+//   - Overwrite policy per stream: a new frame replaces the unconsumed frame
+//     of the SAME stream only (a busy camera never drops another camera's frame)
+//   - Own sequence counter (Frame.Seq is per stream)
+//
+// Thread-safety:
+//   - frame: protected by supplier.inboxMu
+//   - seq, published, drops: atomic (Stats reads without inboxMu)
+type streamInbox struct {
+	id    string // Stream ID ("" = default stream)
+	frame *Frame // Single-slot buffer (nil = consumed, non-nil = unconsumed)
+
+	seq       uint64 // Atomic: last sequence number assigned (distribution)
+	published uint64 // Atomic: frames accepted by Publish
+	drops     uint64 // Atomic: frames overwritten before distribution
+}
+
 // Publish sends a frame to the distribution loop (implements Supplier.Publish).
 //
 // Algorithm (ADR-001, ADR-004):
 //  1. Lock inbox mutex
-//  2. Check if previous frame unconsumed (increment inboxDrops if so)
-//  3. Overwrite inboxFrame (JIT semantics: new replaces old)
-//  4. Signal inboxCond (wake distributionLoop if blocked)
-//  5. Unlock mutex
+//  2. Look up (or create) the inbox of frame.StreamID
+//  3. Check if previous frame of this stream unconsumed (increment drops if so)
+//     Otherwise queue the stream as pending (FIFO across streams)
+//  4. Overwrite the stream's inbox frame (JIT semantics: new replaces old)
+//  5. Signal inboxCond (wake distributionLoop if blocked)
+//  6. Unlock mutex
 //
 // Semantics:
 //   - Non-blocking: Always returns immediately (~1µs)
-//   - Overwrite policy: New frame replaces old (JIT principle)
-//   - Drop tracking: Increments inboxDrops if overwriting unconsumed frame
+//   - Overwrite policy: New frame replaces old of the same stream (JIT principle)
+//   - Drop tracking: Increments inboxDrops (total and per stream) if overwriting
+//   - Publish tracking: Increments published (total and per stream), stamps
+//     the frame's publish time (distribution latency)
 //
 // Thread-safety:
 //   - Safe for concurrent calls (mutex-protected)
-//   - Typically 1 publisher (stream-capture), but supports multiple
+//   - Typically 1 publisher per stream (stream-capture), but supports multiple
 //
 // Contract:
 //   - frame MUST NOT be nil (caller responsibility, no validation)
 //   - frame.Data MUST NOT be modified after Publish (immutability contract)
 //
-// Latency: O(1) - Lock + pointer check + assign + signal ≈ 1µs
+// Latency: O(1) - Lock + map lookup + pointer check + assign + signal ≈ 1µs
 //
 // See: ADR-001 (sync.Cond), ADR-004 (Symmetric JIT), ARCHITECTURE.md (Algorithm 1)
 func (s *supplier) Publish(frame *Frame) {
 	s.inboxMu.Lock()
 
+	// Distribution latency and span start at publish (includes inbox wait)
+	frame.publishedAt = time.Now()
+	atomic.AddUint64(&s.published, 1)
+
+	inbox := s.inboxes[frame.StreamID]
+	if inbox == nil {
+		inbox = &streamInbox{id: frame.StreamID}
+		s.inboxes[frame.StreamID] = inbox
+	}
+	atomic.AddUint64(&inbox.published, 1)
+
 	// Check if previous frame unconsumed (distribution loop slow)
-	if s.inboxFrame != nil {
-		// Increment drop counter atomically (Stats reads this without lock)
+	if inbox.frame != nil {
+		// Increment drop counters atomically (Stats reads these without lock)
 		atomic.AddUint64(&s.inboxDrops, 1)
+		atomic.AddUint64(&inbox.drops, 1)
+	} else {
+		// Stream becomes pending (served in arrival order)
+		s.pending = append(s.pending, inbox)
 	}
 
 	// Overwrite with new frame (JIT semantics)
-	s.inboxFrame = frame
+	inbox.frame = frame
 
 	// Wake distributionLoop if blocked in Wait()
 	s.inboxCond.Signal()
@@ -0,0 +1,102 @@
+package internal
+
+import "time"
+
+// replica is one worker of a replica group (per-replica stats).
+//
+// Thread-safety: all fields protected by the group slot's mu.
+type replica struct {
+	lastConsumedAt  time.Time       // Timestamp of last consume by this replica (idle detection)
+	lastConsumedSeq uint64          // Sequence number of last frame consumed by this replica
+	consumed        uint64          // Frames consumed by this replica
+	generation      uint64          // Subscription generation of the worker ID
+	health          *workerHealth   // Replica's SLA class, thresholds, last verdict
+	latency         *consumeLatency // Replica's frame age / consume interval (nil until first consume)
+	closeErr        error           // Non-nil after Unsubscribe/takeover (reason returned by reads)
+}
+
+// subscribeReplica joins workerID to replica group opts.Group (implements Subscribe with WithReplicaGroup).
+//
+// Architecture (frame-buffer tee, P002):
+//   - The group has ONE WorkerSlot (mailbox): distribution sees the group
+//     as a single subscriber, so each frame is delivered once per group
+//   - Replicas block on the shared slot.cond; the first idle replica takes
+//     the frame (work-stealing, no scheduler)
+//   - JIT drop-old semantics are unchanged: while all replicas are busy, a
+//     new frame overwrites the unconsumed one (group drop counters)
+//
+// Effective throughput: N replicas of a model with latency L consume up to
+// N/L frames per second (vs 1/L for a single worker).
+//
+// The first replica creates the group and defines its stream filter and
+// sampling policy (opts.Streams, MaxRate, ...); later replicas join with
+// the group's settings.
+//
+// Thread-safety: Caller holds registryMu (serializes join/leave).
+func (s *supplier) subscribeReplica(workerID string, opts SubscribeOptions, generation uint64) *Subscription {
+	var slot *WorkerSlot
+	if val, ok := s.groups.Load(opts.Group); ok {
+		slot = val.(*WorkerSlot)
+	} else {
+		slot = newWorkerSlot(opts)
+		slot.group = opts.Group
+		slot.replicas = make(map[string]*replica)
+		s.groups.Store(opts.Group, slot)
+	}
+
+	r := &replica{
+		lastConsumedAt: time.Now(), // Initialize (avoid IsIdle on first Stats call)
+		generation:     generation,
+		health:         newWorkerHealth(opts),
+	}
+	slot.mu.Lock()
+	slot.replicas[workerID] = r
+	slot.mu.Unlock()
+	s.replicaGroups.Store(workerID, slot)
+
+	return &Subscription{supplier: s, workerID: workerID, slot: slot, replica: r}
+}
+
+// removeReplicaLocked removes workerID from its replica group (no-op if not a replica).
+//
+// only restricts removal to that replica (Subscription.Close: no-op if the
+// ID now belongs to a newer subscription); nil removes whichever replica is
+// registered (Unsubscribe, takeover). reason is returned by its reads.
+//
+// Behavior:
+//  1. Mark replica closed, wake blocked replicas (only this one exits)
+//  2. Last replica out: close and remove the group slot (pending frames dropped)
+//
+// Idempotent: Safe to call multiple times.
+//
+// Thread-safety: Caller holds registryMu.
+func (s *supplier) removeReplicaLocked(workerID string, only *replica, reason error) {
+	val, ok := s.replicaGroups.Load(workerID)
+	if !ok {
+		return // Not subscribed (idempotent)
+	}
+	slot := val.(*WorkerSlot)
+
+	slot.mu.Lock()
+	if only != nil && slot.replicas[workerID] != only {
+		slot.mu.Unlock()
+		return // Replaced by a newer subscription (or already removed)
+	}
+	if r, ok := slot.replicas[workerID]; ok {
+		r.closeErr = reason
+		delete(slot.replicas, workerID)
+	}
+	empty := len(slot.replicas) == 0
+	if empty {
+		slot.closed = true
+		slot.closeErr = reason
+		slot.frames = nil
+	}
+	slot.cond.Broadcast() // Wake this replica (others re-check and keep waiting)
+	slot.mu.Unlock()
+
+	s.replicaGroups.Delete(workerID)
+	if empty {
+		s.groups.Delete(slot.group)
+	}
+}
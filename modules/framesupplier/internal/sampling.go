@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// sampler applies a subscription's sampling policy (rate limit, decimation).
+//
+// Policies (all configured policies must admit a frame):
+//   - everyNth: frames with Seq 1, N+1, 2N+1, ... (per stream, same frames for all workers)
+//   - interval: first frame of each wall-clock aligned window (e.g. :00, :10, :20 for 10s)
+//   - minGap:   at most one frame per 1/MaxRate, long-run rate ≤ MaxRate (no drift)
+//
+// State is kept per stream: a multi-stream worker with MaxRate 0.1 receives
+// one frame per stream every 10 seconds.
+//
+// Frames rejected by the policy never reach the worker slot: they count as
+// skipped (intentional), not as drops (worker too slow).
+//
+// Thread-safety:
+//   - admit: called by distributeToWorkers only (distributionLoop, single goroutine)
+//   - skipped: atomic (read by Stats)
+type sampler struct {
+	everyNth uint64        // Deliver every Nth frame (0 = all)
+	interval time.Duration // Aligned sampling window (0 = disabled)
+	minGap   time.Duration // 1 / MaxRate (0 = unlimited)
+
+	streams map[string]*sampleState // streamID → state (distributionLoop only)
+	skipped uint64                  // Atomic: frames skipped by policy
+}
+
+// sampleState tracks one stream's sampling progress.
+type sampleState struct {
+	window  int64     // Last delivered window index (interval policy)
+	hasLast bool      // True after first delivery
+	next    time.Time // Earliest time for next delivery (rate policy)
+}
+
+// newSampler returns the sampler for opts (nil = deliver every frame).
+//
+// Non-positive values disable a policy (MaxRate ≤ 0, EveryNth ≤ 1, SampleInterval ≤ 0).
+func newSampler(opts SubscribeOptions) *sampler {
+	smp := &sampler{streams: make(map[string]*sampleState)}
+	if opts.EveryNth > 1 {
+		smp.everyNth = uint64(opts.EveryNth)
+	}
+	if opts.SampleInterval > 0 {
+		smp.interval = opts.SampleInterval
+	}
+	if opts.MaxRate > 0 {
+		smp.minGap = time.Duration(float64(time.Second) / opts.MaxRate)
+	}
+
+	if smp.everyNth == 0 && smp.interval == 0 && smp.minGap == 0 {
+		return nil // No policy: fast path in distributeToWorkers
+	}
+	return smp
+}
+
+// admit reports whether frame passes the sampling policy (counts skipped frames).
+//
+// Frame time is frame.Timestamp (capture time), or now if unset.
+func (smp *sampler) admit(frame *Frame) bool {
+	if smp == nil {
+		return true
+	}
+
+	if smp.everyNth > 0 && (frame.Seq-1)%smp.everyNth != 0 {
+		atomic.AddUint64(&smp.skipped, 1)
+		return false
+	}
+
+	ts := frame.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	st, ok := smp.streams[frame.StreamID]
+	if !ok {
+		st = &sampleState{}
+		smp.streams[frame.StreamID] = st
+	}
+
+	// Check all time policies before updating state (no partial commits)
+	var window int64
+	if smp.interval > 0 {
+		window = ts.UnixNano() / int64(smp.interval)
+		if st.hasLast && window == st.window {
+			atomic.AddUint64(&smp.skipped, 1)
+			return false
+		}
+	}
+	if smp.minGap > 0 && st.hasLast && ts.Before(st.next) {
+		atomic.AddUint64(&smp.skipped, 1)
+		return false
+	}
+
+	// Deliver: advance schedule (fixed cadence, reset after gaps to avoid bursts)
+	st.window = window
+	if smp.minGap > 0 {
+		st.next = st.next.Add(smp.minGap)
+		if !st.hasLast || !st.next.After(ts) {
+			st.next = ts.Add(smp.minGap)
+		}
+	}
+	st.hasLast = true
+	return true
+}
+
+// skippedCount returns frames skipped by policy (0 for nil sampler).
+func (smp *sampler) skippedCount() uint64 {
+	if smp == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&smp.skipped)
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Span names recorded by the supplier.
+const (
+	// DistributeSpanName covers a frame from Publish until fan-out to all slots.
+	DistributeSpanName = "framesupplier.distribute"
+)
+
+// SpanRecorder receives finished spans (implements the exporter bridge).
+//
+// Contract:
+//   - RecordSpan MUST NOT block (called on the distribution hot path)
+//   - RecordSpan is called concurrently (distribution + batch + worker goroutines)
+//
+// FrameSupplier stays dependency-free: spans are plain data carrying W3C
+// traceparent strings. Adapters convert them for an OpenTelemetry exporter
+// (e.g. stream-capture/trace.Tracer).
+type SpanRecorder interface {
+	RecordSpan(span Span)
+}
+
+// Span is one traced operation on a frame (distribution or worker processing).
+//
+// TraceParent identifies the span itself, Parent the span it descends from
+// (both W3C traceparent strings, "00-<trace-id>-<span-id>-<flags>").
+//
+// Thread-safety: NOT safe for concurrent use. A span belongs to the goroutine
+// that started it (one worker processing one frame).
+type Span struct {
+	// Name of the operation (e.g. "framesupplier.distribute", "worker.PersonDetector")
+	Name string
+
+	// TraceParent of this span (child of Parent, same trace ID).
+	TraceParent string
+
+	// Parent traceparent ("" = root span, frame was published without trace context).
+	Parent string
+
+	// StartTime and EndTime delimit the operation (EndTime zero until End).
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Attributes describe the operation (frame seq, worker ID, ...).
+	Attributes map[string]string
+
+	recorder SpanRecorder // nil = not recorded (TraceParent still valid)
+	ended    bool
+}
+
+// newSpan starts a span as child of parent (new trace if parent is invalid).
+func newSpan(name, parent string, recorder SpanRecorder) *Span {
+	if _, ok := parseTraceparent(parent); !ok {
+		parent = ""
+	}
+	return &Span{
+		Name:        name,
+		TraceParent: childTraceparent(parent),
+		Parent:      parent,
+		StartTime:   time.Now(),
+		recorder:    recorder,
+	}
+}
+
+// SetAttribute adds or replaces an attribute.
+func (s *Span) SetAttribute(key, value string) {
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+	s.Attributes[key] = value
+}
+
+// End finishes the span and hands it to the SpanRecorder.
+//
+// Idempotent: only the first call records the span.
+func (s *Span) End() {
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	if s.recorder != nil {
+		s.recorder.RecordSpan(*s)
+	}
+}
+
+// childTraceparent returns a traceparent with parent's trace ID and a new span ID.
+//
+// Empty parent starts a new trace (new random trace ID, sampled).
+func childTraceparent(parent string) string {
+	var spanID [8]byte
+	rand.Read(spanID[:])
+
+	if ids, ok := parseTraceparent(parent); ok {
+		return "00-" + ids[1] + "-" + hex.EncodeToString(spanID[:]) + "-" + ids[3]
+	}
+
+	var traceID [16]byte
+	rand.Read(traceID[:])
+	return "00-" + hex.EncodeToString(traceID[:]) + "-" + hex.EncodeToString(spanID[:]) + "-01"
+}
+
+// parseTraceparent splits a W3C traceparent (version 00) into its 4 fields.
+//
+// Returns ok=false for malformed values (wrong lengths, non-hex, all-zero IDs).
+func parseTraceparent(tp string) ([]string, bool) {
+	if len(tp) != 55 {
+		return nil, false
+	}
+	parts := strings.Split(tp, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return nil, false
+	}
+	for _, p := range parts[1:] {
+		if _, err := hex.DecodeString(p); err != nil {
+			return nil, false
+		}
+	}
+	if strings.Trim(parts[1], "0") == "" || strings.Trim(parts[2], "0") == "" {
+		return nil, false
+	}
+	return parts, true
+}
+
+// StartSpan starts a span for processing frame (implements Supplier.StartSpan).
+//
+// The span is a child of frame.TraceParent (the distribution span when
+// tracing is enabled). Without a SpanRecorder the span is not recorded, but
+// its TraceParent is still valid for propagation into results.
+func (s *supplier) StartSpan(name string, frame *Frame) *Span {
+	var parent string
+	if frame != nil {
+		parent = frame.TraceParent
+	}
+
+	span := newSpan(name, parent, s.spans)
+	if frame != nil {
+		span.SetAttribute("frame.seq", strconv.FormatUint(frame.Seq, 10))
+	}
+	return span
+}
+
+// startDistributeSpan starts the distribution span and re-parents frame under it.
+//
+// Returns nil when tracing is disabled (frame.TraceParent left unchanged).
+// Called by distributionLoop only, before fan-out (frame not yet shared).
+func (s *supplier) startDistributeSpan(frame *Frame) *Span {
+	if s.spans == nil {
+		return nil
+	}
+
+	span := newSpan(DistributeSpanName, frame.TraceParent, s.spans)
+	if !frame.publishedAt.IsZero() {
+		span.StartTime = frame.publishedAt
+	}
+	frame.TraceParent = span.TraceParent
+	return span
+}
+
+// endDistributeSpan records the distribution span once fan-out is done.
+func (s *supplier) endDistributeSpan(span *Span, frame *Frame) {
+	if span == nil {
+		return
+	}
+	span.SetAttribute("frame.seq", strconv.FormatUint(frame.Seq, 10))
+	span.SetAttribute("stream.id", frame.StreamID)
+	span.End()
+}
@@ -1,11 +1,12 @@
 package internal
 
 import (
+	"sort"
 	"sync/atomic"
 	"time"
 )
 
-// idleThreshold defines when a worker is considered idle (no consume activity).
+// idleThreshold defines when a SLANormal worker is considered idle (no consume activity).
 //
 // Rationale:
 //   - Inference @ 1fps: Expected consume every 1 second
@@ -16,14 +17,20 @@ import (
 //   - Critical workers (PersonDetector): IsIdle → restart required
 //   - BestEffort workers (VLM): IsIdle → acceptable (low priority)
 //
+// Other SLA classes and HealthPolicy.IdleTimeout override it per worker
+// (see resolvePolicy in health.go).
+//
 // See: ARCHITECTURE.md (Idle Detection)
 const idleThreshold = 30 * time.Second
 
 // Stats returns operational statistics snapshot (implements Supplier.Stats).
 //
 // Returns:
-//   - InboxDrops: Atomic read (safe without lock)
+//   - Published, InboxDrops: Atomic reads (safe without lock)
+//   - DistributionLatency: Publish → fan-out histogram (distMu)
+//   - Streams: Map of streamID → StreamStats (snapshot at call time)
 //   - Workers: Map of workerID → WorkerStats (snapshot at call time)
+//   - Groups: Map of group name → GroupStats (replicas also listed in Workers)
 //
 // Semantics:
 //   - Non-blocking: Returns immediately (snapshot, not live view)
@@ -33,17 +40,20 @@ const idleThreshold = 30 * time.Second
 // Use cases:
 //   - Monitor inbox drops (should be ~0 in healthy system)
 //   - Detect idle workers (health checks, restart policies)
+//   - Health verdicts per worker (WorkerStats.Health, SLA class thresholds)
 //   - SLA compliance (drop rate thresholds)
 //
 // Thread-safety: All reads are lock-protected or atomic.
 //
 // See: ARCHITECTURE.md (Operational Monitoring)
 func (s *supplier) Stats() SupplierStats {
-	// Read inbox drops (atomic, no lock needed)
+	// Read inbox counters (atomic, no lock needed)
 	inboxDrops := atomic.LoadUint64(&s.inboxDrops)
+	published := atomic.LoadUint64(&s.published)
 
 	// Collect per-worker stats
 	workers := make(map[string]WorkerStats)
+	var slots []*WorkerSlot
 
 	s.slots.Range(func(key, value interface{}) bool {
 		workerID := key.(string)
@@ -51,28 +61,79 @@ func (s *supplier) Stats() SupplierStats {
 
 		// Lock slot to read stats fields (consistent snapshot)
 		slot.mu.Lock()
+		stat := slot.workerStats(workerID, nil)
+		slot.mu.Unlock()
+
+		workers[workerID] = stat
+		slots = append(slots, slot)
+		return true // Continue iteration
+	})
 
-		// Calculate idle status
-		isIdle := time.Since(slot.lastConsumedAt) > idleThreshold
+	// Collect per-group stats (replicas listed individually in workers)
+	groups := make(map[string]GroupStats)
+	s.groups.Range(func(key, value interface{}) bool {
+		slot := value.(*WorkerSlot)
 
-		// Build WorkerStats
-		stat := WorkerStats{
-			WorkerID:         workerID,
-			LastConsumedAt:   slot.lastConsumedAt,
-			LastConsumedSeq:  slot.lastConsumedSeq,
+		slot.mu.Lock()
+		group := GroupStats{
+			Group:            slot.group,
+			Streams:          slot.streamIDs(),
+			Consumed:         slot.consumed,
+			Skipped:          slot.sampler.skippedCount(),
 			ConsecutiveDrops: slot.consecutiveDrops,
 			TotalDrops:       slot.totalDrops,
-			IsIdle:           isIdle,
 		}
-
+		for workerID, r := range slot.replicas {
+			group.Replicas = append(group.Replicas, workerID)
+			workers[workerID] = slot.workerStats(workerID, r)
+			slots = append(slots, slot) // Counted once per replica (stream Workers)
+		}
 		slot.mu.Unlock()
 
-		workers[workerID] = stat
-		return true // Continue iteration
+		sort.Strings(group.Replicas)
+		groups[group.Group] = group
+		return true
 	})
 
+	// Collect per-stream stats (inbox registry under inboxMu, counters atomic)
+	s.inboxMu.Lock()
+	streams := make(map[string]StreamStats, len(s.inboxes))
+	for id, inbox := range s.inboxes {
+		stat := StreamStats{
+			StreamID:   id,
+			Published:  atomic.LoadUint64(&inbox.published),
+			InboxDrops: atomic.LoadUint64(&inbox.drops),
+			LastSeq:    atomic.LoadUint64(&inbox.seq),
+		}
+		for _, slot := range slots {
+			if slot.accepts(id) {
+				stat.Workers++
+			}
+		}
+		streams[id] = stat
+	}
+	s.inboxMu.Unlock()
+
+	s.distMu.Lock()
+	distLatency := s.distLatency.snapshot()
+	s.distMu.Unlock()
+
 	return SupplierStats{
-		InboxDrops: inboxDrops,
-		Workers:    workers,
+		Published:           published,
+		InboxDrops:          inboxDrops,
+		DistributionLatency: distLatency,
+		Streams:             streams,
+		Workers:             workers,
+		Groups:              groups,
 	}
 }
+
+// recordDistribution records frame's distribution latency (publish → fan-out done).
+//
+// Called by distributionLoop only, after distributeToWorkers.
+func (s *supplier) recordDistribution(frame *Frame) {
+	d := time.Since(frame.publishedAt)
+	s.distMu.Lock()
+	s.distLatency.record(d)
+	s.distMu.Unlock()
+}
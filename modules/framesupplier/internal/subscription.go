@@ -0,0 +1,285 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by OpenSubscription and Subscription reads.
+var (
+	// ErrSubscriptionClosed is returned after Close, Unsubscribe or Stop
+	// (and by OpenSubscription during/after Stop).
+	ErrSubscriptionClosed = errors.New("framesupplier: subscription closed")
+
+	// ErrSubscriptionReplaced is returned after another subscription took
+	// over the worker ID (WithTakeover). Wraps ErrSubscriptionClosed.
+	ErrSubscriptionReplaced = fmt.Errorf("%w: replaced by takeover", ErrSubscriptionClosed)
+
+	// ErrWorkerExists is returned by OpenSubscription when the worker ID
+	// is already subscribed (without WithTakeover).
+	ErrWorkerExists = errors.New("framesupplier: worker ID already subscribed")
+
+	// ErrNoFrame is returned by TryRead when no frame is pending.
+	ErrNoFrame = errors.New("framesupplier: no frame available")
+)
+
+// Subscription is a worker's handle on its mailbox (plain slot or replica group).
+//
+// Unlike the bare readFunc returned by Subscribe, reads can be bounded by a
+// context or timeout, polled, and the handle reports its own stats.
+//
+// Implementation:
+//   - Read waits on slot.cond (same as readFunc, no extra goroutine per read)
+//   - ctx cancellation wakes the waiter via context.AfterFunc (Broadcast),
+//     unregistered when Read returns → no goroutine or timer leaks
+//
+// Thread-safety:
+//   - Read/TryRead/ReadTimeout: single worker goroutine (single consumer, like readFunc)
+//   - Close/Stats: safe from any goroutine
+type Subscription struct {
+	supplier *supplier
+	workerID string
+	slot     *WorkerSlot
+	replica  *replica // nil = plain worker
+}
+
+// OpenSubscription registers a worker and returns its Subscription (implements Supplier.OpenSubscription).
+//
+// Same routing and semantics as Subscribe.
+//
+// Worker IDs are unique (plain workers and replicas share one namespace):
+//   - Duplicate ID: rejected with ErrWorkerExists (the existing
+//     subscription is untouched)
+//   - Duplicate ID + opts.Takeover: the previous subscription is closed
+//     (its reader wakes with ErrSubscriptionReplaced) and replaced
+//
+// Every accepted subscription gets the next generation of its worker ID
+// (1 = first, survives Unsubscribe), so supervisors can tell a restarted
+// worker from the original (WorkerStats.Generation).
+//
+// During/after Stop: rejected with ErrSubscriptionClosed.
+//
+// Thread-safety: Safe for concurrent calls (registryMu serializes registration).
+func (s *supplier) OpenSubscription(workerID string, opts ...SubscribeOption) (*Subscription, error) {
+	return s.open(workerID, opts, false)
+}
+
+// open registers workerID (shared by OpenSubscription and Subscribe).
+//
+// owesUnsubscribe marks a readFunc subscription: its caller calls
+// Unsubscribe(workerID) even when rejected, so a rejected duplicate records
+// that call in s.rejected and Unsubscribe absorbs it (the live worker with
+// the same ID stays subscribed).
+func (s *supplier) open(workerID string, opts []SubscribeOption, owesUnsubscribe bool) (*Subscription, error) {
+	// Check if supplier is stopping (fail-fast)
+	if s.stopping.Load() {
+		return nil, ErrSubscriptionClosed
+	}
+
+	var o SubscribeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	// Duplicate check: reject, or evict previous subscription on takeover
+	if s.subscribedLocked(workerID) {
+		if !o.Takeover {
+			if owesUnsubscribe {
+				s.rejected[workerID]++
+			}
+			return nil, ErrWorkerExists
+		}
+		s.evictLocked(workerID, ErrSubscriptionReplaced)
+	}
+
+	s.generations[workerID]++
+	generation := s.generations[workerID]
+
+	// Replicas share a group mailbox (implemented in replica_group.go)
+	if o.Group != "" {
+		return s.subscribeReplica(workerID, o, generation), nil
+	}
+
+	// Create new slot for this worker and register it in slots map
+	slot := newWorkerSlot(o)
+	slot.generation = generation
+	s.slots.Store(workerID, slot)
+
+	return &Subscription{supplier: s, workerID: workerID, slot: slot}, nil
+}
+
+// subscribedLocked reports whether workerID has a live subscription (caller holds registryMu).
+func (s *supplier) subscribedLocked(workerID string) bool {
+	if _, ok := s.slots.Load(workerID); ok {
+		return true
+	}
+	_, ok := s.replicaGroups.Load(workerID)
+	return ok
+}
+
+// evictLocked closes and unregisters workerID's subscription (caller holds registryMu).
+//
+// reason is returned by the evicted subscription's reads. No-op if not subscribed.
+func (s *supplier) evictLocked(workerID string, reason error) {
+	if val, ok := s.slots.LoadAndDelete(workerID); ok {
+		val.(*WorkerSlot).close(reason)
+		return
+	}
+	s.removeReplicaLocked(workerID, nil, reason)
+}
+
+// Generation returns the subscription generation of the worker ID (1 = first).
+func (sub *Subscription) Generation() uint64 {
+	if sub.replica != nil {
+		return sub.replica.generation
+	}
+	return sub.slot.generation
+}
+
+// WorkerID returns the subscribed worker ID.
+func (sub *Subscription) WorkerID() string {
+	return sub.workerID
+}
+
+// Read blocks until a frame is available, ctx is done, or the subscription closes.
+//
+// Returns:
+//   - (frame, nil): frame of the oldest pending stream
+//   - (nil, ErrSubscriptionClosed): Close, Unsubscribe or Stop
+//   - (nil, ErrSubscriptionReplaced): worker ID taken over (WithTakeover)
+//   - (nil, ctx.Err()): ctx canceled or deadline exceeded
+//
+// A closed subscription wins over a pending frame (same as readFunc).
+func (sub *Subscription) Read(ctx context.Context) (*Frame, error) {
+	slot := sub.slot
+
+	// Wake the waiter on cancellation (no-op for contexts that never end)
+	if ctx.Done() != nil {
+		stop := context.AfterFunc(ctx, func() {
+			slot.mu.Lock()
+			slot.cond.Broadcast() // Waiters re-check (other replicas keep waiting)
+			slot.mu.Unlock()
+		})
+		defer stop()
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+
+	for {
+		if err := sub.closedLocked(); err != nil {
+			return nil, err
+		}
+		if len(slot.frames) > 0 {
+			return sub.takeLocked(), nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		slot.cond.Wait() // Blocks here, releases lock
+	}
+}
+
+// ReadTimeout is Read bounded by d (returns context.DeadlineExceeded on timeout).
+func (sub *Subscription) ReadTimeout(d time.Duration) (*Frame, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	return sub.Read(ctx)
+}
+
+// TryRead returns a pending frame without blocking.
+//
+// Returns ErrNoFrame if the mailbox is empty, the close reason (see Read) if closed.
+func (sub *Subscription) TryRead() (*Frame, error) {
+	sub.slot.mu.Lock()
+	defer sub.slot.mu.Unlock()
+
+	if err := sub.closedLocked(); err != nil {
+		return nil, err
+	}
+	if len(sub.slot.frames) == 0 {
+		return nil, ErrNoFrame
+	}
+	return sub.takeLocked(), nil
+}
+
+// Close unsubscribes the worker (pending and future reads return ErrSubscriptionClosed).
+//
+// Unlike Unsubscribe(workerID), Close only affects this subscription: if the
+// worker ID was subscribed again since, the newer subscription is left alone.
+//
+// Idempotent: Safe to call multiple times.
+func (sub *Subscription) Close() {
+	s := sub.supplier
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	if sub.replica != nil {
+		s.removeReplicaLocked(sub.workerID, sub.replica, ErrSubscriptionClosed)
+
+		// Own reads end even if no longer registered
+		sub.slot.mu.Lock()
+		if sub.replica.closeErr == nil {
+			sub.replica.closeErr = ErrSubscriptionClosed
+		}
+		sub.slot.cond.Broadcast()
+		sub.slot.mu.Unlock()
+		return
+	}
+
+	sub.slot.close(ErrSubscriptionClosed)
+	s.slots.CompareAndDelete(sub.workerID, sub.slot)
+}
+
+// Stats returns this subscription's WorkerStats snapshot.
+//
+// Same values as Supplier.Stats().Workers[workerID] while subscribed.
+func (sub *Subscription) Stats() WorkerStats {
+	sub.slot.mu.Lock()
+	defer sub.slot.mu.Unlock()
+	return sub.slot.workerStats(sub.workerID, sub.replica)
+}
+
+// closedLocked returns the close reason if reads must stop (caller holds slot.mu).
+func (sub *Subscription) closedLocked() error {
+	if sub.replica != nil && sub.replica.closeErr != nil {
+		return sub.replica.closeErr
+	}
+	if sub.slot.closed {
+		return sub.slot.closeErr
+	}
+	return nil
+}
+
+// takeLocked consumes the next frame (caller holds slot.mu, frames non-empty).
+func (sub *Subscription) takeLocked() *Frame {
+	slot := sub.slot
+	frame := slot.pop()
+
+	r := sub.replica
+	if r == nil {
+		if slot.latency == nil {
+			slot.latency = &consumeLatency{}
+		}
+		slot.latency.recordConsume(slot.lastConsumedAt, frame)
+		return frame
+	}
+
+	// Replica: own stats, hand remaining streams to another idle replica
+	r.lastConsumedAt = slot.lastConsumedAt
+	r.lastConsumedSeq = frame.Seq
+	r.consumed++
+	if r.latency == nil {
+		r.latency = &consumeLatency{}
+	}
+	r.latency.recordConsume(slot.lastConsumedAt, frame)
+	if len(slot.frames) > 0 {
+		slot.cond.Signal()
+	}
+	return frame
+}
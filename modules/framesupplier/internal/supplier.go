@@ -9,13 +9,15 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
 // supplier is the concrete implementation of framesupplier.Supplier interface.
 //
 // Goroutine topology:
-//   - 1 fixed: distributionLoop (spawned by Start, stopped by Stop)
+//   - 2 fixed: distributionLoop, healthLoop (spawned by Start, stopped by Stop)
 //   - 0-N/8 transient: batch goroutines (spawned by distributeToWorkers if >8 workers)
+//   - 0-N watch goroutines: one per Watch call (ended by its ctx or Stop)
 //   - N external: worker goroutines (NOT managed by supplier, workers own them)
 //
 // Thread-safety: All public methods safe for concurrent use.
@@ -23,35 +25,80 @@ type supplier struct {
 	// --- Inbox Mailbox (ADR-001, ADR-004) ---
 	// Publisher → Supplier communication
 
-	inboxMu    sync.Mutex // Protects inboxFrame
-	inboxCond  *sync.Cond // Signals distributionLoop
-	inboxFrame *Frame     // Single-slot buffer (nil = consumed, non-nil = unconsumed)
-	inboxDrops uint64     // Atomic counter (incremented when overwriting unconsumed frame)
+	inboxMu    sync.Mutex              // Protects inboxes, pending and inbox frames
+	inboxCond  *sync.Cond              // Signals distributionLoop
+	inboxes    map[string]*streamInbox // Per-stream inbox (keyed by Frame.StreamID)
+	pending    []*streamInbox          // Streams with an unconsumed frame (FIFO, no duplicates)
+	inboxDrops uint64                  // Atomic counter (incremented when overwriting unconsumed frame)
+	published  uint64                  // Atomic counter (frames accepted by Publish, all streams)
 
 	// --- Worker Slots (ADR-001) ---
 	// Supplier → Workers communication
 
 	slots sync.Map // Concurrent map: workerID (string) → *WorkerSlot
 
-	// --- Distribution State ---
+	registryMu  sync.Mutex        // Serializes worker registration (duplicate check, takeover, replica join/leave)
+	generations map[string]uint64 // workerID → subscriptions so far (protected by registryMu, survives Unsubscribe)
+	rejected    map[string]int    // workerID → Unsubscribe calls owed by rejected Subscribe duplicates (registryMu)
 
-	publishSeq uint64 // Atomic counter: global frame sequence (assigned during distribution)
+	// --- Replica Groups (replica_group.go) ---
+
+	groups        sync.Map // Concurrent map: group name (string) → *WorkerSlot (shared mailbox)
+	replicaGroups sync.Map // Concurrent map: replica workerID (string) → *WorkerSlot (its group)
 
 	// --- Lifecycle ---
 
 	ctx    context.Context    // Lifecycle context (cancelled on Stop)
 	cancel context.CancelFunc // Cancel function for ctx
-	wg     sync.WaitGroup     // Tracks distributionLoop goroutine
+	wg     sync.WaitGroup     // Tracks distributionLoop and healthLoop goroutines
 
 	startedMu sync.Mutex // Protects started flag
 	started   bool       // True after Start() called (idempotency guard)
 	stopping  atomic.Bool // True during/after Stop() (prevents Subscribe during shutdown)
+
+	// --- Tracing ---
+
+	spans SpanRecorder // nil = tracing disabled (no spans, TraceParent passed through)
+
+	// --- Health (health.go) ---
+
+	healthInterval time.Duration     // healthLoop evaluation period
+	healthHandler  func(HealthEvent) // nil = verdicts in Stats only
+
+	// --- Monitoring (watch.go) ---
+
+	distMu      sync.Mutex // Protects distLatency
+	distLatency histogram  // Publish → fan-out done (recorded by distributionLoop)
+}
+
+// Options configures a supplier (filled by public Option functions in parent package).
+type Options struct {
+	// SpanRecorder receives distribution and worker spans (nil = no spans).
+	SpanRecorder SpanRecorder
+
+	// HealthInterval is the worker health evaluation period (0 = 1s).
+	HealthInterval time.Duration
+
+	// HealthHandler receives health violation events (nil = Stats only).
+	// Called from the health goroutine, never from distribution.
+	HealthHandler func(HealthEvent)
 }
 
 // NewSupplier creates a new supplier instance (called by public New() in parent package).
 // Exported to allow parent package to construct, but returns unexported *supplier type.
-func NewSupplier() *supplier {
-	s := &supplier{}
+func NewSupplier(opts Options) *supplier {
+	s := &supplier{
+		inboxes:     make(map[string]*streamInbox),
+		generations: make(map[string]uint64),
+		rejected:    make(map[string]int),
+		spans:       opts.SpanRecorder,
+
+		healthInterval: opts.HealthInterval,
+		healthHandler:  opts.HealthHandler,
+	}
+	if s.healthInterval <= 0 {
+		s.healthInterval = defaultHealthInterval
+	}
 	s.inboxCond = sync.NewCond(&s.inboxMu)
 	return s
 }
@@ -61,7 +108,7 @@ func NewSupplier() *supplier {
 // Lifecycle:
 //  1. Validates not already started (idempotency)
 //  2. Sets ctx from caller
-//  3. Spawns distributionLoop goroutine
+//  3. Spawns distributionLoop and healthLoop goroutines
 //  4. Returns immediately (non-blocking)
 //
 // The distributionLoop runs until:
@@ -80,9 +127,10 @@ func (s *supplier) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	s.started = true
 
-	// Spawn distribution goroutine
-	s.wg.Add(1)
+	// Spawn distribution and health goroutines
+	s.wg.Add(2)
 	go s.distributionLoop()
+	go s.healthLoop()
 
 	return nil
 }
@@ -93,8 +141,8 @@ func (s *supplier) Start(ctx context.Context) error {
 //  1. Cancels ctx (signals distributionLoop to exit)
 //  2. Sets stopping flag (prevents new Subscribe calls)
 //  3. Signals inboxCond (wakes distributionLoop if blocked)
-//  4. Closes all worker slots (wakes blocked workers)
-//  5. Waits for distributionLoop to exit (wg.Wait)
+//  4. Closes all worker slots and replica group slots (wakes blocked workers)
+//  5. Waits for distributionLoop and healthLoop to exit (wg.Wait)
 //
 // After Stop():
 //   - Publish() becomes no-op (frames silently dropped)
@@ -123,15 +171,13 @@ func (s *supplier) Stop() error {
 	// Wake distributionLoop if blocked in inboxCond.Wait
 	s.inboxCond.Broadcast()
 
-	// Close all worker slots (wake blocked workers)
-	s.slots.Range(func(key, value interface{}) bool {
-		slot := value.(*WorkerSlot)
-		slot.mu.Lock()
-		slot.closed = true
-		slot.cond.Broadcast() // Wake worker if blocked in readFunc
-		slot.mu.Unlock()
+	// Close all worker slots and replica group slots (wake blocked workers)
+	closeSlot := func(key, value interface{}) bool {
+		value.(*WorkerSlot).close(ErrSubscriptionClosed) // Wake worker(s) if blocked in readFunc
 		return true
-	})
+	}
+	s.slots.Range(closeSlot)
+	s.groups.Range(closeSlot)
 
 	// Wait for distributionLoop to exit
 	s.wg.Wait()
@@ -142,12 +188,16 @@ func (s *supplier) Stop() error {
 // distributionLoop is the core goroutine that consumes inbox and distributes to workers.
 //
 // Algorithm (ADR-001, ADR-004):
-//  1. Wait for frame in inbox (sync.Cond.Wait - efficient blocking)
+//  1. Wait for a pending stream inbox (sync.Cond.Wait - efficient blocking)
 //  2. Check ctx.Done (graceful shutdown)
-//  3. Consume frame from inbox (mark as nil)
-//  4. Distribute to all worker slots (with batching if >8 workers)
+//  3. Consume frame from the oldest pending inbox (mark as nil)
+//  4. Distribute to the worker slots subscribed to that stream
+//     (with batching if >8 workers)
 //  5. Repeat
 //
+// Fairness: pending streams are served in arrival order, so one busy
+// stream cannot starve the others (each stream has at most 1 pending frame).
+//
 // Exits on: ctx.Done() or Stop() called.
 //
 // See: ADR-004 (Symmetric JIT Architecture), ARCHITECTURE.md (Algorithm 2)
@@ -158,7 +208,7 @@ func (s *supplier) distributionLoop() {
 		// Wait for frame or shutdown
 		s.inboxMu.Lock()
 
-		for s.inboxFrame == nil {
+		for len(s.pending) == 0 {
 			// Check shutdown before blocking
 			if s.ctx.Err() != nil {
 				s.inboxMu.Unlock()
@@ -175,12 +225,20 @@ func (s *supplier) distributionLoop() {
 			}
 		}
 
-		// Consume frame
-		frame := s.inboxFrame
-		s.inboxFrame = nil // Mark as consumed
+		// Consume frame of oldest pending stream
+		inbox := s.pending[0]
+		copy(s.pending, s.pending[1:])
+		s.pending = s.pending[:len(s.pending)-1]
+		frame := inbox.frame
+		inbox.frame = nil // Mark as consumed
 		s.inboxMu.Unlock()
 
 		// Distribute to workers (implemented in distribution.go)
-		s.distributeToWorkers(frame)
+		// Span and latency end when fan-out is done (may be after the loop moves on)
+		span := s.startDistributeSpan(frame)
+		s.distributeToWorkers(inbox, frame, func() {
+			s.endDistributeSpan(span, frame)
+			s.recordDistribution(frame)
+		})
 	}
 }
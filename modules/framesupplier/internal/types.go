@@ -4,14 +4,78 @@ import "time"
 
 // SupplierStats is a snapshot of supplier operational state.
 type SupplierStats struct {
+	// Published counts frames accepted by Publish (all streams).
+	Published uint64
+
 	// InboxDrops counts frames dropped at inbox (distribution loop slow).
 	// Should be ~0 in healthy system (distribution is 330× faster than 30fps source).
 	// Non-zero indicates: deadlock, CPU starvation, or design bug.
 	InboxDrops uint64
 
+	// DistributionLatency is the time from Publish to the end of fan-out
+	// (inbox wait + distribution to all subscribed slots), lifetime of the
+	// supplier. Should stay in the µs range; growth means the distribution
+	// loop is starved.
+	DistributionLatency LatencyStats
+
+	// Streams maps stream ID to per-stream statistics ("" = default stream).
+	// A stream appears on its first Publish.
+	Streams map[string]StreamStats
+
 	// Workers maps workerID to per-worker statistics.
 	// Updated on every Subscribe/Unsubscribe/Publish cycle.
+	// Replicas of a replica group appear individually (WorkerStats.ReplicaGroup set).
 	Workers map[string]WorkerStats
+
+	// Groups maps replica group name to per-group statistics.
+	// A group exists from its first replica's Subscribe until its last Unsubscribe.
+	Groups map[string]GroupStats
+}
+
+// GroupStats tracks per-replica-group state (shared mailbox).
+type GroupStats struct {
+	// Group is the replica group name (WithReplicaGroup).
+	Group string
+
+	// Replicas lists the subscribed replica worker IDs (sorted).
+	Replicas []string
+
+	// Streams lists the group's stream IDs (nil = all streams).
+	// Defined by the first replica's subscription.
+	Streams []string
+
+	// Consumed counts frames consumed by all replicas of the group.
+	Consumed uint64
+
+	// Skipped counts frames withheld by the group's sampling policy.
+	Skipped uint64
+
+	// ConsecutiveDrops is the current streak of frames no replica consumed in time.
+	// Resets to 0 on any replica consume.
+	ConsecutiveDrops uint64
+
+	// TotalDrops is the lifetime count of frames dropped by the group
+	// (all replicas busy when the next frame arrived).
+	TotalDrops uint64
+}
+
+// StreamStats tracks per-stream inbox state.
+type StreamStats struct {
+	// StreamID is the stream identifier (Frame.StreamID).
+	StreamID string
+
+	// Published counts frames accepted by Publish for this stream.
+	Published uint64
+
+	// InboxDrops counts frames of this stream overwritten before distribution.
+	InboxDrops uint64
+
+	// LastSeq is the last sequence number assigned to this stream (per-stream counter).
+	LastSeq uint64
+
+	// Workers is the number of workers currently subscribed to this stream
+	// (including workers subscribed to all streams, each replica counted once).
+	Workers int
 }
 
 // WorkerStats tracks per-worker operational state.
@@ -19,15 +83,36 @@ type WorkerStats struct {
 	// WorkerID is the unique identifier for this worker.
 	WorkerID string
 
+	// Generation counts subscriptions of this worker ID (1 = first).
+	// Incremented on every accepted Subscribe (takeover or re-subscribe
+	// after Unsubscribe): a change means the worker was restarted.
+	Generation uint64
+
 	// LastConsumedAt is the timestamp of last successful consume (readFunc return).
 	// Used for idle detection (IsIdle = time.Since > 30s).
 	LastConsumedAt time.Time
 
 	// LastConsumedSeq is the sequence number of last consumed frame.
-	// Monotonically increasing (same as Frame.Seq).
+	// Monotonically increasing for single-stream workers (same as Frame.Seq).
 	// Used for drop rate calculation: TotalDrops / LastConsumedSeq.
+	// Multi-stream workers: sequence of the last consumed frame's stream.
 	LastConsumedSeq uint64
 
+	// Streams lists the subscribed stream IDs (nil = all streams).
+	Streams []string
+
+	// Consumed counts frames returned by this worker's readFunc.
+	Consumed uint64
+
+	// Skipped counts frames withheld by the sampling policy (MaxRate,
+	// EveryNth, SampleInterval). Intentional: NOT counted in TotalDrops.
+	Skipped uint64
+
+	// ReplicaGroup is the worker's replica group ("" = plain worker).
+	// Replicas share the group mailbox: ConsecutiveDrops and TotalDrops
+	// are the group's counters (see SupplierStats.Groups).
+	ReplicaGroup string
+
 	// ConsecutiveDrops is the current streak of unconsumed frames.
 	// Resets to 0 on successful consume.
 	// Use case: detect sudden worker slowdown (was healthy, now struggling).
@@ -37,8 +122,79 @@ type WorkerStats struct {
 	// Use case: SLA compliance, trend analysis.
 	TotalDrops uint64
 
-	// IsIdle indicates worker hasn't consumed frame in >30s.
-	// Calculated: time.Since(LastConsumedAt) > 30s.
+	// IsIdle indicates worker hasn't consumed frame within its IdleTimeout
+	// (SLA class default: Critical 10s, Normal 30s, BestEffort 2min).
+	// Calculated: time.Since(LastConsumedAt) > IdleTimeout.
 	// Use case: health checks, restart policies (critical workers).
 	IsIdle bool
+
+	// SLA is the worker's service level (WithSLA).
+	SLA SLAClass
+
+	// Health is the verdict of the last health evaluation (every
+	// HealthCheckInterval while started; Healthy before the first one).
+	Health HealthStatus
+
+	// Violations lists the thresholds violated at the last evaluation.
+	Violations []Violation
+
+	// FrameAge is the age of consumed frames (Frame.Timestamp → readFunc
+	// return), lifetime of the subscription. Frames without Timestamp are
+	// not counted. Use case: prove "latency over completeness" (p99 stays
+	// near one inference time, no queueing).
+	FrameAge LatencyStats
+
+	// ConsumeInterval is the time between consecutive consumes (effective
+	// worker period, 1 / processing rate).
+	ConsumeInterval LatencyStats
+}
+
+// LatencyStats summarizes a duration histogram (HDR-style, ≤6.25% error).
+//
+// Zero value: no observations.
+type LatencyStats struct {
+	// Count is the number of observations.
+	Count uint64
+
+	// Min, Mean and Max are exact; P50, P95 and P99 are bucket estimates.
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+	Max  time.Duration
 }
+
+// SubscribeOptions configures a worker subscription (filled by public
+// SubscribeOption functions in parent package).
+type SubscribeOptions struct {
+	// Streams limits delivery to these stream IDs (empty = all streams).
+	Streams []string
+
+	// Group joins a replica group ("" = plain worker with its own mailbox).
+	Group string
+
+	// SLA is the worker's service level (default SLANormal).
+	SLA SLAClass
+
+	// Health overrides the SLA class thresholds (zero fields = class default).
+	Health HealthPolicy
+
+	// Takeover replaces an existing subscription with the same worker ID
+	// (closing it) instead of rejecting the new one.
+	Takeover bool
+
+	// MaxRate limits delivery to at most MaxRate frames per second per
+	// stream (0 = unlimited).
+	MaxRate float64
+
+	// EveryNth delivers frames with Seq 1, N+1, 2N+1, ... (0 or 1 = all).
+	EveryNth int
+
+	// SampleInterval delivers the first frame of each wall-clock aligned
+	// window of this length (0 = disabled).
+	SampleInterval time.Duration
+}
+
+// SubscribeOption configures a worker subscription (see Supplier.Subscribe).
+type SubscribeOption func(*SubscribeOptions)
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// defaultWatchInterval is the Watch period when interval <= 0.
+const defaultWatchInterval = time.Second
+
+// StatsDelta is the change in supplier stats over one Watch interval.
+//
+// Counters are differences against the previously delivered delta (the
+// Watch call for the first one); Stats is the full snapshot at At, so
+// consumers needing gauges (IsIdle, Health, FrameAge) read it there.
+type StatsDelta struct {
+	// At is the snapshot time; Interval the time covered by this delta.
+	At       time.Time
+	Interval time.Duration
+
+	// Stats is the full snapshot at At.
+	Stats SupplierStats
+
+	// Published and InboxDrops count frames published/dropped at the inbox
+	// during Interval (all streams).
+	Published  uint64
+	InboxDrops uint64
+
+	// Streams maps stream ID to its delta (streams present at At).
+	Streams map[string]StreamDelta
+
+	// Workers maps workerID to its delta (workers subscribed at At).
+	Workers map[string]WorkerDelta
+
+	// Added and Removed list the workers subscribed/unsubscribed during
+	// Interval (sorted). A restarted worker (new generation) is in neither,
+	// see WorkerDelta.Restarted.
+	Added   []string
+	Removed []string
+}
+
+// StreamDelta is one stream's change over a Watch interval.
+type StreamDelta struct {
+	// Published and InboxDrops count the stream's frames during Interval.
+	Published  uint64
+	InboxDrops uint64
+
+	// Rate is Published per second.
+	Rate float64
+}
+
+// WorkerDelta is one worker's change over a Watch interval.
+//
+// Replicas report their own consumes and their group's drops (same as
+// WorkerStats).
+type WorkerDelta struct {
+	// Consumed, Drops and Skipped count frames during Interval.
+	Consumed uint64
+	Drops    uint64
+	Skipped  uint64
+
+	// ConsumeRate is Consumed per second.
+	ConsumeRate float64
+
+	// DropRate is Drops / (Drops + Consumed) over Interval (0..1, 0 when
+	// nothing was delivered).
+	DropRate float64
+
+	// Restarted is true when the worker ID was subscribed again during
+	// Interval (new WorkerStats.Generation): counters restarted from zero,
+	// the delta counts the new subscription only.
+	Restarted bool
+}
+
+// Watch delivers stats deltas every interval until ctx is done (implements Supplier.Watch).
+//
+// Algorithm:
+//  1. Baseline = Stats() at call time
+//  2. Every interval: snapshot, diff against baseline → StatsDelta
+//  3. Non-blocking send (1-slot buffer): on success the snapshot becomes the
+//     baseline; if the reader is still busy with the previous delta, the
+//     interval is merged into the next one (nothing lost or double counted)
+//  4. Close the channel on ctx.Done() or after Stop (within one interval)
+//
+// interval <= 0 defaults to 1s. Works before Start (all counters zero).
+// After Stop, returns an already closed channel.
+//
+// Cost: a full Stats() snapshot per interval (the same map allocations and
+// brief slot locks as polling Stats() at that rate) plus the Streams and
+// Workers delta maps per delivered delta. Watch saves consumers the diffing
+// and restart bookkeeping, not the snapshot; keep intervals in seconds.
+func (s *supplier) Watch(ctx context.Context, interval time.Duration) <-chan StatsDelta {
+	ch := make(chan StatsDelta, 1)
+	if s.stopping.Load() {
+		close(ch)
+		return ch
+	}
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	baseline, baselineAt := s.Stats(), time.Now()
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				if s.stopping.Load() {
+					return
+				}
+				cur := s.Stats()
+				select {
+				case ch <- diffStats(baseline, cur, baselineAt, now):
+					baseline, baselineAt = cur, now
+				default:
+					// Reader busy: merge into next interval
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// diffStats computes the delta from prev (taken at prevAt) to cur (taken at now).
+func diffStats(prev, cur SupplierStats, prevAt, now time.Time) StatsDelta {
+	elapsed := now.Sub(prevAt)
+	d := StatsDelta{
+		At:         now,
+		Interval:   elapsed,
+		Stats:      cur,
+		Published:  counterDelta(prev.Published, cur.Published),
+		InboxDrops: counterDelta(prev.InboxDrops, cur.InboxDrops),
+		Streams:    make(map[string]StreamDelta, len(cur.Streams)),
+		Workers:    make(map[string]WorkerDelta, len(cur.Workers)),
+	}
+
+	for id, st := range cur.Streams {
+		old := prev.Streams[id] // Zero value for new streams
+		published := counterDelta(old.Published, st.Published)
+		d.Streams[id] = StreamDelta{
+			Published:  published,
+			InboxDrops: counterDelta(old.InboxDrops, st.InboxDrops),
+			Rate:       perSecond(published, elapsed),
+		}
+	}
+
+	for id, ws := range cur.Workers {
+		old, existed := prev.Workers[id]
+		restarted := existed && old.Generation != ws.Generation
+		if !existed || restarted {
+			old = WorkerStats{} // Counters of a new subscription start at 0
+		}
+		if !existed {
+			d.Added = append(d.Added, id)
+		}
+
+		wd := WorkerDelta{
+			Consumed:  counterDelta(old.Consumed, ws.Consumed),
+			Drops:     counterDelta(old.TotalDrops, ws.TotalDrops),
+			Skipped:   counterDelta(old.Skipped, ws.Skipped),
+			Restarted: restarted,
+		}
+		wd.ConsumeRate = perSecond(wd.Consumed, elapsed)
+		if delivered := wd.Consumed + wd.Drops; delivered > 0 {
+			wd.DropRate = float64(wd.Drops) / float64(delivered)
+		}
+		d.Workers[id] = wd
+	}
+
+	for id := range prev.Workers {
+		if _, ok := cur.Workers[id]; !ok {
+			d.Removed = append(d.Removed, id)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	return d
+}
+
+// counterDelta returns cur - prev, or cur if the counter restarted (cur < prev).
+func counterDelta(prev, cur uint64) uint64 {
+	if cur < prev {
+		return cur
+	}
+	return cur - prev
+}
+
+// perSecond converts a count over elapsed into a rate (0 for elapsed <= 0).
+func perSecond(n uint64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(n) / elapsed.Seconds()
+}
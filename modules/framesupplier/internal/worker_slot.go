@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"time"
 )
@@ -8,13 +10,17 @@ import (
 // WorkerSlot represents a per-worker mailbox with sync.Cond blocking semantics.
 //
 // Architecture (ADR-001):
-//   - Single-slot buffer (frame *Frame)
-//   - Overwrite policy (new frame replaces old)
-//   - Blocking consume (sync.Cond.Wait)
+//   - Single-slot buffer per subscribed stream (frames, at most 1 per stream)
+//   - Overwrite policy (new frame replaces old of the same stream)
+//   - Blocking consume (sync.Cond.Wait), oldest stream first
 //   - Drop tracking (consecutiveDrops, totalDrops)
 //
+// A single-stream worker sees exactly the classic single-slot mailbox.
+// A multi-stream worker gets the latest frame of EACH stream in turn, so a
+// fast camera never overwrites a slow camera's frame.
+//
 // Thread-safety:
-//   - All fields protected by mu
+//   - All fields protected by mu (except streams: immutable after Subscribe)
 //   - publishToSlot: called by distributionLoop (or batch goroutines)
 //   - readFunc: called by worker goroutine (single consumer)
 //
@@ -22,20 +28,86 @@ import (
 type WorkerSlot struct {
 	// --- Mailbox State ---
 
-	mu    sync.Mutex // Protects all fields
-	cond  *sync.Cond // Signals worker goroutine
-	frame *Frame     // Single-slot buffer (nil = consumed, non-nil = unconsumed)
+	mu     sync.Mutex // Protects all fields
+	cond   *sync.Cond // Signals worker goroutine
+	frames []*Frame   // Unconsumed frames, at most 1 per stream (oldest first)
+
+	// --- Routing ---
+
+	streams map[string]struct{} // Subscribed stream IDs (nil = all streams)
 
 	// --- Operational Stats ---
 
 	lastConsumedAt   time.Time // Timestamp of last successful consume (for idle detection)
 	lastConsumedSeq  uint64    // Sequence number of last consumed frame
+	consumed         uint64    // Lifetime count of consumed frames
 	consecutiveDrops uint64    // Current streak of unconsumed frames (resets on consume)
 	totalDrops       uint64    // Lifetime count of dropped frames
 
+	// --- Sampling (sampling.go) ---
+
+	sampler *sampler // Sampling policy (nil = every frame, immutable after creation)
+
+	// --- Health (health.go) ---
+
+	health *workerHealth // SLA class, thresholds, last verdict (plain slots; replicas have their own)
+
+	// --- Latency (histogram.go) ---
+
+	latency *consumeLatency // Frame age / consume interval (plain slots, nil until first consume)
+
+	// --- Replica Group (replica_group.go) ---
+
+	group    string              // Group name ("" = plain worker slot)
+	replicas map[string]*replica // Replicas sharing this mailbox (group slots only)
+
 	// --- Lifecycle ---
 
-	closed bool // True after Unsubscribe (signals readFunc to return nil)
+	closed   bool  // True after Unsubscribe (signals readFunc to return nil)
+	closeErr error // Reason returned by Subscription reads once closed
+
+	generation uint64 // Subscription generation of the worker ID (plain slots only)
+}
+
+// newWorkerSlot creates an empty slot with opts' stream filter and sampling policy.
+func newWorkerSlot(opts SubscribeOptions) *WorkerSlot {
+	slot := &WorkerSlot{}
+	slot.cond = sync.NewCond(&slot.mu)
+	slot.lastConsumedAt = time.Now() // Initialize (avoid IsIdle on first Stats call)
+	slot.sampler = newSampler(opts)
+	slot.health = newWorkerHealth(opts)
+
+	// Stream filter (nil = all streams)
+	if len(opts.Streams) > 0 {
+		slot.streams = make(map[string]struct{}, len(opts.Streams))
+		for _, id := range opts.Streams {
+			slot.streams[id] = struct{}{}
+		}
+	}
+	return slot
+}
+
+// pop consumes the frame of the oldest pending stream (caller holds mu, frames non-empty).
+func (slot *WorkerSlot) pop() *Frame {
+	frame := slot.frames[0]
+	copy(slot.frames, slot.frames[1:])
+	slot.frames[len(slot.frames)-1] = nil
+	slot.frames = slot.frames[:len(slot.frames)-1] // Mark as consumed
+
+	slot.lastConsumedAt = time.Now()
+	slot.lastConsumedSeq = frame.Seq
+	slot.consumed++
+	slot.consecutiveDrops = 0 // Reset streak (worker alive and responsive)
+	return frame
+}
+
+// accepts reports whether the slot is subscribed to streamID.
+func (slot *WorkerSlot) accepts(streamID string) bool {
+	if slot.streams == nil {
+		return true
+	}
+	_, ok := slot.streams[streamID]
+	return ok
 }
 
 // publishToSlot publishes a frame to a worker slot (non-blocking).
@@ -43,8 +115,8 @@ type WorkerSlot struct {
 // Algorithm (ADR-001):
 //  1. Lock slot mutex
 //  2. Check if slot closed (worker unsubscribed)
-//  3. Check if previous frame unconsumed (increment drop counters)
-//  4. Overwrite slot.frame (JIT semantics)
+//  3. Check if previous frame of the same stream unconsumed (increment drop counters)
+//  4. Overwrite that frame in place, or append for a new stream (JIT semantics)
 //  5. Signal slot.cond (wake worker if blocked)
 //  6. Unlock mutex
 //
@@ -62,14 +134,20 @@ func (s *supplier) publishToSlot(slot *WorkerSlot, frame *Frame) {
 		return
 	}
 
-	// Check if previous frame unconsumed (worker slow)
-	if slot.frame != nil {
-		slot.consecutiveDrops++
-		slot.totalDrops++
+	// Check if previous frame of this stream unconsumed (worker slow)
+	for i, pending := range slot.frames {
+		if pending.StreamID == frame.StreamID {
+			slot.consecutiveDrops++
+			slot.totalDrops++
+
+			// Overwrite with new frame (JIT semantics, keeps stream's turn)
+			slot.frames[i] = frame
+			slot.cond.Signal()
+			return
+		}
 	}
 
-	// Overwrite with new frame (JIT semantics)
-	slot.frame = frame
+	slot.frames = append(slot.frames, frame)
 
 	// Wake worker if blocked in Wait()
 	slot.cond.Signal()
@@ -78,15 +156,23 @@ func (s *supplier) publishToSlot(slot *WorkerSlot, frame *Frame) {
 // Subscribe registers a worker and returns a blocking read function (implements Supplier.Subscribe).
 //
 // Returns: func() *Frame that blocks until frame available or shutdown.
+// Thin wrapper over OpenSubscription (Read without deadline).
+//
+// Routing: opts.Streams limits the worker to those stream IDs (empty = all
+// streams). opts.Group joins a replica group instead (see subscribeReplica).
+// opts.MaxRate, EveryNth and SampleInterval thin the frames (see sampler).
 //
 // Semantics:
-//   - Mailbox pattern: single-slot buffer, overwrite on publish
+//   - Mailbox pattern: single-slot buffer per stream, overwrite on publish
 //   - Blocking consume: readFunc() blocks until frame available
 //   - Graceful shutdown: returns nil when closed (Unsubscribe or Stop)
 //   - Safe degradation: returns nil-readFunc if called during/after Stop()
+//   - Duplicate workerID: rejected (nil-readFunc) unless opts.Takeover,
+//     which closes the previous subscription (see OpenSubscription).
+//     The rejected caller's Unsubscribe is a no-op (see open)
 //
 // Thread-safety:
-//   - Subscribe: Safe for concurrent calls (sync.Map.Store)
+//   - Subscribe: Safe for concurrent calls (registryMu)
 //   - readFunc: MUST be called from single worker goroutine only
 //
 // Contract:
@@ -94,43 +180,16 @@ func (s *supplier) publishToSlot(slot *WorkerSlot, frame *Frame) {
 //   - Worker MUST NOT call readFunc concurrently (single consumer only)
 //
 // See: ADR-001 (sync.Cond), ADR-005 (Graceful Shutdown), ARCHITECTURE.md (Algorithm 4)
-func (s *supplier) Subscribe(workerID string) func() *Frame {
-	// Check if supplier is stopping (fail-fast)
-	if s.stopping.Load() {
-		// Return nil-readFunc (immediate exit, no goroutine leak)
+func (s *supplier) Subscribe(workerID string, opts ...SubscribeOption) func() *Frame {
+	sub, err := s.open(workerID, opts, true)
+	if err != nil {
+		// Rejected (duplicate ID or stopping): worker exits on first read
 		return func() *Frame { return nil }
 	}
 
-	// Create new slot for this worker
-	slot := &WorkerSlot{}
-	slot.cond = sync.NewCond(&slot.mu)
-	slot.lastConsumedAt = time.Now() // Initialize (avoid IsIdle on first Stats call)
-
-	// Register slot in slots map
-	s.slots.Store(workerID, slot)
-
-	// Return blocking read function (closured slot reference)
+	// Return blocking read function (no deadline, nil on shutdown)
 	return func() *Frame {
-		slot.mu.Lock()
-		defer slot.mu.Unlock()
-
-		// Wait until frame available or closed
-		for slot.frame == nil && !slot.closed {
-			slot.cond.Wait() // Blocks here, releases lock
-		}
-
-		// Check shutdown condition
-		if slot.closed {
-			return nil // Signal worker to exit
-		}
-
-		// Consume frame
-		frame := slot.frame
-		slot.frame = nil // Mark as consumed
-		slot.lastConsumedAt = time.Now()
-		slot.lastConsumedSeq = frame.Seq
-		slot.consecutiveDrops = 0 // Reset streak (worker alive and responsive)
-
+		frame, _ := sub.Read(context.Background())
 		return frame
 	}
 }
@@ -138,9 +197,9 @@ func (s *supplier) Subscribe(workerID string) func() *Frame {
 // Unsubscribe removes a worker and signals its readFunc to return nil (implements Supplier.Unsubscribe).
 //
 // Behavior:
-//  1. Load slot from slots map (no-op if not found)
+//  1. Load slot from slots map (replica: leave its group, no-op if not found)
 //  2. Lock slot, set closed=true
-//  3. Signal slot.cond (wake worker if blocked)
+//  3. Broadcast slot.cond (wake worker if blocked)
 //  4. Delete slot from map
 //
 // After Unsubscribe:
@@ -150,22 +209,90 @@ func (s *supplier) Subscribe(workerID string) func() *Frame {
 //
 // Idempotent: Safe to call multiple times (subsequent calls no-op).
 //
+// Rejected duplicates: the Unsubscribe owed by a rejected Subscribe of
+// workerID is absorbed (no-op), so its deferred cleanup does not evict the
+// live worker holding the ID.
+//
 // Thread-safety: Safe for concurrent calls.
 func (s *supplier) Unsubscribe(workerID string) {
-	// Load slot (no-op if not found)
-	val, ok := s.slots.Load(workerID)
-	if !ok {
-		return // Already unsubscribed (idempotent)
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	// Cleanup of a rejected duplicate: not this ID's live subscription
+	if n := s.rejected[workerID]; n > 0 {
+		if n == 1 {
+			delete(s.rejected, workerID)
+		} else {
+			s.rejected[workerID] = n - 1
+		}
+		return
 	}
 
-	slot := val.(*WorkerSlot)
+	// Mark closed, wake worker and remove from map
+	s.evictLocked(workerID, ErrSubscriptionClosed)
+}
 
-	// Mark closed and wake worker
+// close marks the slot closed and wakes all blocked readers.
+//
+// reason is returned by Subscription reads (first close wins).
+func (slot *WorkerSlot) close(reason error) {
 	slot.mu.Lock()
 	slot.closed = true
-	slot.cond.Signal() // Wake readFunc if blocked
+	if slot.closeErr == nil {
+		slot.closeErr = reason
+	}
+	slot.cond.Broadcast() // Wake reader(s) if blocked
 	slot.mu.Unlock()
+}
+
+// streamIDs returns the subscribed stream IDs, sorted (nil = all streams).
+//
+// Caller must hold slot.mu.
+func (slot *WorkerSlot) streamIDs() []string {
+	var streams []string
+	for id := range slot.streams {
+		streams = append(streams, id)
+	}
+	sort.Strings(streams)
+	return streams
+}
 
-	// Remove from map
-	s.slots.Delete(workerID)
+// workerStats builds the WorkerStats snapshot of workerID (caller holds slot.mu).
+//
+// r is the worker's replica for group slots (nil = plain worker): replicas
+// report their own consumption and the group's drop counters.
+func (slot *WorkerSlot) workerStats(workerID string, r *replica) WorkerStats {
+	stat := WorkerStats{
+		WorkerID:         workerID,
+		LastConsumedAt:   slot.lastConsumedAt,
+		LastConsumedSeq:  slot.lastConsumedSeq,
+		Consumed:         slot.consumed,
+		Skipped:          slot.sampler.skippedCount(),
+		ConsecutiveDrops: slot.consecutiveDrops,
+		TotalDrops:       slot.totalDrops,
+		Streams:          slot.streamIDs(),
+	}
+	stat.Generation = slot.generation
+	h, lat := slot.health, slot.latency
+	if r != nil {
+		h, lat = r.health, r.latency
+		stat.Generation = r.generation
+		stat.LastConsumedAt = r.lastConsumedAt
+		stat.LastConsumedSeq = r.lastConsumedSeq
+		stat.Consumed = r.consumed
+		stat.ReplicaGroup = slot.group
+	}
+
+	// Calculate idle status (per-worker IdleTimeout), last health verdict
+	stat.IsIdle = h.isIdle(time.Now(), stat.LastConsumedAt)
+	stat.SLA = h.class
+	stat.Health = h.status
+	stat.Violations = append([]Violation(nil), h.violations...)
+
+	// Latency histograms (zero until first consume)
+	if lat != nil {
+		stat.FrameAge = lat.age.snapshot()
+		stat.ConsumeInterval = lat.interval.snapshot()
+	}
+	return stat
 }
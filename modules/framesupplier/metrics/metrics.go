@@ -0,0 +1,292 @@
+// Package metrics exposes FrameSupplier stats as Prometheus/OpenMetrics text.
+//
+// Zero dependencies (stdlib only, like framesupplier): the exposition format
+// is written directly instead of pulling in client_golang.
+//
+// # Usage
+//
+//	supplier := framesupplier.New()
+//	http.Handle("/metrics", metrics.Handler(supplier))
+//
+// The handler serves OpenMetrics 1.0 to scrapers that accept it
+// (Accept: application/openmetrics-text), Prometheus text 0.0.4 otherwise.
+// Each scrape costs one Supplier.Stats() snapshot.
+//
+// # Metrics
+//
+// Supplier (no labels):
+//   - framesupplier_published_frames_total: frames accepted by Publish
+//   - framesupplier_inbox_drops_total: frames overwritten at the inbox (should be ~0)
+//   - framesupplier_workers: subscribed workers (replicas counted individually)
+//   - framesupplier_distribution_latency_seconds: summary, Publish → fan-out done
+//
+// Per stream (label stream):
+//   - framesupplier_stream_published_frames_total
+//   - framesupplier_stream_inbox_drops_total
+//
+// Per worker (labels worker, group, sla):
+//   - framesupplier_worker_consumed_frames_total
+//   - framesupplier_worker_drops_total (replicas: their group's drops)
+//   - framesupplier_worker_skipped_frames_total (sampling policy, not drops)
+//   - framesupplier_worker_consecutive_drops: current drop streak
+//   - framesupplier_worker_idle: 1 if idle past the SLA IdleTimeout
+//   - framesupplier_worker_health: 0 healthy, 1 degraded, 2 unhealthy
+//   - framesupplier_worker_generation: subscriptions of the worker ID (restarts)
+//   - framesupplier_worker_frame_age_seconds: summary, Frame.Timestamp → consume
+//   - framesupplier_worker_consume_interval_seconds: summary, time between consumes
+//
+// Summaries carry quantiles 0.5, 0.95 and 0.99 (HDR estimates, ≤6.25% error,
+// NaN before the first observation) over the lifetime of the supplier or
+// subscription.
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+)
+
+// Format is a text exposition format.
+type Format int
+
+const (
+	// FormatPrometheus is the Prometheus text format 0.0.4.
+	FormatPrometheus Format = iota
+
+	// FormatOpenMetrics is OpenMetrics 1.0 text (counter families without
+	// the _total suffix, terminated by "# EOF").
+	FormatOpenMetrics
+)
+
+// ContentType returns the HTTP Content-Type of the format.
+func (f Format) ContentType() string {
+	if f == FormatOpenMetrics {
+		return "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	}
+	return "text/plain; version=0.0.4; charset=utf-8"
+}
+
+// Handler serves s's stats in the format negotiated from the Accept header.
+func Handler(s framesupplier.Supplier) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := Negotiate(r.Header.Get("Accept"))
+		w.Header().Set("Content-Type", format.ContentType())
+		_ = Write(w, s.Stats(), format) // Only fails if the scraper went away
+	})
+}
+
+// Negotiate picks FormatOpenMetrics if accept lists application/openmetrics-text.
+func Negotiate(accept string) Format {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		if strings.TrimSpace(mediaType) == "application/openmetrics-text" {
+			return FormatOpenMetrics
+		}
+	}
+	return FormatPrometheus
+}
+
+// Write encodes stats in format f to w.
+//
+// Output is deterministic: streams and workers are sorted by ID.
+func Write(w io.Writer, stats framesupplier.SupplierStats, f Format) error {
+	e := &encoder{w: bufio.NewWriter(w), format: f}
+
+	// Supplier
+	e.family("framesupplier_published_frames_total", "counter", "Frames accepted by Publish (all streams).")
+	e.sample("framesupplier_published_frames_total", nil, float64(stats.Published))
+
+	e.family("framesupplier_inbox_drops_total", "counter", "Frames overwritten at the inbox before distribution (should be ~0).")
+	e.sample("framesupplier_inbox_drops_total", nil, float64(stats.InboxDrops))
+
+	e.family("framesupplier_workers", "gauge", "Subscribed workers (replicas counted individually).")
+	e.sample("framesupplier_workers", nil, float64(len(stats.Workers)))
+
+	e.family("framesupplier_distribution_latency_seconds", "summary", "Time from Publish to the end of fan-out.")
+	e.summary("framesupplier_distribution_latency_seconds", nil, stats.DistributionLatency)
+
+	// Streams
+	streamIDs := make([]string, 0, len(stats.Streams))
+	for id := range stats.Streams {
+		streamIDs = append(streamIDs, id)
+	}
+	sort.Strings(streamIDs)
+
+	e.family("framesupplier_stream_published_frames_total", "counter", "Frames accepted by Publish per stream.")
+	for _, id := range streamIDs {
+		e.sample("framesupplier_stream_published_frames_total", streamLabels(id), float64(stats.Streams[id].Published))
+	}
+	e.family("framesupplier_stream_inbox_drops_total", "counter", "Frames overwritten at the inbox per stream.")
+	for _, id := range streamIDs {
+		e.sample("framesupplier_stream_inbox_drops_total", streamLabels(id), float64(stats.Streams[id].InboxDrops))
+	}
+
+	// Workers
+	workers := make([]framesupplier.WorkerStats, 0, len(stats.Workers))
+	for _, ws := range stats.Workers {
+		workers = append(workers, ws)
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].WorkerID < workers[j].WorkerID })
+
+	perWorker := []struct {
+		name, typ, help string
+		value           func(framesupplier.WorkerStats) float64
+	}{
+		{"framesupplier_worker_consumed_frames_total", "counter", "Frames consumed by the worker.",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.Consumed) }},
+		{"framesupplier_worker_drops_total", "counter", "Frames dropped because the worker was busy (replicas: group drops).",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.TotalDrops) }},
+		{"framesupplier_worker_skipped_frames_total", "counter", "Frames withheld by the worker's sampling policy (not drops).",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.Skipped) }},
+		{"framesupplier_worker_consecutive_drops", "gauge", "Current streak of frames dropped by the worker.",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.ConsecutiveDrops) }},
+		{"framesupplier_worker_idle", "gauge", "1 if the worker has not consumed within its SLA idle timeout.",
+			func(ws framesupplier.WorkerStats) float64 { return boolValue(ws.IsIdle) }},
+		{"framesupplier_worker_health", "gauge", "Worker health verdict (0 healthy, 1 degraded, 2 unhealthy).",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.Health) }},
+		{"framesupplier_worker_generation", "gauge", "Subscriptions of the worker ID (increments on restart).",
+			func(ws framesupplier.WorkerStats) float64 { return float64(ws.Generation) }},
+	}
+	for _, g := range perWorker {
+		e.family(g.name, g.typ, g.help)
+		for _, ws := range workers {
+			e.sample(g.name, workerLabels(ws), g.value(ws))
+		}
+	}
+
+	e.family("framesupplier_worker_frame_age_seconds", "summary", "Age of consumed frames (Frame.Timestamp to consume).")
+	for _, ws := range workers {
+		e.summary("framesupplier_worker_frame_age_seconds", workerLabels(ws), ws.FrameAge)
+	}
+
+	e.family("framesupplier_worker_consume_interval_seconds", "summary", "Time between consecutive consumes (inference period).")
+	for _, ws := range workers {
+		e.summary("framesupplier_worker_consume_interval_seconds", workerLabels(ws), ws.ConsumeInterval)
+	}
+
+	if f == FormatOpenMetrics {
+		e.line("# EOF")
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// label is a metric label pair.
+type label struct {
+	name, value string
+}
+
+func streamLabels(id string) []label {
+	return []label{{"stream", id}}
+}
+
+func workerLabels(ws framesupplier.WorkerStats) []label {
+	return []label{
+		{"worker", ws.WorkerID},
+		{"group", ws.ReplicaGroup},
+		{"sla", ws.SLA.String()},
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// encoder writes exposition lines (first write error is kept, later writes skipped).
+type encoder struct {
+	w      *bufio.Writer
+	format Format
+	err    error
+}
+
+// family writes the HELP and TYPE lines of a metric family.
+//
+// OpenMetrics names counter families without the _total sample suffix.
+func (e *encoder) family(name, typ, help string) {
+	if typ == "counter" && e.format == FormatOpenMetrics {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	e.line("# HELP " + name + " " + help)
+	e.line("# TYPE " + name + " " + typ)
+}
+
+// sample writes one sample line.
+func (e *encoder) sample(name string, labels []label, value float64) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(l.name)
+			b.WriteString(`="`)
+			b.WriteString(escapeLabel(l.value))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatValue(value))
+	e.line(b.String())
+}
+
+// summary writes quantile, _sum and _count samples of ls (in seconds).
+func (e *encoder) summary(name string, labels []label, ls framesupplier.LatencyStats) {
+	quantiles := []struct {
+		q string
+		v time.Duration
+	}{{"0.5", ls.P50}, {"0.95", ls.P95}, {"0.99", ls.P99}}
+
+	for _, q := range quantiles {
+		value := math.NaN() // No observations yet
+		if ls.Count > 0 {
+			value = q.v.Seconds()
+		}
+		e.sample(name, append(labels[:len(labels):len(labels)], label{"quantile", q.q}), value)
+	}
+	e.sample(name+"_sum", labels, ls.Mean.Seconds()*float64(ls.Count))
+	e.sample(name+"_count", labels, float64(ls.Count))
+}
+
+func (e *encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.WriteString(s + "\n")
+}
+
+// escapeLabel escapes a label value (backslash, double quote, newline).
+func escapeLabel(v string) string {
+	if !strings.ContainsAny(v, "\\\"\n") {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return r.Replace(v)
+}
+
+// formatValue formats a sample value (NaN and ±Inf spelled as the formats require).
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
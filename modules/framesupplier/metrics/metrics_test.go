@@ -0,0 +1,177 @@
+package metrics_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier/metrics"
+)
+
+// --- Test 1: Exposition Format ---
+
+// TestWritePrometheus validates the Prometheus text 0.0.4 encoding.
+//
+// Contract:
+//   - Counter families keep the _total suffix in TYPE lines
+//   - Worker samples carry worker, group and sla labels (values escaped)
+//   - Summaries emit quantiles, _sum and _count (NaN quantiles when empty)
+//   - Per-worker frame age and consume interval summaries
+//   - No "# EOF" terminator
+func TestWritePrometheus(t *testing.T) {
+	stats := framesupplier.SupplierStats{
+		Published:  120,
+		InboxDrops: 2,
+		DistributionLatency: framesupplier.LatencyStats{
+			Count: 4, Mean: 250 * time.Microsecond,
+			P50: 200 * time.Microsecond, P95: 500 * time.Microsecond, P99: 500 * time.Microsecond,
+		},
+		Streams: map[string]framesupplier.StreamStats{
+			"cam-1": {StreamID: "cam-1", Published: 100, InboxDrops: 2},
+		},
+		Workers: map[string]framesupplier.WorkerStats{
+			`Det"ect\or`: {
+				WorkerID:   `Det"ect\or`,
+				Consumed:   90,
+				TotalDrops: 10,
+				IsIdle:     true,
+				SLA:        framesupplier.SLACritical,
+				Health:     framesupplier.Unhealthy,
+				Generation: 2,
+				ConsumeInterval: framesupplier.LatencyStats{
+					Count: 89, Mean: 100 * time.Millisecond,
+					P50: 100 * time.Millisecond, P95: 120 * time.Millisecond, P99: 150 * time.Millisecond,
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, stats, metrics.FormatPrometheus); err != nil {
+		t.Fatalf("Write() err=%v", err)
+	}
+	out := buf.String()
+
+	labels := `{worker="Det\"ect\\or",group="",sla="critical"}`
+	for _, want := range []string{
+		"# TYPE framesupplier_published_frames_total counter\n",
+		"framesupplier_published_frames_total 120\n",
+		"framesupplier_inbox_drops_total 2\n",
+		"framesupplier_workers 1\n",
+		`framesupplier_distribution_latency_seconds{quantile="0.5"} 0.0002` + "\n",
+		"framesupplier_distribution_latency_seconds_sum 0.001\n",
+		"framesupplier_distribution_latency_seconds_count 4\n",
+		`framesupplier_stream_published_frames_total{stream="cam-1"} 100` + "\n",
+		"framesupplier_worker_consumed_frames_total" + labels + " 90\n",
+		"framesupplier_worker_drops_total" + labels + " 10\n",
+		"framesupplier_worker_idle" + labels + " 1\n",
+		"framesupplier_worker_health" + labels + " 2\n",
+		"framesupplier_worker_generation" + labels + " 2\n",
+		`framesupplier_worker_frame_age_seconds{worker="Det\"ect\\or",group="",sla="critical",quantile="0.99"} NaN` + "\n",
+		"framesupplier_worker_frame_age_seconds_count" + labels + " 0\n",
+		`framesupplier_worker_consume_interval_seconds{worker="Det\"ect\\or",group="",sla="critical",quantile="0.95"} 0.12` + "\n",
+		"framesupplier_worker_consume_interval_seconds_count" + labels + " 89\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "# EOF") {
+		t.Error("Prometheus output has # EOF terminator")
+	}
+
+	t.Logf("✅ Prometheus text validated (%d bytes)", len(out))
+}
+
+// TestWriteOpenMetrics validates the OpenMetrics 1.0 differences.
+//
+// Contract:
+//   - Counter families are named without _total (samples keep it)
+//   - Output ends with "# EOF"
+func TestWriteOpenMetrics(t *testing.T) {
+	var buf bytes.Buffer
+	if err := metrics.Write(&buf, framesupplier.SupplierStats{Published: 3}, metrics.FormatOpenMetrics); err != nil {
+		t.Fatalf("Write() err=%v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "# TYPE framesupplier_published_frames counter\n") {
+		t.Errorf("counter family not named without _total:\n%s", out)
+	}
+	if !strings.Contains(out, "framesupplier_published_frames_total 3\n") {
+		t.Errorf("counter sample missing _total suffix:\n%s", out)
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("output does not end with # EOF:\n%s", out)
+	}
+
+	t.Logf("✅ OpenMetrics text validated")
+}
+
+// --- Test 2: HTTP Handler ---
+
+// TestHandlerNegotiation validates content negotiation against a live supplier.
+//
+// Contract:
+//   - Accept: application/openmetrics-text → OpenMetrics content type and body
+//   - Any other Accept → Prometheus text 0.0.4
+//   - Body reflects the supplier's current Stats
+func TestHandlerNegotiation(t *testing.T) {
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+	defer supplier.Stop()
+
+	sub, err := supplier.OpenSubscription("Detector")
+	if err != nil {
+		t.Fatalf("OpenSubscription() failed: %v", err)
+	}
+	defer sub.Close()
+	supplier.Publish(&framesupplier.Frame{Data: []byte{1}, Timestamp: time.Now()})
+	if _, err := sub.ReadTimeout(time.Second); err != nil {
+		t.Fatalf("ReadTimeout() err=%v", err)
+	}
+
+	server := httptest.NewServer(metrics.Handler(supplier))
+	defer server.Close()
+
+	scrape := func(accept string) (string, string) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET err=%v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.Header.Get("Content-Type"), string(body)
+	}
+
+	contentType, body := scrape("application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	if contentType != metrics.FormatOpenMetrics.ContentType() || !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("OpenMetrics scrape: Content-Type=%q, body suffix ok=%v", contentType, strings.HasSuffix(body, "# EOF\n"))
+	}
+
+	contentType, body = scrape("")
+	if contentType != metrics.FormatPrometheus.ContentType() {
+		t.Errorf("default scrape Content-Type=%q, want %q", contentType, metrics.FormatPrometheus.ContentType())
+	}
+	if !strings.Contains(body, `framesupplier_worker_consumed_frames_total{worker="Detector",group="",sla="normal"} 1`) {
+		t.Errorf("body missing Detector consume:\n%s", body)
+	}
+
+	t.Logf("✅ Handler negotiation validated")
+}
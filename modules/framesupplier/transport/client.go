@@ -0,0 +1,195 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+)
+
+// Client is a remote worker's connection (Go side of the protocol).
+//
+// Next behaves like an in-process readFunc: each call means "ready for the
+// next frame" (ACKs the previous one) and blocks until the freshest frame
+// arrives. Used by Go workers running in their own process and by tests;
+// Python workers use examples/python_worker.py.
+//
+// Shared memory (SubscribeRequest.SharedMemory, server WithSharedMemory):
+// Frame.Data aliases the read-only ring mapping (zero-copy, writing faults)
+// and is valid until the next call to Next or Close (the ACK unpins the slot).
+//
+// Thread-safety:
+//   - Next: single worker goroutine
+//   - Close: safe from any goroutine (unblocks Next)
+type Client struct {
+	conn       net.Conn
+	generation uint64
+	shm        *ShmReader // nil = inline frames only
+	maxMessage uint32     // Bound of messages read by Next
+
+	pending   bool   // A frame was returned and not ACKed yet
+	inflight  uint64 // Seq of that frame
+	closeOnce sync.Once
+}
+
+// DialOption configures a Client.
+type DialOption func(*Client)
+
+// WithMaxMessageSize bounds the size of messages read by Next (default 64MB,
+// kept if n is 0).
+//
+// A larger (or corrupt) length prefix fails Next before allocating; the
+// connection is unusable afterwards (Close it). Size it above the largest
+// frame the supplier publishes.
+func WithMaxMessageSize(n uint32) DialOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.maxMessage = n
+		}
+	}
+}
+
+// Dial connects to the server at the Unix socket path and subscribes.
+//
+// Returns a *RemoteError if the server rejected the subscription (errors.Is
+// framesupplier.ErrWorkerExists for a duplicate worker ID). ctx bounds the
+// connect and handshake only.
+func Dial(ctx context.Context, path string, req SubscribeRequest, opts ...DialOption) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Handshake bounded by ctx
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if _, err := conn.Write(encodeSubscribe(req)); err != nil {
+		conn.Close()
+		return nil, dialErr(ctx, err)
+	}
+	typ, payload, err := readMessage(conn, maxControlMessage)
+	if err != nil {
+		conn.Close()
+		return nil, dialErr(ctx, err)
+	}
+
+	switch typ {
+	case msgSubscribed:
+		generation, shmPath, err := decodeSubscribed(payload)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		c := &Client{conn: conn, generation: generation, maxMessage: defaultMaxMessage}
+		for _, opt := range opts {
+			opt(c)
+		}
+		if shmPath != "" {
+			if c.shm, err = OpenShm(shmPath); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		if !stop() {
+			c.Close() // ctx ended right after the handshake
+			return nil, ctx.Err()
+		}
+		return c, nil
+	case msgError:
+		conn.Close()
+		return nil, decodeError(payload)
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("%w: unexpected message type %#x", errMalformed, typ)
+	}
+}
+
+// dialErr prefers ctx's error when the handshake was interrupted by it.
+func dialErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Generation returns the subscription generation assigned by the supplier.
+func (c *Client) Generation() uint64 {
+	return c.generation
+}
+
+// Next ACKs the previous frame and blocks until the next one arrives.
+//
+// Returns a *RemoteError when the server ends the session (errors.Is
+// framesupplier.ErrSubscriptionClosed on supplier Stop,
+// framesupplier.ErrSubscriptionReplaced on takeover), or the connection
+// error (e.g. after Close).
+func (c *Client) Next() (*framesupplier.Frame, error) {
+	if c.pending {
+		if _, err := c.conn.Write(encodeAck(c.inflight)); err != nil {
+			return nil, err
+		}
+		c.pending = false
+	}
+
+	typ, payload, err := readMessage(c.conn, c.maxMessage)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case msgFrame:
+		frame, err := decodeFrame(payload)
+		if err != nil {
+			return nil, err
+		}
+		c.pending, c.inflight = true, frame.Seq
+		return frame, nil
+	case msgFrameRef:
+		return c.frameRef(payload)
+	case msgError:
+		return nil, decodeError(payload)
+	default:
+		return nil, fmt.Errorf("%w: unexpected message type %#x", errMalformed, typ)
+	}
+}
+
+// frameRef resolves a FRAME_REF against the shared-memory ring.
+func (c *Client) frameRef(payload []byte) (*framesupplier.Frame, error) {
+	ref, err := decodeFrameRef(payload)
+	if err != nil {
+		return nil, err
+	}
+	c.pending, c.inflight = true, ref.frame.Seq // ACKed by the next Next even if stale
+
+	if c.shm == nil {
+		return nil, fmt.Errorf("%w: FRAME_REF without shared memory", errMalformed)
+	}
+	slotFrame, err := c.shm.Frame(ref.slot, ref.slotSeq)
+	if err != nil {
+		return nil, err
+	}
+	if len(slotFrame.Data) != ref.length {
+		return nil, ErrShmStale
+	}
+
+	frame := ref.frame
+	frame.Data = slotFrame.Data
+	return frame, nil
+}
+
+// Close disconnects (the server closes the subscription).
+//
+// Idempotent: Safe to call multiple times.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.conn.Close()
+		if c.shm != nil {
+			c.shm.Close()
+		}
+	})
+	return err
+}
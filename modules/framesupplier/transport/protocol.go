@@ -0,0 +1,417 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+)
+
+// ProtocolVersion is the wire protocol version sent in SUBSCRIBE.
+const ProtocolVersion = 1
+
+// Message types (first byte after the length prefix).
+const (
+	msgSubscribe  byte = 0x01 // Client → server, first message
+	msgSubscribed byte = 0x02 // Server → client, handshake reply
+	msgFrame      byte = 0x03 // Server → client, one frame (at most one unacked)
+	msgAck        byte = 0x04 // Client → server, frame processed (ready for next)
+	msgError      byte = 0x05 // Server → client, last message before close
+	msgFrameRef   byte = 0x06 // Server → client, frame in a shared-memory slot
+)
+
+// maxControlMessage bounds control messages: SUBSCRIBE and ACK read by the
+// server, the handshake reply read by the client.
+//
+// Protects both sides from a garbage length prefix.
+const maxControlMessage = 64 << 10
+
+// defaultMaxMessage bounds messages read by a Client after the handshake
+// (FRAME, FRAME_REF, ERROR): a 4K RGB frame (~25MB) with headroom.
+const defaultMaxMessage = 64 << 20
+
+// Code identifies why the server ended a connection (ERROR message).
+type Code byte
+
+const (
+	CodeProtocol     Code = 1 // Malformed or unexpected message
+	CodeWorkerExists Code = 2 // Worker ID already subscribed (no Takeover)
+	CodeClosed       Code = 3 // Subscription closed (supplier Stop, server Close)
+	CodeReplaced     Code = 4 // Another connection took over the worker ID
+)
+
+// String returns the code name.
+func (c Code) String() string {
+	switch c {
+	case CodeProtocol:
+		return "protocol"
+	case CodeWorkerExists:
+		return "worker-exists"
+	case CodeClosed:
+		return "closed"
+	case CodeReplaced:
+		return "replaced"
+	default:
+		return fmt.Sprintf("code(%d)", byte(c))
+	}
+}
+
+// codeFor maps a Subscription read error to its wire code.
+func codeFor(err error) Code {
+	switch {
+	case errors.Is(err, framesupplier.ErrSubscriptionReplaced):
+		return CodeReplaced
+	case errors.Is(err, framesupplier.ErrSubscriptionClosed):
+		return CodeClosed
+	case errors.Is(err, framesupplier.ErrWorkerExists):
+		return CodeWorkerExists
+	default:
+		return CodeProtocol
+	}
+}
+
+// RemoteError is the reason sent by the server in an ERROR message.
+//
+// errors.Is matches the framesupplier error of the code (ErrWorkerExists,
+// ErrSubscriptionClosed, ErrSubscriptionReplaced), so remote workers handle
+// shutdown the same way as in-process ones.
+type RemoteError struct {
+	Code    Code
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return fmt.Sprintf("framesupplier transport: %s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the framesupplier error matching Code (nil for CodeProtocol).
+func (e *RemoteError) Unwrap() error {
+	switch e.Code {
+	case CodeWorkerExists:
+		return framesupplier.ErrWorkerExists
+	case CodeClosed:
+		return framesupplier.ErrSubscriptionClosed
+	case CodeReplaced:
+		return framesupplier.ErrSubscriptionReplaced
+	default:
+		return nil
+	}
+}
+
+// SUBSCRIBE flags.
+const (
+	flagTakeover     byte = 1 << 0
+	flagSharedMemory byte = 1 << 1
+)
+
+// SubscribeRequest is the SUBSCRIBE payload (remote worker's subscription options).
+//
+// Fields map 1:1 to the framesupplier SubscribeOptions (WithStreams,
+// WithReplicaGroup, WithSLA, WithMaxRate, WithEveryNth, WithSampleInterval,
+// WithTakeover); zero values keep the defaults.
+//
+// SharedMemory asks for frames through the server's shared-memory ring
+// (FRAME_REF); ignored if the server has none (frames sent inline).
+type SubscribeRequest struct {
+	WorkerID       string
+	Group          string
+	Streams        []string
+	SLA            framesupplier.SLAClass
+	MaxRate        float64
+	EveryNth       int
+	SampleInterval time.Duration
+	Takeover       bool
+	SharedMemory   bool
+}
+
+// options converts the request to subscribe options.
+func (r SubscribeRequest) options() []framesupplier.SubscribeOption {
+	opts := []framesupplier.SubscribeOption{framesupplier.WithSLA(r.SLA)}
+	if len(r.Streams) > 0 {
+		opts = append(opts, framesupplier.WithStreams(r.Streams...))
+	}
+	if r.Group != "" {
+		opts = append(opts, framesupplier.WithReplicaGroup(r.Group))
+	}
+	if r.MaxRate > 0 {
+		opts = append(opts, framesupplier.WithMaxRate(r.MaxRate))
+	}
+	if r.EveryNth > 1 {
+		opts = append(opts, framesupplier.WithEveryNth(r.EveryNth))
+	}
+	if r.SampleInterval > 0 {
+		opts = append(opts, framesupplier.WithSampleInterval(r.SampleInterval))
+	}
+	if r.Takeover {
+		opts = append(opts, framesupplier.WithTakeover())
+	}
+	return opts
+}
+
+// --- Encoding ---
+//
+// Message: | length u32 | type u8 | payload |   (length = 1 + len(payload))
+// Integers big-endian, strings | len u16 | bytes |, f64 as IEEE 754 bits.
+
+// encoder appends fields to a message buffer.
+type encoder struct {
+	buf []byte
+}
+
+// newMessage starts a message of type typ (length patched by bytes).
+func newMessage(typ byte, sizeHint int) *encoder {
+	e := &encoder{buf: make([]byte, 5, 5+sizeHint)}
+	e.buf[4] = typ
+	return e
+}
+
+func (e *encoder) u8(v byte)     { e.buf = append(e.buf, v) }
+func (e *encoder) u16(v uint16)  { e.buf = binary.BigEndian.AppendUint16(e.buf, v) }
+func (e *encoder) u32(v uint32)  { e.buf = binary.BigEndian.AppendUint32(e.buf, v) }
+func (e *encoder) u64(v uint64)  { e.buf = binary.BigEndian.AppendUint64(e.buf, v) }
+func (e *encoder) raw(b []byte)  { e.buf = append(e.buf, b...) }
+func (e *encoder) f64(v float64) { e.u64(math.Float64bits(v)) }
+
+// str appends a length-prefixed string (truncated to 64KiB).
+func (e *encoder) str(s string) {
+	if len(s) > math.MaxUint16 {
+		s = s[:math.MaxUint16]
+	}
+	e.u16(uint16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// bytes returns the framed message.
+func (e *encoder) bytes() []byte {
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	return e.buf
+}
+
+// errMalformed is returned for truncated or invalid payloads.
+var errMalformed = errors.New("framesupplier transport: malformed message")
+
+// decoder reads fields from a payload (first error sticks, later reads return zero).
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) take(n int) []byte {
+	if d.err != nil || len(d.buf) < n {
+		d.err = errMalformed
+		return make([]byte, n)
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) u8() byte      { return d.take(1)[0] }
+func (d *decoder) u16() uint16   { return binary.BigEndian.Uint16(d.take(2)) }
+func (d *decoder) u32() uint32   { return binary.BigEndian.Uint32(d.take(4)) }
+func (d *decoder) u64() uint64   { return binary.BigEndian.Uint64(d.take(8)) }
+func (d *decoder) f64() float64  { return math.Float64frombits(d.u64()) }
+func (d *decoder) str() string   { return string(d.take(int(d.u16()))) }
+func (d *decoder) rest() []byte  { b := d.buf; d.buf = nil; return b }
+func (d *decoder) finish() error { return d.err }
+
+// readMessage reads one message (limit bounds the length prefix).
+func readMessage(r io.Reader, limit uint32) (byte, []byte, error) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:4])
+	if n == 0 || n > limit {
+		return 0, nil, fmt.Errorf("%w: length %d", errMalformed, n)
+	}
+	payload := make([]byte, n-1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return hdr[4], payload, nil
+}
+
+// SUBSCRIBE: version u8, worker_id str, group str, flags u8 (bit 0 takeover,
+// bit 1 shared memory),
+// sla u8, max_rate f64, every_nth u32, sample_interval_ms u32,
+// n_streams u16, streams str...
+func encodeSubscribe(r SubscribeRequest) []byte {
+	e := newMessage(msgSubscribe, 64)
+	e.u8(ProtocolVersion)
+	e.str(r.WorkerID)
+	e.str(r.Group)
+	var flags byte
+	if r.Takeover {
+		flags |= flagTakeover
+	}
+	if r.SharedMemory {
+		flags |= flagSharedMemory
+	}
+	e.u8(flags)
+	e.u8(byte(r.SLA))
+	e.f64(r.MaxRate)
+	e.u32(uint32(r.EveryNth))
+	e.u32(uint32(r.SampleInterval / time.Millisecond))
+	e.u16(uint16(len(r.Streams)))
+	for _, s := range r.Streams {
+		e.str(s)
+	}
+	return e.bytes()
+}
+
+func decodeSubscribe(payload []byte) (SubscribeRequest, error) {
+	d := &decoder{buf: payload}
+	if v := d.u8(); d.err == nil && v != ProtocolVersion {
+		return SubscribeRequest{}, fmt.Errorf("framesupplier transport: unsupported protocol version %d", v)
+	}
+	var r SubscribeRequest
+	r.WorkerID = d.str()
+	r.Group = d.str()
+	flags := d.u8()
+	r.Takeover = flags&flagTakeover != 0
+	r.SharedMemory = flags&flagSharedMemory != 0
+	r.SLA = framesupplier.SLAClass(d.u8())
+	r.MaxRate = d.f64()
+	r.EveryNth = int(d.u32())
+	r.SampleInterval = time.Duration(d.u32()) * time.Millisecond
+	if n := int(d.u16()); n > 0 {
+		r.Streams = make([]string, 0, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			r.Streams = append(r.Streams, d.str())
+		}
+	}
+	if err := d.finish(); err != nil {
+		return SubscribeRequest{}, err
+	}
+	if r.WorkerID == "" {
+		return SubscribeRequest{}, fmt.Errorf("%w: empty worker ID", errMalformed)
+	}
+	return r, nil
+}
+
+// SUBSCRIBED: generation u64 [, shm_path str, slots u32, slot_size u64].
+//
+// The shared-memory tail is present only if the client asked for it and the
+// server has a ring (ring != nil).
+func encodeSubscribed(generation uint64, ring *ShmRing) []byte {
+	e := newMessage(msgSubscribed, 8)
+	e.u64(generation)
+	if ring != nil {
+		e.str(ring.path)
+		e.u32(uint32(ring.slots))
+		e.u64(uint64(ring.slotSize))
+	}
+	return e.bytes()
+}
+
+// decodeSubscribed returns the generation and the ring path ("" = inline frames).
+func decodeSubscribed(payload []byte) (generation uint64, shmPath string, err error) {
+	d := &decoder{buf: payload}
+	generation = d.u64()
+	if len(d.buf) > 0 {
+		shmPath = d.str()
+		d.u32() // slots, slot_size: read from the segment header
+		d.u64()
+	}
+	return generation, shmPath, d.finish()
+}
+
+// FRAME: seq u64, timestamp_ns i64 (0 = unset), width u32, height u32,
+// stream_id str, trace_parent str, data (rest of message).
+func encodeFrame(f *framesupplier.Frame) []byte {
+	e := newMessage(msgFrame, 32+len(f.StreamID)+len(f.TraceParent)+len(f.Data))
+	encodeFrameMeta(e, f)
+	e.raw(f.Data)
+	return e.bytes()
+}
+
+func decodeFrame(payload []byte) (*framesupplier.Frame, error) {
+	d := &decoder{buf: payload}
+	f := decodeFrameMeta(d)
+	f.Data = d.rest()
+	return f, d.finish()
+}
+
+// encodeFrameMeta appends the fields shared by FRAME and FRAME_REF.
+func encodeFrameMeta(e *encoder, f *framesupplier.Frame) {
+	e.u64(f.Seq)
+	var ts int64
+	if !f.Timestamp.IsZero() {
+		ts = f.Timestamp.UnixNano()
+	}
+	e.u64(uint64(ts))
+	e.u32(uint32(f.Width))
+	e.u32(uint32(f.Height))
+	e.str(f.StreamID)
+	e.str(f.TraceParent)
+}
+
+// decodeFrameMeta reads the fields shared by FRAME and FRAME_REF.
+func decodeFrameMeta(d *decoder) *framesupplier.Frame {
+	f := &framesupplier.Frame{Seq: d.u64()}
+	if ts := int64(d.u64()); ts != 0 {
+		f.Timestamp = time.Unix(0, ts)
+	}
+	f.Width = int(d.u32())
+	f.Height = int(d.u32())
+	f.StreamID = d.str()
+	f.TraceParent = d.str()
+	return f
+}
+
+// FRAME_REF: FRAME fields without data, then slot u32, slot_seq u64
+// (seqlock value of the write), length u32.
+func encodeFrameRef(f *framesupplier.Frame, slot int, slotSeq uint64) []byte {
+	e := newMessage(msgFrameRef, 48+len(f.StreamID)+len(f.TraceParent))
+	encodeFrameMeta(e, f)
+	e.u32(uint32(slot))
+	e.u64(slotSeq)
+	e.u32(uint32(len(f.Data)))
+	return e.bytes()
+}
+
+// frameRef is a decoded FRAME_REF (metadata, Data unset).
+type frameRef struct {
+	frame   *framesupplier.Frame
+	slot    int
+	slotSeq uint64
+	length  int
+}
+
+func decodeFrameRef(payload []byte) (frameRef, error) {
+	d := &decoder{buf: payload}
+	ref := frameRef{frame: decodeFrameMeta(d)}
+	ref.slot = int(d.u32())
+	ref.slotSeq = d.u64()
+	ref.length = int(d.u32())
+	return ref, d.finish()
+}
+
+// ACK: seq u64 (Seq of the processed frame).
+func encodeAck(seq uint64) []byte {
+	e := newMessage(msgAck, 8)
+	e.u64(seq)
+	return e.bytes()
+}
+
+// ERROR: code u8, message str.
+func encodeError(code Code, msg string) []byte {
+	e := newMessage(msgError, 3+len(msg))
+	e.u8(byte(code))
+	e.str(msg)
+	return e.bytes()
+}
+
+func decodeError(payload []byte) *RemoteError {
+	d := &decoder{buf: payload}
+	e := &RemoteError{Code: Code(d.u8()), Message: d.str()}
+	if d.finish() != nil {
+		return &RemoteError{Code: CodeProtocol, Message: "malformed ERROR message"}
+	}
+	return e
+}
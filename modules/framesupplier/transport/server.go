@@ -0,0 +1,430 @@
+// Package transport lets out-of-process workers subscribe to a FrameSupplier
+// over a Unix domain socket.
+//
+// Motivation: inference workers written in Python (ONNX, PyTorch) used to
+// receive frames through their own stdin pipe and goroutine, outside the
+// supplier: no mailbox, no drop accounting. With this transport a remote
+// worker is an ordinary subscription on the Go side:
+//
+//	remote worker ──socket── connection goroutine ── Subscription (mailbox)
+//
+// The connection goroutine reads the mailbox only after the worker ACKed the
+// previous frame (one frame in flight). While the worker is busy the mailbox
+// keeps overwriting, so a slow remote worker gets the freshest frame when it
+// is ready, and its drops, health and latency show up in Supplier.Stats()
+// like any in-process worker.
+//
+// # Protocol
+//
+// Length-prefixed binary messages (integers big-endian, strings u16-prefixed):
+//
+//	| length u32 | type u8 | payload |        length = 1 + len(payload)
+//
+//	SUBSCRIBE  0x01 client → server  version u8 (=1), worker_id str, group str,
+//	                                 flags u8 (bit 0 takeover, bit 1 shm), sla u8,
+//	                                 max_rate f64, every_nth u32,
+//	                                 sample_interval_ms u32, n u16, streams str × n
+//	SUBSCRIBED 0x02 server → client  generation u64 [, shm_path str, slots u32,
+//	                                 slot_size u64]  (tail only if shm granted)
+//	FRAME      0x03 server → client  seq u64, timestamp_ns i64 (0 = unset),
+//	                                 width u32, height u32, stream_id str,
+//	                                 trace_parent str, data (rest of message)
+//	ACK        0x04 client → server  seq u64 (frame processed, send the next)
+//	ERROR      0x05 server → client  code u8, message str (then close)
+//	FRAME_REF  0x06 server → client  FRAME fields without data, slot u32,
+//	                                 slot_seq u64, length u32
+//
+// Session: SUBSCRIBE → SUBSCRIBED (or ERROR) → { FRAME → ACK }*. Closing the
+// connection unsubscribes the worker. ERROR codes: 1 protocol, 2 worker ID
+// already subscribed, 3 subscription closed (supplier stopped), 4 replaced
+// by a takeover.
+//
+// # Shared Memory (Linux)
+//
+// Copying a 6MB RGB frame into every worker's socket defeats the zero-copy
+// design (ADR-002). With a ShmRing (WithSharedMemory) each distributed frame
+// is written once into a /dev/shm segment and workers that asked for shared
+// memory get a FRAME_REF (slot index + seqlock value) instead; the slot stays
+// pinned until their ACK. Frames that do not fit, or arrive while every slot
+// is pinned, fall back to inline FRAME. Segment layout: see shm.go.
+//
+// See examples/python_worker.py for a stdlib-only Python client (inline and
+// shared-memory frames).
+//
+// # Usage
+//
+//	srv := transport.NewServer(supplier)
+//	go srv.ListenAndServe("/run/orion/frames.sock")
+//	defer srv.Close()
+//
+// With shared memory (4 slots of 6MB = 4 in-flight 1080p RGB frames):
+//
+//	ring, err := transport.NewShmRing("/dev/shm/orion-frames", 4, 6<<20)
+//	srv := transport.NewServer(supplier, transport.WithSharedMemory(ring))
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+)
+
+// ErrServerClosed is returned by Serve and ListenAndServe after Close.
+var ErrServerClosed = errors.New("framesupplier transport: server closed")
+
+// ErrAddrInUse is returned by ListenAndServe when the socket path is served
+// by a live process (or cannot be probed).
+var ErrAddrInUse = errors.New("framesupplier transport: address in use")
+
+// Server defaults.
+const (
+	// staleProbeTimeout bounds the connect probe of an existing socket file.
+	staleProbeTimeout = time.Second
+
+	// defaultHandshakeTimeout bounds the wait for SUBSCRIBE after accept.
+	defaultHandshakeTimeout = 5 * time.Second
+
+	// defaultWriteTimeout bounds a single message write (hung worker).
+	defaultWriteTimeout = 5 * time.Second
+)
+
+// Server accepts remote workers and serves each one from its own Subscription.
+//
+// Goroutine topology:
+//   - 1 per Serve call: accept loop
+//   - 2 per connection: mailbox → socket (frames), socket → ACK reader
+//
+// Thread-safety: All methods safe for concurrent use.
+type Server struct {
+	supplier framesupplier.Supplier
+
+	handshakeTimeout time.Duration // SUBSCRIBE deadline after accept
+	writeTimeout     time.Duration // Per-message write deadline
+	ring             *ShmRing      // nil = inline frames only
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup // Tracks connection goroutines
+}
+
+// ServerOption configures a Server.
+type ServerOption func(*Server)
+
+// WithHandshakeTimeout sets how long a new connection may take to send
+// SUBSCRIBE (default 5s).
+func WithHandshakeTimeout(d time.Duration) ServerOption {
+	return func(srv *Server) {
+		srv.handshakeTimeout = d
+	}
+}
+
+// WithWriteTimeout sets the deadline of each message write (default 5s).
+//
+// A worker that stops reading its socket for longer is disconnected (and
+// unsubscribed).
+func WithWriteTimeout(d time.Duration) ServerOption {
+	return func(srv *Server) {
+		srv.writeTimeout = d
+	}
+}
+
+// WithSharedMemory serves workers that ask for it (SubscribeRequest.SharedMemory)
+// through ring: one copy per distributed frame, FRAME_REF over the socket.
+//
+// The caller owns ring (close it after the Server). Default: nil (inline only).
+func WithSharedMemory(ring *ShmRing) ServerOption {
+	return func(srv *Server) {
+		srv.ring = ring
+	}
+}
+
+// NewServer creates a transport server for s (not listening yet).
+func NewServer(s framesupplier.Supplier, opts ...ServerOption) *Server {
+	srv := &Server{
+		supplier:         s,
+		handshakeTimeout: defaultHandshakeTimeout,
+		writeTimeout:     defaultWriteTimeout,
+		listeners:        make(map[net.Listener]struct{}),
+		conns:            make(map[net.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(srv)
+	}
+	return srv
+}
+
+// ListenAndServe listens on the Unix socket path and serves connections.
+//
+// A stale socket file at path (left by a crashed process: connect refused)
+// is removed first. A socket another server still accepts on is left alone
+// (ErrAddrInUse); any other existing file is an error. Returns
+// ErrServerClosed after Close.
+func (srv *Server) ListenAndServe(path string) error {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode().Type() == fs.ModeSocket {
+		if err := removeStaleSocket(path); err != nil {
+			return err
+		}
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	return srv.Serve(ln)
+}
+
+// removeStaleSocket removes the socket file at path if nothing listens on it.
+func removeStaleSocket(path string) error {
+	conn, err := net.DialTimeout("unix", path, staleProbeTimeout)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%w: %s (live server)", ErrAddrInUse, path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return fmt.Errorf("%w: %s (%v)", ErrAddrInUse, path, err)
+	}
+	return os.Remove(path)
+}
+
+// Serve accepts connections on ln until Close (returns ErrServerClosed) or an
+// accept error. ln is closed on return.
+func (srv *Server) Serve(ln net.Listener) error {
+	if !srv.track(ln, nil) {
+		ln.Close()
+		return ErrServerClosed
+	}
+	defer srv.untrack(ln, nil)
+	defer ln.Close()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if srv.isClosed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+		if !srv.track(nil, conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+		go srv.serveConn(conn)
+	}
+}
+
+// Close stops all listeners, disconnects all workers (closing their
+// subscriptions) and waits for connection goroutines.
+//
+// Idempotent: Safe to call multiple times.
+func (srv *Server) Close() error {
+	srv.mu.Lock()
+	srv.closed = true
+	for ln := range srv.listeners {
+		ln.Close()
+	}
+	for conn := range srv.conns {
+		conn.Close()
+	}
+	srv.mu.Unlock()
+
+	srv.wg.Wait()
+	return nil
+}
+
+// track registers a listener or connection (false if the server is closed).
+func (srv *Server) track(ln net.Listener, conn net.Conn) bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	if srv.closed {
+		return false
+	}
+	if ln != nil {
+		srv.listeners[ln] = struct{}{}
+	}
+	if conn != nil {
+		srv.conns[conn] = struct{}{}
+		srv.wg.Add(1)
+	}
+	return true
+}
+
+// untrack unregisters a listener or connection.
+func (srv *Server) untrack(ln net.Listener, conn net.Conn) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	delete(srv.listeners, ln)
+	delete(srv.conns, conn)
+}
+
+func (srv *Server) isClosed() bool {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.closed
+}
+
+// serveConn runs one remote worker session.
+//
+// Algorithm:
+//  1. Read SUBSCRIBE (handshake deadline), open the Subscription
+//  2. Rejected (duplicate ID, supplier stopping) → ERROR, close
+//  3. SUBSCRIBED{generation}; start ACK reader (detects disconnects)
+//  4. Loop: Read mailbox → FRAME (or FRAME_REF, slot pinned) → wait ACK
+//     (one frame in flight, the mailbox keeps overwriting meanwhile = drops
+//     counted in Stats)
+//  5. Subscription closed/replaced → ERROR; disconnect → Close subscription
+func (srv *Server) serveConn(conn net.Conn) {
+	defer srv.wg.Done()
+	defer srv.untrack(nil, conn)
+	defer conn.Close()
+
+	// 1. Handshake
+	conn.SetReadDeadline(time.Now().Add(srv.handshakeTimeout))
+	typ, payload, err := readMessage(conn, maxControlMessage)
+	if err != nil {
+		return
+	}
+	if typ != msgSubscribe {
+		srv.write(conn, encodeError(CodeProtocol, "expected SUBSCRIBE"))
+		return
+	}
+	req, err := decodeSubscribe(payload)
+	if err != nil {
+		srv.write(conn, encodeError(CodeProtocol, err.Error()))
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	// 2. Rejected subscriptions (duplicate worker ID, supplier stopping)
+	sub, err := srv.supplier.OpenSubscription(req.WorkerID, req.options()...)
+	if err != nil {
+		srv.write(conn, encodeError(codeFor(err), err.Error()))
+		return
+	}
+	defer sub.Close()
+
+	// 3. Accepted (shared memory if asked and available)
+	var ring *ShmRing
+	if req.SharedMemory {
+		ring = srv.ring
+	}
+	if srv.write(conn, encodeSubscribed(sub.Generation(), ring)) != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	acks := make(chan uint64, 1)
+	protoErr := make(chan error, 1)
+	go readAcks(conn, acks, protoErr, cancel)
+
+	// 4. One frame in flight (its shared-memory slot pinned until ACK)
+	pinned := -1
+	defer func() {
+		if pinned >= 0 {
+			ring.release(pinned)
+		}
+	}()
+
+	for {
+		frame, err := sub.Read(ctx)
+		if err != nil {
+			srv.closeWith(ctx, conn, protoErr, err)
+			return
+		}
+
+		msg := encodeFrame(frame)
+		if ring != nil {
+			if slot, slotSeq, ok := ring.acquire(frame); ok {
+				pinned = slot
+				msg = encodeFrameRef(frame, slot, slotSeq)
+			}
+		}
+		if srv.write(conn, msg) != nil {
+			return
+		}
+
+		select {
+		case seq := <-acks:
+			if seq != frame.Seq {
+				srv.write(conn, encodeError(CodeProtocol, "ACK does not match in-flight frame"))
+				return
+			}
+		case <-ctx.Done():
+			srv.closeWith(ctx, conn, protoErr, ctx.Err())
+			return
+		}
+		if pinned >= 0 {
+			ring.release(pinned)
+			pinned = -1
+		}
+	}
+}
+
+// closeWith sends the final ERROR for err (read error or context end).
+//
+// A canceled ctx means the ACK reader stopped: protocol violation (reported)
+// or disconnect (nothing to send).
+func (srv *Server) closeWith(ctx context.Context, conn net.Conn, protoErr <-chan error, err error) {
+	if ctx.Err() == nil {
+		srv.write(conn, encodeError(codeFor(err), err.Error()))
+		return
+	}
+	select {
+	case perr := <-protoErr:
+		srv.write(conn, encodeError(CodeProtocol, perr.Error()))
+	default:
+	}
+}
+
+// readAcks forwards ACKs until the connection fails, then cancels the session.
+//
+// Protocol violations (unexpected message type, malformed ACK) are reported
+// on protoErr; disconnects only cancel.
+func readAcks(conn net.Conn, acks chan<- uint64, protoErr chan<- error, cancel context.CancelFunc) {
+	defer cancel()
+
+	for {
+		typ, payload, err := readMessage(conn, maxControlMessage)
+		if err != nil {
+			if errors.Is(err, errMalformed) {
+				protoErr <- err
+			}
+			return
+		}
+		if typ != msgAck {
+			protoErr <- errors.New("unexpected message (only ACK after SUBSCRIBE)")
+			return
+		}
+		d := &decoder{buf: payload}
+		seq := d.u64()
+		if err := d.finish(); err != nil {
+			protoErr <- err
+			return
+		}
+
+		select {
+		case acks <- seq:
+		default:
+			protoErr <- errors.New("ACK without in-flight frame")
+			return
+		}
+	}
+}
+
+// write sends one message within the write timeout.
+func (srv *Server) write(conn net.Conn, msg []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(srv.writeTimeout))
+	_, err := conn.Write(msg)
+	return err
+}
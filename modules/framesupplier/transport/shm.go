@@ -0,0 +1,341 @@
+package transport
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+)
+
+// Shared-memory ring layout (version 1).
+//
+// One segment file (e.g. /dev/shm/orion-frames), all integers little-endian,
+// every offset 64-byte aligned:
+//
+//	Header (64 bytes)
+//	   0  magic      [8]byte "FSSHM\x00\x00\x01"
+//	   8  version    u32 (1)
+//	  12  slots      u32
+//	  16  slot_size  u64 (data capacity per slot, multiple of 64)
+//	  24  reserved
+//	Slot i at 64 + i × (64 + slot_size)
+//	   0  seq        u64 seqlock (odd = write in progress, even = stable)
+//	   8  length     u32 (data bytes)
+//	  12  width      u32
+//	  16  height     u32
+//	  20  reserved   u32
+//	  24  frame_seq  u64 (Frame.Seq)
+//	  32  timestamp  i64 (Unix ns, 0 = unset)
+//	  40  reserved
+//	  64  data       [slot_size]byte
+//
+// Seqlock protocol: the writer increments seq to odd, writes header fields
+// and data, then increments it to even. A reader holding a FRAME_REF
+// (slot, slot_seq) checks seq == slot_seq before and after using the data;
+// any other value means the slot was rewritten (torn read, discard).
+//
+// The server never rewrites a slot pinned by an unacked FRAME_REF, so readers
+// following the ACK discipline never see a torn slot; the seqlock protects
+// readers that keep data past their ACK or poll the ring directly.
+const (
+	shmMagic          = "FSSHM\x00\x00\x01"
+	shmVersion        = 1
+	shmHeaderSize     = 64
+	shmSlotHeaderSize = 64
+)
+
+// Errors returned by shared-memory rings and readers.
+var (
+	// ErrShmUnsupported is returned by NewShmRing and OpenShm on platforms
+	// without shared-memory support (Linux only).
+	ErrShmUnsupported = errors.New("framesupplier transport: shared memory not supported on this platform")
+
+	// ErrShmStale is returned when a slot was rewritten since its FRAME_REF
+	// (seqlock mismatch).
+	ErrShmStale = errors.New("framesupplier transport: shared-memory slot rewritten")
+)
+
+// ShmStats counts ring usage.
+type ShmStats struct {
+	// Writes counts frames copied into the ring.
+	Writes uint64
+
+	// Shared counts FRAME_REFs to an already written frame (no copy: the
+	// same distributed frame sent to several workers).
+	Shared uint64
+
+	// Fallbacks counts frames sent inline instead (larger than slot_size,
+	// or every slot pinned by in-flight frames).
+	Fallbacks uint64
+}
+
+// ShmRing is the writer side of a shared-memory frame ring.
+//
+// Each distributed frame is copied into the ring once, whatever the number of
+// shared-memory workers receiving it: workers get a FRAME_REF (slot index)
+// over their socket instead of the frame bytes. Slots are reused round-robin,
+// skipping slots pinned by unacked frames.
+//
+// Sizing: slots ≥ shared-memory workers + 1 (one in flight per worker, one
+// being written); slot_size ≥ largest frame (6MB for 1080p RGB).
+//
+// Thread-safety: safe for concurrent use by server connections.
+type ShmRing struct {
+	path     string
+	mem      []byte // Read-write mapping of the segment
+	slots    int
+	slotSize int
+
+	mu    sync.Mutex
+	keys  []frameKey // Frame written in each slot (dedup, zero = empty)
+	pins  []int      // Unacked FRAME_REFs per slot
+	next  int        // Round-robin cursor
+	stats ShmStats
+}
+
+// frameKey identifies a distributed frame without retaining it (Frame.Seq is
+// assigned per stream at distribution, starting at 1).
+type frameKey struct {
+	streamID string
+	seq      uint64
+}
+
+// NewShmRing creates the segment file at path (replacing a stale one) and
+// maps it read-write.
+//
+// slotSize is rounded up to a multiple of 64. Close unmaps and removes it.
+// Returns ErrShmUnsupported on non-Linux platforms.
+func NewShmRing(path string, slots, slotSize int) (*ShmRing, error) {
+	if slots < 1 || slotSize < 1 {
+		return nil, fmt.Errorf("framesupplier transport: invalid ring size (slots=%d, slot_size=%d)", slots, slotSize)
+	}
+	slotSize = (slotSize + 63) &^ 63
+
+	mem, err := createSegment(path, shmHeaderSize+slots*(shmSlotHeaderSize+slotSize))
+	if err != nil {
+		return nil, err
+	}
+
+	copy(mem[0:8], shmMagic)
+	binary.LittleEndian.PutUint32(mem[8:], shmVersion)
+	binary.LittleEndian.PutUint32(mem[12:], uint32(slots))
+	binary.LittleEndian.PutUint64(mem[16:], uint64(slotSize))
+
+	return &ShmRing{
+		path:     path,
+		mem:      mem,
+		slots:    slots,
+		slotSize: slotSize,
+		keys:     make([]frameKey, slots),
+		pins:     make([]int, slots),
+	}, nil
+}
+
+// Path returns the segment file path (sent to workers in SUBSCRIBED).
+func (r *ShmRing) Path() string {
+	return r.path
+}
+
+// Stats returns the ring usage counters.
+func (r *ShmRing) Stats() ShmStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Close unmaps the segment and removes its file.
+//
+// Close the Server first: connections may still reference slots.
+func (r *ShmRing) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mem == nil {
+		return nil
+	}
+	err := unmapSegment(r.mem)
+	r.mem = nil
+	if rmErr := os.Remove(r.path); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// acquire pins a slot holding f, writing f into a free slot if needed.
+//
+// Returns ok=false (caller sends the frame inline) if f does not fit or all
+// slots are pinned.
+func (r *ShmRing) acquire(f *framesupplier.Frame) (slot int, seq uint64, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mem == nil || len(f.Data) > r.slotSize {
+		r.stats.Fallbacks++
+		return 0, 0, false
+	}
+
+	// Already written (same frame fanned out to another worker). Matching by
+	// key keeps no reference to f: the ring never pins frames on the Go heap.
+	key := frameKey{streamID: f.StreamID, seq: f.Seq}
+	for i, k := range r.keys {
+		if k == key {
+			r.pins[i]++
+			r.stats.Shared++
+			return i, atomic.LoadUint64(r.seqPtr(i)), true
+		}
+	}
+
+	for n := 0; n < r.slots; n++ {
+		i := (r.next + n) % r.slots
+		if r.pins[i] > 0 {
+			continue
+		}
+		r.next = (i + 1) % r.slots
+		r.keys[i] = key
+		r.pins[i] = 1
+		r.stats.Writes++
+		return i, r.write(i, f), true
+	}
+
+	r.stats.Fallbacks++
+	return 0, 0, false
+}
+
+// release unpins slot (ACK received or connection closed).
+func (r *ShmRing) release(slot int) {
+	r.mu.Lock()
+	r.pins[slot]--
+	r.mu.Unlock()
+}
+
+// write copies f into slot i under the seqlock and returns the stable seq.
+//
+// Caller holds r.mu (single writer).
+func (r *ShmRing) write(i int, f *framesupplier.Frame) uint64 {
+	seqp := r.seqPtr(i)
+	seq := atomic.LoadUint64(seqp)
+	atomic.StoreUint64(seqp, seq+1) // Odd: write in progress
+
+	hdr := r.mem[slotOffset(i, r.slotSize):]
+	binary.LittleEndian.PutUint32(hdr[8:], uint32(len(f.Data)))
+	binary.LittleEndian.PutUint32(hdr[12:], uint32(f.Width))
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(f.Height))
+	binary.LittleEndian.PutUint64(hdr[24:], f.Seq)
+	var ts int64
+	if !f.Timestamp.IsZero() {
+		ts = f.Timestamp.UnixNano()
+	}
+	binary.LittleEndian.PutUint64(hdr[32:], uint64(ts))
+	copy(hdr[shmSlotHeaderSize:shmSlotHeaderSize+r.slotSize], f.Data)
+
+	atomic.StoreUint64(seqp, seq+2) // Even: stable
+	return seq + 2
+}
+
+func (r *ShmRing) seqPtr(i int) *uint64 {
+	return (*uint64)(unsafe.Pointer(&r.mem[slotOffset(i, r.slotSize)]))
+}
+
+// slotOffset returns the offset of slot i's header.
+func slotOffset(i, slotSize int) int {
+	return shmHeaderSize + i*(shmSlotHeaderSize+slotSize)
+}
+
+// ShmReader is the reader side of a shared-memory ring (read-only mapping).
+//
+// Used by Client for FRAME_REF messages; usable directly by Go processes
+// that receive slot references by other means.
+//
+// Thread-safety: safe for concurrent use (read-only).
+type ShmReader struct {
+	mem      []byte
+	slots    int
+	slotSize int
+}
+
+// OpenShm maps the segment at path read-only and validates its header.
+//
+// Returns ErrShmUnsupported on non-Linux platforms.
+func OpenShm(path string) (*ShmReader, error) {
+	mem, err := openSegment(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(mem) < shmHeaderSize || string(mem[0:8]) != shmMagic ||
+		binary.LittleEndian.Uint32(mem[8:]) != shmVersion {
+		unmapSegment(mem)
+		return nil, fmt.Errorf("framesupplier transport: %s is not a frame ring (version %d)", path, shmVersion)
+	}
+
+	r := &ShmReader{
+		mem:      mem,
+		slots:    int(binary.LittleEndian.Uint32(mem[12:])),
+		slotSize: int(binary.LittleEndian.Uint64(mem[16:])),
+	}
+	if len(mem) < slotOffset(r.slots, r.slotSize) {
+		unmapSegment(mem)
+		return nil, fmt.Errorf("framesupplier transport: %s truncated", path)
+	}
+	return r, nil
+}
+
+// Slots returns the number of slots and the data capacity per slot.
+func (r *ShmReader) Slots() (slots, slotSize int) {
+	return r.slots, r.slotSize
+}
+
+// Frame returns the frame in slot if its seqlock still equals seq.
+//
+// Frame.Data aliases the read-only mapping (zero-copy; writing to it
+// faults). Call Valid after using the data to detect a concurrent rewrite.
+func (r *ShmReader) Frame(slot int, seq uint64) (*framesupplier.Frame, error) {
+	if slot < 0 || slot >= r.slots {
+		return nil, fmt.Errorf("framesupplier transport: slot %d out of range", slot)
+	}
+	if !r.Valid(slot, seq) {
+		return nil, ErrShmStale
+	}
+
+	hdr := r.mem[slotOffset(slot, r.slotSize):]
+	n := int(binary.LittleEndian.Uint32(hdr[8:]))
+	if n > r.slotSize {
+		return nil, ErrShmStale
+	}
+	f := &framesupplier.Frame{
+		Data:   hdr[shmSlotHeaderSize : shmSlotHeaderSize+n : shmSlotHeaderSize+n],
+		Width:  int(binary.LittleEndian.Uint32(hdr[12:])),
+		Height: int(binary.LittleEndian.Uint32(hdr[16:])),
+		Seq:    binary.LittleEndian.Uint64(hdr[24:]),
+	}
+	if ts := int64(binary.LittleEndian.Uint64(hdr[32:])); ts != 0 {
+		f.Timestamp = time.Unix(0, ts)
+	}
+
+	// Header fields read under the seqlock
+	if !r.Valid(slot, seq) {
+		return nil, ErrShmStale
+	}
+	return f, nil
+}
+
+// Valid reports whether slot still holds the write identified by seq.
+func (r *ShmReader) Valid(slot int, seq uint64) bool {
+	p := (*uint64)(unsafe.Pointer(&r.mem[slotOffset(slot, r.slotSize)]))
+	return seq&1 == 0 && atomic.LoadUint64(p) == seq
+}
+
+// Close unmaps the segment (frames returned by Frame become invalid).
+func (r *ShmReader) Close() error {
+	if r.mem == nil {
+		return nil
+	}
+	err := unmapSegment(r.mem)
+	r.mem = nil
+	return err
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package transport
+
+import (
+	"os"
+	"syscall"
+)
+
+// createSegment creates (or truncates a stale) segment file of size bytes
+// and maps it read-write (MAP_SHARED).
+//
+// Mode 0600: readers must run as the same user (or chmod the file).
+func createSegment(path string, size int) ([]byte, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // Mapping outlives the descriptor
+
+	if err := f.Truncate(int64(size)); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	mem, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return mem, nil
+}
+
+// openSegment maps an existing segment file read-only.
+func openSegment(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// unmapSegment releases a mapping.
+func unmapSegment(mem []byte) error {
+	return syscall.Munmap(mem)
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package transport
+
+// createSegment is not supported on this platform (workers use inline frames)
+func createSegment(path string, size int) ([]byte, error) {
+	return nil, ErrShmUnsupported
+}
+
+// openSegment is not supported on this platform
+func openSegment(path string) ([]byte, error) {
+	return nil, ErrShmUnsupported
+}
+
+// unmapSegment is a no-op (nothing is ever mapped)
+func unmapSegment(mem []byte) error {
+	return nil
+}
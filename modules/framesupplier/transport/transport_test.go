@@ -0,0 +1,324 @@
+package transport_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier"
+	"github.com/e7canasta/orion-care-sensor/modules/framesupplier/transport"
+)
+
+// startServer starts a supplier and a transport server on a temporary socket.
+func startServer(t *testing.T, opts ...transport.ServerOption) (framesupplier.Supplier, string) {
+	t.Helper()
+
+	supplier := framesupplier.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := supplier.Start(ctx); err != nil {
+		t.Fatalf("Start() failed: %v", err)
+	}
+
+	// Short dir: Unix socket paths are limited to ~104 bytes
+	dir, err := os.MkdirTemp("", "fs")
+	if err != nil {
+		t.Fatalf("MkdirTemp() err=%v", err)
+	}
+	path := filepath.Join(dir, "frames.sock")
+
+	srv := transport.NewServer(supplier, opts...)
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe(path) }()
+
+	// Wait for the socket
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("socket not created within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Cleanup(func() {
+		srv.Close()
+		if err := <-served; !errors.Is(err, transport.ErrServerClosed) {
+			t.Errorf("ListenAndServe() err=%v, want ErrServerClosed", err)
+		}
+		supplier.Stop()
+		cancel()
+		os.RemoveAll(dir)
+	})
+	return supplier, path
+}
+
+// dial subscribes workerID with a 1s handshake bound.
+func dial(t *testing.T, path string, req transport.SubscribeRequest, opts ...transport.DialOption) (*transport.Client, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return transport.Dial(ctx, path, req, opts...)
+}
+
+// publishAndWait publishes frame and waits until it was distributed.
+func publishAndWait(t *testing.T, supplier framesupplier.Supplier, frame *framesupplier.Frame) {
+	t.Helper()
+	before := supplier.Stats().Streams[frame.StreamID].LastSeq
+	supplier.Publish(frame)
+	deadline := time.Now().Add(time.Second)
+	for supplier.Stats().Streams[frame.StreamID].LastSeq == before {
+		if time.Now().After(deadline) {
+			t.Fatal("frame not distributed within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitWorker waits until the worker's stats satisfy cond.
+func waitWorker(t *testing.T, supplier framesupplier.Supplier, workerID string, cond func(framesupplier.WorkerStats, bool) bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		ws, ok := supplier.Stats().Workers[workerID]
+		if cond(ws, ok) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("worker %s stats not reached within 1s (subscribed=%v, %+v)", workerID, ok, ws)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// --- Test 1: Remote Mailbox Semantics ---
+
+// TestRemoteWorkerMailbox validates a remote worker behaves like an in-process one.
+//
+// Contract:
+//   - Frames arrive intact (data, dimensions, stream, timestamp, trace parent, seq)
+//   - One frame in flight: while the worker is busy the mailbox overwrites
+//   - The next frame after ACK is the freshest; overwritten frames are drops in Stats
+func TestRemoteWorkerMailbox(t *testing.T) {
+	supplier, path := startServer(t)
+
+	client, err := dial(t, path, transport.SubscribeRequest{WorkerID: "PyDetector", SLA: framesupplier.SLACritical})
+	if err != nil {
+		t.Fatalf("Dial() err=%v", err)
+	}
+	defer client.Close()
+	if client.Generation() != 1 {
+		t.Errorf("Generation() = %d, want 1", client.Generation())
+	}
+
+	ts := time.Now().Add(-20 * time.Millisecond)
+	publishAndWait(t, supplier, &framesupplier.Frame{
+		Data:        []byte("jpeg-1"),
+		Width:       640,
+		Height:      480,
+		Timestamp:   ts,
+		StreamID:    "",
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+
+	// First frame goes in flight immediately (worker ready after SUBSCRIBE)
+	waitWorker(t, supplier, "PyDetector", func(ws framesupplier.WorkerStats, _ bool) bool { return ws.Consumed == 1 })
+
+	// Worker busy: frames 2..6 overwrite the mailbox (4 drops)
+	for i := 2; i <= 6; i++ {
+		publishAndWait(t, supplier, &framesupplier.Frame{Data: []byte{byte(i)}, Width: 640, Height: 480})
+	}
+
+	frame, err := client.Next()
+	if err != nil {
+		t.Fatalf("Next() err=%v", err)
+	}
+	if string(frame.Data) != "jpeg-1" || frame.Width != 640 || frame.Height != 480 || frame.Seq != 1 ||
+		!frame.Timestamp.Equal(ts) || frame.TraceParent == "" {
+		t.Errorf("first frame = %+v, want jpeg-1 640x480 seq=1 with timestamp and trace parent", frame)
+	}
+
+	frame, err = client.Next() // ACKs frame 1
+	if err != nil {
+		t.Fatalf("Next() err=%v", err)
+	}
+	if frame.Seq != 6 || !bytes.Equal(frame.Data, []byte{6}) {
+		t.Errorf("second frame seq=%d, want freshest (6)", frame.Seq)
+	}
+
+	waitWorker(t, supplier, "PyDetector", func(ws framesupplier.WorkerStats, _ bool) bool { return ws.Consumed == 2 })
+	stat := supplier.Stats().Workers["PyDetector"]
+	if stat.TotalDrops != 4 || stat.SLA != framesupplier.SLACritical {
+		t.Errorf("stats TotalDrops=%d SLA=%v, want 4 drops, critical", stat.TotalDrops, stat.SLA)
+	}
+
+	t.Logf("✅ Remote mailbox validated (consumed=%d drops=%d)", stat.Consumed, stat.TotalDrops)
+}
+
+// --- Test 2: Remote Subscription Lifecycle ---
+
+// TestRemoteWorkerLifecycle validates registration errors and disconnects.
+//
+// Contract:
+//   - Duplicate worker ID → Dial returns ErrWorkerExists
+//   - Takeover → previous connection's Next returns ErrSubscriptionReplaced
+//   - Disconnect → worker unsubscribed
+//   - Supplier Stop → Next returns ErrSubscriptionClosed
+func TestRemoteWorkerLifecycle(t *testing.T) {
+	supplier, path := startServer(t)
+
+	first, err := dial(t, path, transport.SubscribeRequest{WorkerID: "VLM"})
+	if err != nil {
+		t.Fatalf("Dial() err=%v", err)
+	}
+	defer first.Close()
+
+	// In-process and remote workers share the worker ID namespace
+	if _, err := dial(t, path, transport.SubscribeRequest{WorkerID: "VLM"}); !errors.Is(err, framesupplier.ErrWorkerExists) {
+		t.Errorf("duplicate Dial() err=%v, want ErrWorkerExists", err)
+	}
+
+	second, err := dial(t, path, transport.SubscribeRequest{WorkerID: "VLM", Takeover: true})
+	if err != nil {
+		t.Fatalf("takeover Dial() err=%v", err)
+	}
+	if second.Generation() != 2 {
+		t.Errorf("takeover Generation() = %d, want 2", second.Generation())
+	}
+	if _, err := first.Next(); !errors.Is(err, framesupplier.ErrSubscriptionReplaced) {
+		t.Errorf("replaced Next() err=%v, want ErrSubscriptionReplaced", err)
+	}
+
+	// Disconnect unsubscribes
+	second.Close()
+	waitWorker(t, supplier, "VLM", func(_ framesupplier.WorkerStats, ok bool) bool { return !ok })
+
+	// Stop ends remote sessions
+	third, err := dial(t, path, transport.SubscribeRequest{WorkerID: "Tracker", Streams: []string{"cam-1"}})
+	if err != nil {
+		t.Fatalf("Dial() err=%v", err)
+	}
+	defer third.Close()
+	if ws := supplier.Stats().Workers["Tracker"]; len(ws.Streams) != 1 || ws.Streams[0] != "cam-1" {
+		t.Errorf("Tracker streams = %v, want [cam-1]", ws.Streams)
+	}
+	supplier.Stop()
+	if _, err := third.Next(); !errors.Is(err, framesupplier.ErrSubscriptionClosed) {
+		t.Errorf("Next() after Stop err=%v, want ErrSubscriptionClosed", err)
+	}
+
+	t.Logf("✅ Remote lifecycle validated")
+}
+
+// --- Test 3: Protocol Errors ---
+
+// TestProtocolError validates malformed sessions are rejected with an ERROR message.
+//
+// Contract:
+//   - A first message other than SUBSCRIBE → ERROR code 1 (protocol), then close
+//   - Client: a message above WithMaxMessageSize fails Next (no allocation)
+func TestProtocolError(t *testing.T) {
+	supplier, path := startServer(t)
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial() err=%v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(time.Second))
+
+	// ACK (type 0x04, seq 0) instead of SUBSCRIBE
+	msg := []byte{0, 0, 0, 9, 0x04, 0, 0, 0, 0, 0, 0, 0, 0}
+	if _, err := conn.Write(msg); err != nil {
+		t.Fatalf("Write() err=%v", err)
+	}
+
+	var hdr [6]byte // length u32, type u8, code u8
+	if _, err := io.ReadFull(conn, hdr[:]); err != nil {
+		t.Fatalf("read ERROR err=%v", err)
+	}
+	if hdr[4] != 0x05 || transport.Code(hdr[5]) != transport.CodeProtocol {
+		t.Errorf("reply type=%#x code=%d, want ERROR (0x05) code 1", hdr[4], hdr[5])
+	}
+	rest := make([]byte, binary.BigEndian.Uint32(hdr[:4])-2)
+	io.ReadFull(conn, rest)
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("connection not closed after ERROR (err=%v)", err)
+	}
+
+	// Client-side bound (frame of 1KB, limit 512B)
+	client, err := dial(t, path, transport.SubscribeRequest{WorkerID: "Bounded"}, transport.WithMaxMessageSize(512))
+	if err != nil {
+		t.Fatalf("Dial() err=%v", err)
+	}
+	defer client.Close()
+	publishAndWait(t, supplier, &framesupplier.Frame{Data: make([]byte, 1024)})
+	if frame, err := client.Next(); err == nil {
+		t.Errorf("Next() = %d bytes, want error above WithMaxMessageSize", len(frame.Data))
+	}
+
+	t.Logf("✅ Protocol error validated")
+}
+
+// --- Test 5: Socket Path Ownership ---
+
+// TestListenAndServeSocketInUse validates only stale socket files are replaced.
+//
+// Contract:
+//   - A socket a live server accepts on → ErrAddrInUse, the live server keeps serving
+//   - A socket file nobody listens on (crashed process) → removed and served
+func TestListenAndServeSocketInUse(t *testing.T) {
+	supplier, path := startServer(t)
+
+	second := transport.NewServer(supplier)
+	if err := second.ListenAndServe(path); !errors.Is(err, transport.ErrAddrInUse) {
+		t.Fatalf("second ListenAndServe() err=%v, want ErrAddrInUse", err)
+	}
+	client, err := dial(t, path, transport.SubscribeRequest{WorkerID: "StillServed"})
+	if err != nil {
+		t.Fatalf("Dial() to first server err=%v", err)
+	}
+	client.Close()
+
+	// Stale socket: listener closed without unlinking the file
+	stale := filepath.Join(filepath.Dir(path), "stale.sock")
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: stale, Net: "unix"})
+	if err != nil {
+		t.Fatalf("ListenUnix() err=%v", err)
+	}
+	ln.SetUnlinkOnClose(false)
+	ln.Close()
+
+	srv := transport.NewServer(supplier)
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe(stale) }()
+	defer func() {
+		srv.Close()
+		if err := <-served; !errors.Is(err, transport.ErrServerClosed) {
+			t.Errorf("ListenAndServe(stale) err=%v, want ErrServerClosed", err)
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		client, err := dial(t, stale, transport.SubscribeRequest{WorkerID: "Recovered"})
+		if err == nil {
+			client.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("stale socket not replaced within 1s (last err=%v)", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Logf("✅ Socket path ownership validated")
+}
@@ -0,0 +1,477 @@
+package streamcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/degrade"
+)
+
+const (
+	defaultDegradeInterval      = 5 * time.Second
+	defaultDegradeMaxDropRate   = 10.0
+	defaultDegradeRecoverDrop   = 2.0
+	defaultDegradeMaxCPU        = 85.0
+	defaultDegradeRecoverCPU    = 60.0
+	defaultDegradeStepDownAfter = 2
+	defaultDegradeStepUpAfter   = 6
+)
+
+// AdaptiveTarget is a stream whose source settings can change while running
+//
+// Implemented by RTSPStream (see RTSPStream.Reconfigure).
+type AdaptiveTarget interface {
+	StreamProvider
+	Settings() StreamSettings
+	Reconfigure(settings StreamSettings) error
+}
+
+// DegradationRung is one step of the degradation ladder
+//
+// Rungs are cumulative: each inherits the settings of the previous rung
+// (or the stream's own settings for the first) and overrides what it sets.
+type DegradationRung struct {
+	// Name identifies the rung in events and stats (default: "rung-N")
+	Name string
+	// TargetFPS caps the frame rate (0 = inherit)
+	// Never raises the FPS above the current target
+	TargetFPS float64
+	// Resolution changes the output resolution (nil = inherit)
+	Resolution *Resolution
+	// SourceURL switches the camera URL, e.g. to a substream ("" = inherit)
+	SourceURL string
+}
+
+// DegradationConfig configures adaptive degradation under CPU pressure
+type DegradationConfig struct {
+	// Ladder lists the degraded rungs, mildest first (required)
+	// Level 0 is the stream's own configuration; level N applies Ladder[0..N-1]
+	Ladder []DegradationRung
+	// Interval is the sampling period (default: 5s)
+	// Set to 0 to use default value
+	Interval time.Duration
+
+	// MaxDropRate steps down when more than this percent of frames is dropped
+	// in an interval (default: 10); RecoverDropRate allows stepping up (default: 2)
+	MaxDropRate     float64
+	RecoverDropRate float64
+	// MaxDecodeLatencyMS steps down when decode latency p95 exceeds it
+	// (default: 0 = disabled; only available with VAAPI or MJPEG decode)
+	// RecoverDecodeLatencyMS allows stepping up (default: half of the max)
+	MaxDecodeLatencyMS     float64
+	RecoverDecodeLatencyMS float64
+	// MaxCPUPercent steps down when process CPU (percent of all cores) exceeds
+	// it (default: 85); RecoverCPUPercent allows stepping up (default: 60)
+	MaxCPUPercent     float64
+	RecoverCPUPercent float64
+
+	// StepDownAfter is the number of consecutive intervals under pressure
+	// before stepping down (default: 2)
+	StepDownAfter int
+	// StepUpAfter is the number of consecutive intervals with headroom
+	// before stepping back up (default: 6)
+	StepUpAfter int
+
+	// OnStep is called after every level change (and failed change) from the
+	// monitoring goroutine. Must not block.
+	OnStep func(DegradationEvent)
+}
+
+// Validate checks if the configuration is valid
+//
+// Returns an error if:
+//   - Ladder is empty
+//   - A rung FPS is outside valid range (0.1-30.0) or a resolution is unknown
+//   - A threshold or count is negative, or a recover value exceeds its max
+func (c DegradationConfig) Validate() error {
+	if len(c.Ladder) == 0 {
+		return fmt.Errorf("degradation ladder is empty")
+	}
+
+	for i, rung := range c.Ladder {
+		if rung.TargetFPS != 0 && (rung.TargetFPS < 0.1 || rung.TargetFPS > 30) {
+			return fmt.Errorf("rung %d: invalid FPS %.2f (must be 0.1-30)", i+1, rung.TargetFPS)
+		}
+		if rung.Resolution != nil && (*rung.Resolution < Res480p || *rung.Resolution > Res1080p) {
+			return fmt.Errorf("rung %d: invalid resolution %d", i+1, int(*rung.Resolution))
+		}
+	}
+
+	if c.Interval < 0 || c.StepDownAfter < 0 || c.StepUpAfter < 0 {
+		return fmt.Errorf("degradation interval and step counts must be >= 0")
+	}
+
+	limits := []struct {
+		name         string
+		max, recover float64
+	}{
+		{"drop rate", c.MaxDropRate, c.RecoverDropRate},
+		{"decode latency", c.MaxDecodeLatencyMS, c.RecoverDecodeLatencyMS},
+		{"cpu", c.MaxCPUPercent, c.RecoverCPUPercent},
+	}
+	for _, l := range limits {
+		if l.max < 0 || l.recover < 0 {
+			return fmt.Errorf("invalid %s threshold (must be >= 0)", l.name)
+		}
+		if l.max > 0 && l.recover > l.max {
+			return fmt.Errorf("%s recover threshold %.1f exceeds max %.1f", l.name, l.recover, l.max)
+		}
+	}
+
+	return nil
+}
+
+// DegradationEvent describes a step on the degradation ladder
+type DegradationEvent struct {
+	// Time is when the step was taken
+	Time time.Time
+	// FromLevel and ToLevel are ladder levels (0 = full quality)
+	FromLevel int
+	ToLevel   int
+	// Rung is the name of the target rung ("" for level 0)
+	Rung string
+	// Reason describes the triggering signal (e.g. "cpu 91.2% > 85.0%")
+	Reason string
+	// Settings are the source settings applied for ToLevel
+	Settings StreamSettings
+	// DropRate, DecodeLatencyP95MS and CPUPercent are the interval measurements
+	DropRate           float64
+	DecodeLatencyP95MS float64
+	CPUPercent         float64
+	// Err is set if the settings could not be applied (level unchanged)
+	Err error
+}
+
+// AdaptiveStream steps a stream down a degradation ladder under pressure
+//
+// Every interval, AdaptiveStream measures the stream's own frame drop rate
+// (over the interval, not since start), decode latency p95 and the process
+// CPU usage. After StepDownAfter intervals over a limit it applies the next
+// rung (lower FPS, lower resolution, substream URL); after StepUpAfter
+// intervals with headroom on every signal it steps back up. Each step is
+// logged, reported to OnStep, and visible in Stats().DegradationLevel.
+//
+// FPS-only rungs are applied by caps renegotiation; resolution and URL
+// changes rebuild the pipeline (~3s) on the same frame channel.
+//
+// Example:
+//
+//	res := streamcapture.Res512p
+//	adaptive, _ := streamcapture.NewAdaptiveStream(stream, streamcapture.DegradationConfig{
+//	    Ladder: []streamcapture.DegradationRung{
+//	        {Name: "low-fps", TargetFPS: 1},
+//	        {Name: "low-res", Resolution: &res},
+//	        {Name: "substream", SourceURL: "rtsp://camera/sub"},
+//	    },
+//	})
+//	frames, _ := adaptive.Start(ctx)
+//
+// The level survives Stop()/Start(); the wrapped stream keeps the settings
+// of the current rung.
+type AdaptiveStream struct {
+	StreamProvider
+
+	target AdaptiveTarget
+	cfg    DegradationConfig
+
+	// Injected dependencies (see Option)
+	logger    *slog.Logger
+	clock     Clock
+	cpuSample func(now time.Time) (float64, bool)
+
+	// Ladder state (mu serializes decisions and Reconfigure calls)
+	mu          sync.Mutex
+	ctrl        *degrade.Controller
+	baseline    StreamSettings // Level 0 settings (SetTargetFPS updates the FPS)
+	lastFrames  uint64
+	lastDropped uint64
+	level       atomic.Int32 // Current level (lock-free for Stats)
+
+	// Lifecycle
+	lifecycle sync.Mutex
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewAdaptiveStream wraps target with adaptive degradation
+//
+// Options: WithLogger, WithClock (sampling interval).
+func NewAdaptiveStream(target AdaptiveTarget, cfg DegradationConfig, opts ...Option) (*AdaptiveStream, error) {
+	if target == nil {
+		return nil, fmt.Errorf("stream-capture: adaptive stream requires a target stream")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	if cfg.Interval == 0 {
+		cfg.Interval = defaultDegradeInterval
+	}
+	if cfg.MaxDropRate == 0 {
+		cfg.MaxDropRate = defaultDegradeMaxDropRate
+		if cfg.RecoverDropRate == 0 {
+			cfg.RecoverDropRate = defaultDegradeRecoverDrop
+		}
+	}
+	if cfg.MaxDecodeLatencyMS > 0 && cfg.RecoverDecodeLatencyMS == 0 {
+		cfg.RecoverDecodeLatencyMS = cfg.MaxDecodeLatencyMS / 2
+	}
+	if cfg.MaxCPUPercent == 0 {
+		cfg.MaxCPUPercent = defaultDegradeMaxCPU
+		if cfg.RecoverCPUPercent == 0 {
+			cfg.RecoverCPUPercent = defaultDegradeRecoverCPU
+		}
+	}
+	if cfg.StepDownAfter == 0 {
+		cfg.StepDownAfter = defaultDegradeStepDownAfter
+	}
+	if cfg.StepUpAfter == 0 {
+		cfg.StepUpAfter = defaultDegradeStepUpAfter
+	}
+
+	s := &AdaptiveStream{
+		StreamProvider: target,
+		target:         target,
+		cfg:            cfg,
+		logger:         options.logger,
+		clock:          options.clock,
+		cpuSample:      degrade.NewCPUSampler().Sample,
+		baseline:       target.Settings(),
+		ctrl: degrade.New(degrade.Thresholds{
+			MaxDropRate:        cfg.MaxDropRate,
+			RecoverDropRate:    cfg.RecoverDropRate,
+			MaxDecodeP95MS:     cfg.MaxDecodeLatencyMS,
+			RecoverDecodeP95MS: cfg.RecoverDecodeLatencyMS,
+			MaxCPUPercent:      cfg.MaxCPUPercent,
+			RecoverCPUPercent:  cfg.RecoverCPUPercent,
+			StepDownAfter:      cfg.StepDownAfter,
+			StepUpAfter:        cfg.StepUpAfter,
+		}, len(cfg.Ladder)),
+	}
+
+	// Rungs must be applicable to the target's settings (fail-fast)
+	for level := 1; level <= len(cfg.Ladder); level++ {
+		if err := s.settingsFor(level).Validate(); err != nil {
+			return nil, fmt.Errorf("stream-capture: rung %d (%s): %w", level, s.rungName(level), err)
+		}
+	}
+
+	s.logger.Info("stream-capture: adaptive degradation enabled",
+		"rungs", len(cfg.Ladder),
+		"interval", cfg.Interval,
+		"max_drop_rate", cfg.MaxDropRate,
+		"max_decode_latency_ms", cfg.MaxDecodeLatencyMS,
+		"max_cpu_percent", cfg.MaxCPUPercent,
+	)
+
+	return s, nil
+}
+
+// settingsFor returns the source settings of a level (baseline + rungs)
+func (s *AdaptiveStream) settingsFor(level int) StreamSettings {
+	settings := s.baseline
+	for _, rung := range s.cfg.Ladder[:level] {
+		if rung.TargetFPS > 0 && rung.TargetFPS < settings.TargetFPS {
+			settings.TargetFPS = rung.TargetFPS
+		}
+		if rung.Resolution != nil {
+			settings.Resolution = *rung.Resolution
+		}
+		if rung.SourceURL != "" {
+			settings.URL = rung.SourceURL
+		}
+	}
+	return settings
+}
+
+// rungName returns the name of a level ("" for level 0)
+func (s *AdaptiveStream) rungName(level int) string {
+	if level <= 0 || level > len(s.cfg.Ladder) {
+		return ""
+	}
+	if name := s.cfg.Ladder[level-1].Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("rung-%d", level)
+}
+
+// Start starts the wrapped stream and the monitoring goroutine
+func (s *AdaptiveStream) Start(ctx context.Context) (<-chan Frame, error) {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.done != nil {
+		return nil, fmt.Errorf("stream-capture: stream already started")
+	}
+
+	frames, err := s.target.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Interval deltas start from the current counters
+	stats := s.target.Stats()
+	s.mu.Lock()
+	s.lastFrames, s.lastDropped = stats.FrameCount, stats.FramesDropped
+	s.mu.Unlock()
+	s.cpuSample(s.clock.Now())
+
+	s.done = make(chan struct{})
+	s.wg.Add(1)
+	go s.monitor(s.done)
+
+	return frames, nil
+}
+
+// monitor samples the stream every interval until done is closed
+func (s *AdaptiveStream) monitor(done <-chan struct{}) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-s.clock.After(s.cfg.Interval):
+			s.observe()
+		}
+	}
+}
+
+// observe takes one sample and applies a level change if needed
+func (s *AdaptiveStream) observe() {
+	stats := s.target.Stats()
+	now := s.clock.Now()
+	cpu, hasCPU := s.cpuSample(now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Windowed drop rate (counters may have been reset, see ResetStats)
+	frames, dropped := stats.FrameCount, stats.FramesDropped
+	if frames < s.lastFrames || dropped < s.lastDropped {
+		s.lastFrames, s.lastDropped = 0, 0
+	}
+	deltaFrames, deltaDropped := frames-s.lastFrames, dropped-s.lastDropped
+	s.lastFrames, s.lastDropped = frames, dropped
+
+	var dropRate float64
+	if total := deltaFrames + deltaDropped; total > 0 {
+		dropRate = float64(deltaDropped) / float64(total) * 100
+	}
+
+	sample := degrade.Sample{
+		DropRate:    dropRate,
+		DecodeP95MS: stats.DecodeLatencyP95MS,
+		HasDecode:   stats.DecodeLatencyP95MS > 0,
+		CPUPercent:  cpu,
+		HasCPU:      hasCPU,
+	}
+
+	from := s.ctrl.Level()
+	to, reason, changed := s.ctrl.Observe(sample)
+	if !changed {
+		return
+	}
+
+	settings := s.settingsFor(to)
+	event := DegradationEvent{
+		Time:               now,
+		FromLevel:          from,
+		ToLevel:            to,
+		Rung:               s.rungName(to),
+		Reason:             reason,
+		Settings:           settings,
+		DropRate:           dropRate,
+		DecodeLatencyP95MS: stats.DecodeLatencyP95MS,
+		CPUPercent:         cpu,
+	}
+
+	if err := s.target.Reconfigure(settings); err != nil {
+		s.ctrl.SetLevel(from)
+		event.Err = err
+		s.logger.Error("stream-capture: degradation step failed, level unchanged",
+			"error", err,
+			"from_level", from,
+			"to_level", to,
+			"reason", reason,
+		)
+	} else {
+		s.level.Store(int32(to))
+		s.logger.Warn("stream-capture: degradation level changed",
+			"from_level", from,
+			"to_level", to,
+			"rung", event.Rung,
+			"reason", reason,
+			"url", RedactURL(settings.URL),
+			"resolution", settings.Resolution.String(),
+			"target_fps", settings.TargetFPS,
+		)
+	}
+
+	if s.cfg.OnStep != nil {
+		s.cfg.OnStep(event)
+	}
+}
+
+// Stop stops the monitoring goroutine and the wrapped stream
+//
+// Idempotent. The current level is kept for the next Start().
+func (s *AdaptiveStream) Stop() error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.done != nil {
+		close(s.done)
+		s.done = nil
+		s.wg.Wait()
+	}
+
+	return s.target.Stop()
+}
+
+// SetTargetFPS changes the full-quality FPS
+//
+// The effective FPS is capped by the current rung: while degraded to a
+// lower FPS, the new value applies once the stream steps back up.
+func (s *AdaptiveStream) SetTargetFPS(fps float64) error {
+	if fps < 0.1 || fps > 30 {
+		return fmt.Errorf(
+			"stream-capture: invalid FPS %.2f (must be 0.1-30)",
+			fps,
+		)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.baseline.TargetFPS
+	s.baseline.TargetFPS = fps
+	if err := s.target.Reconfigure(s.settingsFor(s.ctrl.Level())); err != nil {
+		s.baseline.TargetFPS = old
+		return err
+	}
+	return nil
+}
+
+// Stats returns the wrapped stream statistics with the current ladder level
+func (s *AdaptiveStream) Stats() StreamStats {
+	stats := s.target.Stats()
+	level := int(s.level.Load())
+	stats.DegradationLevel = level
+	stats.DegradationRung = s.rungName(level)
+	return stats
+}
+
+// Level returns the current ladder level (0 = full quality)
+func (s *AdaptiveStream) Level() int {
+	return int(s.level.Load())
+}
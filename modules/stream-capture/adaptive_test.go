@@ -0,0 +1,296 @@
+package streamcapture
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+)
+
+// RTSPStream supports pipeline reconfiguration (compile-time check)
+var _ AdaptiveTarget = (*RTSPStream)(nil)
+
+// fakeAdaptiveTarget records Reconfigure calls and serves settable stats
+type fakeAdaptiveTarget struct {
+	chanProvider
+
+	mu          sync.Mutex
+	settings    StreamSettings
+	stats       StreamStats
+	applied     []StreamSettings
+	reconfigErr error
+}
+
+func (f *fakeAdaptiveTarget) Stats() StreamStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+func (f *fakeAdaptiveTarget) Settings() StreamSettings {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.settings
+}
+
+func (f *fakeAdaptiveTarget) Reconfigure(settings StreamSettings) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.reconfigErr != nil {
+		return f.reconfigErr
+	}
+	f.settings = settings
+	f.applied = append(f.applied, settings)
+	return nil
+}
+
+// addFrames advances the fake counters by delivered and dropped frames
+func (f *fakeAdaptiveTarget) addFrames(delivered, dropped uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats.FrameCount += delivered
+	f.stats.FramesDropped += dropped
+}
+
+func newFakeAdaptiveTarget() *fakeAdaptiveTarget {
+	return &fakeAdaptiveTarget{
+		chanProvider: chanProvider{frames: make(chan Frame)},
+		settings: StreamSettings{
+			URL:        "rtsp://camera/main",
+			Resolution: Res1080p,
+			TargetFPS:  5,
+		},
+	}
+}
+
+func testLadder() []DegradationRung {
+	res := Res720p
+	return []DegradationRung{
+		{Name: "low-fps", TargetFPS: 2},
+		{Name: "low-res", Resolution: &res},
+		{Name: "substream", SourceURL: "rtsp://camera/sub"},
+	}
+}
+
+// tick waits for the monitor to block on the fake clock and advances one interval
+func tick(t *testing.T, fake *clock.Fake, interval time.Duration) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for fake.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("monitor goroutine not waiting on clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	fake.Advance(interval)
+
+	// Wait until the sample was processed (monitor waits on the clock again)
+	for fake.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("monitor goroutine did not finish the sample")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestDegradationConfig_Validate(t *testing.T) {
+	bad := Resolution(42)
+	tests := []struct {
+		name    string
+		cfg     DegradationConfig
+		wantErr bool
+	}{
+		{"valid", DegradationConfig{Ladder: testLadder()}, false},
+		{"empty ladder", DegradationConfig{}, true},
+		{"invalid fps", DegradationConfig{Ladder: []DegradationRung{{TargetFPS: 60}}}, true},
+		{"invalid resolution", DegradationConfig{Ladder: []DegradationRung{{Resolution: &bad}}}, true},
+		{"negative threshold", DegradationConfig{Ladder: testLadder(), MaxCPUPercent: -1}, true},
+		{"recover above max", DegradationConfig{Ladder: testLadder(), MaxCPUPercent: 50, RecoverCPUPercent: 70}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdaptiveStream_StepsDownAndUp(t *testing.T) {
+	target := newFakeAdaptiveTarget()
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+
+	var mu sync.Mutex
+	var events []DegradationEvent
+
+	adaptive, err := NewAdaptiveStream(target, DegradationConfig{
+		Ladder:        testLadder(),
+		Interval:      time.Second,
+		StepDownAfter: 1,
+		StepUpAfter:   1,
+		OnStep: func(e DegradationEvent) {
+			mu.Lock()
+			events = append(events, e)
+			mu.Unlock()
+		},
+	}, WithClock(fake))
+	if err != nil {
+		t.Fatalf("NewAdaptiveStream() error = %v", err)
+	}
+
+	cpu := 95.0
+	adaptive.cpuSample = func(time.Time) (float64, bool) { return cpu, true }
+
+	if _, err := adaptive.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer adaptive.Stop()
+
+	// Pressure: step down through the whole ladder (each step followed by a settle sample)
+	for i := 0; i < 8; i++ {
+		tick(t, fake, time.Second)
+	}
+
+	stats := adaptive.Stats()
+	if stats.DegradationLevel != 3 || stats.DegradationRung != "substream" {
+		t.Fatalf("Stats() level/rung = %d %q, want 3 substream", stats.DegradationLevel, stats.DegradationRung)
+	}
+	want := StreamSettings{URL: "rtsp://camera/sub", Resolution: Res720p, TargetFPS: 2}
+	if got := target.Settings(); got != want {
+		t.Errorf("target settings = %+v, want %+v", got, want)
+	}
+
+	// Headroom: step back up to full quality
+	cpu = 10
+	for i := 0; i < 8; i++ {
+		tick(t, fake, time.Second)
+	}
+
+	if adaptive.Level() != 0 {
+		t.Fatalf("Level() = %d after recovery, want 0", adaptive.Level())
+	}
+	want = StreamSettings{URL: "rtsp://camera/main", Resolution: Res1080p, TargetFPS: 5}
+	if got := target.Settings(); got != want {
+		t.Errorf("target settings = %+v, want %+v", got, want)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	wantLevels := [][2]int{{0, 1}, {1, 2}, {2, 3}, {3, 2}, {2, 1}, {1, 0}}
+	if len(events) != len(wantLevels) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantLevels))
+	}
+	for i, e := range events {
+		if e.FromLevel != wantLevels[i][0] || e.ToLevel != wantLevels[i][1] || e.Reason == "" || e.Err != nil {
+			t.Errorf("event %d = %d→%d %q err=%v, want %d→%d", i,
+				e.FromLevel, e.ToLevel, e.Reason, e.Err, wantLevels[i][0], wantLevels[i][1])
+		}
+	}
+}
+
+func TestAdaptiveStream_WindowedDropRate(t *testing.T) {
+	target := newFakeAdaptiveTarget()
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+
+	// Heavy drops before Start must not count against the stream
+	target.addFrames(100, 900)
+
+	adaptive, err := NewAdaptiveStream(target, DegradationConfig{
+		Ladder:        testLadder(),
+		Interval:      time.Second,
+		StepDownAfter: 1,
+	}, WithClock(fake))
+	if err != nil {
+		t.Fatalf("NewAdaptiveStream() error = %v", err)
+	}
+	adaptive.cpuSample = func(time.Time) (float64, bool) { return 0, false }
+
+	if _, err := adaptive.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer adaptive.Stop()
+
+	target.addFrames(100, 1)
+	tick(t, fake, time.Second)
+	if adaptive.Level() != 0 {
+		t.Fatalf("Level() = %d with 1%% interval drop rate, want 0", adaptive.Level())
+	}
+
+	target.addFrames(50, 50)
+	tick(t, fake, time.Second)
+	if adaptive.Level() != 1 {
+		t.Fatalf("Level() = %d with 50%% interval drop rate, want 1", adaptive.Level())
+	}
+}
+
+func TestAdaptiveStream_ReconfigureFailure(t *testing.T) {
+	target := newFakeAdaptiveTarget()
+	target.reconfigErr = errors.New("rebuild failed")
+	fake := clock.NewFake(time.Unix(1700000000, 0))
+
+	events := make(chan DegradationEvent, 10)
+	adaptive, err := NewAdaptiveStream(target, DegradationConfig{
+		Ladder:        testLadder(),
+		Interval:      time.Second,
+		StepDownAfter: 1,
+		OnStep:        func(e DegradationEvent) { events <- e },
+	}, WithClock(fake))
+	if err != nil {
+		t.Fatalf("NewAdaptiveStream() error = %v", err)
+	}
+	adaptive.cpuSample = func(time.Time) (float64, bool) { return 99, true }
+
+	if _, err := adaptive.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer adaptive.Stop()
+
+	tick(t, fake, time.Second)
+
+	select {
+	case e := <-events:
+		if e.Err == nil {
+			t.Error("event Err = nil, want reconfigure error")
+		}
+	default:
+		t.Fatal("no event for failed step")
+	}
+	if adaptive.Level() != 0 {
+		t.Errorf("Level() = %d after failed step, want 0", adaptive.Level())
+	}
+}
+
+func TestAdaptiveStream_SetTargetFPSCappedByRung(t *testing.T) {
+	target := newFakeAdaptiveTarget()
+	adaptive, err := NewAdaptiveStream(target, DegradationConfig{Ladder: testLadder()})
+	if err != nil {
+		t.Fatalf("NewAdaptiveStream() error = %v", err)
+	}
+
+	if err := adaptive.SetTargetFPS(10); err != nil {
+		t.Fatalf("SetTargetFPS() error = %v", err)
+	}
+	if got := target.Settings().TargetFPS; got != 10 {
+		t.Errorf("target FPS = %.1f at level 0, want 10", got)
+	}
+
+	// Degraded to low-fps: new baseline is capped by the rung
+	adaptive.mu.Lock()
+	adaptive.ctrl.SetLevel(1)
+	adaptive.mu.Unlock()
+
+	if err := adaptive.SetTargetFPS(8); err != nil {
+		t.Fatalf("SetTargetFPS() error = %v", err)
+	}
+	if got := target.Settings().TargetFPS; got != 2 {
+		t.Errorf("target FPS = %.1f at low-fps rung, want 2", got)
+	}
+	if err := adaptive.SetTargetFPS(100); err == nil {
+		t.Error("SetTargetFPS(100) succeeded, want error")
+	}
+}
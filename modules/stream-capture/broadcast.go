@@ -0,0 +1,428 @@
+package streamcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DropPolicy selects which frame a subscriber loses when its buffer is full
+type DropPolicy int
+
+const (
+	// DropNewest discards the incoming frame (same as StreamProvider channels)
+	DropNewest DropPolicy = iota
+	// DropOldest evicts the oldest buffered frame to make room for the new one
+	// (consumer always gets the freshest frames, at the cost of gaps)
+	DropOldest
+)
+
+// String returns the policy name
+func (p DropPolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop-oldest"
+	default:
+		return "drop-newest"
+	}
+}
+
+// SubscribeConfig configures an independent consumer of a Broadcaster
+type SubscribeConfig struct {
+	// Name identifies the subscriber in logs and stats (e.g., "recorder", "preview")
+	Name string
+	// BufferSize is the subscriber channel capacity (default: WithFrameBufferSize, 10)
+	// Set to 0 to use default value
+	BufferSize int
+	// Policy selects which frame is dropped when the buffer is full (default: DropNewest)
+	Policy DropPolicy
+}
+
+// SubscriptionStats contains per-subscriber delivery statistics
+type SubscriptionStats struct {
+	// Name is the subscriber name
+	Name string
+	// Policy is the drop policy
+	Policy DropPolicy
+	// BufferSize is the channel capacity
+	BufferSize int
+	// Buffered is the number of frames waiting in the channel
+	Buffered int
+	// Delivered is the number of frames placed in the channel
+	Delivered uint64
+	// Dropped is the number of frames this subscriber lost (buffer full);
+	// with DropOldest, evicted frames are counted here too
+	Dropped uint64
+}
+
+// Subscription is one consumer attached to a Broadcaster
+//
+// Each subscription has its own channel, buffer depth, drop policy and
+// counters: a slow subscriber only loses its own frames.
+type Subscription struct {
+	b      *Broadcaster
+	name   string
+	policy DropPolicy
+	frames chan Frame
+	closed bool // Guarded by b.mu
+
+	delivered uint64
+	dropped   uint64
+}
+
+// Broadcaster fans out a StreamProvider's frames to multiple subscribers
+//
+// A provider's channel has a single reader: two consumers reading it (e.g.
+// framesupplier and a Recorder, or Warmup and the application) silently
+// steal frames from each other. Broadcaster reads the channel once and
+// delivers every frame to each Subscription.
+//
+// Broadcaster is itself a StreamProvider: Start returns the channel of a
+// default subscription (frame buffer size from WithFrameBufferSize,
+// DropNewest), so it is a drop-in replacement. Additional consumers call
+// Subscribe:
+//
+//	b, _ := streamcapture.NewBroadcaster(stream)
+//	frames, _ := b.Start(ctx) // default consumer
+//	rec, _ := b.Subscribe(streamcapture.SubscribeConfig{
+//	    Name:       "recorder",
+//	    BufferSize: 30,
+//	    Policy:     streamcapture.DropOldest,
+//	})
+//	go consume(rec.Frames())
+//
+// Frame.Data is shared by all subscribers and must be treated as read-only.
+// Subscription channels close on Stop() or when the provider's channel
+// closes; Subscribe again after a restart.
+type Broadcaster struct {
+	StreamProvider
+
+	// Injected dependencies (see Option)
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+
+	// Subscribers (fan-out holds mu while delivering a frame)
+	mu      sync.Mutex
+	subs    []*Subscription
+	primary *Subscription
+
+	// Lifecycle (serializes Start/Stop; done is guarded by lifecycle)
+	lifecycle sync.Mutex
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewBroadcaster wraps provider for multi-subscriber fan-out
+//
+// Options: WithLogger, WithClock (warmup), WithFrameBufferSize (default
+// subscription capacity, also the default for SubscribeConfig.BufferSize).
+func NewBroadcaster(provider StreamProvider, opts ...Option) (*Broadcaster, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("stream-capture: broadcaster requires a stream provider")
+	}
+
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	return &Broadcaster{
+		StreamProvider:  provider,
+		logger:          options.logger,
+		clock:           options.clock,
+		frameBufferSize: options.frameBufferSize,
+	}, nil
+}
+
+// Start starts the wrapped provider and returns the default subscription channel
+//
+// Subscriptions created before Start receive frames from the first one.
+func (b *Broadcaster) Start(ctx context.Context) (<-chan Frame, error) {
+	b.lifecycle.Lock()
+	defer b.lifecycle.Unlock()
+
+	if b.done != nil {
+		return nil, fmt.Errorf("stream-capture: stream already started")
+	}
+
+	in, err := b.StreamProvider.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	primary := b.addLocked(SubscribeConfig{Name: "default", BufferSize: b.frameBufferSize})
+	b.primary = primary
+	subscribers := len(b.subs)
+	b.mu.Unlock()
+
+	b.logger.Info("stream-capture: broadcaster started",
+		"subscribers", subscribers,
+	)
+
+	b.done = make(chan struct{})
+	b.wg.Add(1)
+	go b.fanOut(in, b.done)
+
+	return primary.frames, nil
+}
+
+// fanOut delivers each provider frame to every subscription
+func (b *Broadcaster) fanOut(in <-chan Frame, done <-chan struct{}) {
+	defer b.wg.Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		case frame, ok := <-in:
+			if !ok {
+				// Provider closed its channel: propagate to subscribers
+				b.mu.Lock()
+				b.closeAllLocked()
+				b.mu.Unlock()
+				return
+			}
+
+			b.mu.Lock()
+			for _, sub := range b.subs {
+				sub.offer(frame)
+			}
+			b.mu.Unlock()
+		}
+	}
+}
+
+// Subscribe attaches a new independent consumer
+//
+// May be called before or after Start. The subscription receives frames
+// until Close(), Stop(), or the end of the provider's stream.
+func (b *Broadcaster) Subscribe(cfg SubscribeConfig) (*Subscription, error) {
+	if cfg.BufferSize < 0 {
+		return nil, fmt.Errorf("stream-capture: invalid subscriber buffer size %d (must be >= 0)", cfg.BufferSize)
+	}
+	if cfg.Policy != DropNewest && cfg.Policy != DropOldest {
+		return nil, fmt.Errorf("stream-capture: invalid drop policy %d", cfg.Policy)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := b.addLocked(cfg)
+
+	b.logger.Info("stream-capture: subscriber added",
+		"subscriber", sub.name,
+		"buffer_size", cap(sub.frames),
+		"policy", sub.policy.String(),
+	)
+
+	return sub, nil
+}
+
+// addLocked creates and registers a subscription (b.mu held)
+func (b *Broadcaster) addLocked(cfg SubscribeConfig) *Subscription {
+	size := cfg.BufferSize
+	if size == 0 {
+		size = b.frameBufferSize
+	}
+	name := cfg.Name
+	if name == "" {
+		name = fmt.Sprintf("subscriber-%d", len(b.subs)+1)
+	}
+
+	sub := &Subscription{
+		b:      b,
+		name:   name,
+		policy: cfg.Policy,
+		frames: make(chan Frame, size),
+	}
+	b.subs = append(b.subs, sub)
+	return sub
+}
+
+// closeAllLocked closes every subscription channel (b.mu held)
+func (b *Broadcaster) closeAllLocked() {
+	for _, sub := range b.subs {
+		if !sub.closed {
+			sub.closed = true
+			close(sub.frames)
+		}
+	}
+	b.subs = nil
+}
+
+// Stop stops the wrapped provider and closes all subscription channels
+//
+// Idempotent.
+func (b *Broadcaster) Stop() error {
+	b.lifecycle.Lock()
+	defer b.lifecycle.Unlock()
+
+	if b.done == nil {
+		return b.StreamProvider.Stop()
+	}
+
+	close(b.done)
+	b.done = nil
+	err := b.StreamProvider.Stop()
+
+	// fanOut takes b.mu per frame, so wait without holding it
+	b.wg.Wait()
+
+	b.mu.Lock()
+	stats := b.subscriberStatsLocked()
+	b.closeAllLocked()
+	b.primary = nil
+	b.mu.Unlock()
+
+	for _, s := range stats {
+		b.logger.Info("stream-capture: subscriber closed",
+			"subscriber", s.Name,
+			"delivered", s.Delivered,
+			"dropped", s.Dropped,
+		)
+	}
+
+	return err
+}
+
+// Pause pauses the wrapped provider and drains every subscription
+//
+// No frame captured before Pause reaches any consumer afterwards.
+func (b *Broadcaster) Pause() error {
+	if err := b.StreamProvider.Pause(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, sub := range b.subs {
+		drainFrames(sub.frames)
+	}
+	return nil
+}
+
+// Warmup measures FPS stability on the default subscription
+//
+// To warm up without consuming the application's frames, use
+// Subscription.Warmup on a dedicated subscription instead.
+func (b *Broadcaster) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	b.mu.Lock()
+	if b.primary == nil {
+		b.mu.Unlock()
+		return nil, fmt.Errorf("stream-capture: stream not started")
+	}
+	frames := b.primary.frames
+	b.mu.Unlock()
+
+	return warmupFrames(ctx, frames, duration, b.clock, b.logger)
+}
+
+// Subscribers returns delivery statistics for every active subscription
+func (b *Broadcaster) Subscribers() []SubscriptionStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscriberStatsLocked()
+}
+
+// subscriberStatsLocked snapshots subscription stats (b.mu held)
+func (b *Broadcaster) subscriberStatsLocked() []SubscriptionStats {
+	stats := make([]SubscriptionStats, 0, len(b.subs))
+	for _, sub := range b.subs {
+		stats = append(stats, sub.Stats())
+	}
+	return stats
+}
+
+// offer delivers a frame according to the drop policy (b.mu held)
+func (s *Subscription) offer(frame Frame) {
+	select {
+	case s.frames <- frame:
+		atomic.AddUint64(&s.delivered, 1)
+		return
+	default:
+	}
+
+	if s.policy == DropOldest {
+		// Evict the oldest frame (the consumer may have read it meanwhile)
+		select {
+		case <-s.frames:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+		select {
+		case s.frames <- frame:
+			atomic.AddUint64(&s.delivered, 1)
+			return
+		default:
+		}
+	}
+
+	atomic.AddUint64(&s.dropped, 1)
+}
+
+// Frames returns the subscription channel
+func (s *Subscription) Frames() <-chan Frame {
+	return s.frames
+}
+
+// Name returns the subscriber name
+func (s *Subscription) Name() string {
+	return s.name
+}
+
+// Stats returns delivery statistics for this subscription
+//
+// Thread-safe - uses atomic operations for counters.
+func (s *Subscription) Stats() SubscriptionStats {
+	return SubscriptionStats{
+		Name:       s.name,
+		Policy:     s.policy,
+		BufferSize: cap(s.frames),
+		Buffered:   len(s.frames),
+		Delivered:  atomic.LoadUint64(&s.delivered),
+		Dropped:    atomic.LoadUint64(&s.dropped),
+	}
+}
+
+// Warmup measures FPS stability on this subscription only
+//
+// Other subscribers keep receiving every frame during warmup.
+func (s *Subscription) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	return warmupFrames(ctx, s.frames, duration, s.b.clock, s.b.logger)
+}
+
+// Close detaches the subscription and closes its channel
+//
+// Idempotent. Other subscribers are not affected.
+func (s *Subscription) Close() {
+	b := s.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.frames)
+
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			break
+		}
+	}
+	if b.primary == s {
+		b.primary = nil
+	}
+
+	b.logger.Info("stream-capture: subscriber removed",
+		"subscriber", s.name,
+		"delivered", atomic.LoadUint64(&s.delivered),
+		"dropped", atomic.LoadUint64(&s.dropped),
+	)
+}
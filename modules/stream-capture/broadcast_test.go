@@ -0,0 +1,218 @@
+package streamcapture
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// receive reads one frame or fails after a timeout
+func receive(t *testing.T, frames <-chan Frame) Frame {
+	t.Helper()
+
+	select {
+	case f, ok := <-frames:
+		if !ok {
+			t.Fatal("channel closed, want frame")
+		}
+		return f
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for frame")
+	}
+	return Frame{}
+}
+
+// waitDelivered waits until sub has been offered n frames
+func waitDelivered(t *testing.T, sub *Subscription, n uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		stats := sub.Stats()
+		if stats.Delivered+stats.Dropped >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("subscriber %s not offered %d frames: %+v", sub.Name(), n, sub.Stats())
+}
+
+func TestBroadcaster_EverySubscriberGetsEveryFrame(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	b, err := NewBroadcaster(provider)
+	if err != nil {
+		t.Fatalf("NewBroadcaster() error = %v", err)
+	}
+
+	// Subscribe before Start
+	early, err := b.Subscribe(SubscribeConfig{Name: "early"})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	frames, err := b.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := b.Start(context.Background()); err == nil {
+		t.Error("second Start() succeeded, want error")
+	}
+
+	late, err := b.Subscribe(SubscribeConfig{Name: "late", BufferSize: 3})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	for _, f := range testFrames(3) {
+		provider.frames <- f
+	}
+
+	for _, ch := range []<-chan Frame{frames, early.Frames(), late.Frames()} {
+		for want := uint64(1); want <= 3; want++ {
+			if got := receive(t, ch).Seq; got != want {
+				t.Errorf("seq = %d, want %d", got, want)
+			}
+		}
+	}
+
+	stats := b.Subscribers()
+	if len(stats) != 3 {
+		t.Fatalf("Subscribers() = %d entries, want 3", len(stats))
+	}
+	for _, s := range stats {
+		if s.Delivered != 3 || s.Dropped != 0 {
+			t.Errorf("subscriber %s delivered/dropped = %d/%d, want 3/0", s.Name, s.Delivered, s.Dropped)
+		}
+	}
+	if stats[2].BufferSize != 3 {
+		t.Errorf("late BufferSize = %d, want 3", stats[2].BufferSize)
+	}
+
+	if b.Stats().FrameCount != 42 {
+		t.Error("Stats() not forwarded to wrapped provider")
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for _, ch := range []<-chan Frame{frames, early.Frames(), late.Frames()} {
+		if _, ok := <-ch; ok {
+			t.Error("subscription channel open after Stop")
+		}
+	}
+	if err := b.Stop(); err != nil {
+		t.Errorf("second Stop() error = %v, want nil", err)
+	}
+}
+
+func TestBroadcaster_DropPolicies(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	b, err := NewBroadcaster(provider)
+	if err != nil {
+		t.Fatalf("NewBroadcaster() error = %v", err)
+	}
+	if _, err := b.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer b.Stop()
+
+	newest, _ := b.Subscribe(SubscribeConfig{Name: "newest", BufferSize: 2, Policy: DropNewest})
+	oldest, _ := b.Subscribe(SubscribeConfig{Name: "oldest", BufferSize: 2, Policy: DropOldest})
+
+	// Nobody reads: 5 frames into 2-slot buffers
+	for _, f := range testFrames(5) {
+		provider.frames <- f
+	}
+	waitDelivered(t, newest, 5)
+	waitDelivered(t, oldest, 5)
+
+	if got := []uint64{receive(t, newest.Frames()).Seq, receive(t, newest.Frames()).Seq}; got[0] != 1 || got[1] != 2 {
+		t.Errorf("DropNewest kept seqs %v, want [1 2]", got)
+	}
+	if got := []uint64{receive(t, oldest.Frames()).Seq, receive(t, oldest.Frames()).Seq}; got[0] != 4 || got[1] != 5 {
+		t.Errorf("DropOldest kept seqs %v, want [4 5]", got)
+	}
+
+	if s := newest.Stats(); s.Delivered != 2 || s.Dropped != 3 {
+		t.Errorf("DropNewest delivered/dropped = %d/%d, want 2/3", s.Delivered, s.Dropped)
+	}
+	if s := oldest.Stats(); s.Delivered != 5 || s.Dropped != 3 {
+		t.Errorf("DropOldest delivered/dropped = %d/%d, want 5/3", s.Delivered, s.Dropped)
+	}
+}
+
+func TestBroadcaster_SubscriptionClose(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	b, err := NewBroadcaster(provider)
+	if err != nil {
+		t.Fatalf("NewBroadcaster() error = %v", err)
+	}
+	frames, err := b.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer b.Stop()
+
+	sub, _ := b.Subscribe(SubscribeConfig{Name: "preview"})
+	sub.Close()
+	sub.Close() // idempotent
+
+	if _, ok := <-sub.Frames(); ok {
+		t.Error("closed subscription channel still open")
+	}
+
+	provider.frames <- testFrames(1)[0]
+	if got := receive(t, frames).Seq; got != 1 {
+		t.Errorf("default subscriber seq = %d, want 1", got)
+	}
+	if n := len(b.Subscribers()); n != 1 {
+		t.Errorf("Subscribers() = %d entries after Close, want 1", n)
+	}
+}
+
+func TestBroadcaster_ProviderChannelClosed(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	b, err := NewBroadcaster(provider)
+	if err != nil {
+		t.Fatalf("NewBroadcaster() error = %v", err)
+	}
+	frames, err := b.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	sub, _ := b.Subscribe(SubscribeConfig{})
+
+	close(provider.frames)
+
+	for _, ch := range []<-chan Frame{frames, sub.Frames()} {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Error("received frame, want closed channel")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("subscription not closed after provider channel closed")
+		}
+	}
+
+	if err := b.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
+
+func TestBroadcaster_Subscribe_Invalid(t *testing.T) {
+	b, err := NewBroadcaster(&chanProvider{frames: make(chan Frame)})
+	if err != nil {
+		t.Fatalf("NewBroadcaster() error = %v", err)
+	}
+
+	if _, err := b.Subscribe(SubscribeConfig{BufferSize: -1}); err == nil {
+		t.Error("Subscribe() with negative buffer succeeded, want error")
+	}
+	if _, err := b.Subscribe(SubscribeConfig{Policy: DropPolicy(7)}); err == nil {
+		t.Error("Subscribe() with unknown policy succeeded, want error")
+	}
+	if _, err := NewBroadcaster(nil); err == nil {
+		t.Error("NewBroadcaster(nil) succeeded, want error")
+	}
+}
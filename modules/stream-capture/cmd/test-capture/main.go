@@ -9,21 +9,34 @@ import (
 	"image/png"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/config"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/framelog"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/preview"
 )
 
 // Version information
 const version = "v0.1.0"
 
 func main() {
+	// Subcommand: camera qualification (scripted scenario + report)
+	if len(os.Args) > 1 && os.Args[1] == "qualify" {
+		os.Exit(runQualify(os.Args[2:]))
+	}
+
 	// Parse command-line flags
-	rtspURL := flag.String("url", "", "RTSP stream URL (required)")
+	rtspURL := flag.String("url", "", "Stream URL: rtsp:// or http(s):// for MJPEG cameras (required unless --config)")
+	configPath := flag.String("config", "", "Camera file (.yaml, .yml, .json); replaces --url, --resolution, --fps, --source, --accel")
+	cameraID := flag.String("camera", "", "Camera ID to capture from --config (default: the only camera)")
+	secretsPath := flag.String("secrets", "", "Secrets file with camera credentials referenced by --config (mode 0600)")
 	resolution := flag.String("resolution", "720p", "Resolution: 512p, 720p, 1080p")
 	fps := flag.Float64("fps", 2.0, "Target FPS (0.1-30)")
 	sourceStream := flag.String("source", "test", "Source stream identifier")
@@ -33,6 +46,10 @@ func main() {
 	maxFrames := flag.Int("max-frames", 0, "Maximum frames to capture (0 = unlimited)")
 	statsInterval := flag.Int("stats-interval", 10, "Seconds between stats reports")
 	accel := flag.String("accel", "auto", "Acceleration mode: auto, vaapi, software")
+	previewAddr := flag.String("preview", "", "Serve live browser preview on this address (e.g. :8080)")
+	previewFPS := flag.Float64("preview-fps", 5.0, "Maximum preview frame rate (independent of --fps)")
+	mjpegMode := flag.String("mjpeg-mode", "stream", "HTTP camera mode: stream (multipart MJPEG), snapshot (JPEG polling)")
+	recordPath := flag.String("record", "", "Record delivered frames to a frame log for later replay (optional)")
 	skipWarmup := flag.Bool("skip-warmup", false, "Skip FPS stability warmup")
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	showVersion := flag.Bool("version", false, "Show version and exit")
@@ -45,11 +62,14 @@ func main() {
 	}
 
 	// Validate required flags
-	if *rtspURL == "" {
-		fmt.Fprintf(os.Stderr, "Error: --url flag is required\n\n")
+	if *rtspURL == "" && *configPath == "" {
+		fmt.Fprintf(os.Stderr, "Error: --url or --config flag is required\n\n")
 		fmt.Fprintf(os.Stderr, "Usage example:\n")
 		fmt.Fprintf(os.Stderr, "  test-capture --url rtsp://192.168.1.100/stream\n")
-		fmt.Fprintf(os.Stderr, "  test-capture --url rtsp://192.168.1.100/stream --output ./frames --fps 1.0\n\n")
+		fmt.Fprintf(os.Stderr, "  test-capture --url rtsp://192.168.1.100/stream --output ./frames --fps 1.0\n")
+		fmt.Fprintf(os.Stderr, "  test-capture --url http://192.168.1.101/snapshot.jpg --mjpeg-mode snapshot\n")
+		fmt.Fprintf(os.Stderr, "  test-capture --config cameras.yaml --secrets secrets.yaml --camera hall\n")
+		fmt.Fprintf(os.Stderr, "  test-capture qualify --url rtsp://192.168.1.100/stream --model \"Acme IPC-200\"\n\n")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -64,21 +84,27 @@ func main() {
 	}))
 	slog.SetDefault(logger)
 
-	// Parse resolution
-	var res streamcapture.Resolution
-	switch *resolution {
-	case "480p":
-		res = streamcapture.Res480p
-	case "512p":
-		res = streamcapture.Res512p
-	case "640p":
-		res = streamcapture.Res640p
-	case "720p":
-		res = streamcapture.Res720p
-	case "1080p":
-		res = streamcapture.Res1080p
-	default:
-		log.Fatalf("Invalid resolution: %s (must be 480p, 512p, 640p, 720p, or 1080p)", *resolution)
+	// Parse resolution and acceleration mode
+	res, err := streamcapture.ParseResolution(*resolution)
+	if err != nil {
+		log.Fatalf("Invalid --resolution: %v", err)
+	}
+	accelMode, err := streamcapture.ParseHardwareAccel(*accel)
+	if err != nil {
+		log.Fatalf("Invalid --accel: %v", err)
+	}
+
+	rtspCfg := streamcapture.RTSPConfig{
+		URL:          *rtspURL,
+		Resolution:   res,
+		TargetFPS:    *fps,
+		SourceStream: *sourceStream,
+		Acceleration: accelMode,
+	}
+
+	// Camera file (credentials resolved from the secrets file)
+	if *configPath != "" {
+		rtspCfg = loadCameraConfig(*configPath, *secretsPath, *cameraID)
 	}
 
 	// Validate output format
@@ -106,10 +132,10 @@ func main() {
 	fmt.Printf("╚═══════════════════════════════════════════════════════════╝\n")
 	fmt.Printf("\n")
 	fmt.Printf("Configuration:\n")
-	fmt.Printf("  RTSP URL:      %s\n", *rtspURL)
-	fmt.Printf("  Resolution:    %s\n", *resolution)
-	fmt.Printf("  Target FPS:    %.2f\n", *fps)
-	fmt.Printf("  Source Stream: %s\n", *sourceStream)
+	fmt.Printf("  Stream URL:    %s\n", streamcapture.RedactURL(rtspCfg.URL))
+	fmt.Printf("  Resolution:    %s\n", rtspCfg.Resolution)
+	fmt.Printf("  Target FPS:    %.2f\n", rtspCfg.TargetFPS)
+	fmt.Printf("  Source Stream: %s\n", rtspCfg.SourceStream)
 	if *outputDir != "" {
 		fmt.Printf("  Output Dir:    %s\n", *outputDir)
 	} else {
@@ -122,31 +148,72 @@ func main() {
 	}
 	fmt.Printf("\n")
 
-	// Parse acceleration mode
-	var accelMode streamcapture.HardwareAccel
-	switch *accel {
-	case "auto":
-		accelMode = streamcapture.AccelAuto
-	case "vaapi":
-		accelMode = streamcapture.AccelVAAPI
-	case "software":
-		accelMode = streamcapture.AccelSoftware
-	default:
-		log.Fatalf("Invalid acceleration mode: %s (must be auto, vaapi, or software)", *accel)
+	// Create stream (RTSP via GStreamer, or HTTP MJPEG/snapshot in pure Go)
+	var stream streamcapture.StreamProvider
+	if strings.HasPrefix(rtspCfg.URL, "http://") || strings.HasPrefix(rtspCfg.URL, "https://") {
+		var mode streamcapture.MJPEGMode
+		switch *mjpegMode {
+		case "stream":
+			mode = streamcapture.MJPEGModeStream
+		case "snapshot":
+			mode = streamcapture.MJPEGModeSnapshot
+		default:
+			log.Fatalf("Invalid MJPEG mode: %s (must be stream or snapshot)", *mjpegMode)
+		}
+
+		mjpegStream, err := streamcapture.NewMJPEGStream(streamcapture.MJPEGConfig{
+			URL:          rtspCfg.URL,
+			Mode:         mode,
+			TargetFPS:    rtspCfg.TargetFPS,
+			SourceStream: rtspCfg.SourceStream,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create MJPEG stream: %v", err)
+		}
+		stream = mjpegStream
+	} else {
+		rtspStream, err := streamcapture.NewRTSPStream(rtspCfg)
+		if err != nil {
+			log.Fatalf("Failed to create RTSP stream: %v", err)
+		}
+		stream = rtspStream
 	}
 
-	// Create RTSP stream
-	cfg := streamcapture.RTSPConfig{
-		URL:          *rtspURL,
-		Resolution:   res,
-		TargetFPS:    *fps,
-		SourceStream: *sourceStream,
-		Acceleration: accelMode,
+	// Frame log recording (optional, replay with streamcapture.NewReplayStream)
+	var logWriter *framelog.Writer
+	var recorder *streamcapture.Recorder
+	if *recordPath != "" {
+		var err error
+		logWriter, err = framelog.Create(*recordPath)
+		if err != nil {
+			log.Fatalf("Failed to create frame log: %v", err)
+		}
+		recorder, err = streamcapture.NewRecorder(stream, logWriter)
+		if err != nil {
+			log.Fatalf("Failed to create recorder: %v", err)
+		}
+		stream = recorder
+		slog.Info("Recording frames", "path", *recordPath)
 	}
 
-	stream, err := streamcapture.NewRTSPStream(cfg)
-	if err != nil {
-		log.Fatalf("Failed to create RTSP stream: %v", err)
+	// Live preview server (optional, for installers aiming the camera)
+	var pv *preview.Server
+	if *previewAddr != "" {
+		var err error
+		pv, err = preview.New(stream, preview.Config{
+			MaxFPS:  *previewFPS,
+			Overlay: true,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create preview server: %v", err)
+		}
+
+		go func() {
+			if err := http.ListenAndServe(*previewAddr, pv); err != nil {
+				slog.Error("Preview server stopped", "error", err)
+			}
+		}()
+		slog.Info("Preview server enabled", "address", *previewAddr)
 	}
 
 	// Set up context with cancellation
@@ -158,7 +225,7 @@ func main() {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
 	// Start stream (non-blocking, returns immediately)
-	slog.Info("Starting RTSP stream...")
+	slog.Info("Starting stream...")
 	frameChan, err := stream.Start(ctx)
 	if err != nil {
 		log.Fatalf("Failed to start stream: %v", err)
@@ -281,6 +348,10 @@ func main() {
 
 			frameCount++
 
+			if pv != nil {
+				pv.Observe(frame)
+			}
+
 			// Log frame arrival (compact format)
 			fmt.Printf("[%s] Frame #%-6d | Seq: %-8d | Size: %6.1f KB | Timestamp: %s\n",
 				time.Now().Format("15:04:05"),
@@ -314,6 +385,16 @@ shutdown:
 	if err := stream.Stop(); err != nil {
 		slog.Error("Error stopping stream", "error", err)
 	}
+	if logWriter != nil {
+		if err := logWriter.Close(); err != nil {
+			slog.Error("Error closing frame log", "error", err)
+		}
+		slog.Info("Frame log written",
+			"path", *recordPath,
+			"frames", recorder.Recorded(),
+			"error", recorder.Err(),
+		)
+	}
 
 	// Final stats
 	finalStats := stream.Stats()
@@ -346,6 +427,44 @@ shutdown:
 	slog.Info("Test capture completed successfully")
 }
 
+// loadCameraConfig resolves one camera of a camera file (exits on error)
+//
+// All configuration errors of the file are printed at once. cameraID may be
+// empty when the file defines a single camera.
+func loadCameraConfig(configPath, secretsPath, cameraID string) streamcapture.RTSPConfig {
+	file, err := config.Load(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load camera config: %v", err)
+	}
+
+	var secrets config.Secrets
+	if secretsPath != "" {
+		secrets, err = config.LoadSecrets(secretsPath)
+		if err != nil {
+			log.Fatalf("Failed to load secrets: %v", err)
+		}
+	}
+
+	streams, err := file.Resolve(secrets)
+	if err != nil {
+		log.Fatalf("Invalid camera config %s:\n%v", configPath, err)
+	}
+
+	if cameraID == "" {
+		if len(streams) != 1 {
+			log.Fatalf("Camera config %s defines %d cameras, select one with --camera", configPath, len(streams))
+		}
+		cameraID = streams[0].ID
+	}
+
+	selected, ok := config.Find(streams, cameraID)
+	if !ok {
+		log.Fatalf("Camera %q not found in %s", cameraID, configPath)
+	}
+	slog.Info("Camera loaded from config", "camera", selected.ID, "url", selected.RedactedURL())
+	return selected.Config
+}
+
 // saveFrame saves a frame to disk as PNG or JPEG
 func saveFrame(outputDir string, frame streamcapture.Frame, format string, jpegQuality int) error {
 	// Create filename with timestamp and sequence
@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
+)
+
+// firstFrameTimeout bounds Start() → first frame in a qualification run
+const firstFrameTimeout = 30 * time.Second
+
+// qualifyOptions configures a qualification scenario
+type qualifyOptions struct {
+	Base          streamcapture.RTSPConfig // URL and reconnect settings
+	Model         string
+	Accelerations []streamcapture.HardwareAccel
+	Resolutions   []streamcapture.Resolution
+	FPSSteps      []float64
+	Warmup        time.Duration
+	StepDuration  time.Duration
+	Limits        qualifyLimits
+}
+
+// streamFactory creates the stream under test (replaced in tests)
+type streamFactory func(cfg streamcapture.RTSPConfig) (streamcapture.StreamProvider, error)
+
+// newRTSPProvider is the production streamFactory
+func newRTSPProvider(cfg streamcapture.RTSPConfig) (streamcapture.StreamProvider, error) {
+	return streamcapture.NewRTSPStream(cfg)
+}
+
+// runQualify implements "test-capture qualify"; returns the process exit code
+//
+// Exit codes: 0 = camera passed, 2 = camera failed, 1 = usage/setup error.
+func runQualify(args []string) int {
+	fs := flag.NewFlagSet("qualify", flag.ExitOnError)
+	rtspURL := fs.String("url", "", "RTSP URL of the camera (required unless --config)")
+	configPath := fs.String("config", "", "Camera file (.yaml, .yml, .json)")
+	cameraID := fs.String("camera", "", "Camera ID in --config (default: the only camera)")
+	secretsPath := fs.String("secrets", "", "Secrets file with camera credentials (mode 0600)")
+	model := fs.String("model", "", "Camera model, used in the report title and file name (default: host)")
+	accels := fs.String("accels", "software,vaapi", "Acceleration modes to test (VAAPI skipped if unavailable)")
+	resolutions := fs.String("resolutions", "720p", "Resolutions to test (comma-separated)")
+	fpsSteps := fs.String("fps-steps", "1,2,5", "Target FPS sequence applied with SetTargetFPS (comma-separated)")
+	warmup := fs.Duration("warmup", 5*time.Second, "Warmup duration per run")
+	stepDuration := fs.Duration("step-duration", 10*time.Second, "Measurement duration per FPS step")
+	maxFPSError := fs.Float64("max-fps-error", 10, "Maximum FPS deviation from target (percent)")
+	maxInterruption := fs.Duration("max-interruption", 3*time.Second, "Maximum frame gap after SetTargetFPS")
+	reportDir := fs.String("report-dir", ".", "Directory for the JSON and Markdown reports")
+	debug := fs.Bool("debug", false, "Enable debug logging")
+	fs.Parse(args)
+
+	logLevel := slog.LevelWarn
+	if *debug {
+		logLevel = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel})))
+
+	base := streamcapture.RTSPConfig{URL: *rtspURL}
+	switch {
+	case *configPath != "":
+		base = loadCameraConfig(*configPath, *secretsPath, *cameraID)
+	case *rtspURL == "":
+		fmt.Fprintf(os.Stderr, "Error: --url or --config flag is required\n\n")
+		fmt.Fprintf(os.Stderr, "Usage example:\n")
+		fmt.Fprintf(os.Stderr, "  test-capture qualify --url rtsp://192.168.1.100/stream --model \"Acme IPC-200\"\n\n")
+		fs.PrintDefaults()
+		return 1
+	}
+
+	opts := qualifyOptions{
+		Base:         base,
+		Model:        *model,
+		Warmup:       *warmup,
+		StepDuration: *stepDuration,
+		Limits: qualifyLimits{
+			MaxFPSErrorPct:    *maxFPSError,
+			MaxInterruptionMS: float64(maxInterruption.Milliseconds()),
+		},
+	}
+	if opts.Model == "" {
+		opts.Model = "camera"
+		if u, err := url.Parse(base.URL); err == nil && u.Hostname() != "" {
+			opts.Model = u.Hostname()
+		}
+	}
+
+	var err error
+	if opts.Accelerations, err = parseList(*accels, streamcapture.ParseHardwareAccel); err != nil {
+		log.Fatalf("Invalid --accels: %v", err)
+	}
+	if opts.Resolutions, err = parseList(*resolutions, streamcapture.ParseResolution); err != nil {
+		log.Fatalf("Invalid --resolutions: %v", err)
+	}
+	if opts.FPSSteps, err = parseList(*fpsSteps, parseFPS); err != nil {
+		log.Fatalf("Invalid --fps-steps: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report := qualify(ctx, newRTSPProvider, opts, func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
+	})
+
+	paths, err := writeReport(*reportDir, report)
+	if err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+
+	fmt.Printf("\n%s", renderMarkdown(report))
+	for _, path := range paths {
+		fmt.Printf("Report written: %s\n", path)
+	}
+
+	if ctx.Err() != nil {
+		fmt.Fprintf(os.Stderr, "Qualification interrupted, report is partial\n")
+		return 1
+	}
+	if !report.Pass {
+		return 2
+	}
+	return 0
+}
+
+// qualify runs every acceleration × resolution combination
+//
+// progress receives one line per phase for the console.
+func qualify(ctx context.Context, newStream streamFactory, opts qualifyOptions, progress func(string, ...any)) qualifyReport {
+	started := time.Now()
+	report := qualifyReport{
+		Model:      opts.Model,
+		URL:        streamcapture.RedactURL(opts.Base.URL),
+		StartedAt:  started,
+		Thresholds: opts.Limits,
+	}
+
+	for _, accel := range opts.Accelerations {
+		for _, res := range opts.Resolutions {
+			if ctx.Err() != nil {
+				break
+			}
+			progress("▶ %s / %s", accel, res)
+			report.Runs = append(report.Runs, qualifyCase(ctx, newStream, opts, accel, res, progress))
+		}
+	}
+
+	report.DurationS = time.Since(started).Seconds()
+
+	// Pass: at least one run executed and every executed run passed
+	executed := 0
+	report.Pass = true
+	for _, run := range report.Runs {
+		if run.Skipped != "" {
+			continue
+		}
+		executed++
+		report.Pass = report.Pass && run.Pass
+	}
+	report.Pass = report.Pass && executed > 0
+
+	return report
+}
+
+// qualifyCase runs the scenario for one acceleration mode and resolution
+//
+// This function:
+//  1. Creates and starts the stream at the first FPS step
+//  2. Measures startup time (Start → first frame)
+//  3. Runs Warmup
+//  4. Applies each FPS step with SetTargetFPS and measures it
+//  5. Collects decode latency and error telemetry from Stats
+func qualifyCase(ctx context.Context, newStream streamFactory, opts qualifyOptions,
+	accel streamcapture.HardwareAccel, res streamcapture.Resolution, progress func(string, ...any)) qualifyRun {
+
+	run := qualifyRun{Acceleration: accel.String(), Resolution: res.String()}
+
+	cfg := opts.Base
+	cfg.Acceleration = accel
+	cfg.Resolution = res
+	cfg.TargetFPS = opts.FPSSteps[0]
+
+	stream, err := newStream(cfg)
+	if err != nil {
+		if accel == streamcapture.AccelVAAPI {
+			run.Skipped = fmt.Sprintf("VAAPI not available: %v", err)
+			progress("  skipped: %s", run.Skipped)
+		} else {
+			run.Error = err.Error()
+		}
+		return run
+	}
+	defer stream.Stop()
+
+	startedAt := time.Now()
+	frames, err := stream.Start(ctx)
+	if err != nil {
+		run.Error = fmt.Sprintf("start failed: %v", err)
+		return run
+	}
+
+	first, err := nextFrame(ctx, frames, firstFrameTimeout)
+	if err != nil {
+		run.Error = fmt.Sprintf("no frame within %v: %v", firstFrameTimeout, err)
+		collectStats(&run, stream.Stats())
+		return run
+	}
+	run.StartupMS = msBetween(startedAt, first.Timestamp)
+	progress("  first frame after %.0f ms", run.StartupMS)
+
+	if opts.Warmup > 0 {
+		ws, err := stream.Warmup(ctx, opts.Warmup)
+		if err != nil {
+			run.Error = fmt.Sprintf("warmup failed: %v", err)
+			collectStats(&run, stream.Stats())
+			return run
+		}
+		run.Warmup = &qualifyWarmup{
+			FPSMean:      ws.FPSMean,
+			FPSStdDev:    ws.FPSStdDev,
+			JitterMeanMS: ws.JitterMean * 1000,
+			JitterMaxMS:  ws.JitterMax * 1000,
+			Stable:       ws.IsStable,
+		}
+		progress("  warmup: %.2f FPS, stable %v", ws.FPSMean, ws.IsStable)
+	}
+
+	for i, fps := range opts.FPSSteps {
+		drain(frames)
+		changedAt := time.Now()
+		if i > 0 {
+			if err := stream.SetTargetFPS(fps); err != nil {
+				run.Error = fmt.Sprintf("SetTargetFPS(%.2f) failed: %v", fps, err)
+				break
+			}
+		}
+
+		timestamps, err := collectFrames(ctx, frames, opts.StepDuration)
+		step := measureStep(fps, changedAt, timestamps, opts.Limits)
+		run.Steps = append(run.Steps, step)
+		progress("  %.2f FPS target: %.2f actual (%.1f%%), interruption %.0f ms — %s",
+			fps, step.ActualFPS, step.AccuracyPct, step.InterruptionMS, passLabel(step.Pass))
+		if err != nil {
+			run.Error = err.Error()
+			break
+		}
+	}
+
+	collectStats(&run, stream.Stats())
+
+	run.Pass = run.Error == "" && run.errorCount() == 0 && len(run.Steps) == len(opts.FPSSteps)
+	for _, step := range run.Steps {
+		run.Pass = run.Pass && step.Pass
+	}
+	return run
+}
+
+// collectStats copies decode latency and error telemetry into the run
+func collectStats(run *qualifyRun, stats streamcapture.StreamStats) {
+	run.UsingVAAPI = stats.UsingVAAPI
+	run.DecodeLatencyMeanMS = stats.DecodeLatencyMeanMS
+	run.DecodeLatencyP95MS = stats.DecodeLatencyP95MS
+	run.DecodeLatencyMaxMS = stats.DecodeLatencyMaxMS
+	run.FramesDropped = stats.FramesDropped
+	run.Reconnects = stats.Reconnects
+	run.ErrorsNetwork = stats.ErrorsNetwork
+	run.ErrorsCodec = stats.ErrorsCodec
+	run.ErrorsAuth = stats.ErrorsAuth
+	run.ErrorsUnknown = stats.ErrorsUnknown
+	for _, rec := range stats.RecentErrors {
+		run.RecentErrors = append(run.RecentErrors,
+			fmt.Sprintf("%s [%s] %s", rec.Time.Format(time.TimeOnly), rec.Category, rec.Message))
+	}
+}
+
+// nextFrame waits for a single frame
+func nextFrame(ctx context.Context, frames <-chan streamcapture.Frame, timeout time.Duration) (streamcapture.Frame, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case frame, ok := <-frames:
+		if !ok {
+			return streamcapture.Frame{}, errors.New("frame channel closed")
+		}
+		return frame, nil
+	case <-timer.C:
+		return streamcapture.Frame{}, errors.New("timeout")
+	case <-ctx.Done():
+		return streamcapture.Frame{}, ctx.Err()
+	}
+}
+
+// collectFrames returns the capture timestamps of frames received during d
+func collectFrames(ctx context.Context, frames <-chan streamcapture.Frame, d time.Duration) ([]time.Time, error) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var timestamps []time.Time
+	for {
+		select {
+		case frame, ok := <-frames:
+			if !ok {
+				return timestamps, errors.New("frame channel closed")
+			}
+			timestamps = append(timestamps, frame.Timestamp)
+		case <-timer.C:
+			return timestamps, nil
+		case <-ctx.Done():
+			return timestamps, ctx.Err()
+		}
+	}
+}
+
+// drain discards frames already buffered in the channel
+func drain(frames <-chan streamcapture.Frame) {
+	for {
+		select {
+		case <-frames:
+		default:
+			return
+		}
+	}
+}
+
+// parseList parses a comma-separated list with the given element parser
+func parseList[T any](s string, parse func(string) (T, error)) ([]T, error) {
+	var out []T
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		v, err := parse(field)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("empty list %q", s)
+	}
+	return out, nil
+}
+
+// parseFPS parses a target FPS (0.1-30)
+func parseFPS(s string) (float64, error) {
+	fps, err := strconv.ParseFloat(s, 64)
+	if err != nil || fps < 0.1 || fps > 30 {
+		return 0, fmt.Errorf("invalid FPS %q (must be 0.1-30)", s)
+	}
+	return fps, nil
+}
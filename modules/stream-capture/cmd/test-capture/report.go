@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// qualifyReport is the result of a camera qualification (written as JSON and Markdown)
+type qualifyReport struct {
+	Model      string        `json:"model"`
+	URL        string        `json:"url"` // Redacted
+	StartedAt  time.Time     `json:"started_at"`
+	DurationS  float64       `json:"duration_s"`
+	Pass       bool          `json:"pass"`
+	Thresholds qualifyLimits `json:"thresholds"`
+	Runs       []qualifyRun  `json:"runs"`
+}
+
+// qualifyLimits are the pass/fail criteria
+type qualifyLimits struct {
+	MaxFPSErrorPct    float64 `json:"max_fps_error_pct"`
+	MaxInterruptionMS float64 `json:"max_interruption_ms"`
+}
+
+// qualifyRun is one acceleration × resolution combination
+type qualifyRun struct {
+	Acceleration string `json:"acceleration"`
+	Resolution   string `json:"resolution"`
+	Pass         bool   `json:"pass"`
+	Skipped      string `json:"skipped,omitempty"` // Reason (e.g. VAAPI not available)
+	Error        string `json:"error,omitempty"`   // Fatal error that aborted the run
+
+	StartupMS float64        `json:"startup_ms"` // Start() → first frame
+	Warmup    *qualifyWarmup `json:"warmup,omitempty"`
+	Steps     []qualifyStep  `json:"steps"`
+
+	UsingVAAPI          bool     `json:"using_vaapi"`
+	DecodeLatencyMeanMS float64  `json:"decode_latency_mean_ms"`
+	DecodeLatencyP95MS  float64  `json:"decode_latency_p95_ms"`
+	DecodeLatencyMaxMS  float64  `json:"decode_latency_max_ms"`
+	FramesDropped       uint64   `json:"frames_dropped"`
+	Reconnects          uint32   `json:"reconnects"`
+	ErrorsNetwork       uint64   `json:"errors_network"`
+	ErrorsCodec         uint64   `json:"errors_codec"`
+	ErrorsAuth          uint64   `json:"errors_auth"`
+	ErrorsUnknown       uint64   `json:"errors_unknown"`
+	RecentErrors        []string `json:"recent_errors,omitempty"`
+}
+
+// qualifyWarmup summarizes WarmupStats
+type qualifyWarmup struct {
+	FPSMean      float64 `json:"fps_mean"`
+	FPSStdDev    float64 `json:"fps_stddev"`
+	JitterMeanMS float64 `json:"jitter_mean_ms"`
+	JitterMaxMS  float64 `json:"jitter_max_ms"`
+	Stable       bool    `json:"stable"`
+}
+
+// qualifyStep is one target FPS of the scenario
+type qualifyStep struct {
+	TargetFPS      float64 `json:"target_fps"`
+	ActualFPS      float64 `json:"actual_fps"`
+	AccuracyPct    float64 `json:"accuracy_pct"`    // ActualFPS / TargetFPS × 100
+	JitterMS       float64 `json:"jitter_ms"`       // Stddev of inter-frame intervals
+	IntervalP95MS  float64 `json:"interval_p95_ms"` // 95th percentile inter-frame interval
+	InterruptionMS float64 `json:"interruption_ms"` // Longest gap beyond the expected interval after SetTargetFPS
+	Frames         int     `json:"frames"`
+	Pass           bool    `json:"pass"`
+}
+
+// errorCount returns the total number of classified errors of the run
+func (r qualifyRun) errorCount() uint64 {
+	return r.ErrorsNetwork + r.ErrorsCodec + r.ErrorsAuth + r.ErrorsUnknown
+}
+
+// measureStep computes FPS accuracy, jitter and interruption of one step
+//
+// changedAt is when SetTargetFPS was called; timestamps are frame capture
+// times received after it. The interruption is the longest gap (starting at
+// changedAt) minus the expected interval, i.e. the time the camera delivered
+// nothing it should have. FPS and jitter are measured between frames, so the
+// renegotiation gap before the first frame does not skew them.
+func measureStep(target float64, changedAt time.Time, timestamps []time.Time, limits qualifyLimits) qualifyStep {
+	step := qualifyStep{TargetFPS: target, Frames: len(timestamps)}
+	expected := 1000 / target
+
+	if len(timestamps) == 0 {
+		return step
+	}
+
+	intervals := make([]float64, 0, len(timestamps)-1)
+	maxGap := msBetween(changedAt, timestamps[0])
+	for i := 1; i < len(timestamps); i++ {
+		interval := msBetween(timestamps[i-1], timestamps[i])
+		intervals = append(intervals, interval)
+		maxGap = math.Max(maxGap, interval)
+	}
+	step.InterruptionMS = math.Max(0, maxGap-expected)
+
+	if len(intervals) > 0 {
+		span := msBetween(timestamps[0], timestamps[len(timestamps)-1])
+		if span > 0 {
+			step.ActualFPS = float64(len(intervals)) * 1000 / span
+		}
+		step.AccuracyPct = step.ActualFPS / target * 100
+
+		var sum, sumSq float64
+		for _, interval := range intervals {
+			sum += interval
+			sumSq += interval * interval
+		}
+		mean := sum / float64(len(intervals))
+		step.JitterMS = math.Sqrt(math.Max(0, sumSq/float64(len(intervals))-mean*mean))
+		step.IntervalP95MS = percentile(intervals, 0.95)
+	}
+
+	step.Pass = len(intervals) > 0 &&
+		math.Abs(step.AccuracyPct-100) <= limits.MaxFPSErrorPct &&
+		step.InterruptionMS <= limits.MaxInterruptionMS
+	return step
+}
+
+// msBetween returns b - a in milliseconds
+func msBetween(a, b time.Time) float64 {
+	return float64(b.Sub(a).Microseconds()) / 1000
+}
+
+// percentile returns the p-quantile (nearest rank) of values
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// writeReport writes <dir>/qualify-<name>-<timestamp>.{json,md}
+//
+// Returns the paths of the written files.
+func writeReport(dir string, report qualifyReport) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("qualify-%s-%s", slug(report.Model), report.StartedAt.Format("20060102-150405")))
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON report: %w", err)
+	}
+	if err := os.WriteFile(base+".json", append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	if err := os.WriteFile(base+".md", []byte(renderMarkdown(report)), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+
+	return []string{base + ".json", base + ".md"}, nil
+}
+
+// slug turns a model name into a file name fragment
+func slug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	if s := strings.Trim(b.String(), "-"); s != "" {
+		return s
+	}
+	return "camera"
+}
+
+// passLabel formats a pass/fail flag
+func passLabel(pass bool) string {
+	if pass {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+// renderMarkdown renders the report for humans (purchase decisions, tickets)
+func renderMarkdown(r qualifyReport) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Camera Qualification: %s\n\n", r.Model)
+	fmt.Fprintf(&b, "- **Result**: %s\n", passLabel(r.Pass))
+	fmt.Fprintf(&b, "- **URL**: `%s`\n", r.URL)
+	fmt.Fprintf(&b, "- **Date**: %s\n", r.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Duration**: %.0fs\n", r.DurationS)
+	fmt.Fprintf(&b, "- **Criteria**: FPS within ±%.0f%% of target, hot-reload interruption ≤ %.0f ms, no stream errors\n\n",
+		r.Thresholds.MaxFPSErrorPct, r.Thresholds.MaxInterruptionMS)
+
+	for _, run := range r.Runs {
+		fmt.Fprintf(&b, "## %s / %s", run.Acceleration, run.Resolution)
+		switch {
+		case run.Skipped != "":
+			fmt.Fprintf(&b, " — SKIPPED\n\n%s\n\n", run.Skipped)
+			continue
+		default:
+			fmt.Fprintf(&b, " — %s\n\n", passLabel(run.Pass))
+		}
+		if run.Error != "" {
+			fmt.Fprintf(&b, "**Error**: %s\n\n", run.Error)
+		}
+
+		fmt.Fprintf(&b, "- Startup (first frame): %.0f ms\n", run.StartupMS)
+		if w := run.Warmup; w != nil {
+			fmt.Fprintf(&b, "- Warmup: %.2f FPS (stddev %.2f), jitter mean %.1f ms / max %.1f ms, stable: %v\n",
+				w.FPSMean, w.FPSStdDev, w.JitterMeanMS, w.JitterMaxMS, w.Stable)
+		}
+		fmt.Fprintf(&b, "- Decode latency: mean %.2f ms, P95 %.2f ms, max %.2f ms (VAAPI: %v)\n",
+			run.DecodeLatencyMeanMS, run.DecodeLatencyP95MS, run.DecodeLatencyMaxMS, run.UsingVAAPI)
+		fmt.Fprintf(&b, "- Errors: network %d, codec %d, auth %d, unknown %d; reconnects %d; dropped frames %d\n\n",
+			run.ErrorsNetwork, run.ErrorsCodec, run.ErrorsAuth, run.ErrorsUnknown, run.Reconnects, run.FramesDropped)
+
+		if len(run.Steps) > 0 {
+			b.WriteString("| Target FPS | Actual FPS | Accuracy | Jitter (ms) | Interval P95 (ms) | Interruption (ms) | Frames | Result |\n")
+			b.WriteString("|-----------:|-----------:|---------:|------------:|------------------:|------------------:|-------:|--------|\n")
+			for _, s := range run.Steps {
+				fmt.Fprintf(&b, "| %.2f | %.2f | %.1f%% | %.1f | %.1f | %.0f | %d | %s |\n",
+					s.TargetFPS, s.ActualFPS, s.AccuracyPct, s.JitterMS, s.IntervalP95MS, s.InterruptionMS, s.Frames, passLabel(s.Pass))
+			}
+			b.WriteString("\n")
+		}
+
+		if len(run.RecentErrors) > 0 {
+			b.WriteString("Recent errors:\n\n")
+			for _, msg := range run.RecentErrors {
+				fmt.Fprintf(&b, "- %s\n", msg)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
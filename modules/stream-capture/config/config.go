@@ -0,0 +1,380 @@
+// Package config loads camera definitions for stream-capture from YAML, JSON
+// or environment variables and turns them into validated RTSPConfig values.
+//
+// Credentials are kept out of the camera file: a camera references a named
+// entry of a separate secrets file, and the password is only injected into
+// the URL when the RTSPConfig is produced. URLs are reported through
+// streamcapture.RedactURL, so validation errors never contain passwords.
+//
+// Camera file (cameras.yaml):
+//
+//	defaults:
+//	  resolution: 720p
+//	  target_fps: 2
+//	  acceleration: auto
+//	cameras:
+//	  - id: hall
+//	    url: rtsp://10.0.0.5:554/stream1
+//	    credentials: hall-cam
+//	  - id: room-101
+//	    url: rtsp://10.0.0.6/live
+//	    credentials: room-cams
+//	    target_fps: 1
+//	    source_stream: LQ
+//
+// Secrets file (secrets.yaml, mode 0600):
+//
+//	credentials:
+//	  hall-cam:  {username: admin, password: s3cret}
+//	  room-cams: {username: viewer, password: hunter2}
+//
+// Example:
+//
+//	file, err := config.Load("cameras.yaml")
+//	secrets, err := config.LoadSecrets("secrets.yaml")
+//	streams, err := file.Resolve(secrets) // all camera errors at once
+//
+//	for _, s := range streams {
+//	    stream, err := streamcapture.NewRTSPStream(s.Config)
+//	    slog.Info("camera ready", "id", s.ID, "url", s.RedactedURL())
+//	}
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is the encoding of a camera or secrets file
+type Format string
+
+const (
+	// FormatYAML is YAML (.yaml, .yml)
+	FormatYAML Format = "yaml"
+	// FormatJSON is JSON (.json)
+	FormatJSON Format = "json"
+)
+
+// FormatFromPath returns the file format implied by the path extension
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("config: unsupported file extension %q (use .yaml, .yml or .json)", filepath.Ext(path))
+	}
+}
+
+// Duration is a time.Duration written as a Go duration string ("500ms", "30s")
+type Duration time.Duration
+
+// UnmarshalText parses a duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalText formats the duration as a Go duration string
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Camera describes a single RTSP camera
+//
+// Zero-valued fields inherit from File.Defaults. Resolution and Acceleration
+// use the names of streamcapture.Resolution and streamcapture.HardwareAccel
+// ("720p", "vaapi", ...).
+type Camera struct {
+	// ID identifies the camera (required, unique within a file)
+	ID string `yaml:"id" json:"id"`
+	// URL is the RTSP URL without credentials (rtsp:// or rtsps://)
+	URL string `yaml:"url" json:"url"`
+	// Credentials names an entry of the secrets file (optional)
+	Credentials string `yaml:"credentials" json:"credentials"`
+	// FallbackURLs are tried in order when URL fails repeatedly (substream,
+	// relay); same rules and credentials as URL
+	FallbackURLs []string `yaml:"fallback_urls" json:"fallback_urls"`
+
+	Resolution   string  `yaml:"resolution" json:"resolution"`
+	TargetFPS    float64 `yaml:"target_fps" json:"target_fps"`
+	SourceStream string  `yaml:"source_stream" json:"source_stream"`
+	Acceleration string  `yaml:"acceleration" json:"acceleration"`
+
+	MaxReconnectAttempts  int      `yaml:"max_reconnect_attempts" json:"max_reconnect_attempts"`
+	ReconnectInitialDelay Duration `yaml:"reconnect_initial_delay" json:"reconnect_initial_delay"`
+	ReconnectMaxDelay     Duration `yaml:"reconnect_max_delay" json:"reconnect_max_delay"`
+}
+
+// withDefaults returns c with zero-valued fields taken from defaults
+//
+// ID, URL and FallbackURLs are never inherited.
+func (c Camera) withDefaults(defaults Camera) Camera {
+	if c.Credentials == "" {
+		c.Credentials = defaults.Credentials
+	}
+	if c.Resolution == "" {
+		c.Resolution = defaults.Resolution
+	}
+	if c.TargetFPS == 0 {
+		c.TargetFPS = defaults.TargetFPS
+	}
+	if c.SourceStream == "" {
+		c.SourceStream = defaults.SourceStream
+	}
+	if c.Acceleration == "" {
+		c.Acceleration = defaults.Acceleration
+	}
+	if c.MaxReconnectAttempts == 0 {
+		c.MaxReconnectAttempts = defaults.MaxReconnectAttempts
+	}
+	if c.ReconnectInitialDelay == 0 {
+		c.ReconnectInitialDelay = defaults.ReconnectInitialDelay
+	}
+	if c.ReconnectMaxDelay == 0 {
+		c.ReconnectMaxDelay = defaults.ReconnectMaxDelay
+	}
+	return c
+}
+
+// File is a camera configuration file
+type File struct {
+	// Defaults applies to every camera that leaves a field unset
+	Defaults Camera `yaml:"defaults" json:"defaults"`
+	// Cameras lists the configured cameras
+	Cameras []Camera `yaml:"cameras" json:"cameras"`
+}
+
+// Stream is a resolved camera, ready for streamcapture.NewRTSPStream
+type Stream struct {
+	// ID is the camera identifier
+	ID string
+	// Config has credentials injected into Config.URL; never log it directly
+	Config streamcapture.RTSPConfig
+}
+
+// RedactedURL returns Config.URL with the password replaced (safe to log)
+func (s Stream) RedactedURL() string {
+	return streamcapture.RedactURL(s.Config.URL)
+}
+
+// Load reads a camera file; the format is chosen by extension
+func Load(path string) (File, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return File{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("config: %w", err)
+	}
+
+	file, err := Parse(data, format)
+	if err != nil {
+		return File{}, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Parse decodes a camera file
+//
+// Unknown keys are rejected so that typos ("target_fsp") are not silently
+// ignored.
+func Parse(data []byte, format Format) (File, error) {
+	var file File
+	if err := decode(data, format, &file); err != nil {
+		return File{}, err
+	}
+	return file, nil
+}
+
+// decode unmarshals data in the given format, rejecting unknown keys
+func decode(data []byte, format Format, v any) error {
+	switch format {
+	case FormatYAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// Resolve validates every camera and produces its RTSPConfig
+//
+// This method:
+//  1. Applies Defaults to each camera
+//  2. Parses resolution and acceleration names
+//  3. Injects the referenced credentials into the URL
+//  4. Validates URL scheme, FPS range and credentials references
+//
+// Errors are not fail-on-first: all problems of all cameras are joined
+// (errors.Join) and returned together, each prefixed with the camera ID.
+// URLs in errors are redacted. On error, no streams are returned.
+func (f File) Resolve(secrets Secrets) ([]Stream, error) {
+	var errs []error
+
+	if len(f.Cameras) == 0 {
+		errs = append(errs, fmt.Errorf("no cameras configured"))
+	}
+
+	streams := make([]Stream, 0, len(f.Cameras))
+	seen := make(map[string]bool, len(f.Cameras))
+	for i, cam := range f.Cameras {
+		name := cam.ID
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+			errs = append(errs, fmt.Errorf("camera %s: id is required", name))
+		} else if seen[cam.ID] {
+			errs = append(errs, fmt.Errorf("camera %q: duplicate id", cam.ID))
+		}
+		seen[cam.ID] = true
+
+		cfg, camErrs := resolveCamera(cam.withDefaults(f.Defaults), secrets)
+		for _, err := range camErrs {
+			errs = append(errs, fmt.Errorf("camera %q: %w", name, err))
+		}
+		streams = append(streams, Stream{ID: cam.ID, Config: cfg})
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return streams, nil
+}
+
+// resolveCamera builds the RTSPConfig of a single camera, collecting all errors
+func resolveCamera(cam Camera, secrets Secrets) (streamcapture.RTSPConfig, []error) {
+	var errs []error
+
+	cfg := streamcapture.RTSPConfig{
+		TargetFPS:             cam.TargetFPS,
+		SourceStream:          cam.SourceStream,
+		MaxReconnectAttempts:  cam.MaxReconnectAttempts,
+		ReconnectInitialDelay: time.Duration(cam.ReconnectInitialDelay),
+		ReconnectMaxDelay:     time.Duration(cam.ReconnectMaxDelay),
+	}
+
+	resolution := cam.Resolution
+	if resolution == "" {
+		resolution = streamcapture.Res720p.String()
+	}
+	res, err := streamcapture.ParseResolution(resolution)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.Resolution = res
+
+	accel, err := streamcapture.ParseHardwareAccel(cam.Acceleration)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.Acceleration = accel
+
+	rawURL, err := injectCredentials(cam.URL, cam.Credentials, secrets)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.URL = rawURL
+
+	for i, fallback := range cam.FallbackURLs {
+		if fallback == "" {
+			errs = append(errs, fmt.Errorf("fallback_urls[%d] is empty", i))
+			continue
+		}
+		rawFallback, err := injectCredentials(fallback, cam.Credentials, secrets)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("fallback_urls[%d]: %w", i, err))
+		}
+		cfg.FallbackURLs = append(cfg.FallbackURLs, rawFallback)
+	}
+
+	// Same rules as RTSPConfig.Validate, checked individually so that every
+	// problem is reported (Validate stops at the first one)
+	if cam.URL == "" {
+		errs = append(errs, fmt.Errorf("url is required"))
+	}
+	if cam.TargetFPS == 0 {
+		errs = append(errs, fmt.Errorf("target_fps is required"))
+	} else if cam.TargetFPS < 0.1 || cam.TargetFPS > 30 {
+		errs = append(errs, fmt.Errorf("invalid target_fps %.2f (must be 0.1-30)", cam.TargetFPS))
+	}
+
+	return cfg, errs
+}
+
+// injectCredentials returns rawURL with the named credentials as userinfo
+func injectCredentials(rawURL, credentials string, secrets Secrets) (string, error) {
+	if rawURL == "" {
+		return "", nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", fmt.Errorf("invalid url %s: %w", streamcapture.RedactURL(rawURL), err)
+	}
+
+	if u.Scheme != "rtsp" && u.Scheme != "rtsps" {
+		return "", fmt.Errorf("invalid url scheme %q (must be rtsp or rtsps)", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid url %s: missing host", streamcapture.RedactURL(rawURL))
+	}
+
+	_, hasPassword := u.User.Password()
+	if credentials == "" {
+		return rawURL, nil
+	}
+	if hasPassword {
+		return "", fmt.Errorf("url embeds a password and also references credentials %q (move the password to the secrets file)", credentials)
+	}
+
+	cred, ok := secrets.Credentials[credentials]
+	if !ok {
+		return "", fmt.Errorf("credentials %q not found in secrets", credentials)
+	}
+	if cred.Password == "" {
+		u.User = url.User(cred.Username)
+	} else {
+		u.User = url.UserPassword(cred.Username, cred.Password)
+	}
+	return u.String(), nil
+}
+
+// Find returns the stream with the given ID
+func Find(streams []Stream, id string) (Stream, bool) {
+	for _, s := range streams {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Stream{}, false
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultEnvPrefix is the variable prefix used by LoadEnv callers by convention
+const DefaultEnvPrefix = "STREAM_CAPTURE_"
+
+// LoadEnv builds a camera file from environment variables
+//
+// <PREFIX>CAMERAS lists the camera IDs (comma-separated). Each camera is
+// configured by variables named after its upper-cased ID, with '-' and '.'
+// replaced by '_':
+//
+//	STREAM_CAPTURE_CAMERAS=hall,room-101
+//	STREAM_CAPTURE_HALL_URL=rtsp://10.0.0.5/stream1
+//	STREAM_CAPTURE_HALL_CREDENTIALS=hall-cam
+//	STREAM_CAPTURE_ROOM_101_URL=rtsp://10.0.0.6/live
+//	STREAM_CAPTURE_ROOM_101_FPS=1
+//
+// Per-camera variables: _URL, _FALLBACK_URLS (comma-separated),
+// _CREDENTIALS, _RESOLUTION, _FPS, _SOURCE_STREAM, _ACCELERATION,
+// _MAX_RECONNECT_ATTEMPTS, _RECONNECT_INITIAL_DELAY, _RECONNECT_MAX_DELAY.
+// The same suffixes without a camera ID (<PREFIX>FPS, ...) set Defaults.
+//
+// Passwords have no variable of their own: reference a secrets file entry
+// with _CREDENTIALS instead. Malformed numbers and durations are reported
+// together.
+func LoadEnv(prefix string) (File, error) {
+	return parseEnv(prefix, os.LookupEnv)
+}
+
+// parseEnv implements LoadEnv on top of an injectable lookup function
+func parseEnv(prefix string, lookup func(string) (string, bool)) (File, error) {
+	var file File
+	var errs []error
+
+	ids, ok := lookup(prefix + "CAMERAS")
+	if !ok || strings.TrimSpace(ids) == "" {
+		return File{}, fmt.Errorf("config: %sCAMERAS is not set", prefix)
+	}
+
+	errs = append(errs, envCamera(prefix, lookup, &file.Defaults)...)
+
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		cam := Camera{ID: id}
+		errs = append(errs, envCamera(prefix+envKey(id)+"_", lookup, &cam)...)
+		file.Cameras = append(file.Cameras, cam)
+	}
+
+	if len(errs) > 0 {
+		return File{}, errors.Join(errs...)
+	}
+	return file, nil
+}
+
+// envCamera fills cam from <prefix>URL, <prefix>FPS, ...
+func envCamera(prefix string, lookup func(string) (string, bool), cam *Camera) []error {
+	var errs []error
+
+	str := func(suffix string, dst *string) {
+		if v, ok := lookup(prefix + suffix); ok {
+			*dst = v
+		}
+	}
+	str("URL", &cam.URL)
+	str("CREDENTIALS", &cam.Credentials)
+	str("RESOLUTION", &cam.Resolution)
+	str("SOURCE_STREAM", &cam.SourceStream)
+	str("ACCELERATION", &cam.Acceleration)
+
+	if v, ok := lookup(prefix + "FALLBACK_URLS"); ok {
+		for _, u := range strings.Split(v, ",") {
+			cam.FallbackURLs = append(cam.FallbackURLs, strings.TrimSpace(u))
+		}
+	}
+
+	if v, ok := lookup(prefix + "FPS"); ok {
+		fps, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%sFPS: invalid number %q", prefix, v))
+		}
+		cam.TargetFPS = fps
+	}
+	if v, ok := lookup(prefix + "MAX_RECONNECT_ATTEMPTS"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%sMAX_RECONNECT_ATTEMPTS: invalid integer %q", prefix, v))
+		}
+		cam.MaxReconnectAttempts = n
+	}
+
+	duration := func(suffix string, dst *Duration) {
+		v, ok := lookup(prefix + suffix)
+		if !ok {
+			return
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: invalid duration %q", prefix, suffix, v))
+		}
+		*dst = Duration(d)
+	}
+	duration("RECONNECT_INITIAL_DELAY", &cam.ReconnectInitialDelay)
+	duration("RECONNECT_MAX_DELAY", &cam.ReconnectMaxDelay)
+
+	return errs
+}
+
+// envKey converts a camera ID to its variable name segment ("room-101" → "ROOM_101")
+func envKey(id string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", " ", "_").Replace(id))
+}
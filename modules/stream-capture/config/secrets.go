@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+)
+
+// Credential is a username/password pair for a camera
+//
+// String and LogValue redact the password, so a Credential can be printed
+// or logged without leaking it.
+type Credential struct {
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+// String returns "username:xxxxx" (password redacted)
+func (c Credential) String() string {
+	if c.Password == "" {
+		return c.Username
+	}
+	return c.Username + ":xxxxx"
+}
+
+// LogValue implements slog.LogValuer (password redacted)
+func (c Credential) LogValue() slog.Value {
+	return slog.StringValue(c.String())
+}
+
+// Secrets holds named camera credentials, loaded separately from the camera file
+type Secrets struct {
+	Credentials map[string]Credential `yaml:"credentials" json:"credentials"`
+}
+
+// LoadSecrets reads a secrets file; the format is chosen by extension
+//
+// The file must not be readable by group or others (chmod 600): a
+// world-readable secrets file is rejected rather than used. The check is
+// skipped on Windows, where Unix permission bits are not meaningful.
+func LoadSecrets(path string) (Secrets, error) {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return Secrets{}, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("config: %w", err)
+	}
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		return Secrets{}, fmt.Errorf("config: secrets file %s is accessible by group/others (mode %04o, want 0600)", path, perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("config: %w", err)
+	}
+
+	secrets, err := ParseSecrets(data, format)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("config: %s: %w", path, err)
+	}
+	return secrets, nil
+}
+
+// ParseSecrets decodes a secrets file
+func ParseSecrets(data []byte, format Format) (Secrets, error) {
+	var secrets Secrets
+	if err := decode(data, format, &secrets); err != nil {
+		return Secrets{}, err
+	}
+
+	for name, cred := range secrets.Credentials {
+		if cred.Username == "" {
+			return Secrets{}, fmt.Errorf("credentials %q: username is required", name)
+		}
+	}
+	return secrets, nil
+}
@@ -0,0 +1,63 @@
+package streamcapture
+
+import (
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsp"
+)
+
+// ErrorRecord describes a single stream error (see StreamStats.RecentErrors)
+//
+// The four StreamStats error counters say how often a sensor failed;
+// ErrorRecord says what actually happened, without shell access to logs.
+type ErrorRecord struct {
+	// Time is when the error was observed
+	Time time.Time
+	// Category is the telemetry classification (same as the StreamStats counters)
+	Category ErrorCategory
+	// Element is the GStreamer element that posted the error (e.g. "rtspsrc0").
+	// Empty for HTTP sources (MJPEGStream).
+	Element string
+	// Message is the human-readable error message
+	Message string
+	// Debug is the GStreamer debug string (source location and details)
+	Debug string
+	// Domain is the GError domain (e.g. "gst-resource-error-quark"), empty if unavailable
+	Domain string
+	// Code is the GError code within Domain (0 if unavailable)
+	Code int
+	// ReconnectAttempt is the number of consecutive failed attempts before this error
+	ReconnectAttempt int
+}
+
+// toErrorRecord converts an internal record to the public type
+func toErrorRecord(rec rtsp.ErrorRecord) ErrorRecord {
+	return ErrorRecord{
+		Time:             rec.Time,
+		Category:         ErrorCategory(rec.Category),
+		Element:          rec.Element,
+		Message:          rec.Message,
+		Debug:            rec.Debug,
+		Domain:           string(rec.Domain),
+		Code:             rec.Code,
+		ReconnectAttempt: rec.ReconnectAttempt,
+	}
+}
+
+// errorHistoryStats returns the recent errors (oldest first) and the last one
+//
+// Shared by every provider so StreamStats error details have the same shape
+// regardless of the camera protocol.
+func errorHistoryStats(history *rtsp.ErrorHistory) (recent []ErrorRecord, last *ErrorRecord) {
+	records := history.Records()
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	recent = make([]ErrorRecord, len(records))
+	for i, rec := range records {
+		recent[i] = toErrorRecord(rec)
+	}
+	last = &recent[len(recent)-1]
+	return recent, last
+}
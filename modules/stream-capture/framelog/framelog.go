@@ -0,0 +1,267 @@
+// Package framelog implements an append-only file format for recording
+// captured frames and reading them back bit-for-bit.
+//
+// A frame log stores exactly what a consumer received: pixel payload plus
+// the metadata of streamcapture.Frame (sequence number, capture timestamp,
+// dimensions, source stream, trace ID) and the time the frame was received,
+// which replay uses to reproduce the original timing.
+//
+// File layout (all integers little-endian):
+//
+//	Header   magic "ORFLOG" | version u16 | created unix-nanos i64 | reserved [8]
+//	Record*  magic "FRAM" | body length u32 | CRC-32 (IEEE) of body u32 | body
+//	         body: seq u64 | timestamp i64 | received i64 | width u32 | height u32 |
+//	               format u8 | source len u16 + bytes | trace ID len u16 + bytes | payload
+//	Index    magic "ORFLIDX1" | count u64 | {offset u64, seq u64, received i64}* | CRC-32 u32
+//	Trailer  index offset u64 | magic "ORFLEND1"
+//
+// Records are appended as frames arrive; the index and trailer are written
+// by Writer.Close. A log whose writer crashed (no trailer) is still readable:
+// Reader rebuilds the index by scanning records and drops a truncated or
+// corrupt tail.
+//
+// Example:
+//
+//	w, _ := framelog.Create("incident.flog")
+//	w.Write(framelog.Record{Seq: frame.Seq, Timestamp: frame.Timestamp, ...})
+//	w.Close()
+//
+//	r, _ := framelog.Open("incident.flog")
+//	defer r.Close()
+//	for i := 0; i < r.Len(); i++ {
+//	    rec, err := r.Read(i)
+//	}
+//
+// streamcapture.NewRecorder taps a StreamProvider into a Writer and
+// streamcapture.NewReplayStream replays a log as a StreamProvider.
+package framelog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// Version is the format version written by this package
+	Version = 1
+
+	headerSize       = 24
+	recordHeaderSize = 12
+	recordFixedSize  = 8 + 8 + 8 + 4 + 4 + 1 + 2 + 2 // body without strings and payload
+	indexEntrySize   = 24
+	trailerSize      = 16
+
+	// maxRecordSize rejects absurd lengths when scanning a damaged file
+	maxRecordSize = 512 << 20
+)
+
+var (
+	headerMagic  = [6]byte{'O', 'R', 'F', 'L', 'O', 'G'}
+	recordMagic  = [4]byte{'F', 'R', 'A', 'M'}
+	indexMagic   = [8]byte{'O', 'R', 'F', 'L', 'I', 'D', 'X', '1'}
+	trailerMagic = [8]byte{'O', 'R', 'F', 'L', 'E', 'N', 'D', '1'}
+)
+
+// ErrCorrupt is returned when a record fails validation (bad magic, length or CRC)
+var ErrCorrupt = errors.New("framelog: corrupt record")
+
+// PixelFormat identifies the payload layout
+type PixelFormat uint8
+
+const (
+	// FormatRGB24 is interleaved 8-bit RGB (streamcapture.Frame.Data)
+	FormatRGB24 PixelFormat = 1
+)
+
+// String returns the format name
+func (f PixelFormat) String() string {
+	switch f {
+	case FormatRGB24:
+		return "rgb24"
+	default:
+		return fmt.Sprintf("format(%d)", uint8(f))
+	}
+}
+
+// Record is a single recorded frame
+type Record struct {
+	Seq          uint64
+	Timestamp    time.Time // Capture timestamp (streamcapture.Frame.Timestamp)
+	Received     time.Time // When the frame was received by the recorder
+	Width        int
+	Height       int
+	Format       PixelFormat
+	SourceStream string
+	TraceID      string
+	Data         []byte
+}
+
+// IndexEntry locates a record in the file
+type IndexEntry struct {
+	Offset   int64
+	Seq      uint64
+	Received time.Time
+}
+
+// Header is the file header
+type Header struct {
+	Version uint16
+	Created time.Time
+}
+
+// Writer appends records to a frame log
+//
+// Not safe for concurrent use.
+type Writer struct {
+	w      *bufio.Writer
+	closer io.Closer // Underlying file (nil if not owned)
+	offset int64
+	index  []IndexEntry
+	closed bool
+}
+
+// Create creates (truncates) a frame log file
+func Create(path string) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("framelog: %w", err)
+	}
+	w, err := NewWriter(f, time.Now())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	w.closer = f
+	return w, nil
+}
+
+// NewWriter writes a header to w and returns a Writer appending to it
+//
+// The caller keeps ownership of w (Close does not close it).
+func NewWriter(w io.Writer, created time.Time) (*Writer, error) {
+	fw := &Writer{w: bufio.NewWriterSize(w, 1<<20)}
+
+	var header [headerSize]byte
+	copy(header[0:6], headerMagic[:])
+	binary.LittleEndian.PutUint16(header[6:], Version)
+	binary.LittleEndian.PutUint64(header[8:], uint64(created.UnixNano()))
+	if _, err := fw.w.Write(header[:]); err != nil {
+		return nil, fmt.Errorf("framelog: write header: %w", err)
+	}
+	fw.offset = headerSize
+	return fw, nil
+}
+
+// Write appends a record
+//
+// The record is buffered; call Flush to push it to the underlying writer.
+func (w *Writer) Write(rec Record) error {
+	if w.closed {
+		return errors.New("framelog: write on closed writer")
+	}
+	if len(rec.SourceStream) > 0xFFFF || len(rec.TraceID) > 0xFFFF {
+		return errors.New("framelog: source stream or trace ID too long")
+	}
+
+	bodyLen := recordFixedSize + len(rec.SourceStream) + len(rec.TraceID) + len(rec.Data)
+	if bodyLen > maxRecordSize {
+		return fmt.Errorf("framelog: record too large (%d bytes)", bodyLen)
+	}
+
+	body := make([]byte, bodyLen)
+	binary.LittleEndian.PutUint64(body[0:], rec.Seq)
+	binary.LittleEndian.PutUint64(body[8:], uint64(rec.Timestamp.UnixNano()))
+	binary.LittleEndian.PutUint64(body[16:], uint64(rec.Received.UnixNano()))
+	binary.LittleEndian.PutUint32(body[24:], uint32(rec.Width))
+	binary.LittleEndian.PutUint32(body[28:], uint32(rec.Height))
+	body[32] = byte(rec.Format)
+	pos := 33
+	binary.LittleEndian.PutUint16(body[pos:], uint16(len(rec.SourceStream)))
+	pos += 2 + copy(body[pos+2:], rec.SourceStream)
+	binary.LittleEndian.PutUint16(body[pos:], uint16(len(rec.TraceID)))
+	pos += 2 + copy(body[pos+2:], rec.TraceID)
+	copy(body[pos:], rec.Data)
+
+	var header [recordHeaderSize]byte
+	copy(header[0:4], recordMagic[:])
+	binary.LittleEndian.PutUint32(header[4:], uint32(bodyLen))
+	binary.LittleEndian.PutUint32(header[8:], crc32.ChecksumIEEE(body))
+
+	if _, err := w.w.Write(header[:]); err != nil {
+		return fmt.Errorf("framelog: write record: %w", err)
+	}
+	if _, err := w.w.Write(body); err != nil {
+		return fmt.Errorf("framelog: write record: %w", err)
+	}
+
+	w.index = append(w.index, IndexEntry{Offset: w.offset, Seq: rec.Seq, Received: rec.Received})
+	w.offset += recordHeaderSize + int64(bodyLen)
+	return nil
+}
+
+// Flush writes buffered records to the underlying writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Len returns the number of records written
+func (w *Writer) Len() int {
+	return len(w.index)
+}
+
+// Close writes the index and trailer, flushes, and closes the file if owned
+//
+// Idempotent.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := w.writeIndex()
+	if flushErr := w.w.Flush(); err == nil {
+		err = flushErr
+	}
+	if w.closer != nil {
+		if closeErr := w.closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("framelog: close: %w", err)
+	}
+	return nil
+}
+
+// writeIndex appends the index block and trailer
+func (w *Writer) writeIndex() error {
+	indexOffset := w.offset
+
+	block := make([]byte, 16+len(w.index)*indexEntrySize+4)
+	copy(block[0:8], indexMagic[:])
+	binary.LittleEndian.PutUint64(block[8:], uint64(len(w.index)))
+	entries := block[16 : 16+len(w.index)*indexEntrySize]
+	for i, e := range w.index {
+		b := entries[i*indexEntrySize:]
+		binary.LittleEndian.PutUint64(b[0:], uint64(e.Offset))
+		binary.LittleEndian.PutUint64(b[8:], e.Seq)
+		binary.LittleEndian.PutUint64(b[16:], uint64(e.Received.UnixNano()))
+	}
+	binary.LittleEndian.PutUint32(block[len(block)-4:], crc32.ChecksumIEEE(entries))
+
+	var trailer [trailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[0:], uint64(indexOffset))
+	copy(trailer[8:], trailerMagic[:])
+
+	if _, err := w.w.Write(block); err != nil {
+		return err
+	}
+	_, err := w.w.Write(trailer[:])
+	return err
+}
@@ -0,0 +1,234 @@
+package framelog
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testRecords returns n records 100ms apart with distinct payloads
+func testRecords(n int) []Record {
+	base := time.Unix(1700000000, 0)
+	records := make([]Record, n)
+	for i := range records {
+		records[i] = Record{
+			Seq:          uint64(i + 10),
+			Timestamp:    base.Add(time.Duration(i) * 100 * time.Millisecond),
+			Received:     base.Add(time.Duration(i)*100*time.Millisecond + 5*time.Millisecond),
+			Width:        4,
+			Height:       2,
+			Format:       FormatRGB24,
+			SourceStream: "LQ",
+			TraceID:      "trace-" + string(rune('a'+i)),
+			Data:         bytes.Repeat([]byte{byte(i)}, 4*2*3),
+		}
+	}
+	return records
+}
+
+// writeLog encodes records into memory, optionally without Close (no index)
+func writeLog(t *testing.T, records []Record, close bool) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, rec := range records {
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if close {
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	} else if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func assertRecord(t *testing.T, got, want Record) {
+	t.Helper()
+
+	if got.Seq != want.Seq || !got.Timestamp.Equal(want.Timestamp) || !got.Received.Equal(want.Received) {
+		t.Errorf("record seq/time = %d %v %v, want %d %v %v",
+			got.Seq, got.Timestamp, got.Received, want.Seq, want.Timestamp, want.Received)
+	}
+	if got.Width != want.Width || got.Height != want.Height || got.Format != want.Format {
+		t.Errorf("record geometry = %dx%d %v, want %dx%d %v",
+			got.Width, got.Height, got.Format, want.Width, want.Height, want.Format)
+	}
+	if got.SourceStream != want.SourceStream || got.TraceID != want.TraceID {
+		t.Errorf("record source/trace = %q %q, want %q %q",
+			got.SourceStream, got.TraceID, want.SourceStream, want.TraceID)
+	}
+	if !bytes.Equal(got.Data, want.Data) {
+		t.Errorf("record %d payload mismatch", want.Seq)
+	}
+}
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	records := testRecords(5)
+	path := filepath.Join(t.TempDir(), "test.flog")
+
+	w, err := Create(path)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, rec := range records {
+		if err := w.Write(rec); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if w.Len() != len(records) {
+		t.Errorf("Writer.Len() = %d, want %d", w.Len(), len(records))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close() error = %v, want nil (idempotent)", err)
+	}
+	if err := w.Write(records[0]); err == nil {
+		t.Error("Write() after Close succeeded, want error")
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer r.Close()
+
+	if r.Header().Version != Version {
+		t.Errorf("Header().Version = %d, want %d", r.Header().Version, Version)
+	}
+	if r.Recovered() {
+		t.Error("Recovered() = true for a closed log, want false")
+	}
+	if r.Len() != len(records) {
+		t.Fatalf("Len() = %d, want %d", r.Len(), len(records))
+	}
+
+	for i, want := range records {
+		got, err := r.Read(i)
+		if err != nil {
+			t.Fatalf("Read(%d) error = %v", i, err)
+		}
+		assertRecord(t, got, want)
+
+		entry := r.Entry(i)
+		if entry.Seq != want.Seq || !entry.Received.Equal(want.Received) {
+			t.Errorf("Entry(%d) = %+v, want seq %d received %v", i, entry, want.Seq, want.Received)
+		}
+	}
+
+	if _, err := r.Read(len(records)); err == nil {
+		t.Error("Read() out of range succeeded, want error")
+	}
+}
+
+func TestReader_Find(t *testing.T) {
+	data := writeLog(t, testRecords(5), true)
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+
+	i, ok := r.Find(12)
+	if !ok || i != 2 {
+		t.Errorf("Find(12) = %d, %v, want 2, true", i, ok)
+	}
+	if _, ok := r.Find(99); ok {
+		t.Error("Find(99) found a record, want not found")
+	}
+}
+
+func TestReader_RecoversWithoutIndex(t *testing.T) {
+	records := testRecords(4)
+
+	tests := []struct {
+		name string
+		data func() []byte
+		want int
+	}{
+		{
+			name: "writer not closed",
+			data: func() []byte { return writeLog(t, records, false) },
+			want: 4,
+		},
+		{
+			name: "truncated last record",
+			data: func() []byte {
+				data := writeLog(t, records, false)
+				return data[:len(data)-7]
+			},
+			want: 3,
+		},
+		{
+			name: "truncated trailer",
+			data: func() []byte {
+				data := writeLog(t, records, true)
+				return data[:len(data)-3]
+			},
+			want: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data()
+			r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			if !r.Recovered() {
+				t.Error("Recovered() = false, want true")
+			}
+			if r.Len() != tt.want {
+				t.Fatalf("Len() = %d, want %d", r.Len(), tt.want)
+			}
+			for i := 0; i < r.Len(); i++ {
+				got, err := r.Read(i)
+				if err != nil {
+					t.Fatalf("Read(%d) error = %v", i, err)
+				}
+				assertRecord(t, got, records[i])
+			}
+		})
+	}
+}
+
+func TestReader_DetectsCorruption(t *testing.T) {
+	data := writeLog(t, testRecords(3), true)
+
+	// Flip a payload byte of the second record
+	r, err := NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	offset := r.Entry(1).Offset
+	data[offset+recordHeaderSize+recordFixedSize+10] ^= 0xFF
+
+	r, err = NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	if _, err := r.Read(0); err != nil {
+		t.Errorf("Read(0) error = %v, want nil", err)
+	}
+	if _, err := r.Read(1); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("Read(1) error = %v, want ErrCorrupt", err)
+	}
+}
+
+func TestNewReader_RejectsForeignFile(t *testing.T) {
+	data := []byte("this is not a frame log at all")
+	if _, err := NewReader(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("NewReader() succeeded on foreign data, want error")
+	}
+}
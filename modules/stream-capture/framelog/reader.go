@@ -0,0 +1,273 @@
+package framelog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+// Reader reads records from a frame log by index
+//
+// Safe for concurrent Read calls (reads use io.ReaderAt).
+type Reader struct {
+	r         io.ReaderAt
+	size      int64
+	closer    io.Closer
+	header    Header
+	index     []IndexEntry
+	bySeq     map[uint64]int
+	recovered bool
+}
+
+// Open opens a frame log file
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("framelog: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("framelog: %w", err)
+	}
+
+	r, err := NewReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	r.closer = f
+	return r, nil
+}
+
+// NewReader reads the header and index of a frame log of the given size
+//
+// If the file has no valid index (writer did not Close), records are
+// scanned and the index is rebuilt; Recovered then reports true.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	var header [headerSize]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, fmt.Errorf("framelog: read header: %w", err)
+	}
+	if !bytes.Equal(header[0:6], headerMagic[:]) {
+		return nil, errors.New("framelog: not a frame log (bad magic)")
+	}
+	version := binary.LittleEndian.Uint16(header[6:])
+	if version != Version {
+		return nil, fmt.Errorf("framelog: unsupported version %d", version)
+	}
+
+	fr := &Reader{
+		r:    r,
+		size: size,
+		header: Header{
+			Version: version,
+			Created: time.Unix(0, int64(binary.LittleEndian.Uint64(header[8:]))),
+		},
+	}
+
+	index, err := readIndex(r, size)
+	if err != nil {
+		index = scanIndex(r, size)
+		fr.recovered = true
+	}
+	fr.index = index
+
+	fr.bySeq = make(map[uint64]int, len(index))
+	for i, e := range index {
+		if _, exists := fr.bySeq[e.Seq]; !exists {
+			fr.bySeq[e.Seq] = i
+		}
+	}
+	return fr, nil
+}
+
+// readIndex reads the index block referenced by the trailer
+func readIndex(r io.ReaderAt, size int64) ([]IndexEntry, error) {
+	if size < headerSize+trailerSize {
+		return nil, errors.New("no trailer")
+	}
+
+	var trailer [trailerSize]byte
+	if _, err := r.ReadAt(trailer[:], size-trailerSize); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(trailer[8:], trailerMagic[:]) {
+		return nil, errors.New("no trailer")
+	}
+
+	indexOffset := int64(binary.LittleEndian.Uint64(trailer[0:]))
+	if indexOffset < headerSize || indexOffset > size-trailerSize-16 {
+		return nil, errors.New("bad index offset")
+	}
+
+	var head [16]byte
+	if _, err := r.ReadAt(head[:], indexOffset); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(head[0:8], indexMagic[:]) {
+		return nil, errors.New("bad index magic")
+	}
+	count := binary.LittleEndian.Uint64(head[8:])
+	if count > uint64(size/indexEntrySize) {
+		return nil, errors.New("bad index count")
+	}
+
+	entries := make([]byte, int(count)*indexEntrySize+4)
+	if _, err := r.ReadAt(entries, indexOffset+16); err != nil {
+		return nil, err
+	}
+	crc := binary.LittleEndian.Uint32(entries[len(entries)-4:])
+	entries = entries[:len(entries)-4]
+	if crc32.ChecksumIEEE(entries) != crc {
+		return nil, errors.New("bad index checksum")
+	}
+
+	index := make([]IndexEntry, count)
+	for i := range index {
+		b := entries[i*indexEntrySize:]
+		index[i] = IndexEntry{
+			Offset:   int64(binary.LittleEndian.Uint64(b[0:])),
+			Seq:      binary.LittleEndian.Uint64(b[8:]),
+			Received: time.Unix(0, int64(binary.LittleEndian.Uint64(b[16:]))),
+		}
+	}
+	return index, nil
+}
+
+// scanIndex rebuilds the index by walking records from the header
+//
+// Stops at the first invalid record (truncated write, corruption, or the
+// start of a partially written index).
+func scanIndex(r io.ReaderAt, size int64) []IndexEntry {
+	var index []IndexEntry
+	offset := int64(headerSize)
+	for offset+recordHeaderSize <= size {
+		rec, next, err := readRecord(r, offset, size)
+		if err != nil {
+			break
+		}
+		index = append(index, IndexEntry{Offset: offset, Seq: rec.Seq, Received: rec.Received})
+		offset = next
+	}
+	return index
+}
+
+// readRecord reads and validates the record at offset
+//
+// Returns the record and the offset of the next one.
+func readRecord(r io.ReaderAt, offset, size int64) (Record, int64, error) {
+	var header [recordHeaderSize]byte
+	if _, err := r.ReadAt(header[:], offset); err != nil {
+		return Record{}, 0, err
+	}
+	if !bytes.Equal(header[0:4], recordMagic[:]) {
+		return Record{}, 0, ErrCorrupt
+	}
+	bodyLen := int64(binary.LittleEndian.Uint32(header[4:]))
+	if bodyLen < recordFixedSize || bodyLen > maxRecordSize || offset+recordHeaderSize+bodyLen > size {
+		return Record{}, 0, ErrCorrupt
+	}
+
+	body := make([]byte, bodyLen)
+	if _, err := r.ReadAt(body, offset+recordHeaderSize); err != nil {
+		return Record{}, 0, err
+	}
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[8:]) {
+		return Record{}, 0, ErrCorrupt
+	}
+
+	rec, err := decodeBody(body)
+	if err != nil {
+		return Record{}, 0, err
+	}
+	return rec, offset + recordHeaderSize + bodyLen, nil
+}
+
+// decodeBody parses a validated record body
+func decodeBody(body []byte) (Record, error) {
+	rec := Record{
+		Seq:       binary.LittleEndian.Uint64(body[0:]),
+		Timestamp: time.Unix(0, int64(binary.LittleEndian.Uint64(body[8:]))),
+		Received:  time.Unix(0, int64(binary.LittleEndian.Uint64(body[16:]))),
+		Width:     int(binary.LittleEndian.Uint32(body[24:])),
+		Height:    int(binary.LittleEndian.Uint32(body[28:])),
+		Format:    PixelFormat(body[32]),
+	}
+
+	pos := 33
+	readString := func() (string, bool) {
+		if pos+2 > len(body) {
+			return "", false
+		}
+		n := int(binary.LittleEndian.Uint16(body[pos:]))
+		pos += 2
+		if pos+n > len(body) {
+			return "", false
+		}
+		s := string(body[pos : pos+n])
+		pos += n
+		return s, true
+	}
+
+	var ok bool
+	if rec.SourceStream, ok = readString(); !ok {
+		return Record{}, ErrCorrupt
+	}
+	if rec.TraceID, ok = readString(); !ok {
+		return Record{}, ErrCorrupt
+	}
+	rec.Data = body[pos:]
+	return rec, nil
+}
+
+// Header returns the file header
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Len returns the number of records
+func (r *Reader) Len() int {
+	return len(r.index)
+}
+
+// Recovered reports whether the index was rebuilt by scanning (writer did not Close)
+func (r *Reader) Recovered() bool {
+	return r.recovered
+}
+
+// Entry returns the index entry of record i (no payload read)
+func (r *Reader) Entry(i int) IndexEntry {
+	return r.index[i]
+}
+
+// Read returns record i, validating its checksum
+func (r *Reader) Read(i int) (Record, error) {
+	if i < 0 || i >= len(r.index) {
+		return Record{}, fmt.Errorf("framelog: record %d out of range [0, %d)", i, len(r.index))
+	}
+	rec, _, err := readRecord(r.r, r.index[i].Offset, r.size)
+	if err != nil {
+		return Record{}, fmt.Errorf("framelog: record %d: %w", i, err)
+	}
+	return rec, nil
+}
+
+// Find returns the index of the first record with the given sequence number
+func (r *Reader) Find(seq uint64) (int, bool) {
+	i, ok := r.bySeq[seq]
+	return i, ok
+}
+
+// Close closes the file if the Reader owns it
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
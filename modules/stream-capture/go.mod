@@ -3,6 +3,6 @@ module github.com/e7canasta/orion-care-sensor/modules/stream-capture
 go 1.21
 
 require (
-	github.com/google/uuid v1.6.0 // indirect
 	github.com/tinyzimmer/go-gst v0.2.33 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,271 @@
+//go:build integration
+
+// Integration tests: real GStreamer pipeline against a local RTSP server
+// (internal/rtsptest). Run with:
+//
+//	go test -tags integration -run Integration -v .
+//
+// Tests skip when the required GStreamer elements are not installed
+// (gstreamer1.0-plugins-good for rtspsrc/rtph264depay, gstreamer1.0-libav
+// for avdec_h264).
+package streamcapture
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsp"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsptest"
+	"github.com/tinyzimmer/go-gst/gst"
+)
+
+// requireGStreamer skips the test if the software decode pipeline cannot be built
+func requireGStreamer(t *testing.T) {
+	t.Helper()
+	gst.Init(nil)
+	for _, name := range []string{
+		"rtspsrc", "rtph264depay", "avdec_h264", "videoconvert",
+		"videoscale", "videorate", "capsfilter", "appsink",
+	} {
+		if _, err := gst.NewElement(name); err != nil {
+			t.Skipf("Skipping integration test: GStreamer element %s not available: %v", name, err)
+		}
+	}
+}
+
+// newTestServer starts a local RTSP server closed at test end
+func newTestServer(t *testing.T, cfg rtsptest.Config) *rtsptest.Server {
+	t.Helper()
+	srv, err := rtsptest.NewServer(cfg)
+	if err != nil {
+		t.Fatalf("rtsptest.NewServer() error = %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+// newIntegrationStream creates a software-decoded stream with fast reconnects
+func newIntegrationStream(t *testing.T, url string, fps float64) *RTSPStream {
+	t.Helper()
+	stream, err := NewRTSPStream(RTSPConfig{
+		URL:                   url,
+		Resolution:            Res480p,
+		TargetFPS:             fps,
+		SourceStream:          "integration",
+		Acceleration:          AccelSoftware,
+		MaxReconnectAttempts:  3,
+		ReconnectInitialDelay: 100 * time.Millisecond,
+		ReconnectMaxDelay:     500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewRTSPStream() error = %v", err)
+	}
+	t.Cleanup(func() { stream.Stop() })
+	return stream
+}
+
+// receiveFrames waits for n frames
+func receiveFrames(t *testing.T, frames <-chan Frame, n int, timeout time.Duration) []Frame {
+	t.Helper()
+	deadline := time.After(timeout)
+	received := make([]Frame, 0, n)
+	for len(received) < n {
+		select {
+		case frame, ok := <-frames:
+			if !ok {
+				t.Fatalf("frame channel closed after %d/%d frames", len(received), n)
+			}
+			received = append(received, frame)
+		case <-deadline:
+			t.Fatalf("received %d/%d frames within %v", len(received), n, timeout)
+		}
+	}
+	return received
+}
+
+// countFrames counts frames received during d
+func countFrames(frames <-chan Frame, d time.Duration) int {
+	deadline := time.After(d)
+	count := 0
+	for {
+		select {
+		case _, ok := <-frames:
+			if !ok {
+				return count
+			}
+			count++
+		case <-deadline:
+			return count
+		}
+	}
+}
+
+// waitFor polls cond until it holds or timeout expires
+func waitFor(t *testing.T, timeout time.Duration, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout after %v waiting for %s", timeout, what)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// TestIntegration_Lifecycle tests Start → frames → Stats → Stop
+func TestIntegration_Lifecycle(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{FPS: 10})
+	stream := newIntegrationStream(t, srv.URL(), 5)
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	received := receiveFrames(t, frames, 5, 15*time.Second)
+	width, height := Res480p.Dimensions()
+	for _, frame := range received {
+		if frame.Width != width || frame.Height != height {
+			t.Fatalf("frame %d is %dx%d, want %dx%d", frame.Seq, frame.Width, frame.Height, width, height)
+		}
+		if len(frame.Data) != width*height*3 {
+			t.Fatalf("frame %d has %d bytes, want %d (RGB)", frame.Seq, len(frame.Data), width*height*3)
+		}
+	}
+
+	stats := stream.Stats()
+	if !stats.IsConnected || stats.FrameCount < 5 {
+		t.Errorf("Stats() = connected %v, frames %d", stats.IsConnected, stats.FrameCount)
+	}
+
+	if err := stream.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if live := rtsp.LivePipelines(); live != 0 {
+		t.Errorf("LivePipelines() = %d after Stop, want 0", live)
+	}
+	waitFor(t, 5*time.Second, "server session closed", func() bool { return srv.ActiveSessions() == 0 })
+}
+
+// TestIntegration_SetTargetFPS tests hot-reload of the frame rate on a live stream
+func TestIntegration_SetTargetFPS(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{FPS: 10})
+	stream := newIntegrationStream(t, srv.URL(), 5)
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	receiveFrames(t, frames, 3, 15*time.Second)
+
+	before := countFrames(frames, 3*time.Second)
+
+	if err := stream.SetTargetFPS(1); err != nil {
+		t.Fatalf("SetTargetFPS(1) error = %v", err)
+	}
+	countFrames(frames, time.Second) // renegotiation settles
+	after := countFrames(frames, 3*time.Second)
+
+	t.Logf("frames in 3s: %d at 5 FPS, %d at 1 FPS", before, after)
+	if after*2 >= before {
+		t.Errorf("frame rate not reduced: %d frames before, %d after SetTargetFPS(1)", before, after)
+	}
+	if got := stream.Stats().FPSTarget; got != 1 {
+		t.Errorf("Stats().FPSTarget = %v, want 1", got)
+	}
+}
+
+// TestIntegration_ReconnectAfterDrop tests recovery from a lost connection
+func TestIntegration_ReconnectAfterDrop(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{FPS: 10})
+	stream := newIntegrationStream(t, srv.URL(), 5)
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	receiveFrames(t, frames, 3, 15*time.Second)
+
+	srv.DropConnections()
+
+	waitFor(t, 20*time.Second, "reconnect", func() bool { return stream.Stats().Reconnects >= 1 })
+	waitFor(t, 20*time.Second, "new server connection", func() bool { return srv.Connections() >= 2 })
+
+	// Frames flow again after the reconnect
+	countFrames(frames, 500*time.Millisecond)
+	receiveFrames(t, frames, 3, 15*time.Second)
+}
+
+// TestIntegration_WrongCredentials tests auth classification and URL redaction
+func TestIntegration_WrongCredentials(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{Username: "admin", Password: "s3cret"})
+	stream := newIntegrationStream(t, srv.URLWithCredentials("admin", "wrong-password"), 5)
+
+	if _, err := stream.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitFor(t, 20*time.Second, "auth error", func() bool { return stream.Stats().ErrorsAuth >= 1 })
+
+	stats := stream.Stats()
+	if stats.LastError == nil || stats.LastError.Category != ErrCategoryAuth {
+		t.Fatalf("LastError = %+v, want auth category", stats.LastError)
+	}
+	for _, rec := range stats.RecentErrors {
+		if strings.Contains(rec.Message, "wrong-password") || strings.Contains(rec.Debug, "wrong-password") {
+			t.Errorf("error record leaks the password: %+v", rec)
+		}
+	}
+	if stats.FrameCount != 0 {
+		t.Errorf("FrameCount = %d with wrong credentials, want 0", stats.FrameCount)
+	}
+}
+
+// TestIntegration_CodecChange tests codec classification when the camera switches codec
+func TestIntegration_CodecChange(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{FPS: 10})
+	srv.SetCodec(rtsptest.CodecH265)
+	stream := newIntegrationStream(t, srv.URL(), 5)
+
+	if _, err := stream.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitFor(t, 20*time.Second, "codec error", func() bool { return stream.Stats().ErrorsCodec >= 1 })
+
+	if last := stream.Stats().LastError; last == nil || last.Category != ErrCategoryCodec {
+		t.Errorf("LastError = %+v, want codec category", last)
+	}
+}
+
+// TestIntegration_StallRecovery tests a camera that stops sending without disconnecting
+func TestIntegration_StallRecovery(t *testing.T) {
+	requireGStreamer(t)
+	srv := newTestServer(t, rtsptest.Config{FPS: 10})
+	stream := newIntegrationStream(t, srv.URL(), 5)
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	receiveFrames(t, frames, 3, 15*time.Second)
+
+	srv.SetStalled(true)
+	countFrames(frames, 500*time.Millisecond) // drain in-flight frames
+	if n := countFrames(frames, 1500*time.Millisecond); n != 0 {
+		t.Errorf("received %d frames while stalled, want 0", n)
+	}
+	if latency := stream.Stats().LatencyMS; latency < 1000 {
+		t.Errorf("Stats().LatencyMS = %d while stalled, want >= 1000", latency)
+	}
+
+	srv.SetStalled(false)
+	receiveFrames(t, frames, 3, 15*time.Second)
+}
@@ -0,0 +1,43 @@
+package degrade
+
+import (
+	"runtime"
+	"time"
+)
+
+// CPUSampler measures process CPU usage between successive calls
+//
+// Usage is normalized to all cores: 100% means every core is busy with
+// this process.
+type CPUSampler struct {
+	lastWall time.Time
+	lastCPU  time.Duration
+	cpus     int
+}
+
+// NewCPUSampler creates a sampler; the first Sample establishes the baseline
+func NewCPUSampler() *CPUSampler {
+	return &CPUSampler{cpus: runtime.NumCPU()}
+}
+
+// Sample returns CPU usage since the previous call
+//
+// ok is false on the first call and on platforms without process CPU time.
+func (c *CPUSampler) Sample(now time.Time) (percent float64, ok bool) {
+	cpu, supported := processCPUTime()
+	if !supported {
+		return 0, false
+	}
+
+	prevWall, prevCPU := c.lastWall, c.lastCPU
+	c.lastWall, c.lastCPU = now, cpu
+	if prevWall.IsZero() {
+		return 0, false
+	}
+
+	wall := now.Sub(prevWall)
+	if wall <= 0 || c.cpus <= 0 {
+		return 0, false
+	}
+	return float64(cpu-prevCPU) / float64(wall) / float64(c.cpus) * 100, true
+}
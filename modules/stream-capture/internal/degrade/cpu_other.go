@@ -0,0 +1,10 @@
+//go:build !unix
+
+package degrade
+
+import "time"
+
+// processCPUTime is not supported on this platform (CPU signal disabled)
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,145 @@
+// Package degrade decides when a stream should step down or up its
+// degradation ladder.
+//
+// The Controller is pure decision logic: it is fed one Sample per interval
+// (windowed drop rate, decode latency, process CPU) and returns the new
+// ladder level. Applying a level (FPS, resolution, URL) is the caller's job,
+// which keeps this package free of GStreamer and testable without sleeping.
+package degrade
+
+import "fmt"
+
+// Thresholds configures pressure detection and hysteresis
+//
+// A signal is under pressure when it exceeds its Max value, and has
+// headroom when it is at or below its Recover value. Max values of 0
+// disable the signal.
+type Thresholds struct {
+	MaxDropRate     float64 // Percent of frames dropped in the interval
+	RecoverDropRate float64
+
+	MaxDecodeP95MS     float64 // Decode latency p95 in milliseconds
+	RecoverDecodeP95MS float64
+
+	MaxCPUPercent     float64 // Process CPU, percent of all cores
+	RecoverCPUPercent float64
+
+	StepDownAfter int // Consecutive samples under pressure before stepping down
+	StepUpAfter   int // Consecutive samples with headroom before stepping up
+}
+
+// Sample is one observation interval
+type Sample struct {
+	DropRate    float64
+	DecodeP95MS float64
+	CPUPercent  float64
+	HasDecode   bool // Decode latency available (VAAPI or MJPEG)
+	HasCPU      bool // CPU usage available (platform support)
+}
+
+// Controller tracks the current level and pressure streaks
+//
+// Not safe for concurrent use.
+type Controller struct {
+	t      Thresholds
+	levels int // Highest level (ladder length)
+	level  int
+	bad    int // Consecutive samples under pressure
+	good   int // Consecutive samples with headroom
+	settle bool
+}
+
+// New creates a controller at level 0 for a ladder of the given length
+func New(t Thresholds, levels int) *Controller {
+	if t.StepDownAfter < 1 {
+		t.StepDownAfter = 1
+	}
+	if t.StepUpAfter < 1 {
+		t.StepUpAfter = 1
+	}
+	return &Controller{t: t, levels: levels}
+}
+
+// Level returns the current level (0 = full quality)
+func (c *Controller) Level() int {
+	return c.level
+}
+
+// SetLevel forces the level (e.g. rollback after a failed apply) and resets streaks
+func (c *Controller) SetLevel(level int) {
+	if level < 0 {
+		level = 0
+	}
+	if level > c.levels {
+		level = c.levels
+	}
+	c.level = level
+	c.bad, c.good = 0, 0
+}
+
+// Observe feeds one sample and returns the new level
+//
+// changed is true when the level moved; reason describes the triggering
+// signal. The sample right after a change is ignored: it covers the
+// reconfiguration itself (pipeline rebuild, caps renegotiation).
+func (c *Controller) Observe(s Sample) (level int, reason string, changed bool) {
+	if c.settle {
+		c.settle = false
+		return c.level, "", false
+	}
+
+	if reason := c.pressure(s); reason != "" {
+		c.good = 0
+		c.bad++
+		if c.bad >= c.t.StepDownAfter && c.level < c.levels {
+			c.level++
+			c.bad = 0
+			c.settle = true
+			return c.level, reason, true
+		}
+		return c.level, "", false
+	}
+	c.bad = 0
+
+	if !c.headroom(s) {
+		c.good = 0
+		return c.level, "", false
+	}
+
+	c.good++
+	if c.good >= c.t.StepUpAfter && c.level > 0 {
+		c.level--
+		c.good = 0
+		c.settle = true
+		return c.level, "headroom recovered", true
+	}
+	return c.level, "", false
+}
+
+// pressure returns a description of the first signal over its limit ("" if none)
+func (c *Controller) pressure(s Sample) string {
+	if c.t.MaxDropRate > 0 && s.DropRate > c.t.MaxDropRate {
+		return fmt.Sprintf("drop rate %.1f%% > %.1f%%", s.DropRate, c.t.MaxDropRate)
+	}
+	if c.t.MaxDecodeP95MS > 0 && s.HasDecode && s.DecodeP95MS > c.t.MaxDecodeP95MS {
+		return fmt.Sprintf("decode p95 %.1fms > %.1fms", s.DecodeP95MS, c.t.MaxDecodeP95MS)
+	}
+	if c.t.MaxCPUPercent > 0 && s.HasCPU && s.CPUPercent > c.t.MaxCPUPercent {
+		return fmt.Sprintf("cpu %.1f%% > %.1f%%", s.CPUPercent, c.t.MaxCPUPercent)
+	}
+	return ""
+}
+
+// headroom reports whether every enabled signal is at or below its recover value
+func (c *Controller) headroom(s Sample) bool {
+	if c.t.MaxDropRate > 0 && s.DropRate > c.t.RecoverDropRate {
+		return false
+	}
+	if c.t.MaxDecodeP95MS > 0 && s.HasDecode && s.DecodeP95MS > c.t.RecoverDecodeP95MS {
+		return false
+	}
+	if c.t.MaxCPUPercent > 0 && s.HasCPU && s.CPUPercent > c.t.RecoverCPUPercent {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package degrade
+
+import "testing"
+
+func testThresholds() Thresholds {
+	return Thresholds{
+		MaxDropRate:       10,
+		RecoverDropRate:   2,
+		MaxCPUPercent:     85,
+		RecoverCPUPercent: 60,
+		StepDownAfter:     2,
+		StepUpAfter:       3,
+	}
+}
+
+func TestController_StepDownAndUp(t *testing.T) {
+	c := New(testThresholds(), 2)
+
+	hot := Sample{CPUPercent: 95, HasCPU: true}
+	cool := Sample{CPUPercent: 30, HasCPU: true}
+
+	steps := []struct {
+		sample    Sample
+		wantLevel int
+		changed   bool
+	}{
+		{hot, 0, false},  // 1st bad sample
+		{hot, 1, true},   // 2nd bad sample: step down
+		{hot, 1, false},  // settle sample ignored
+		{hot, 1, false},  // 1st bad sample at level 1
+		{hot, 2, true},   // step down
+		{hot, 2, false},  // settle
+		{hot, 2, false},  // bottom of the ladder
+		{hot, 2, false},  // still bottom
+		{cool, 2, false}, // 1st good
+		{cool, 2, false}, // 2nd good
+		{cool, 1, true},  // 3rd good: step up
+		{cool, 1, false}, // settle
+		{cool, 1, false},
+		{cool, 1, false},
+		{cool, 0, true},
+		{cool, 0, false},
+		{cool, 0, false},
+	}
+
+	for i, step := range steps {
+		level, reason, changed := c.Observe(step.sample)
+		if level != step.wantLevel || changed != step.changed {
+			t.Fatalf("step %d: Observe() = level %d changed %v, want level %d changed %v",
+				i, level, changed, step.wantLevel, step.changed)
+		}
+		if changed && reason == "" {
+			t.Errorf("step %d: changed without reason", i)
+		}
+	}
+}
+
+func TestController_Hysteresis(t *testing.T) {
+	c := New(testThresholds(), 1)
+	c.SetLevel(1)
+
+	// Between recover and max: neither pressure nor headroom
+	warm := Sample{CPUPercent: 70, HasCPU: true, DropRate: 1}
+	for i := 0; i < 10; i++ {
+		if _, _, changed := c.Observe(warm); changed {
+			t.Fatalf("sample %d: level changed in hysteresis band", i)
+		}
+	}
+
+	// A bad sample breaks the good streak
+	cool := Sample{CPUPercent: 10, HasCPU: true}
+	c.Observe(cool)
+	c.Observe(cool)
+	c.Observe(Sample{DropRate: 50})
+	c.Observe(cool)
+	if _, _, changed := c.Observe(cool); changed {
+		t.Error("stepped up before StepUpAfter consecutive good samples")
+	}
+}
+
+func TestController_Signals(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      Thresholds
+		sample Sample
+		want   bool // pressure
+	}{
+		{"drop rate", testThresholds(), Sample{DropRate: 20}, true},
+		{"cpu", testThresholds(), Sample{CPUPercent: 90, HasCPU: true}, true},
+		{"cpu unavailable", testThresholds(), Sample{CPUPercent: 90}, false},
+		{"decode disabled", testThresholds(), Sample{DecodeP95MS: 500, HasDecode: true}, false},
+		{"decode", Thresholds{MaxDecodeP95MS: 100}, Sample{DecodeP95MS: 500, HasDecode: true}, true},
+		{"decode unavailable", Thresholds{MaxDecodeP95MS: 100}, Sample{DecodeP95MS: 500}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.t.StepDownAfter = 1
+			c := New(tt.t, 1)
+			if _, _, changed := c.Observe(tt.sample); changed != tt.want {
+				t.Errorf("Observe() changed = %v, want %v", changed, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+// Package mjpeg implements HTTP camera sources: MJPEG over HTTP
+// (multipart/x-mixed-replace) and polled JPEG snapshot endpoints.
+//
+// This package only deals with HTTP transport and JPEG decoding. Lifecycle,
+// reconnection and statistics are handled by the parent package, which reuses
+// the same machinery as the RTSP pipeline (internal/rtsp).
+package mjpeg
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultMaxPartSize bounds the size of a single JPEG read from the camera.
+	// 8 MB comfortably fits 4K JPEGs while protecting against a misbehaving
+	// server streaming an endless part.
+	DefaultMaxPartSize = 8 * 1024 * 1024
+)
+
+// ErrStalled is returned when the camera keeps the connection open but stops
+// sending frames (no part received within the read timeout).
+var ErrStalled = errors.New("mjpeg: no frame received within read timeout")
+
+// StatusError is returned when the camera answers with a non-2xx HTTP status.
+type StatusError struct {
+	Code   int
+	Status string
+}
+
+func (e *StatusError) Error() string { return fmt.Sprintf("http status %s", e.Status) }
+
+// ContentTypeError is returned when the response is not what the mode expects
+// (e.g. an HTML login page instead of multipart/x-mixed-replace).
+type ContentTypeError struct {
+	ContentType string
+	Expected    string
+}
+
+func (e *ContentTypeError) Error() string {
+	return fmt.Sprintf("unexpected content type %q (expected %s)", e.ContentType, e.Expected)
+}
+
+// PartTooLargeError is returned when a JPEG exceeds the configured maximum size.
+type PartTooLargeError struct {
+	Limit int64
+}
+
+func (e *PartTooLargeError) Error() string {
+	return fmt.Sprintf("jpeg part exceeds %d bytes", e.Limit)
+}
+
+// StreamReader reads JPEG parts from a multipart/x-mixed-replace response.
+//
+// mime/multipart is not used on purpose: it only delivers a part once the next
+// boundary arrives, which adds a full frame period of latency on a live camera.
+// StreamReader honors the per-part Content-Length header (sent by virtually all
+// cameras) and falls back to boundary scanning when it is missing.
+//
+// Not safe for concurrent use (single reader goroutine).
+type StreamReader struct {
+	resp        *http.Response
+	br          *bufio.Reader
+	tp          *textproto.Reader
+	boundary    string
+	cancel      context.CancelFunc
+	readTimeout time.Duration
+	maxPartSize int64
+	stalled     atomic.Bool
+
+	atPartStart bool // Boundary line already consumed (boundary-scan fallback)
+	closed      bool // Closing boundary seen
+}
+
+// OpenStream connects to an MJPEG endpoint and validates the response.
+//
+// Returns a *StatusError for non-2xx responses and a *ContentTypeError if the
+// response is not multipart/x-mixed-replace with a boundary parameter.
+//
+// readTimeout bounds the wait for each part (stall detection). Zero disables it.
+func OpenStream(ctx context.Context, client *http.Client, url string, readTimeout time.Duration) (*StreamReader, error) {
+	reqCtx, cancel := context.WithCancel(ctx)
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, url, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// Stall protection also covers the initial response headers
+	var headerTimer *time.Timer
+	if readTimeout > 0 {
+		headerTimer = time.AfterFunc(readTimeout, cancel)
+	}
+	resp, err := client.Do(req)
+	if headerTimer != nil && !headerTimer.Stop() {
+		// Timer fired: even if Do won the race, the body's context is cancelled
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, ErrStalled
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		cancel()
+		return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	boundary, err := parseBoundary(contentType)
+	if err != nil {
+		resp.Body.Close()
+		cancel()
+		return nil, err
+	}
+
+	br := bufio.NewReaderSize(resp.Body, 64*1024)
+	return &StreamReader{
+		resp:        resp,
+		br:          br,
+		tp:          textproto.NewReader(br),
+		boundary:    boundary,
+		cancel:      cancel,
+		readTimeout: readTimeout,
+		maxPartSize: DefaultMaxPartSize,
+	}, nil
+}
+
+// Next blocks until the next JPEG part is received and returns its payload.
+//
+// Returns ErrStalled if no part arrives within the read timeout, or the
+// underlying transport error (io.EOF when the camera closes the stream).
+func (r *StreamReader) Next() ([]byte, error) {
+	if r.readTimeout > 0 {
+		timer := time.AfterFunc(r.readTimeout, func() {
+			r.stalled.Store(true)
+			r.cancel()
+		})
+		defer timer.Stop()
+	}
+
+	data, err := r.next()
+	if err != nil && r.stalled.Load() {
+		return nil, ErrStalled
+	}
+	return data, err
+}
+
+func (r *StreamReader) next() ([]byte, error) {
+	for {
+		if r.closed {
+			return nil, io.EOF
+		}
+
+		if !r.atPartStart {
+			if err := r.skipToBoundary(); err != nil {
+				return nil, err
+			}
+		}
+		r.atPartStart = false
+
+		header, err := r.tp.ReadMIMEHeader()
+		if err != nil {
+			return nil, err
+		}
+
+		var data []byte
+		if n, ok := contentLength(header); ok {
+			if n > r.maxPartSize {
+				return nil, &PartTooLargeError{Limit: r.maxPartSize}
+			}
+			data = make([]byte, n)
+			if _, err := io.ReadFull(r.br, data); err != nil {
+				return nil, err
+			}
+		} else {
+			data, err = r.readUntilBoundary()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Some cameras interleave non-image parts (e.g. text metadata)
+		if ct := header.Get("Content-Type"); ct != "" && !strings.HasPrefix(strings.ToLower(ct), "image/") {
+			continue
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		return data, nil
+	}
+}
+
+// skipToBoundary discards bytes up to and including the next boundary line
+func (r *StreamReader) skipToBoundary() error {
+	delimiter := "--" + r.boundary
+	var skipped int64
+
+	for {
+		line, err := r.br.ReadString('\n')
+		if err != nil {
+			return err
+		}
+
+		skipped += int64(len(line))
+		if skipped > r.maxPartSize {
+			return &PartTooLargeError{Limit: r.maxPartSize}
+		}
+
+		switch strings.TrimSpace(line) {
+		case delimiter:
+			return nil
+		case delimiter + "--":
+			r.closed = true
+			return io.EOF
+		}
+	}
+}
+
+// readUntilBoundary reads part data up to the next boundary line (no Content-Length)
+//
+// Consumes the boundary line, so the next call starts directly at the part headers.
+// Accepts both CRLF and bare LF before the boundary (seen on cheap firmwares).
+func (r *StreamReader) readUntilBoundary() ([]byte, error) {
+	delimiter := []byte("\n--" + r.boundary)
+	last := delimiter[len(delimiter)-1]
+	var data []byte
+
+	for {
+		b, err := r.br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+
+		if int64(len(data)) > r.maxPartSize+int64(len(delimiter)) {
+			return nil, &PartTooLargeError{Limit: r.maxPartSize}
+		}
+
+		if b != last || !bytes.HasSuffix(data, delimiter) {
+			continue
+		}
+
+		data = data[:len(data)-len(delimiter)]
+		data = bytes.TrimSuffix(data, []byte("\r"))
+
+		// Rest of the boundary line: "--" marks the closing boundary
+		rest, err := r.br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if strings.HasPrefix(rest, "--") || err == io.EOF {
+			r.closed = true
+		} else {
+			r.atPartStart = true
+		}
+
+		return data, nil
+	}
+}
+
+// contentLength parses the part Content-Length header
+func contentLength(header textproto.MIMEHeader) (int64, bool) {
+	value := strings.TrimSpace(header.Get("Content-Length"))
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// Close releases the HTTP connection. Safe to call multiple times.
+func (r *StreamReader) Close() error {
+	r.cancel()
+	return r.resp.Body.Close()
+}
+
+// FetchSnapshot performs a single GET against a snapshot endpoint and returns the JPEG bytes.
+//
+// Returns a *StatusError for non-2xx responses and a *ContentTypeError if the
+// server declares a non-image content type.
+func FetchSnapshot(ctx context.Context, client *http.Client, url string, timeout time.Duration) ([]byte, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &StatusError{Code: resp.StatusCode, Status: resp.Status}
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(strings.ToLower(ct), "image/") {
+		return nil, &ContentTypeError{ContentType: ct, Expected: "image/jpeg"}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, DefaultMaxPartSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > DefaultMaxPartSize {
+		return nil, &PartTooLargeError{Limit: DefaultMaxPartSize}
+	}
+
+	return data, nil
+}
+
+// parseBoundary extracts the multipart boundary from a Content-Type header.
+//
+// Tolerates cameras that (incorrectly) include the leading "--" in the
+// boundary parameter, a common firmware quirk.
+func parseBoundary(contentType string) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		return "", &ContentTypeError{ContentType: contentType, Expected: "multipart/x-mixed-replace"}
+	}
+
+	boundary := strings.TrimPrefix(params["boundary"], "--")
+	if boundary == "" {
+		return "", &ContentTypeError{ContentType: contentType, Expected: "multipart/x-mixed-replace with boundary"}
+	}
+
+	return boundary, nil
+}
@@ -0,0 +1,88 @@
+package mjpeg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+)
+
+// DecodeError indicates the payload received from the camera is not a valid JPEG.
+//
+// Classified as codec error (the camera answered, but with data we cannot decode).
+type DecodeError struct {
+	Err error
+}
+
+func (e *DecodeError) Error() string { return fmt.Sprintf("jpeg decode failed: %v", e.Err) }
+
+func (e *DecodeError) Unwrap() error { return e.Err }
+
+// DecodeJPEG decodes a JPEG image into interleaved RGB bytes (RGBRGB...)
+//
+// Output format matches the GStreamer appsink caps used by the RTSP pipeline
+// (video/x-raw,format=RGB), so downstream consumers cannot tell the sources apart.
+//
+// Fast paths exist for YCbCr (baseline camera JPEGs) and Gray images.
+// Other color models fall back to the generic image.At() conversion.
+//
+// Returns the RGB buffer and its dimensions, or a *DecodeError.
+func DecodeJPEG(data []byte) (rgb []byte, width, height int, err error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, &DecodeError{Err: err}
+	}
+
+	bounds := img.Bounds()
+	width, height = bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, 0, 0, &DecodeError{Err: fmt.Errorf("empty image %dx%d", width, height)}
+	}
+
+	rgb = make([]byte, width*height*3)
+
+	switch src := img.(type) {
+	case *image.YCbCr:
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				yi := src.YOffset(x, y)
+				ci := src.COffset(x, y)
+				r, g, b := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+				rgb[i+0] = r
+				rgb[i+1] = g
+				rgb[i+2] = b
+				i += 3
+			}
+		}
+
+	case *image.Gray:
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			row := src.Pix[(y-bounds.Min.Y)*src.Stride:]
+			for x := 0; x < width; x++ {
+				v := row[x]
+				rgb[i+0] = v
+				rgb[i+1] = v
+				rgb[i+2] = v
+				i += 3
+			}
+		}
+
+	default:
+		// CMYK and other exotic encodings (rare on IP cameras)
+		i := 0
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, _ := img.At(x, y).RGBA()
+				rgb[i+0] = uint8(r >> 8)
+				rgb[i+1] = uint8(g >> 8)
+				rgb[i+2] = uint8(b >> 8)
+				i += 3
+			}
+		}
+	}
+
+	return rgb, width, height, nil
+}
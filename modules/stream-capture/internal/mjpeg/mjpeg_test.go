@@ -0,0 +1,293 @@
+package mjpeg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// encodeTestJPEG returns a solid-color JPEG of the given size
+func encodeTestJPEG(t *testing.T, width, height int, c color.RGBA) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("failed to encode test JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// writePart writes a single multipart/x-mixed-replace part
+func writePart(w io.Writer, boundary string, data []byte) {
+	fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(data))
+	w.Write(data)
+	fmt.Fprint(w, "\r\n")
+}
+
+func TestDecodeJPEG(t *testing.T) {
+	t.Run("YCbCr", func(t *testing.T) {
+		data := encodeTestJPEG(t, 32, 16, color.RGBA{R: 200, G: 40, B: 40, A: 255})
+
+		rgb, width, height, err := DecodeJPEG(data)
+		if err != nil {
+			t.Fatalf("DecodeJPEG failed: %v", err)
+		}
+		if width != 32 || height != 16 {
+			t.Fatalf("expected 32x16, got %dx%d", width, height)
+		}
+		if len(rgb) != width*height*3 {
+			t.Fatalf("expected %d bytes, got %d", width*height*3, len(rgb))
+		}
+
+		// JPEG is lossy: allow a small tolerance around the encoded color
+		r, g, b := int(rgb[0]), int(rgb[1]), int(rgb[2])
+		if abs(r-200) > 8 || abs(g-40) > 8 || abs(b-40) > 8 {
+			t.Errorf("unexpected pixel color (%d,%d,%d), expected ~(200,40,40)", r, g, b)
+		}
+	})
+
+	t.Run("Gray", func(t *testing.T) {
+		img := image.NewGray(image.Rect(0, 0, 8, 8))
+		for i := range img.Pix {
+			img.Pix[i] = 128
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, img, nil); err != nil {
+			t.Fatalf("failed to encode gray JPEG: %v", err)
+		}
+
+		rgb, width, height, err := DecodeJPEG(buf.Bytes())
+		if err != nil {
+			t.Fatalf("DecodeJPEG failed: %v", err)
+		}
+		if width != 8 || height != 8 {
+			t.Fatalf("expected 8x8, got %dx%d", width, height)
+		}
+		if rgb[0] != rgb[1] || rgb[1] != rgb[2] {
+			t.Errorf("gray pixel should have R=G=B, got (%d,%d,%d)", rgb[0], rgb[1], rgb[2])
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, _, _, err := DecodeJPEG([]byte("not a jpeg"))
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Fatalf("expected *DecodeError, got %T (%v)", err, err)
+		}
+	})
+}
+
+func TestOpenStream_ReadsParts(t *testing.T) {
+	frame := encodeTestJPEG(t, 16, 16, color.RGBA{G: 255, A: 255})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Leading "--" in the boundary parameter is a common firmware quirk
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=--myboundary")
+		for i := 0; i < 3; i++ {
+			writePart(w, "myboundary", frame)
+		}
+	}))
+	defer server.Close()
+
+	reader, err := OpenStream(context.Background(), server.Client(), server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	defer reader.Close()
+
+	for i := 0; i < 3; i++ {
+		data, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next() #%d failed: %v", i, err)
+		}
+		if !bytes.Equal(data, frame) {
+			t.Fatalf("part #%d does not match sent JPEG (%d vs %d bytes)", i, len(data), len(frame))
+		}
+	}
+
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after last part, got %v", err)
+	}
+}
+
+func TestOpenStream_WithoutContentLength(t *testing.T) {
+	frame := encodeTestJPEG(t, 16, 16, color.RGBA{R: 255, A: 255})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=frame")
+		for i := 0; i < 2; i++ {
+			fmt.Fprint(w, "--frame\r\nContent-Type: image/jpeg\r\n\r\n")
+			w.Write(frame)
+			fmt.Fprint(w, "\r\n")
+		}
+		fmt.Fprint(w, "--frame--\r\n")
+	}))
+	defer server.Close()
+
+	reader, err := OpenStream(context.Background(), server.Client(), server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("OpenStream failed: %v", err)
+	}
+	defer reader.Close()
+
+	for i := 0; i < 2; i++ {
+		data, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Next() #%d failed: %v", i, err)
+		}
+		if !bytes.Equal(data, frame) {
+			t.Fatalf("part #%d does not match sent JPEG (%d vs %d bytes)", i, len(data), len(frame))
+		}
+	}
+
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after closing boundary, got %v", err)
+	}
+}
+
+func TestOpenStream_Errors(t *testing.T) {
+	t.Run("Unauthorized", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}))
+		defer server.Close()
+
+		_, err := OpenStream(context.Background(), server.Client(), server.URL, time.Second)
+
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected StatusError 401, got %v", err)
+		}
+	})
+
+	t.Run("WrongContentType", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, "<html>login</html>")
+		}))
+		defer server.Close()
+
+		_, err := OpenStream(context.Background(), server.Client(), server.URL, time.Second)
+
+		var ctErr *ContentTypeError
+		if !errors.As(err, &ctErr) {
+			t.Fatalf("expected ContentTypeError, got %v", err)
+		}
+	})
+
+	t.Run("HeaderTimerRace", func(t *testing.T) {
+		// Headers arrive just as the header timer fires: the response is
+		// usable only in appearance (its context is already cancelled)
+		client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"multipart/x-mixed-replace; boundary=b"}},
+				Body:       io.NopCloser(bytes.NewReader(nil)),
+			}, nil
+		})}
+
+		_, err := OpenStream(context.Background(), client, "http://camera.invalid/stream", 50*time.Millisecond)
+		if !errors.Is(err, ErrStalled) {
+			t.Fatalf("expected ErrStalled, got %v", err)
+		}
+	})
+
+	t.Run("Stalled", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=b")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			// Keep connection open without sending any part
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer server.Close()
+		defer close(release)
+
+		reader, err := OpenStream(context.Background(), server.Client(), server.URL, 100*time.Millisecond)
+		if err != nil {
+			t.Fatalf("OpenStream failed: %v", err)
+		}
+		defer reader.Close()
+
+		start := time.Now()
+		_, err = reader.Next()
+		if !errors.Is(err, ErrStalled) {
+			t.Fatalf("expected ErrStalled, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("stall detection took too long: %v", elapsed)
+		}
+	})
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestFetchSnapshot(t *testing.T) {
+	frame := encodeTestJPEG(t, 16, 16, color.RGBA{B: 255, A: 255})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/snapshot.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write(frame)
+		case "/forbidden.jpg":
+			http.Error(w, "forbidden", http.StatusForbidden)
+		default:
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "hello")
+		}
+	}))
+	defer server.Close()
+
+	data, err := FetchSnapshot(context.Background(), server.Client(), server.URL+"/snapshot.jpg", time.Second)
+	if err != nil {
+		t.Fatalf("FetchSnapshot failed: %v", err)
+	}
+	if !bytes.Equal(data, frame) {
+		t.Errorf("snapshot does not match served JPEG")
+	}
+
+	_, err = FetchSnapshot(context.Background(), server.Client(), server.URL+"/forbidden.jpg", time.Second)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != http.StatusForbidden {
+		t.Errorf("expected StatusError 403, got %v", err)
+	}
+
+	_, err = FetchSnapshot(context.Background(), server.Client(), server.URL+"/other", time.Second)
+	var ctErr *ContentTypeError
+	if !errors.As(err, &ctErr) {
+		t.Errorf("expected ContentTypeError, got %v", err)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
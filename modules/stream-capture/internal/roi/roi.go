@@ -0,0 +1,111 @@
+// Package roi crops and scales packed RGB frames.
+//
+// Used by ROIManager to derive region-of-interest streams from a single
+// decoded source: the crop is done in Go on the frame already produced by
+// the pipeline, so no extra RTSP connection or decoder is needed.
+package roi
+
+import "math"
+
+// BytesPerPixel is the size of one packed RGB pixel
+const BytesPerPixel = 3
+
+// PixelRect converts a normalized rectangle to pixel bounds [x0,x1) x [y0,y1)
+//
+// Bounds are clamped to the frame and cover at least one pixel.
+func PixelRect(x, y, w, h float64, width, height int) (x0, y0, x1, y1 int) {
+	x0 = clamp(int(math.Floor(x*float64(width)+epsilon)), 0, width-1)
+	y0 = clamp(int(math.Floor(y*float64(height)+epsilon)), 0, height-1)
+	x1 = clamp(int(math.Ceil((x+w)*float64(width)-epsilon)), x0+1, width)
+	y1 = clamp(int(math.Ceil((y+h)*float64(height)-epsilon)), y0+1, height)
+	return x0, y0, x1, y1
+}
+
+// epsilon absorbs float error in normalized coordinates (0.1+0.2 != 0.3)
+const epsilon = 1e-6
+
+// Extract crops src to [x0,x1) x [y0,y1) and scales the crop to dstW x dstH
+//
+// src is packed RGB of srcW x srcH pixels. Scaling is bilinear; a crop at
+// its native size is a plain row copy. Returns nil if src is too short for
+// the given dimensions.
+func Extract(src []byte, srcW, srcH, x0, y0, x1, y1, dstW, dstH int) []byte {
+	if len(src) < srcW*srcH*BytesPerPixel || dstW <= 0 || dstH <= 0 {
+		return nil
+	}
+
+	cropW, cropH := x1-x0, y1-y0
+	dst := make([]byte, dstW*dstH*BytesPerPixel)
+	stride := srcW * BytesPerPixel
+
+	if cropW == dstW && cropH == dstH {
+		rowBytes := cropW * BytesPerPixel
+		for row := 0; row < cropH; row++ {
+			start := (y0+row)*stride + x0*BytesPerPixel
+			copy(dst[row*rowBytes:], src[start:start+rowBytes])
+		}
+		return dst
+	}
+
+	// Source sample positions (pixel centers), precomputed per column
+	xs := make([]sample, dstW)
+	for dx := range xs {
+		xs[dx] = samplePos(dx, dstW, cropW, x0)
+	}
+
+	for dy := 0; dy < dstH; dy++ {
+		ys := samplePos(dy, dstH, cropH, y0)
+		row0 := src[ys.i0*stride:]
+		row1 := src[ys.i1*stride:]
+		out := dst[dy*dstW*BytesPerPixel:]
+
+		for dx, xsPos := range xs {
+			a0, a1 := xsPos.i0*BytesPerPixel, xsPos.i1*BytesPerPixel
+			for c := 0; c < BytesPerPixel; c++ {
+				top := lerp(row0[a0+c], row0[a1+c], xsPos.f)
+				bottom := lerp(row1[a0+c], row1[a1+c], xsPos.f)
+				out[dx*BytesPerPixel+c] = uint8(top + (bottom-top)*ys.f + 0.5)
+			}
+		}
+	}
+
+	return dst
+}
+
+// sample is an interpolation position between source pixels i0 and i1
+type sample struct {
+	i0, i1 int
+	f      float64 // Weight of i1 (0-1)
+}
+
+// samplePos maps destination index d (of n) into a source span of size
+// pixels starting at offset
+func samplePos(d, n, size, offset int) sample {
+	pos := (float64(d)+0.5)*float64(size)/float64(n) - 0.5
+	if pos < 0 {
+		pos = 0
+	}
+	i0 := int(pos)
+	if i0 > size-1 {
+		i0 = size - 1
+	}
+	i1 := i0 + 1
+	if i1 > size-1 {
+		i1 = size - 1
+	}
+	return sample{i0: offset + i0, i1: offset + i1, f: pos - float64(i0)}
+}
+
+func lerp(a, b uint8, f float64) float64 {
+	return float64(a) + (float64(b)-float64(a))*f
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+package roi
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testImage returns a w x h RGB image where each pixel encodes its position
+func testImage(w, h int) []byte {
+	img := make([]byte, w*h*BytesPerPixel)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := (y*w + x) * BytesPerPixel
+			img[i], img[i+1], img[i+2] = byte(x), byte(y), 200
+		}
+	}
+	return img
+}
+
+func TestPixelRect(t *testing.T) {
+	tests := []struct {
+		name           string
+		x, y, w, h     float64
+		x0, y0, x1, y1 int
+	}{
+		{"full frame", 0, 0, 1, 1, 0, 0, 100, 50},
+		{"center quarter", 0.25, 0.25, 0.5, 0.5, 25, 12, 75, 38},
+		{"float error", 0.1, 0.2, 0.2, 0.1, 10, 10, 30, 15},
+		{"clamped", 0.9, 0.9, 0.5, 0.5, 90, 45, 100, 50},
+		{"at least one pixel", 0.5, 0.5, 0, 0, 50, 25, 51, 26},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x0, y0, x1, y1 := PixelRect(tt.x, tt.y, tt.w, tt.h, 100, 50)
+			if x0 != tt.x0 || y0 != tt.y0 || x1 != tt.x1 || y1 != tt.y1 {
+				t.Errorf("PixelRect() = (%d,%d)-(%d,%d), want (%d,%d)-(%d,%d)",
+					x0, y0, x1, y1, tt.x0, tt.y0, tt.x1, tt.y1)
+			}
+		})
+	}
+}
+
+func TestExtract_NativeCrop(t *testing.T) {
+	src := testImage(8, 6)
+
+	dst := Extract(src, 8, 6, 2, 1, 5, 4, 3, 3)
+	if len(dst) != 3*3*BytesPerPixel {
+		t.Fatalf("len(dst) = %d, want %d", len(dst), 3*3*BytesPerPixel)
+	}
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 3; x++ {
+			i := (y*3 + x) * BytesPerPixel
+			if dst[i] != byte(x+2) || dst[i+1] != byte(y+1) || dst[i+2] != 200 {
+				t.Fatalf("pixel (%d,%d) = %v, want source (%d,%d)", x, y, dst[i:i+3], x+2, y+1)
+			}
+		}
+	}
+
+	// Full frame at native size is an exact copy
+	if full := Extract(src, 8, 6, 0, 0, 8, 6, 8, 6); !bytes.Equal(full, src) {
+		t.Error("full-frame native Extract() differs from source")
+	}
+}
+
+func TestExtract_Scale(t *testing.T) {
+	// Constant image stays constant at any size
+	src := bytes.Repeat([]byte{10, 20, 30}, 16*16)
+	for _, size := range [][2]int{{4, 4}, {32, 8}, {7, 13}} {
+		dst := Extract(src, 16, 16, 0, 0, 16, 16, size[0], size[1])
+		if want := bytes.Repeat([]byte{10, 20, 30}, size[0]*size[1]); !bytes.Equal(dst, want) {
+			t.Errorf("Extract() to %dx%d changed a constant image", size[0], size[1])
+		}
+	}
+
+	// 2x downscale of a horizontal gradient averages neighbouring columns
+	grad := testImage(4, 2)
+	dst := Extract(grad, 4, 2, 0, 0, 4, 2, 2, 1)
+	if dst[0] != 1 || dst[3] != 3 { // (0+1)/2 rounded, (2+3)/2 rounded
+		t.Errorf("downscaled red = %d, %d, want 1, 3", dst[0], dst[3])
+	}
+
+	// 2x zoom of a crop stays within the crop's values
+	zoom := Extract(grad, 4, 2, 2, 0, 4, 2, 4, 4)
+	for i := 0; i < len(zoom); i += BytesPerPixel {
+		if zoom[i] < 2 || zoom[i] > 3 {
+			t.Fatalf("zoomed red = %d outside crop range [2,3]", zoom[i])
+		}
+	}
+}
+
+func TestExtract_ShortSource(t *testing.T) {
+	if dst := Extract(make([]byte, 10), 8, 6, 0, 0, 8, 6, 8, 6); dst != nil {
+		t.Error("Extract() with short source returned data, want nil")
+	}
+}
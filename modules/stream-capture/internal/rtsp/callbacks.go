@@ -6,7 +6,8 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
 	"github.com/tinyzimmer/go-gst/gst"
 	"github.com/tinyzimmer/go-gst/gst/app"
 )
@@ -20,7 +21,10 @@ type Frame struct {
 	Height       int
 	Data         []byte
 	SourceStream string
-	TraceID      string
+	// Trace is the context of the frame's capture span (new trace per frame)
+	Trace trace.SpanContext
+	// DecodedAt is when the decoded frame reached the appsink (CallbackContext.Clock, span start)
+	DecodedAt time.Time
 }
 
 // LatencyWindow maintains a rolling window of decode latency samples
@@ -85,14 +89,17 @@ func (w *LatencyWindow) GetStats() (mean, p95, max float64) {
 
 // CallbackContext holds state needed by GStreamer callbacks
 type CallbackContext struct {
-	FrameChan       chan<- Frame                  // Uses internal Frame type
-	FrameCounter    *uint64                       // Atomic counter for sequence numbers
-	BytesRead       *uint64                       // Atomic counter for bytes read
-	FramesDropped   *uint64                       // Atomic counter for dropped frames (channel full)
-	Width           int                           // Frame width in pixels
-	Height          int                           // Frame height in pixels
-	SourceStream    string                        // Stream identifier (e.g., "LQ", "HQ")
+	FrameChan       chan<- Frame                   // Uses internal Frame type
+	FrameCounter    *uint64                        // Atomic counter for sequence numbers
+	BytesRead       *uint64                        // Atomic counter for bytes read
+	FramesDropped   *uint64                        // Atomic counter for dropped frames (channel full)
+	Width           int                            // Frame width in pixels
+	Height          int                            // Frame height in pixels
+	SourceStream    string                         // Stream identifier (e.g., "LQ", "HQ")
 	DecodeLatencies *atomic.Pointer[LatencyWindow] // Lock-free latency tracking (nil if disabled)
+	Paused          *atomic.Bool                   // Discard samples while set (nil if pause unsupported)
+	Logger          *slog.Logger                   // Logger (nil = slog.Default())
+	Clock           clock.Clock                    // Frame timestamps and DecodedAt (nil = system clock)
 }
 
 // OnNewSample is called by GStreamer when a new frame is available
@@ -104,14 +111,25 @@ type CallbackContext struct {
 //  4. Creates a Frame struct with metadata
 //  5. Sends frame to channel (non-blocking - drops if full)
 //
+// While ctx.Paused is set, samples are pulled (releasing the GStreamer buffer)
+// and discarded before any copy or counter update. The pipeline keeps PLAYING,
+// so the RTSP session stays alive.
+//
 // Returns gst.FlowOK to continue processing, or gst.FlowEOS/FlowError on failure.
 func OnNewSample(sink *app.Sink, ctx *CallbackContext) gst.FlowReturn {
+	log := loggerOrDefault(ctx.Logger)
+
 	// Pull sample from appsink
 	sample := sink.PullSample()
 	if sample == nil {
 		// Graceful degradation: skip frame instead of terminating stream
 		// A single corrupted frame should not kill the entire pipeline
-		slog.Warn("rtsp: failed to pull sample from appsink, skipping frame")
+		log.Warn("rtsp: failed to pull sample from appsink, skipping frame")
+		return gst.FlowOK
+	}
+
+	// Paused (privacy mode): discard sample, keep pipeline flowing
+	if ctx.Paused != nil && ctx.Paused.Load() {
 		return gst.FlowOK
 	}
 
@@ -119,7 +137,7 @@ func OnNewSample(sink *app.Sink, ctx *CallbackContext) gst.FlowReturn {
 	buffer := sample.GetBuffer()
 	if buffer == nil {
 		// Graceful degradation: skip frame instead of terminating stream
-		slog.Warn("rtsp: failed to get buffer from sample, skipping frame")
+		log.Warn("rtsp: failed to get buffer from sample, skipping frame")
 		return gst.FlowOK
 	}
 
@@ -159,7 +177,7 @@ func OnNewSample(sink *app.Sink, ctx *CallbackContext) gst.FlowReturn {
 	data := mapInfo.Bytes()
 	if data == nil || len(data) == 0 {
 		buffer.Unmap()
-		slog.Warn("rtsp: empty buffer received")
+		log.Warn("rtsp: empty buffer received")
 		return gst.FlowOK
 	}
 
@@ -173,30 +191,32 @@ func OnNewSample(sink *app.Sink, ctx *CallbackContext) gst.FlowReturn {
 	atomic.AddUint64(ctx.BytesRead, uint64(len(data)))
 
 	// Create frame struct (using internal Frame type)
+	now := clock.OrSystem(ctx.Clock).Now()
 	frame := Frame{
 		Seq:          seq,
-		Timestamp:    time.Now(),
+		Timestamp:    now,
 		Width:        ctx.Width,
 		Height:       ctx.Height,
 		Data:         frameData,
 		SourceStream: ctx.SourceStream,
-		TraceID:      uuid.New().String(),
+		Trace:        trace.NewRoot(),
+		DecodedAt:    now,
 	}
 
 	// Send frame (non-blocking - drop if channel full)
 	select {
 	case ctx.FrameChan <- frame:
-		slog.Debug("rtsp: frame sent",
+		log.Debug("rtsp: frame sent",
 			"seq", frame.Seq,
 			"size_bytes", len(data),
-			"trace_id", frame.TraceID,
+			"trace_id", frame.Trace.TraceID,
 		)
 	default:
 		// Track dropped frame at callback layer
 		atomic.AddUint64(ctx.FramesDropped, 1)
-		slog.Debug("rtsp: dropping frame, channel full",
+		log.Debug("rtsp: dropping frame, channel full",
 			"seq", frame.Seq,
-			"trace_id", frame.TraceID,
+			"trace_id", frame.Trace.TraceID,
 		)
 	}
 
@@ -209,19 +229,22 @@ func OnNewSample(sink *app.Sink, ctx *CallbackContext) gst.FlowReturn {
 // to connect a callback to link them when they appear.
 //
 // This callback links the rtspsrc output pad to the rtph264depay input pad.
-func OnPadAdded(srcElement *gst.Element, srcPad *gst.Pad, sinkElement *gst.Element) {
-	slog.Debug("rtsp: pad-added signal received", "pad", srcPad.GetName())
+// A nil logger uses slog.Default().
+func OnPadAdded(srcElement *gst.Element, srcPad *gst.Pad, sinkElement *gst.Element, logger *slog.Logger) {
+	log := loggerOrDefault(logger)
+
+	log.Debug("rtsp: pad-added signal received", "pad", srcPad.GetName())
 
 	// Get sink pad from rtph264depay
 	sinkPad := sinkElement.GetStaticPad("sink")
 	if sinkPad == nil {
-		slog.Error("rtsp: failed to get sink pad from rtph264depay")
+		log.Error("rtsp: failed to get sink pad from rtph264depay")
 		return
 	}
 
 	// Link pads
 	if ret := srcPad.Link(sinkPad); ret != gst.PadLinkOK {
-		slog.Error("rtsp: failed to link pads",
+		log.Error("rtsp: failed to link pads",
 			"src_pad", srcPad.GetName(),
 			"sink_pad", sinkPad.GetName(),
 			"ret", ret,
@@ -229,7 +252,7 @@ func OnPadAdded(srcElement *gst.Element, srcPad *gst.Pad, sinkElement *gst.Eleme
 		return
 	}
 
-	slog.Debug("rtsp: pads linked successfully",
+	log.Debug("rtsp: pads linked successfully",
 		"src_pad", srcPad.GetName(),
 		"sink_pad", sinkPad.GetName(),
 	)
@@ -36,6 +36,21 @@ func (e ErrorCategory) String() string {
 	}
 }
 
+// ErrorDomain is a GError domain (the GQuark string of the error domain)
+type ErrorDomain string
+
+// GStreamer error domains (see gst_*_error_quark)
+const (
+	// DomainCore is used for core errors (state changes, negotiation, missing plugins)
+	DomainCore ErrorDomain = "gst-core-error-quark"
+	// DomainLibrary is used for errors from supporting libraries
+	DomainLibrary ErrorDomain = "gst-library-error-quark"
+	// DomainResource is used for resource errors (network, files, devices)
+	DomainResource ErrorDomain = "gst-resource-error-quark"
+	// DomainStream is used for stream errors (decode, demux, format)
+	DomainStream ErrorDomain = "gst-stream-error-quark"
+)
+
 // ClassifyGStreamerError analyzes a GStreamer error and categorizes it for telemetry
 //
 // This enables better debugging in production by distinguishing between:
@@ -44,13 +59,82 @@ func (e ErrorCategory) String() string {
 // - Auth issues (credentials needed)
 // - Unknown issues (need investigation)
 //
-// Classification is based on error message heuristics and error codes.
-// Note: go-gst's GError does not expose Domain(), so we rely on string matching.
-func ClassifyGStreamerError(gerr *gst.GError) ErrorCategory {
+// Classification uses the GError domain/code when available (see
+// ParseErrorDomain): elements post structured codes such as
+// RESOURCE/NOT_AUTHORIZED or STREAM/DECODE, which are reliable across
+// GStreamer versions and locales. go-gst's GError does not expose the domain,
+// so it is extracted from the bus message separately.
+//
+// Generic codes (*_FAILED) and missing domains fall back to keyword heuristics
+// on the message and debug string.
+func ClassifyGStreamerError(gerr *gst.GError, domain ErrorDomain, code gst.ErrorCode) ErrorCategory {
+	if category, ok := classifyDomainCode(domain, code); ok {
+		return category
+	}
+
 	if gerr == nil {
 		return ErrCategoryUnknown
 	}
 
+	return classifyByKeywords(gerr)
+}
+
+// classifyDomainCode maps a GError domain/code pair to an ErrorCategory
+//
+// Returns false for codes that carry no category information (e.g. FAILED),
+// so the caller can fall back to heuristics.
+func classifyDomainCode(domain ErrorDomain, code gst.ErrorCode) (ErrorCategory, bool) {
+	switch domain {
+	case DomainResource:
+		switch code {
+		case gst.ResourceErrorNotAuthorized:
+			return ErrCategoryAuth, true
+		case gst.ResourceErrorNotFound,
+			gst.ResourceErrorBusy,
+			gst.ResourceErrorOpenRead,
+			gst.ResourceErrorOpenWrite,
+			gst.ResourceErrorOpenReadWrite,
+			gst.ResourceErrorClose,
+			gst.ResourceErrorRead,
+			gst.ResourceErrorWrite,
+			gst.ResourceErrorSeek,
+			gst.ResourceErrorSync:
+			// For rtspsrc, "resource" is the camera connection
+			return ErrCategoryNetwork, true
+		}
+
+	case DomainStream:
+		switch code {
+		case gst.StreamErrorNotImplemented,
+			gst.StreamErrorTypeNotFound,
+			gst.StreamErrorWrongType,
+			gst.StreamErrorCodecNotFound,
+			gst.StreamErrorDecode,
+			gst.StreamErrorDemux,
+			gst.StreamErrorFormat,
+			gst.StreamErrorDecrypt,
+			gst.StreamErrorDecryptNoKey:
+			return ErrCategoryCodec, true
+		}
+
+	case DomainCore:
+		switch code {
+		case gst.CoreErrorNegotiation,
+			gst.CoreErrorCaps,
+			gst.CoreErrorMissingPlugin:
+			return ErrCategoryCodec, true
+		}
+	}
+
+	return ErrCategoryUnknown, false
+}
+
+// classifyByKeywords categorizes an error from its message and debug string
+//
+// Fallback for errors without a usable domain/code. Order matters: the
+// rtspsrc debug string usually mentions "rtsp", so auth and codec keywords
+// are checked before network keywords.
+func classifyByKeywords(gerr *gst.GError) ErrorCategory {
 	errMsg := strings.ToLower(gerr.Error())
 	debugStr := strings.ToLower(gerr.DebugString())
 
@@ -0,0 +1,112 @@
+package rtsp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tinyzimmer/go-gst/gst"
+)
+
+// TestClassifyGStreamerError_DomainCode verifies structured GError codes take
+// precedence over message keywords
+func TestClassifyGStreamerError_DomainCode(t *testing.T) {
+	// rtspsrc mentions "rtsp" in nearly every message, which the keyword
+	// heuristics read as a network error
+	misleading := gst.NewGError(0, errors.New("rtsp decode failure"))
+
+	tests := []struct {
+		name   string
+		domain ErrorDomain
+		code   gst.ErrorCode
+		want   ErrorCategory
+	}{
+		{"not authorized", DomainResource, gst.ResourceErrorNotAuthorized, ErrCategoryAuth},
+		{"not found", DomainResource, gst.ResourceErrorNotFound, ErrCategoryNetwork},
+		{"open read write", DomainResource, gst.ResourceErrorOpenReadWrite, ErrCategoryNetwork},
+		{"read", DomainResource, gst.ResourceErrorRead, ErrCategoryNetwork},
+		{"decode", DomainStream, gst.StreamErrorDecode, ErrCategoryCodec},
+		{"codec not found", DomainStream, gst.StreamErrorCodecNotFound, ErrCategoryCodec},
+		{"format", DomainStream, gst.StreamErrorFormat, ErrCategoryCodec},
+		{"negotiation", DomainCore, gst.CoreErrorNegotiation, ErrCategoryCodec},
+		{"missing plugin", DomainCore, gst.CoreErrorMissingPlugin, ErrCategoryCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyGStreamerError(misleading, tt.domain, tt.code); got != tt.want {
+				t.Errorf("ClassifyGStreamerError(%s, %d) = %s, want %s", tt.domain, tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestClassifyGStreamerError_Fallback verifies keyword heuristics are used for
+// generic codes and missing domains
+func TestClassifyGStreamerError_Fallback(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    string
+		domain ErrorDomain
+		code   gst.ErrorCode
+		want   ErrorCategory
+	}{
+		{"no domain auth", "401 Unauthorized", "", 0, ErrCategoryAuth},
+		{"no domain network", "Could not connect to server", "", 0, ErrCategoryNetwork},
+		{"resource failed", "connection timeout", DomainResource, gst.ResourceErrorFailed, ErrCategoryNetwork},
+		{"stream failed", "not negotiated", DomainStream, gst.StreamErrorFailed, ErrCategoryCodec},
+		{"unmatched", "something odd", DomainCore, gst.CoreErrorFailed, ErrCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gerr := gst.NewGError(tt.code, errors.New(tt.msg))
+			if got := ClassifyGStreamerError(gerr, tt.domain, tt.code); got != tt.want {
+				t.Errorf("ClassifyGStreamerError(%q) = %s, want %s", tt.msg, got, tt.want)
+			}
+		})
+	}
+
+	if got := ClassifyGStreamerError(nil, "", 0); got != ErrCategoryUnknown {
+		t.Errorf("ClassifyGStreamerError(nil) = %s, want unknown", got)
+	}
+}
+
+// TestErrorHistory_Ring verifies bounded growth and oldest-first ordering
+func TestErrorHistory_Ring(t *testing.T) {
+	history := NewErrorHistory(3)
+
+	if _, ok := history.Last(); ok {
+		t.Fatal("expected no last error on empty history")
+	}
+	if got := history.Records(); len(got) != 0 {
+		t.Fatalf("expected empty records, got %d", len(got))
+	}
+
+	for i := 1; i <= 5; i++ {
+		history.Add(ErrorRecord{Message: fmt.Sprintf("err-%d", i), ReconnectAttempt: i})
+
+		records := history.Records()
+		if want := min(i, 3); len(records) != want {
+			t.Fatalf("after %d adds: expected %d records, got %d", i, want, len(records))
+		}
+		if last, _ := history.Last(); last.ReconnectAttempt != i {
+			t.Errorf("after %d adds: Last() = %d", i, last.ReconnectAttempt)
+		}
+		if records[len(records)-1].ReconnectAttempt != i {
+			t.Errorf("after %d adds: newest record not last", i)
+		}
+	}
+
+	records := history.Records()
+	for i, want := range []string{"err-3", "err-4", "err-5"} {
+		if records[i].Message != want {
+			t.Errorf("records[%d] = %q, want %q", i, records[i].Message, want)
+		}
+	}
+
+	history.Reset()
+	if got := history.Records(); len(got) != 0 {
+		t.Errorf("expected empty records after Reset, got %d", len(got))
+	}
+}
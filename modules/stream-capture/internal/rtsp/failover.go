@@ -0,0 +1,192 @@
+package rtsp
+
+import "sync"
+
+// FailoverThresholds is the number of consecutive failures per error
+// category before the active URL is abandoned
+//
+// A failure is consecutive when no frame was delivered since the previous
+// one. Values <= 0 use the defaults (see DefaultFailoverThresholds).
+type FailoverThresholds struct {
+	Network int
+	Codec   int
+	Auth    int
+	Unknown int
+}
+
+// DefaultFailoverThresholds returns the default per-category thresholds
+//
+// Auth fails over immediately (retrying the same credentials will not help),
+// network and unknown errors after 3 attempts, codec errors after 2 (a
+// substream often uses a different profile the decoder handles).
+func DefaultFailoverThresholds() FailoverThresholds {
+	return FailoverThresholds{
+		Network: 3,
+		Codec:   2,
+		Auth:    1,
+		Unknown: 3,
+	}
+}
+
+// withDefaults fills unset thresholds with the default values
+func (t FailoverThresholds) withDefaults() FailoverThresholds {
+	def := DefaultFailoverThresholds()
+	if t.Network <= 0 {
+		t.Network = def.Network
+	}
+	if t.Codec <= 0 {
+		t.Codec = def.Codec
+	}
+	if t.Auth <= 0 {
+		t.Auth = def.Auth
+	}
+	if t.Unknown <= 0 {
+		t.Unknown = def.Unknown
+	}
+	return t
+}
+
+// limit returns the threshold for a category
+func (t FailoverThresholds) limit(category ErrorCategory) int {
+	switch category {
+	case ErrCategoryNetwork:
+		return t.Network
+	case ErrCategoryCodec:
+		return t.Codec
+	case ErrCategoryAuth:
+		return t.Auth
+	default:
+		return t.Unknown
+	}
+}
+
+// Failover tracks the active URL of an ordered list (primary first) and
+// decides when to move to the next one
+//
+// Pure decision logic: switching the pipeline is the caller's job.
+// Thread-safe.
+type Failover struct {
+	mu         sync.Mutex
+	urls       []string
+	thresholds FailoverThresholds
+	active     int
+	failures   [ErrCategoryUnknown + 1]int // Consecutive failures per category
+	failovers  uint32
+}
+
+// NewFailover creates a tracker for urls (urls[0] is the primary)
+func NewFailover(urls []string, thresholds FailoverThresholds) *Failover {
+	return &Failover{
+		urls:       append([]string(nil), urls...),
+		thresholds: thresholds.withDefaults(),
+	}
+}
+
+// Enabled reports whether there is at least one fallback URL
+func (f *Failover) Enabled() bool {
+	return len(f.urls) > 1
+}
+
+// Active returns the index and URL currently in use
+func (f *Failover) Active() (int, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active, f.urls[f.active]
+}
+
+// Primary returns the primary URL
+func (f *Failover) Primary() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.urls[0]
+}
+
+// SetPrimary replaces the primary URL and makes it active (e.g. Reconfigure)
+func (f *Failover) SetPrimary(url string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.urls[0] = url
+	f.active = 0
+	f.resetLocked()
+}
+
+// Failovers returns the number of switches away from the active URL
+func (f *Failover) Failovers() uint32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failovers
+}
+
+// ResetFailovers clears the failover counter (see ResetStats)
+func (f *Failover) ResetFailovers() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failovers = 0
+}
+
+// RecordFailure counts a failure of the active URL
+//
+// progressed is true when frames were delivered since the previous failure:
+// the streak restarts, so a camera that works for hours between glitches is
+// never abandoned. Returns the index of the URL to switch to, or -1 to keep
+// retrying the active one.
+func (f *Failover) RecordFailure(category ErrorCategory, progressed bool) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.urls) < 2 {
+		return -1
+	}
+	if progressed {
+		f.resetLocked()
+	}
+	if category < 0 || category > ErrCategoryUnknown {
+		category = ErrCategoryUnknown
+	}
+
+	f.failures[category]++
+	if f.failures[category] < f.thresholds.limit(category) {
+		return -1
+	}
+	return f.nextLocked()
+}
+
+// Next returns the index of the URL after the active one (wrapping to the
+// primary), or -1 without fallbacks
+//
+// Used when the reconnect budget is exhausted before any threshold is hit.
+func (f *Failover) Next() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.urls) < 2 {
+		return -1
+	}
+	return f.nextLocked()
+}
+
+// nextLocked returns the index after the active one (caller holds f.mu)
+func (f *Failover) nextLocked() int {
+	return (f.active + 1) % len(f.urls)
+}
+
+// Switch makes urls[index] active and returns its URL
+//
+// Moving to a fallback counts as a failover; returning to the primary does
+// not (it is the recovery).
+func (f *Failover) Switch(index int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if index != 0 {
+		f.failovers++
+	}
+	f.active = index
+	f.resetLocked()
+	return f.urls[index]
+}
+
+// resetLocked clears the failure streaks (caller holds f.mu)
+func (f *Failover) resetLocked() {
+	f.failures = [ErrCategoryUnknown + 1]int{}
+}
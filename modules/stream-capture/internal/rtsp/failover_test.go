@@ -0,0 +1,186 @@
+package rtsp
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsptest"
+)
+
+func TestFailover_ThresholdPerCategory(t *testing.T) {
+	f := NewFailover([]string{"rtsp://cam/main", "rtsp://cam/sub", "rtsp://relay/cam"}, FailoverThresholds{Network: 2})
+
+	// Streaks are counted per category
+	if next := f.RecordFailure(ErrCategoryNetwork, false); next != -1 {
+		t.Fatalf("RecordFailure() after 1 network error = %d, want -1", next)
+	}
+	if next := f.RecordFailure(ErrCategoryCodec, false); next != -1 {
+		t.Fatalf("RecordFailure() after 1 codec error = %d, want -1 (default threshold 2)", next)
+	}
+	if next := f.RecordFailure(ErrCategoryNetwork, false); next != 1 {
+		t.Fatalf("RecordFailure() after 2 network errors = %d, want 1", next)
+	}
+
+	if url := f.Switch(1); url != "rtsp://cam/sub" {
+		t.Errorf("Switch(1) = %q, want substream", url)
+	}
+	if f.Failovers() != 1 {
+		t.Errorf("Failovers() = %d, want 1", f.Failovers())
+	}
+
+	// Auth fails over immediately by default; past the last URL wraps to the primary
+	f.Switch(2)
+	if next := f.RecordFailure(ErrCategoryAuth, false); next != 0 {
+		t.Errorf("RecordFailure(auth) on last URL = %d, want 0", next)
+	}
+
+	// Fail-back is not a failover
+	f.Switch(0)
+	if f.Failovers() != 2 {
+		t.Errorf("Failovers() = %d after fail-back, want 2", f.Failovers())
+	}
+	if idx, url := f.Active(); idx != 0 || url != "rtsp://cam/main" {
+		t.Errorf("Active() = %d %q, want primary", idx, url)
+	}
+}
+
+func TestFailover_ProgressResetsStreak(t *testing.T) {
+	f := NewFailover([]string{"rtsp://cam/main", "rtsp://cam/sub"}, FailoverThresholds{})
+
+	f.RecordFailure(ErrCategoryNetwork, false)
+	f.RecordFailure(ErrCategoryNetwork, false)
+
+	// Frames flowed since the last failure: not consecutive
+	if next := f.RecordFailure(ErrCategoryNetwork, true); next != -1 {
+		t.Fatalf("RecordFailure() after progress = %d, want -1", next)
+	}
+	f.RecordFailure(ErrCategoryNetwork, false)
+	if next := f.RecordFailure(ErrCategoryNetwork, false); next != 1 {
+		t.Errorf("RecordFailure() after 3 consecutive = %d, want 1", next)
+	}
+}
+
+func TestFailover_NoFallbacks(t *testing.T) {
+	f := NewFailover([]string{"rtsp://cam/main"}, FailoverThresholds{})
+
+	if f.Enabled() {
+		t.Error("Enabled() = true without fallbacks")
+	}
+	for i := 0; i < 5; i++ {
+		if next := f.RecordFailure(ErrCategoryAuth, false); next != -1 {
+			t.Fatalf("RecordFailure() = %d without fallbacks, want -1", next)
+		}
+	}
+	if next := f.Next(); next != -1 {
+		t.Errorf("Next() = %d without fallbacks, want -1", next)
+	}
+}
+
+func TestFailover_SetPrimary(t *testing.T) {
+	f := NewFailover([]string{"rtsp://cam/main", "rtsp://cam/sub"}, FailoverThresholds{})
+	f.Switch(1)
+
+	f.SetPrimary("rtsp://cam/other")
+	if idx, url := f.Active(); idx != 0 || url != "rtsp://cam/other" {
+		t.Errorf("Active() = %d %q after SetPrimary, want new primary", idx, url)
+	}
+	if f.Next() != 1 {
+		t.Errorf("Next() = %d, want fallback kept", f.Next())
+	}
+}
+
+func TestProbeURL(t *testing.T) {
+	server, err := rtsptest.NewServer(rtsptest.Config{Username: "admin", Password: "secret"})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	// OPTIONS is answered without credentials
+	if err := ProbeURL(context.Background(), server.URLWithCredentials("admin", "secret"), time.Second); err != nil {
+		t.Errorf("ProbeURL() on running server error = %v", err)
+	}
+
+	addr := server.URL()
+	server.Close()
+	if err := ProbeURL(context.Background(), addr, time.Second); err == nil {
+		t.Error("ProbeURL() on closed server succeeded, want error")
+	}
+
+	if err := ProbeURL(context.Background(), "http://cam/snapshot.jpg", time.Second); err == nil {
+		t.Error("ProbeURL() with http scheme succeeded, want error")
+	}
+}
+
+func TestProbeURL_NonRTSPServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 512)
+		conn.Read(buf)
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+	}()
+
+	if err := ProbeURL(context.Background(), "rtsp://"+listener.Addr().String()+"/stream", time.Second); err == nil {
+		t.Error("ProbeURL() on HTTP server succeeded, want error")
+	}
+}
+
+func TestProbeURL_RTSPS(t *testing.T) {
+	// httptest provides a certificate for 127.0.0.1
+	certSource := httptest.NewTLSServer(nil)
+	defer certSource.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", certSource.TLS)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 512)
+				if _, err := conn.Read(buf); err != nil {
+					return
+				}
+				conn.Write([]byte("RTSP/1.0 200 OK\r\nCSeq: 1\r\n\r\n"))
+			}()
+		}
+	}()
+
+	addr := "rtsps://admin:secret@" + listener.Addr().String() + "/stream"
+
+	// Untrusted certificate: verified like rtspsrc does
+	if err := ProbeURL(context.Background(), addr, time.Second); err == nil {
+		t.Error("ProbeURL() with untrusted certificate succeeded, want error")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(certSource.Certificate())
+	saved := probeTLSConfig
+	probeTLSConfig = &tls.Config{RootCAs: roots}
+	defer func() { probeTLSConfig = saved }()
+
+	if err := ProbeURL(context.Background(), addr, time.Second); err != nil {
+		t.Errorf("ProbeURL() on rtsps server error = %v", err)
+	}
+}
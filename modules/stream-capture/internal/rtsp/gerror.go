@@ -0,0 +1,64 @@
+//go:build cgo
+
+package rtsp
+
+/*
+#cgo pkg-config: gstreamer-1.0
+#include <gst/gst.h>
+
+// parse_error_domain extracts the GError domain quark string and code from an
+// ERROR or WARNING message. *domain is NULL if the message carries no GError.
+static void parse_error_domain(GstMessage *msg, const gchar **domain, gint *code) {
+	GError *err = NULL;
+
+	*domain = NULL;
+	*code = 0;
+
+	switch (GST_MESSAGE_TYPE(msg)) {
+	case GST_MESSAGE_ERROR:
+		gst_message_parse_error(msg, &err, NULL);
+		break;
+	case GST_MESSAGE_WARNING:
+		gst_message_parse_warning(msg, &err, NULL);
+		break;
+	default:
+		return;
+	}
+
+	if (err == NULL) {
+		return;
+	}
+
+	// Quark strings are interned for the process lifetime (safe after free)
+	*domain = g_quark_to_string(err->domain);
+	*code = err->code;
+	g_error_free(err);
+}
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/tinyzimmer/go-gst/gst"
+)
+
+// ParseErrorDomain returns the GError domain and code of an ERROR/WARNING bus message
+//
+// go-gst's Message.ParseError() drops the domain (and the code is only set
+// for errors built with gst.NewGError), so it is read directly from the
+// GstMessage. Returns false if the message carries no GError.
+func ParseErrorDomain(msg *gst.Message) (ErrorDomain, gst.ErrorCode, bool) {
+	if msg == nil {
+		return "", 0, false
+	}
+
+	var cDomain *C.gchar
+	var cCode C.gint
+	C.parse_error_domain((*C.GstMessage)(unsafe.Pointer(msg.Instance())), &cDomain, &cCode)
+	if cDomain == nil {
+		return "", 0, false
+	}
+
+	return ErrorDomain(C.GoString((*C.char)(unsafe.Pointer(cDomain)))), gst.ErrorCode(cCode), true
+}
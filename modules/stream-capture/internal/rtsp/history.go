@@ -0,0 +1,89 @@
+package rtsp
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultErrorHistorySize is the number of recent errors kept per stream
+const DefaultErrorHistorySize = 16
+
+// ErrorRecord describes a single pipeline/source error for post-mortem debugging
+type ErrorRecord struct {
+	Time             time.Time
+	Category         ErrorCategory
+	Element          string      // Element that posted the error (e.g. "rtspsrc0"), empty for HTTP sources
+	Message          string      // Human-readable error message
+	Debug            string      // Debug string (source file, function, details)
+	Domain           ErrorDomain // GError domain, empty if unavailable
+	Code             int         // GError code within Domain (0 if unavailable)
+	ReconnectAttempt int         // Consecutive failed attempts before this error (0 = first)
+}
+
+// ErrorHistory is a bounded ring buffer of recent errors
+//
+// Errors are rare (at most one per reconnection attempt), so a mutex is
+// sufficient. Thread-safe.
+type ErrorHistory struct {
+	mu      sync.Mutex
+	records []ErrorRecord
+	next    int // Index of the slot to overwrite next
+	full    bool
+}
+
+// NewErrorHistory creates a ring holding the last size errors
+//
+// size <= 0 uses DefaultErrorHistorySize.
+func NewErrorHistory(size int) *ErrorHistory {
+	if size <= 0 {
+		size = DefaultErrorHistorySize
+	}
+	return &ErrorHistory{records: make([]ErrorRecord, size)}
+}
+
+// Add appends a record, overwriting the oldest one when the ring is full
+func (h *ErrorHistory) Add(rec ErrorRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records[h.next] = rec
+	h.next = (h.next + 1) % len(h.records)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Records returns a copy of the stored errors, oldest first
+func (h *ErrorHistory) Records() []ErrorRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]ErrorRecord(nil), h.records[:h.next]...)
+	}
+
+	out := make([]ErrorRecord, 0, len(h.records))
+	out = append(out, h.records[h.next:]...)
+	return append(out, h.records[:h.next]...)
+}
+
+// Last returns the most recent error, or false if none was recorded
+func (h *ErrorHistory) Last() (ErrorRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full && h.next == 0 {
+		return ErrorRecord{}, false
+	}
+	return h.records[(h.next-1+len(h.records))%len(h.records)], true
+}
+
+// Reset discards all stored errors
+func (h *ErrorHistory) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clear(h.records)
+	h.next = 0
+	h.full = false
+}
@@ -7,6 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
 	"github.com/tinyzimmer/go-gst/gst"
 )
 
@@ -16,23 +17,77 @@ type ErrorCounters struct {
 	Codec   *uint64 // Codec/stream errors (decode failures, format issues)
 	Auth    *uint64 // Authentication/authorization errors
 	Unknown *uint64 // Unclassified errors
+
+	History *ErrorHistory // Recent error details (optional)
+}
+
+// Record increments the counter matching the given category (atomic)
+//
+// Shared by every source type (RTSP bus errors, MJPEG HTTP errors) so that
+// StreamStats.Errors has the same meaning regardless of the camera protocol.
+func (c *ErrorCounters) Record(category ErrorCategory) {
+	switch category {
+	case ErrCategoryNetwork:
+		atomic.AddUint64(c.Network, 1)
+	case ErrCategoryCodec:
+		atomic.AddUint64(c.Codec, 1)
+	case ErrCategoryAuth:
+		atomic.AddUint64(c.Auth, 1)
+	default:
+		atomic.AddUint64(c.Unknown, 1)
+	}
+}
+
+// RecordError counts the error and stores its details in History (if set)
+func (c *ErrorCounters) RecordError(rec ErrorRecord) {
+	c.Record(rec.Category)
+	if c.History != nil {
+		c.History.Add(rec)
+	}
+}
+
+// PipelineError is returned by MonitorPipelineBus for a bus error
+//
+// Carries the category so the caller can apply per-category policies
+// (see Failover).
+type PipelineError struct {
+	Category ErrorCategory
+	Message  string // Redacted error message
+}
+
+func (e *PipelineError) Error() string {
+	return fmt.Sprintf("pipeline error [%s]: %s", e.Category.String(), e.Message)
 }
 
 // MonitorMetrics holds stream metrics for monitoring
 type MonitorMetrics struct {
-	RTSPURL        string
+	RTSPURL        string // Redacted URL (logged, never the raw credentials)
 	Resolution     string
 	FrameCount     *uint64
 	ReconnectCount *uint32
 	StartedAt      time.Time
+	Logger         *slog.Logger // Logger (nil = slog.Default())
+	Clock          clock.Clock  // Error timestamps and uptime (nil = system clock)
+
+	// Redact scrubs credentials from GStreamer error/debug text before it
+	// is logged or recorded (nil = text kept as-is)
+	Redact func(string) string
+}
+
+// redact applies m.Redact if set
+func (m *MonitorMetrics) redact(text string) string {
+	if m.Redact == nil {
+		return text
+	}
+	return m.Redact(text)
 }
 
 // MonitorPipelineBus monitors the GStreamer pipeline bus for messages
 //
 // This function:
 //  1. Polls pipeline bus for messages (EOS, Error, StateChanged)
-//  2. Classifies errors for telemetry
-//  3. Updates error counters atomically
+//  2. Classifies errors for telemetry (GError domain/code, keyword fallback)
+//  3. Updates error counters atomically and records error details
 //  4. Resets reconnection state on PLAYING transition
 //
 // Returns an error if the pipeline encounters an error (triggers reconnection).
@@ -51,6 +106,9 @@ func MonitorPipelineBus(
 	reconnectState *ReconnectState,
 	metrics *MonitorMetrics,
 ) error {
+	log := loggerOrDefault(metrics.Logger)
+	clk := clock.OrSystem(metrics.Clock)
+
 	if pipeline == nil {
 		return fmt.Errorf("pipeline not initialized")
 	}
@@ -60,7 +118,7 @@ func MonitorPipelineBus(
 	for {
 		select {
 		case <-ctx.Done():
-			slog.Debug("stream-capture: context cancelled, stopping pipeline monitor")
+			log.Debug("stream-capture: context cancelled, stopping pipeline monitor")
 			return nil
 
 		default:
@@ -72,48 +130,56 @@ func MonitorPipelineBus(
 
 			switch msg.Type() {
 			case gst.MessageEOS:
-				slog.Info("stream-capture: end of stream received",
+				log.Info("stream-capture: end of stream received",
 					"rtsp_url", metrics.RTSPURL,
-					"uptime", time.Since(metrics.StartedAt),
+					"uptime", clk.Since(metrics.StartedAt),
 					"frames_processed", atomic.LoadUint64(metrics.FrameCount),
 				)
 				return fmt.Errorf("end of stream")
 
 			case gst.MessageError:
 				gerr := msg.ParseError()
+				domain, code, _ := ParseErrorDomain(msg)
 
 				// Classify error for telemetry
-				category := ClassifyGStreamerError(gerr)
-
-				// Update error counters (atomic)
-				switch category {
-				case ErrCategoryNetwork:
-					atomic.AddUint64(errorCounters.Network, 1)
-				case ErrCategoryCodec:
-					atomic.AddUint64(errorCounters.Codec, 1)
-				case ErrCategoryAuth:
-					atomic.AddUint64(errorCounters.Auth, 1)
-				case ErrCategoryUnknown:
-					atomic.AddUint64(errorCounters.Unknown, 1)
-				}
+				category := ClassifyGStreamerError(gerr, domain, code)
+
+				// rtspsrc embeds the location (with credentials) in its messages
+				errMsg := metrics.redact(gerr.Error())
+				errDebug := metrics.redact(gerr.DebugString())
+
+				// Update error counters (atomic) and history
+				errorCounters.RecordError(ErrorRecord{
+					Time:             clk.Now(),
+					Category:         category,
+					Element:          msg.Source(),
+					Message:          errMsg,
+					Debug:            errDebug,
+					Domain:           domain,
+					Code:             int(code),
+					ReconnectAttempt: reconnectState.CurrentRetries,
+				})
 
-				slog.Error("stream-capture: pipeline error",
-					"error", gerr.Error(),
-					"debug", gerr.DebugString(),
+				log.Error("stream-capture: pipeline error",
+					"error", errMsg,
+					"debug", errDebug,
 					"category", category.String(),
+					"element", msg.Source(),
+					"domain", domain,
+					"code", int(code),
 					"rtsp_url", metrics.RTSPURL,
 					"resolution", metrics.Resolution,
-					"uptime", time.Since(metrics.StartedAt),
+					"uptime", clk.Since(metrics.StartedAt),
 					"frames_processed", atomic.LoadUint64(metrics.FrameCount),
 					"reconnects", atomic.LoadUint32(reconnectState.Reconnects),
 				)
 				// Return error to trigger reconnection
-				return fmt.Errorf("pipeline error [%s]: %s", category.String(), gerr.Error())
+				return &PipelineError{Category: category, Message: errMsg}
 
 			case gst.MessageStateChanged:
 				if msg.Source() == pipeline.GetName() {
 					old, new := msg.ParseStateChanged()
-					slog.Debug("stream-capture: pipeline state changed",
+					log.Debug("stream-capture: pipeline state changed",
 						"from", old,
 						"to", new,
 					)
@@ -121,7 +187,7 @@ func MonitorPipelineBus(
 					// Reset reconnection state when reaching PLAYING state
 					if new == gst.StatePlaying {
 						ResetReconnectState(reconnectState)
-						slog.Info("stream-capture: pipeline playing, reconnect state reset")
+						log.Info("stream-capture: pipeline playing, reconnect state reset")
 					}
 				}
 			}
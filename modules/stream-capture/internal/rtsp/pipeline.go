@@ -3,6 +3,7 @@ package rtsp
 import (
 	"fmt"
 	"log/slog"
+	"sync/atomic"
 	"time"
 
 	"github.com/tinyzimmer/go-gst/gst"
@@ -15,7 +16,20 @@ type PipelineConfig struct {
 	Width        int
 	Height       int
 	TargetFPS    float64
-	Acceleration int // 0=Auto, 1=VAAPI, 2=Software (from streamcapture.HardwareAccel)
+	Acceleration int          // 0=Auto, 1=VAAPI, 2=Software (from streamcapture.HardwareAccel)
+	Logger       *slog.Logger // Logger (nil = slog.Default())
+}
+
+// livePipelines counts pipelines created by CreatePipeline and not yet
+// destroyed by DestroyPipeline (leak detection for start/stop cycles)
+var livePipelines atomic.Int64
+
+// LivePipelines returns the number of pipelines currently alive
+//
+// Used by tests to verify that repeated Start()/Stop() cycles release every
+// GStreamer pipeline.
+func LivePipelines() int64 {
+	return livePipelines.Load()
 }
 
 // PipelineElements holds references to GStreamer pipeline elements
@@ -27,6 +41,8 @@ type PipelineElements struct {
 	CapsFilter *gst.Element
 	RTSPSrc    *gst.Element
 	UsingVAAPI bool // True if VAAPI hardware acceleration is active
+
+	destroyed atomic.Bool // Set once by DestroyPipeline (idempotent cleanup)
 }
 
 // CreatePipeline creates and configures a GStreamer pipeline for RTSP streaming
@@ -42,6 +58,8 @@ type PipelineElements struct {
 // Returns PipelineElements with references to key elements for hot-reload,
 // or an error if pipeline creation fails.
 func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
+	log := loggerOrDefault(cfg.Logger)
+
 	// Initialize GStreamer (safe to call multiple times)
 	gst.Init(nil)
 
@@ -66,7 +84,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 	latency := 200
 	if cfg.TargetFPS <= 2.0 {
 		latency = 50
-		slog.Debug("rtsp: using low-latency buffer", "latency_ms", latency, "target_fps", cfg.TargetFPS)
+		log.Debug("rtsp: using low-latency buffer", "latency_ms", latency, "target_fps", cfg.TargetFPS)
 	}
 	rtspsrc.SetProperty("latency", latency)
 
@@ -102,7 +120,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 		decoder, err = gst.NewElement("vaapih264dec")
 		if err != nil {
 			// Fallback to generic VAAPI decoder
-			slog.Warn("rtsp: vaapih264dec not available, using vaapidecodebin", "error", err)
+			log.Warn("rtsp: vaapih264dec not available, using vaapidecodebin", "error", err)
 			decoder, err = gst.NewElement("vaapidecodebin")
 			if err != nil {
 				return nil, fmt.Errorf("failed to create VAAPI decoder (VAAPI required): %w", err)
@@ -111,14 +129,14 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 			// OPTIMIZATION Level 3: Low-latency mode for vaapih264dec
 			// Safe for H.264 Main profile (no B-frames)
 			decoder.SetProperty("low-latency", true)
-			slog.Debug("rtsp: using vaapih264dec with low-latency mode")
+			log.Debug("rtsp: using vaapih264dec with low-latency mode")
 		}
 
 		// OPTIMIZATION Level 2: Skip corrupt frames for low FPS
 		// When target < source FPS, decoder can skip damaged frames
 		if cfg.TargetFPS < 6.0 {
 			decoder.SetProperty("output-corrupt", false)
-			slog.Debug("rtsp: decoder will skip corrupt frames", "target_fps", cfg.TargetFPS)
+			log.Debug("rtsp: decoder will skip corrupt frames", "target_fps", cfg.TargetFPS)
 		}
 
 		vaapiPostproc, err = gst.NewElement("vaapipostproc")
@@ -174,7 +192,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 
 				scaler = nil
 				usingVAAPI = true
-				slog.Info("rtsp: using optimized VAAPI pipeline (vaapih264dec)")
+				log.Info("rtsp: using optimized VAAPI pipeline (vaapih264dec)")
 			} else {
 				// vaapipostproc failed, fallback to software
 				vaapiPostproc = nil
@@ -187,7 +205,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 				converter.SetProperty("chroma-mode", 0)
 				scaler, _ = gst.NewElement("videoscale")
 				usingVAAPI = false
-				slog.Warn("rtsp: VAAPI unavailable, using software decoder")
+				log.Warn("rtsp: VAAPI unavailable, using software decoder")
 			}
 		} else {
 			// vaapih264dec failed, use software
@@ -201,7 +219,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 			converter.SetProperty("chroma-mode", 0)
 			scaler, _ = gst.NewElement("videoscale")
 			usingVAAPI = false
-			slog.Warn("rtsp: VAAPI unavailable, using software decoder")
+			log.Warn("rtsp: VAAPI unavailable, using software decoder")
 		}
 
 	case AccelSoftware:
@@ -230,7 +248,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 			return nil, fmt.Errorf("failed to create videoscale: %w", err)
 		}
 
-		slog.Info("rtsp: using software decoder with multi-threading")
+		log.Info("rtsp: using software decoder with multi-threading")
 
 	default:
 		return nil, fmt.Errorf("invalid acceleration mode: %d", cfg.Acceleration)
@@ -248,7 +266,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 	// Immediate drop decisions (no 500ms smoothing window)
 	if cfg.TargetFPS <= 2.0 {
 		videorate.SetProperty("average-period", uint64(0))
-		slog.Debug("rtsp: videorate using immediate drop mode", "target_fps", cfg.TargetFPS)
+		log.Debug("rtsp: videorate using immediate drop mode", "target_fps", cfg.TargetFPS)
 	}
 
 	// Create capsfilter with framerate control
@@ -274,7 +292,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 	// OPTIMIZATION Level 2: QoS events for upstream frame dropping
 	// When appsink drops frames, notify upstream elements to drop BEFORE decoding
 	appsink.SetProperty("qos", true)
-	slog.Debug("rtsp: appsink QoS events enabled (pre-decode drops)")
+	log.Debug("rtsp: appsink QoS events enabled (pre-decode drops)")
 
 	// OPTIMIZATION Level 1: Add RGB capsfilter after videoconvert (format lock)
 	// Note: We do NOT add NV12 capsfilter because video/x-raw(memory:VASurface)
@@ -292,7 +310,7 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 		// Lock RGB format with target resolution (no framerate yet)
 		capsRGBStr := fmt.Sprintf("video/x-raw,format=RGB,width=%d,height=%d", cfg.Width, cfg.Height)
 		capsRGB.SetProperty("caps", gst.NewCapsFromString(capsRGBStr))
-		slog.Debug("rtsp: RGB format lock enabled", "caps", capsRGBStr)
+		log.Debug("rtsp: RGB format lock enabled", "caps", capsRGBStr)
 	}
 
 	// Add all elements to pipeline (conditionally based on VAAPI usage)
@@ -331,11 +349,11 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 
 		// Add probe to vaapipostproc output to measure decode latency
 		// This captures the timestamp when the frame exits the GPU decoder
-		if err := addDecodeLatencyProbe(vaapiPostproc); err != nil {
-			slog.Warn("rtsp: failed to add decode latency probe, continuing without telemetry", "error", err)
+		if err := addDecodeLatencyProbe(vaapiPostproc, log); err != nil {
+			log.Warn("rtsp: failed to add decode latency probe, continuing without telemetry", "error", err)
 		}
 
-		slog.Info("rtsp: optimized VAAPI pipeline created",
+		log.Info("rtsp: optimized VAAPI pipeline created",
 			"decoder", "vaapih264dec",
 			"gpu_scaling", true,
 			"format", "NV12→RGB",
@@ -370,11 +388,13 @@ func CreatePipeline(cfg PipelineConfig) (*PipelineElements, error) {
 		}
 
 		// Add probe to decoder output to measure decode latency (software decode)
-		if err := addDecodeLatencyProbe(decoder); err != nil {
-			slog.Warn("rtsp: failed to add decode latency probe, continuing without telemetry", "error", err)
+		if err := addDecodeLatencyProbe(decoder, log); err != nil {
+			log.Warn("rtsp: failed to add decode latency probe, continuing without telemetry", "error", err)
 		}
 	}
 
+	livePipelines.Add(1)
+
 	return &PipelineElements{
 		Pipeline:   pipeline,
 		AppSink:    appsink,
@@ -419,6 +439,10 @@ func DestroyPipeline(elements *PipelineElements) error {
 		return fmt.Errorf("failed to set pipeline to NULL: %w", err)
 	}
 
+	if elements.destroyed.CompareAndSwap(false, true) {
+		livePipelines.Add(-1)
+	}
+
 	return nil
 }
 
@@ -429,7 +453,7 @@ func DestroyPipeline(elements *PipelineElements) error {
 // in OnNewSample callback.
 //
 // Performance: ~50-100ns overhead per frame (timestamp capture + metadata attachment).
-func addDecodeLatencyProbe(element *gst.Element) error {
+func addDecodeLatencyProbe(element *gst.Element, log *slog.Logger) error {
 	// Get source pad (output) from decoder element
 	srcPad := element.GetStaticPad("src")
 	if srcPad == nil {
@@ -457,7 +481,7 @@ func addDecodeLatencyProbe(element *gst.Element) error {
 		return gst.PadProbeOK
 	})
 
-	slog.Debug("rtsp: decode latency probe installed", "element", element.GetName())
+	log.Debug("rtsp: decode latency probe installed", "element", element.GetName())
 	return nil
 }
 
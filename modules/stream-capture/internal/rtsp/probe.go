@@ -0,0 +1,98 @@
+package rtsp
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default ports used when the URL has no explicit port
+const (
+	defaultRTSPPort  = "554"
+	defaultRTSPSPort = "322"
+)
+
+// probeTLSConfig is used for rtsps:// probes. The server certificate is
+// verified, like rtspsrc does by default (tls-validation-flags).
+var probeTLSConfig = &tls.Config{}
+
+// ProbeURL checks that the RTSP server behind rawURL answers an OPTIONS request
+//
+// This is a cheap liveness check used to decide when to fail back to the
+// primary URL: it does not open a media session, so the camera's session
+// limit is not consumed. OPTIONS is unauthenticated on most cameras, so bad
+// credentials are not detected here (the pipeline fails over again if so).
+//
+// Returns nil on a 2xx response. rtsps:// URLs are probed over TLS.
+func ProbeURL(ctx context.Context, rawURL string, timeout time.Duration) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("rtsp: probe: invalid URL: %w", err)
+	}
+
+	var (
+		port   string
+		dialer interface {
+			DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+		}
+	)
+	switch u.Scheme {
+	case "rtsp":
+		port, dialer = defaultRTSPPort, &net.Dialer{}
+	case "rtsps":
+		port, dialer = defaultRTSPSPort, &tls.Dialer{Config: probeTLSConfig}
+	default:
+		return fmt.Errorf("rtsp: probe: unsupported scheme %q", u.Scheme)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), port)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return fmt.Errorf("rtsp: probe: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Never send credentials in the request line
+	u.User = nil
+	req := fmt.Sprintf("OPTIONS %s RTSP/1.0\r\nCSeq: 1\r\nUser-Agent: stream-capture\r\n\r\n", u.String())
+	if _, err := conn.Write([]byte(req)); err != nil {
+		return fmt.Errorf("rtsp: probe: %w", err)
+	}
+
+	status, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("rtsp: probe: %w", err)
+	}
+
+	// Status line: RTSP/1.0 200 OK
+	fields := strings.Fields(status)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "RTSP/") {
+		return fmt.Errorf("rtsp: probe: malformed response %q", strings.TrimSpace(status))
+	}
+	code, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return fmt.Errorf("rtsp: probe: malformed status %q", fields[1])
+	}
+	if code < 200 || code > 299 {
+		return fmt.Errorf("rtsp: probe: status %d", code)
+	}
+
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 	"log/slog"
 	"sync/atomic"
 	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
 )
 
 // ReconnectConfig contains configuration for exponential backoff reconnection
@@ -13,6 +15,19 @@ type ReconnectConfig struct {
 	MaxRetries    int           // Maximum number of reconnection attempts (default: 5)
 	RetryDelay    time.Duration // Initial retry delay (default: 1 second)
 	MaxRetryDelay time.Duration // Maximum retry delay cap (default: 30 seconds)
+	Logger        *slog.Logger  // Logger (nil = slog.Default())
+	Clock         clock.Clock   // Backoff timer (nil = system clock)
+}
+
+// loggerOrDefault returns l, or slog.Default() if nil
+//
+// Every internal/rtsp entry point accepts an optional logger so callers can
+// inject one tagged with the camera ID (multi-camera logs).
+func loggerOrDefault(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
 }
 
 // DefaultReconnectConfig returns default reconnection configuration
@@ -54,11 +69,14 @@ func RunWithReconnect(
 	cfg ReconnectConfig,
 	state *ReconnectState,
 ) error {
+	log := loggerOrDefault(cfg.Logger)
+	clk := clock.OrSystem(cfg.Clock)
+
 	for {
 		// Check context before attempting connection
 		select {
 		case <-ctx.Done():
-			slog.Info("rtsp: context cancelled, stopping reconnection")
+			log.Info("rtsp: context cancelled, stopping reconnection")
 			return ctx.Err()
 		default:
 		}
@@ -68,12 +86,12 @@ func RunWithReconnect(
 		if err == nil {
 			// Connection successful - reset retry counter
 			state.CurrentRetries = 0
-			slog.Info("rtsp: connection established successfully")
+			log.Info("rtsp: connection established successfully")
 			return nil
 		}
 
 		// Connection failed
-		slog.Error("rtsp: connection failed", "error", err)
+		log.Error("rtsp: connection failed", "error", err)
 
 		// Check if max retries exceeded
 		state.CurrentRetries++
@@ -86,7 +104,7 @@ func RunWithReconnect(
 		// Calculate exponential backoff delay
 		delay := calculateBackoff(state.CurrentRetries, cfg)
 
-		slog.Warn("rtsp: retrying connection",
+		log.Warn("rtsp: retrying connection",
 			"attempt", state.CurrentRetries,
 			"max_retries", cfg.MaxRetries,
 			"delay", delay,
@@ -94,10 +112,10 @@ func RunWithReconnect(
 
 		// Wait with backoff (or until context cancelled)
 		select {
-		case <-time.After(delay):
+		case <-clk.After(delay):
 			continue // Retry
 		case <-ctx.Done():
-			slog.Info("rtsp: context cancelled during backoff")
+			log.Info("rtsp: context cancelled during backoff")
 			return ctx.Err()
 		}
 	}
@@ -132,5 +150,4 @@ func calculateBackoff(attempt int, cfg ReconnectConfig) time.Duration {
 // the retry counter for future reconnection attempts.
 func ResetReconnectState(state *ReconnectState) {
 	state.CurrentRetries = 0
-	slog.Debug("rtsp: reconnect state reset")
 }
@@ -0,0 +1,75 @@
+package rtsp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+)
+
+// TestRunWithReconnect_FakeClockBackoff verifies the exponential backoff
+// schedule against an injected clock (no real sleeping)
+func TestRunWithReconnect_FakeClockBackoff(t *testing.T) {
+	fake := clock.NewFake(time.Unix(0, 0))
+	cfg := ReconnectConfig{
+		MaxRetries:    3,
+		RetryDelay:    time.Second,
+		MaxRetryDelay: 3 * time.Second,
+		Clock:         fake,
+	}
+	state := &ReconnectState{Reconnects: new(uint32)}
+
+	var attempts atomic.Int32
+	connect := func(ctx context.Context) error {
+		attempts.Add(1)
+		return errors.New("connection refused")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunWithReconnect(context.Background(), connect, cfg, state)
+	}()
+
+	// waitBackoff blocks until the loop is waiting on the fake clock
+	waitBackoff := func(wantAttempts int32) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for fake.Waiters() == 0 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		if got := attempts.Load(); got != wantAttempts {
+			t.Fatalf("expected %d attempts before backoff, got %d", wantAttempts, got)
+		}
+	}
+
+	// Backoff: 1s, 2s, 3s (capped)
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		waitBackoff(int32(i + 1))
+
+		// Just short of the delay: no retry yet
+		fake.Advance(delay - time.Millisecond)
+		if fake.Waiters() != 1 {
+			t.Fatalf("attempt %d: retried before %v backoff elapsed", i+1, delay)
+		}
+		fake.Advance(time.Millisecond)
+	}
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected max retries error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunWithReconnect did not give up after max retries")
+	}
+
+	if got := attempts.Load(); got != 4 {
+		t.Errorf("expected 4 attempts (initial + 3 retries), got %d", got)
+	}
+	if got := atomic.LoadUint32(state.Reconnects); got != 4 {
+		t.Errorf("expected 4 reconnects counted, got %d", got)
+	}
+}
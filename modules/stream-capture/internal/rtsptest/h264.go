@@ -0,0 +1,188 @@
+package rtsptest
+
+// Minimal H.264 generator: every frame is an IDR picture made of I_PCM
+// macroblocks (raw samples, no prediction or transform), which any
+// conforming decoder accepts. Bitrate is high (~384 bytes per macroblock),
+// but no encoder is needed and frames are deterministic.
+//
+// Stream layout: Baseline profile, 4:2:0 8-bit, CAVLC, pic_order_cnt_type 2,
+// one slice per picture. Width and height must be multiples of 16.
+
+const (
+	nalTypeIDR = 5
+	nalTypeSPS = 7
+	nalTypePPS = 8
+
+	mbTypeIPCM = 25 // mb_type for I_PCM in I slices
+	sliceTypeI = 7  // I slice, all slices of the picture are I
+
+	profileBaseline = 66
+	levelIDC        = 40 // Level 4.0: room for I_PCM bitrates at test resolutions
+)
+
+// bitWriter writes an RBSP most-significant bit first
+type bitWriter struct {
+	buf   []byte
+	nbits int // bits used in the last byte (0 = byte aligned)
+}
+
+func (w *bitWriter) writeBit(b uint) {
+	if w.nbits == 0 {
+		w.buf = append(w.buf, 0)
+	}
+	if b != 0 {
+		w.buf[len(w.buf)-1] |= 0x80 >> w.nbits
+	}
+	w.nbits = (w.nbits + 1) % 8
+}
+
+// writeBits writes the n low bits of v
+func (w *bitWriter) writeBits(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.writeBit(uint(v>>i) & 1)
+	}
+}
+
+// writeUE writes an unsigned Exp-Golomb code ue(v)
+func (w *bitWriter) writeUE(v uint64) {
+	v++
+	n := 0
+	for x := v; x > 1; x >>= 1 {
+		n++
+	}
+	w.writeBits(0, n)
+	w.writeBits(v, n+1)
+}
+
+// writeSE writes a signed Exp-Golomb code se(v)
+func (w *bitWriter) writeSE(v int64) {
+	if v > 0 {
+		w.writeUE(uint64(2*v - 1))
+	} else {
+		w.writeUE(uint64(-2 * v))
+	}
+}
+
+// alignZero pads with zero bits to the next byte boundary
+func (w *bitWriter) alignZero() {
+	for w.nbits != 0 {
+		w.writeBit(0)
+	}
+}
+
+// trailing writes rbsp_trailing_bits (stop bit + zero alignment)
+func (w *bitWriter) trailing() {
+	w.writeBit(1)
+	w.alignZero()
+}
+
+// writeBytes appends raw bytes (writer must be byte aligned)
+func (w *bitWriter) writeBytes(b []byte) {
+	w.buf = append(w.buf, b...)
+}
+
+// nalUnit wraps an RBSP in a NAL header and applies emulation prevention
+func nalUnit(refIdc, nalType byte, rbsp []byte) []byte {
+	out := make([]byte, 0, len(rbsp)+len(rbsp)/64+1)
+	out = append(out, refIdc<<5|nalType)
+
+	zeros := 0
+	for _, b := range rbsp {
+		if zeros >= 2 && b <= 3 {
+			out = append(out, 3)
+			zeros = 0
+		}
+		out = append(out, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return out
+}
+
+// encodeSPS returns the sequence parameter set NAL unit
+func encodeSPS(width, height int) []byte {
+	var w bitWriter
+	w.writeBits(profileBaseline, 8)
+	w.writeBits(0, 8) // constraint_set flags + reserved_zero_2bits
+	w.writeBits(levelIDC, 8)
+	w.writeUE(0)  // seq_parameter_set_id
+	w.writeUE(0)  // log2_max_frame_num_minus4
+	w.writeUE(2)  // pic_order_cnt_type (output order = decode order)
+	w.writeUE(1)  // max_num_ref_frames
+	w.writeBit(0) // gaps_in_frame_num_value_allowed_flag
+	w.writeUE(uint64(width/16 - 1))
+	w.writeUE(uint64(height/16 - 1))
+	w.writeBit(1) // frame_mbs_only_flag
+	w.writeBit(1) // direct_8x8_inference_flag
+	w.writeBit(0) // frame_cropping_flag
+	w.writeBit(0) // vui_parameters_present_flag
+	w.trailing()
+	return nalUnit(3, nalTypeSPS, w.buf)
+}
+
+// encodePPS returns the picture parameter set NAL unit
+func encodePPS() []byte {
+	var w bitWriter
+	w.writeUE(0)      // pic_parameter_set_id
+	w.writeUE(0)      // seq_parameter_set_id
+	w.writeBit(0)     // entropy_coding_mode_flag (CAVLC)
+	w.writeBit(0)     // bottom_field_pic_order_in_frame_present_flag
+	w.writeUE(0)      // num_slice_groups_minus1
+	w.writeUE(0)      // num_ref_idx_l0_default_active_minus1
+	w.writeUE(0)      // num_ref_idx_l1_default_active_minus1
+	w.writeBit(0)     // weighted_pred_flag
+	w.writeBits(0, 2) // weighted_bipred_idc
+	w.writeSE(0)      // pic_init_qp_minus26
+	w.writeSE(0)      // pic_init_qs_minus26
+	w.writeSE(0)      // chroma_qp_index_offset
+	w.writeBit(0)     // deblocking_filter_control_present_flag
+	w.writeBit(0)     // constrained_intra_pred_flag
+	w.writeBit(0)     // redundant_pic_cnt_present_flag
+	w.trailing()
+	return nalUnit(3, nalTypePPS, w.buf)
+}
+
+// encodeIDR returns an IDR slice NAL unit of I_PCM macroblocks
+//
+// The picture shows a diagonal luma gradient that moves with frameIndex, so
+// consecutive frames differ. idrPicID must differ between consecutive IDRs.
+func encodeIDR(width, height int, frameIndex uint64, idrPicID uint64) []byte {
+	mbWidth, mbHeight := width/16, height/16
+
+	var w bitWriter
+	// slice_header
+	w.writeUE(0)          // first_mb_in_slice
+	w.writeUE(sliceTypeI) // slice_type
+	w.writeUE(0)          // pic_parameter_set_id
+	w.writeBits(0, 4)     // frame_num (log2_max_frame_num = 4), always 0 for IDR
+	w.writeUE(idrPicID)   // idr_pic_id
+	w.writeBit(0)         // no_output_of_prior_pics_flag
+	w.writeBit(0)         // long_term_reference_flag
+	w.writeSE(0)          // slice_qp_delta
+
+	// slice_data: one I_PCM macroblock_layer per macroblock
+	luma := make([]byte, 256)
+	chroma := make([]byte, 128)
+	for i := range chroma {
+		chroma[i] = 128
+	}
+	for mby := 0; mby < mbHeight; mby++ {
+		for mbx := 0; mbx < mbWidth; mbx++ {
+			w.writeUE(mbTypeIPCM)
+			w.alignZero() // pcm_alignment_zero_bit
+
+			// 16..235 (video range), never 0
+			y := byte(16 + (uint64(mbx+mby)*8+frameIndex*4)%220)
+			for i := range luma {
+				luma[i] = y
+			}
+			w.writeBytes(luma)
+			w.writeBytes(chroma)
+		}
+	}
+	w.trailing()
+	return nalUnit(3, nalTypeIDR, w.buf)
+}
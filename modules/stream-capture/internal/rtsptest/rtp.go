@@ -0,0 +1,85 @@
+package rtsptest
+
+import "encoding/binary"
+
+const (
+	rtpPayloadType = 96
+	rtpClockRate   = 90000
+	rtpMaxPayload  = 1400 // Fits a typical MTU; FU-A fragmentation above this
+
+	nalTypeFUA = 28
+)
+
+// packetizer turns H.264 access units into RTP packets (RFC 6184)
+type packetizer struct {
+	seq  uint16
+	ssrc uint32
+}
+
+// packetize returns the RTP packets of one access unit
+//
+// NAL units up to rtpMaxPayload are sent as single NAL unit packets, larger
+// ones as FU-A fragments. The marker bit is set on the last packet.
+func (p *packetizer) packetize(nals [][]byte, timestamp uint32) [][]byte {
+	var packets [][]byte
+	for i, nal := range nals {
+		last := i == len(nals)-1
+
+		if len(nal) <= rtpMaxPayload {
+			packets = append(packets, p.packet(nal, timestamp, last))
+			continue
+		}
+
+		indicator := nal[0]&0xE0 | nalTypeFUA
+		nalType := nal[0] & 0x1F
+		payload := nal[1:]
+		for start := true; len(payload) > 0; start = false {
+			n := len(payload)
+			if n > rtpMaxPayload-2 {
+				n = rtpMaxPayload - 2
+			}
+			end := n == len(payload)
+
+			header := nalType
+			if start {
+				header |= 0x80
+			}
+			if end {
+				header |= 0x40
+			}
+
+			frag := make([]byte, 0, n+2)
+			frag = append(frag, indicator, header)
+			frag = append(frag, payload[:n]...)
+			packets = append(packets, p.packet(frag, timestamp, last && end))
+			payload = payload[n:]
+		}
+	}
+	return packets
+}
+
+// packet builds a single RTP packet
+func (p *packetizer) packet(payload []byte, timestamp uint32, marker bool) []byte {
+	pkt := make([]byte, 12+len(payload))
+	pkt[0] = 0x80 // version 2
+	pkt[1] = rtpPayloadType
+	if marker {
+		pkt[1] |= 0x80
+	}
+	binary.BigEndian.PutUint16(pkt[2:], p.seq)
+	binary.BigEndian.PutUint32(pkt[4:], timestamp)
+	binary.BigEndian.PutUint32(pkt[8:], p.ssrc)
+	copy(pkt[12:], payload)
+	p.seq++
+	return pkt
+}
+
+// interleave frames an RTP packet for RTSP over TCP (RFC 2326 §10.12)
+func interleave(channel byte, pkt []byte) []byte {
+	out := make([]byte, 4+len(pkt))
+	out[0] = '$'
+	out[1] = channel
+	binary.BigEndian.PutUint16(out[2:], uint16(len(pkt)))
+	copy(out[4:], pkt)
+	return out
+}
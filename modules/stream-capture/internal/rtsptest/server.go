@@ -0,0 +1,549 @@
+// Package rtsptest provides a local RTSP server for integration tests.
+//
+// The server streams a generated H.264 video (see h264.go) over RTSP with
+// TCP interleaved transport, the only transport RTSPStream uses. Faults are
+// injected at runtime to exercise reconnection and error classification
+// against a real GStreamer pipeline:
+//
+//	srv, err := rtsptest.NewServer(rtsptest.Config{FPS: 10})
+//	defer srv.Close()
+//
+//	stream, _ := streamcapture.NewRTSPStream(streamcapture.RTSPConfig{URL: srv.URL(), ...})
+//
+//	srv.DropConnections()         // connection lost → reconnect
+//	srv.SetStalled(true)          // connection kept, no packets
+//	srv.SetCredentials("u", "p")  // 401 for clients without these credentials
+//	srv.SetCodec(rtsptest.CodecH265) // SDP advertises a codec the pipeline cannot depay
+//
+// The server is pure Go (no GStreamer), so it can also be exercised by
+// plain unit tests.
+package rtsptest
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultWidth  = 320
+	defaultHeight = 240
+	defaultFPS    = 10.0
+	defaultPath   = "/stream"
+
+	// writeTimeout bounds a single interleaved write; a client that stops
+	// reading for longer is disconnected
+	writeTimeout = 2 * time.Second
+
+	sessionTimeoutSec = 60
+)
+
+// Codec is the video codec advertised in the SDP
+type Codec int
+
+const (
+	// CodecH264 advertises H.264 (matches the generated payload)
+	CodecH264 Codec = iota
+	// CodecH265 advertises H.265: clients negotiate a codec they cannot
+	// depayload/decode with an H.264 pipeline (codec change fault)
+	CodecH265
+)
+
+// String returns the RTP encoding name of the codec
+func (c Codec) String() string {
+	switch c {
+	case CodecH265:
+		return "H265"
+	default:
+		return "H264"
+	}
+}
+
+// Config configures the test server
+type Config struct {
+	// Width and Height of the generated video (multiples of 16, default 320x240)
+	Width  int
+	Height int
+	// FPS is the generated frame rate (default 10)
+	FPS float64
+	// Path is the stream path (default "/stream")
+	Path string
+	// Username and Password enable Basic authentication (empty = no auth)
+	Username string
+	Password string
+	// Logger (nil = slog.Default())
+	Logger *slog.Logger
+}
+
+// Server is a local RTSP server with fault injection
+type Server struct {
+	cfg      Config
+	logger   *slog.Logger
+	listener net.Listener
+	sps, pps []byte
+
+	mu       sync.Mutex
+	sessions map[*session]struct{}
+	username string
+	password string
+	codec    Codec
+
+	stalled     atomic.Bool
+	framesSent  atomic.Uint64
+	connections atomic.Uint64
+	nextSession atomic.Uint64
+
+	closed chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewServer starts a server listening on a random localhost port
+//
+// Returns an error if the dimensions are not multiples of 16 or the
+// listener cannot be created. Close must be called to release it.
+func NewServer(cfg Config) (*Server, error) {
+	if cfg.Width == 0 {
+		cfg.Width = defaultWidth
+	}
+	if cfg.Height == 0 {
+		cfg.Height = defaultHeight
+	}
+	if cfg.FPS == 0 {
+		cfg.FPS = defaultFPS
+	}
+	if cfg.Path == "" {
+		cfg.Path = defaultPath
+	}
+	if cfg.Width%16 != 0 || cfg.Height%16 != 0 {
+		return nil, fmt.Errorf("rtsptest: dimensions %dx%d must be multiples of 16", cfg.Width, cfg.Height)
+	}
+	if cfg.FPS < 0 {
+		return nil, fmt.Errorf("rtsptest: invalid FPS %.2f", cfg.FPS)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("rtsptest: listen: %w", err)
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		logger:   logger,
+		listener: listener,
+		sps:      encodeSPS(cfg.Width, cfg.Height),
+		pps:      encodePPS(),
+		sessions: make(map[*session]struct{}),
+		username: cfg.Username,
+		password: cfg.Password,
+		closed:   make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+
+	return s, nil
+}
+
+// URL returns the stream URL without credentials
+func (s *Server) URL() string {
+	return "rtsp://" + s.listener.Addr().String() + s.cfg.Path
+}
+
+// URLWithCredentials returns the stream URL with userinfo
+func (s *Server) URLWithCredentials(username, password string) string {
+	u := url.URL{
+		Scheme: "rtsp",
+		User:   url.UserPassword(username, password),
+		Host:   s.listener.Addr().String(),
+		Path:   s.cfg.Path,
+	}
+	return u.String()
+}
+
+// Close stops the server and disconnects all clients
+func (s *Server) Close() error {
+	select {
+	case <-s.closed:
+		return nil
+	default:
+	}
+	close(s.closed)
+
+	err := s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+	return err
+}
+
+// DropConnections closes every client connection (simulates a network cut)
+//
+// Returns the number of connections closed. New connections are accepted
+// normally afterwards.
+func (s *Server) DropConnections() int {
+	s.mu.Lock()
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+
+	for _, sess := range sessions {
+		sess.close()
+	}
+	if len(sessions) > 0 {
+		s.logger.Debug("rtsptest: connections dropped", "count", len(sessions))
+	}
+	return len(sessions)
+}
+
+// SetStalled stops (true) or resumes (false) packet delivery
+//
+// Connections and RTSP sessions stay open while stalled, like a camera whose
+// encoder hangs.
+func (s *Server) SetStalled(stalled bool) {
+	s.stalled.Store(stalled)
+}
+
+// SetCredentials changes the required Basic credentials (empty = no auth)
+//
+// Applies to subsequent requests; playing sessions are not interrupted.
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+}
+
+// SetCodec changes the codec advertised by subsequent DESCRIBE responses
+func (s *Server) SetCodec(codec Codec) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codec = codec
+}
+
+// FramesSent returns the number of frames sent to all clients
+func (s *Server) FramesSent() uint64 {
+	return s.framesSent.Load()
+}
+
+// Connections returns the number of connections accepted since start
+func (s *Server) Connections() uint64 {
+	return s.connections.Load()
+}
+
+// ActiveSessions returns the number of open client connections
+func (s *Server) ActiveSessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// acceptLoop accepts connections until the listener is closed
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			select {
+			case <-s.closed:
+			default:
+				s.logger.Warn("rtsptest: accept failed", "error", err)
+			}
+			return
+		}
+
+		s.connections.Add(1)
+		sess := &session{
+			server: s,
+			id:     strconv.FormatUint(1000+s.nextSession.Add(1), 10),
+			conn:   conn,
+			done:   make(chan struct{}),
+		}
+
+		s.mu.Lock()
+		s.sessions[sess] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			sess.serve()
+
+			s.mu.Lock()
+			delete(s.sessions, sess)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// checkAuth reports whether the Authorization header matches the credentials
+func (s *Server) checkAuth(header string) bool {
+	s.mu.Lock()
+	username, password := s.username, s.password
+	s.mu.Unlock()
+
+	if username == "" && password == "" {
+		return true
+	}
+
+	encoded, ok := strings.CutPrefix(header, "Basic ")
+	if !ok {
+		return false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return false
+	}
+	return string(decoded) == username+":"+password
+}
+
+// sdp returns the session description for DESCRIBE
+func (s *Server) sdp() string {
+	s.mu.Lock()
+	codec := s.codec
+	s.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString("v=0\r\n")
+	b.WriteString("o=- 0 0 IN IP4 127.0.0.1\r\n")
+	b.WriteString("s=rtsptest\r\n")
+	b.WriteString("c=IN IP4 0.0.0.0\r\n")
+	b.WriteString("t=0 0\r\n")
+	fmt.Fprintf(&b, "m=video 0 RTP/AVP %d\r\n", rtpPayloadType)
+	fmt.Fprintf(&b, "a=rtpmap:%d %s/%d\r\n", rtpPayloadType, codec, rtpClockRate)
+	if codec == CodecH264 {
+		fmt.Fprintf(&b, "a=fmtp:%d packetization-mode=1;profile-level-id=%02x00%02x;sprop-parameter-sets=%s,%s\r\n",
+			rtpPayloadType, profileBaseline, levelIDC,
+			base64.StdEncoding.EncodeToString(s.sps),
+			base64.StdEncoding.EncodeToString(s.pps),
+		)
+	}
+	fmt.Fprintf(&b, "a=framerate:%g\r\n", s.cfg.FPS)
+	b.WriteString("a=control:trackID=0\r\n")
+	return b.String()
+}
+
+// session is a single client connection
+type session struct {
+	server *Server
+	id     string
+	conn   net.Conn
+
+	writeMu   sync.Mutex
+	playing   bool
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// close terminates the connection (idempotent)
+func (c *session) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// serve handles RTSP requests until the connection closes
+func (c *session) serve() {
+	defer c.close()
+
+	br := bufio.NewReader(c.conn)
+	tp := textproto.NewReader(br)
+
+	for {
+		// Interleaved data from the client (RTCP receiver reports): skip
+		if b, err := br.Peek(1); err == nil && b[0] == '$' {
+			var header [4]byte
+			if _, err := io.ReadFull(br, header[:]); err != nil {
+				return
+			}
+			size := int(header[2])<<8 | int(header[3])
+			if _, err := br.Discard(size); err != nil {
+				return
+			}
+			continue
+		}
+
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		if line == "" {
+			continue
+		}
+		headers, err := tp.ReadMIMEHeader()
+		if err != nil && !errors.Is(err, io.EOF) {
+			return
+		}
+		if n, _ := strconv.Atoi(headers.Get("Content-Length")); n > 0 {
+			if _, err := br.Discard(n); err != nil {
+				return
+			}
+		}
+
+		method, uri, _ := strings.Cut(line, " ")
+		uri, _, _ = strings.Cut(uri, " ")
+
+		if !c.handle(method, uri, headers) {
+			return
+		}
+	}
+}
+
+// handle answers one request; returns false when the connection must close
+func (c *session) handle(method, uri string, headers textproto.MIMEHeader) bool {
+	s := c.server
+	cseq := headers.Get("CSeq")
+
+	s.logger.Debug("rtsptest: request", "method", method, "uri", uri, "session", c.id)
+
+	if method != "OPTIONS" {
+		if !s.checkAuth(headers.Get("Authorization")) {
+			return c.respond(cseq, 401, "Unauthorized", map[string]string{
+				"WWW-Authenticate": `Basic realm="rtsptest"`,
+			}, "")
+		}
+		if !c.validPath(uri) {
+			return c.respond(cseq, 404, "Not Found", nil, "")
+		}
+	}
+
+	switch method {
+	case "OPTIONS":
+		return c.respond(cseq, 200, "OK", map[string]string{
+			"Public": "OPTIONS, DESCRIBE, SETUP, PLAY, TEARDOWN, GET_PARAMETER, SET_PARAMETER",
+		}, "")
+
+	case "DESCRIBE":
+		return c.respond(cseq, 200, "OK", map[string]string{
+			"Content-Type": "application/sdp",
+			"Content-Base": s.URL() + "/",
+		}, s.sdp())
+
+	case "SETUP":
+		// TCP interleaved only (RTSPStream sets protocols=TCP)
+		if !strings.Contains(headers.Get("Transport"), "TCP") {
+			return c.respond(cseq, 461, "Unsupported Transport", nil, "")
+		}
+		return c.respond(cseq, 200, "OK", map[string]string{
+			"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1",
+			"Session":   c.id + ";timeout=" + strconv.Itoa(sessionTimeoutSec),
+		}, "")
+
+	case "PLAY":
+		ok := c.respond(cseq, 200, "OK", map[string]string{
+			"Session": c.id,
+			"Range":   "npt=0.000-",
+		}, "")
+		if ok && !c.playing {
+			c.playing = true
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				c.stream()
+			}()
+		}
+		return ok
+
+	case "GET_PARAMETER", "SET_PARAMETER":
+		return c.respond(cseq, 200, "OK", map[string]string{"Session": c.id}, "")
+
+	case "TEARDOWN":
+		c.respond(cseq, 200, "OK", map[string]string{"Session": c.id}, "")
+		return false
+
+	default:
+		return c.respond(cseq, 405, "Method Not Allowed", nil, "")
+	}
+}
+
+// validPath reports whether the request URI targets the stream (or its track)
+func (c *session) validPath(uri string) bool {
+	if uri == "*" {
+		return true
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	return path == c.server.cfg.Path || strings.HasPrefix(path, c.server.cfg.Path+"/")
+}
+
+// respond writes an RTSP response; returns false if the write failed
+func (c *session) respond(cseq string, code int, reason string, headers map[string]string, body string) bool {
+	var b strings.Builder
+	fmt.Fprintf(&b, "RTSP/1.0 %d %s\r\n", code, reason)
+	fmt.Fprintf(&b, "CSeq: %s\r\n", cseq)
+	for k, v := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n", len(body))
+	b.WriteString(body)
+
+	return c.write([]byte(b.String())) == nil
+}
+
+// write sends data with a deadline (serialized with the stream goroutine)
+func (c *session) write(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.conn.Write(data)
+	return err
+}
+
+// stream sends one access unit (SPS, PPS, IDR) per frame interval
+func (c *session) stream() {
+	s := c.server
+	interval := time.Duration(float64(time.Second) / s.cfg.FPS)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	p := &packetizer{ssrc: uint32(s.nextSession.Load())}
+	start := time.Now()
+	var frameIndex, idrPicID uint64
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-s.closed:
+			return
+		case now := <-ticker.C:
+			if s.stalled.Load() {
+				continue
+			}
+
+			idr := encodeIDR(s.cfg.Width, s.cfg.Height, frameIndex, idrPicID)
+			timestamp := uint32(now.Sub(start).Seconds() * rtpClockRate)
+			for _, pkt := range p.packetize([][]byte{s.sps, s.pps, idr}, timestamp) {
+				if err := c.write(interleave(0, pkt)); err != nil {
+					c.close()
+					return
+				}
+			}
+
+			frameIndex++
+			idrPicID = (idrPicID + 1) % 2
+			s.framesSent.Add(1)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package rtsptest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bitReader reads an RBSP (emulation prevention already removed)
+type bitReader struct {
+	buf []byte
+	pos int // bit position
+}
+
+func (r *bitReader) bit() uint64 {
+	b := uint64(r.buf[r.pos/8]>>(7-r.pos%8)) & 1
+	r.pos++
+	return b
+}
+
+func (r *bitReader) bits(n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		v = v<<1 | r.bit()
+	}
+	return v
+}
+
+func (r *bitReader) ue() uint64 {
+	zeros := 0
+	for r.bit() == 0 {
+		zeros++
+	}
+	return (1<<zeros - 1) + r.bits(zeros)
+}
+
+func (r *bitReader) align() {
+	for r.pos%8 != 0 {
+		r.pos++
+	}
+}
+
+// unescape removes emulation prevention bytes from a NAL payload
+func unescape(nal []byte) []byte {
+	out := make([]byte, 0, len(nal))
+	zeros := 0
+	for _, b := range nal {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+			continue
+		}
+		out = append(out, b)
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+	}
+	return out
+}
+
+// TestBitWriter_ExpGolomb tests ue(v)/se(v) codes against the spec table
+func TestBitWriter_ExpGolomb(t *testing.T) {
+	tests := []struct {
+		write func(*bitWriter)
+		want  string
+	}{
+		{func(w *bitWriter) { w.writeUE(0) }, "1"},
+		{func(w *bitWriter) { w.writeUE(1) }, "010"},
+		{func(w *bitWriter) { w.writeUE(2) }, "011"},
+		{func(w *bitWriter) { w.writeUE(3) }, "00100"},
+		{func(w *bitWriter) { w.writeUE(25) }, "000011010"},
+		{func(w *bitWriter) { w.writeSE(1) }, "010"},
+		{func(w *bitWriter) { w.writeSE(-1) }, "011"},
+		{func(w *bitWriter) { w.writeSE(2) }, "00100"},
+	}
+
+	for i, tt := range tests {
+		var w bitWriter
+		tt.write(&w)
+		r := bitReader{buf: w.buf}
+		var got strings.Builder
+		for j := 0; j < len(tt.want); j++ {
+			fmt.Fprint(&got, r.bit())
+		}
+		if got.String() != tt.want {
+			t.Errorf("case %d: bits = %s, want %s", i, got.String(), tt.want)
+		}
+	}
+}
+
+// TestNALUnit_EmulationPrevention tests that start-code patterns are escaped
+func TestNALUnit_EmulationPrevention(t *testing.T) {
+	got := nalUnit(3, nalTypeIDR, []byte{0, 0, 1, 0, 0, 0, 0, 0, 4})
+	want := []byte{0x65, 0, 0, 3, 1, 0, 0, 3, 0, 0, 3, 0, 4}
+	if !bytes.Equal(got, want) {
+		t.Errorf("nalUnit() = % x, want % x", got, want)
+	}
+}
+
+// TestEncodeIDR_Structure parses the generated SPS and IDR slice back
+func TestEncodeIDR_Structure(t *testing.T) {
+	const width, height = 64, 48
+
+	sps := bitReader{buf: unescape(encodeSPS(width, height))[1:]}
+	if profile := sps.bits(8); profile != profileBaseline {
+		t.Errorf("profile_idc = %d", profile)
+	}
+	sps.bits(16) // constraints + level
+	sps.ue()     // sps id
+	sps.ue()     // log2_max_frame_num_minus4
+	if poc := sps.ue(); poc != 2 {
+		t.Errorf("pic_order_cnt_type = %d, want 2", poc)
+	}
+	sps.ue()  // max_num_ref_frames
+	sps.bit() // gaps
+	if w := (sps.ue() + 1) * 16; w != width {
+		t.Errorf("width = %d, want %d", w, width)
+	}
+	if h := (sps.ue() + 1) * 16; h != height {
+		t.Errorf("height = %d, want %d", h, height)
+	}
+
+	nal := unescape(encodeIDR(width, height, 3, 1))
+	if nal[0]&0x1F != nalTypeIDR {
+		t.Fatalf("nal_unit_type = %d, want %d", nal[0]&0x1F, nalTypeIDR)
+	}
+	r := bitReader{buf: nal[1:]}
+	r.ue() // first_mb_in_slice
+	if st := r.ue(); st != sliceTypeI {
+		t.Errorf("slice_type = %d, want %d", st, sliceTypeI)
+	}
+	r.ue()    // pps id
+	r.bits(4) // frame_num
+	if id := r.ue(); id != 1 {
+		t.Errorf("idr_pic_id = %d, want 1", id)
+	}
+	r.bits(2) // dec_ref_pic_marking
+	r.ue()    // slice_qp_delta (se 0)
+
+	mbs := (width / 16) * (height / 16)
+	for i := 0; i < mbs; i++ {
+		if mbType := r.ue(); mbType != mbTypeIPCM {
+			t.Fatalf("mb %d: mb_type = %d, want I_PCM", i, mbType)
+		}
+		r.align()
+		for j := 0; j < 384; j++ {
+			if sample := r.bits(8); sample == 0 {
+				t.Fatalf("mb %d: zero PCM sample", i)
+			}
+		}
+	}
+	if stop := r.bit(); stop != 1 {
+		t.Error("missing rbsp_stop_one_bit")
+	}
+	r.align()
+	if r.pos/8 != len(r.buf) {
+		t.Errorf("%d trailing bytes after slice", len(r.buf)-r.pos/8)
+	}
+}
+
+// testClient is a minimal RTSP client over TCP
+type testClient struct {
+	t    *testing.T
+	conn net.Conn
+	br   *bufio.Reader
+	cseq int
+}
+
+func dial(t *testing.T, srv *Server) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", srv.listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &testClient{t: t, conn: conn, br: bufio.NewReader(conn)}
+}
+
+// do sends a request and returns status code, headers and body
+func (c *testClient) do(method, uri string, headers map[string]string) (int, textproto.MIMEHeader, string) {
+	c.t.Helper()
+	c.cseq++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s RTSP/1.0\r\nCSeq: %d\r\n", method, uri, c.cseq)
+	for k, v := range headers {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	b.WriteString("\r\n")
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		c.t.Fatalf("%s: write: %v", method, err)
+	}
+
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	tp := textproto.NewReader(c.br)
+	line, err := tp.ReadLine()
+	if err != nil {
+		c.t.Fatalf("%s: read status: %v", method, err)
+	}
+	fields := strings.SplitN(line, " ", 3)
+	code, _ := strconv.Atoi(fields[1])
+	hdr, err := tp.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("%s: read headers: %v", method, err)
+	}
+	if got := hdr.Get("CSeq"); got != strconv.Itoa(c.cseq) {
+		c.t.Errorf("%s: CSeq = %q, want %d", method, got, c.cseq)
+	}
+	body := make([]byte, 0)
+	if n, _ := strconv.Atoi(hdr.Get("Content-Length")); n > 0 {
+		body = make([]byte, n)
+		if _, err := io.ReadFull(c.br, body); err != nil {
+			c.t.Fatalf("%s: read body: %v", method, err)
+		}
+	}
+	return code, hdr, string(body)
+}
+
+// play performs DESCRIBE/SETUP/PLAY with TCP interleaved transport
+func (c *testClient) play(srv *Server) {
+	c.t.Helper()
+	if code, _, _ := c.do("DESCRIBE", srv.URL(), nil); code != 200 {
+		c.t.Fatalf("DESCRIBE = %d", code)
+	}
+	code, hdr, _ := c.do("SETUP", srv.URL()+"/trackID=0", map[string]string{
+		"Transport": "RTP/AVP/TCP;unicast;interleaved=0-1",
+	})
+	if code != 200 {
+		c.t.Fatalf("SETUP = %d", code)
+	}
+	session, _, _ := strings.Cut(hdr.Get("Session"), ";")
+	if code, _, _ := c.do("PLAY", srv.URL(), map[string]string{"Session": session}); code != 200 {
+		c.t.Fatalf("PLAY = %d", code)
+	}
+}
+
+// readAccessUnit reassembles the NAL units of one frame (until the marker bit)
+func (c *testClient) readAccessUnit(timeout time.Duration) ([][]byte, error) {
+	c.conn.SetReadDeadline(time.Now().Add(timeout))
+
+	var nals [][]byte
+	var fu []byte
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(c.br, header[:]); err != nil {
+			return nil, err
+		}
+		if header[0] != '$' {
+			return nil, fmt.Errorf("not interleaved data: %q", header[0])
+		}
+		pkt := make([]byte, binary.BigEndian.Uint16(header[2:]))
+		if _, err := io.ReadFull(c.br, pkt); err != nil {
+			return nil, err
+		}
+
+		payload := pkt[12:]
+		switch payload[0] & 0x1F {
+		case nalTypeFUA:
+			if payload[1]&0x80 != 0 {
+				fu = []byte{payload[0]&0xE0 | payload[1]&0x1F}
+			}
+			fu = append(fu, payload[2:]...)
+			if payload[1]&0x40 != 0 {
+				nals = append(nals, fu)
+			}
+		default:
+			nals = append(nals, payload)
+		}
+
+		if pkt[1]&0x80 != 0 {
+			return nals, nil
+		}
+	}
+}
+
+func newTestServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+	if cfg.Width == 0 {
+		cfg.Width, cfg.Height = 64, 48
+	}
+	if cfg.FPS == 0 {
+		cfg.FPS = 50
+	}
+	srv, err := NewServer(cfg)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+// TestServer_Play tests the RTSP handshake and the RTP payload
+func TestServer_Play(t *testing.T) {
+	srv := newTestServer(t, Config{})
+	c := dial(t, srv)
+
+	if code, hdr, _ := c.do("OPTIONS", srv.URL(), nil); code != 200 || !strings.Contains(hdr.Get("Public"), "DESCRIBE") {
+		t.Fatalf("OPTIONS = %d %v", code, hdr)
+	}
+
+	code, _, sdp := c.do("DESCRIBE", srv.URL(), nil)
+	if code != 200 {
+		t.Fatalf("DESCRIBE = %d", code)
+	}
+	wantSprop := base64.StdEncoding.EncodeToString(srv.sps)
+	if !strings.Contains(sdp, "H264/90000") || !strings.Contains(sdp, wantSprop) {
+		t.Errorf("SDP missing H264 rtpmap or sprop-parameter-sets:\n%s", sdp)
+	}
+
+	if code, _, _ := c.do("SETUP", srv.URL()+"/trackID=0", map[string]string{
+		"Transport": "RTP/AVP;unicast;client_port=5000-5001",
+	}); code != 461 {
+		t.Errorf("SETUP over UDP = %d, want 461", code)
+	}
+	if code, _, _ := c.do("DESCRIBE", "rtsp://127.0.0.1/other", nil); code != 404 {
+		t.Errorf("DESCRIBE wrong path = %d, want 404", code)
+	}
+
+	c.play(srv)
+
+	nals, err := c.readAccessUnit(2 * time.Second)
+	if err != nil {
+		t.Fatalf("readAccessUnit() error = %v", err)
+	}
+	if len(nals) != 3 {
+		t.Fatalf("access unit has %d NAL units, want 3 (SPS, PPS, IDR)", len(nals))
+	}
+	if !bytes.Equal(nals[0], srv.sps) || !bytes.Equal(nals[1], srv.pps) {
+		t.Error("SPS/PPS in stream differ from SDP")
+	}
+	if !bytes.Equal(nals[2], encodeIDR(64, 48, 0, 0)) {
+		t.Error("reassembled IDR differs from the generated frame")
+	}
+}
+
+// TestServer_Auth tests Basic authentication and credential changes
+func TestServer_Auth(t *testing.T) {
+	srv := newTestServer(t, Config{Username: "admin", Password: "s3cret"})
+	c := dial(t, srv)
+
+	code, hdr, _ := c.do("DESCRIBE", srv.URL(), nil)
+	if code != 401 || !strings.HasPrefix(hdr.Get("WWW-Authenticate"), "Basic") {
+		t.Errorf("DESCRIBE without credentials = %d %v, want 401 Basic", code, hdr)
+	}
+
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:s3cret"))
+	if code, _, _ := c.do("DESCRIBE", srv.URL(), map[string]string{"Authorization": auth}); code != 200 {
+		t.Errorf("DESCRIBE with credentials = %d, want 200", code)
+	}
+
+	srv.SetCredentials("admin", "rotated")
+	if code, _, _ := c.do("DESCRIBE", srv.URL(), map[string]string{"Authorization": auth}); code != 401 {
+		t.Errorf("DESCRIBE with old credentials = %d, want 401", code)
+	}
+}
+
+// TestServer_Faults tests codec change, stall and connection drop
+func TestServer_Faults(t *testing.T) {
+	srv := newTestServer(t, Config{})
+
+	srv.SetCodec(CodecH265)
+	c := dial(t, srv)
+	if _, _, sdp := c.do("DESCRIBE", srv.URL(), nil); !strings.Contains(sdp, "H265/90000") {
+		t.Errorf("SDP after SetCodec(H265):\n%s", sdp)
+	}
+	srv.SetCodec(CodecH264)
+
+	c.play(srv)
+	if _, err := c.readAccessUnit(2 * time.Second); err != nil {
+		t.Fatalf("readAccessUnit() error = %v", err)
+	}
+
+	// Stall: connection open, no packets
+	srv.SetStalled(true)
+	time.Sleep(50 * time.Millisecond) // let an in-flight frame finish
+	c.conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	c.br.Discard(c.br.Buffered())
+	if _, err := c.readAccessUnit(200 * time.Millisecond); err == nil {
+		t.Error("received frame while stalled")
+	}
+	if srv.ActiveSessions() != 1 {
+		t.Errorf("ActiveSessions() = %d while stalled, want 1", srv.ActiveSessions())
+	}
+
+	// Drop: client sees the connection closed, new connections are accepted
+	srv.SetStalled(false)
+	if n := srv.DropConnections(); n != 1 {
+		t.Errorf("DropConnections() = %d, want 1", n)
+	}
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	if _, err := io.Copy(io.Discard, c.br); err != nil {
+		t.Errorf("read after drop: %v, want EOF", err)
+	}
+
+	c2 := dial(t, srv)
+	c2.play(srv)
+	if _, err := c2.readAccessUnit(2 * time.Second); err != nil {
+		t.Fatalf("readAccessUnit() after drop error = %v", err)
+	}
+	if got := srv.Connections(); got != 2 {
+		t.Errorf("Connections() = %d, want 2", got)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
 )
 
 const (
@@ -29,19 +31,23 @@ const (
 //   sourceFrames: Channel of external frames (e.g., streamcapture.Frame)
 //   duration: Warmup duration
 //   converter: Function to convert T → warmup.Frame
+//   opts: Clock and logger (zero value = system clock, slog.Default())
 //
 // Returns warmup statistics or error if warmup fails.
 //
 // Example usage from rtsp.go:
 //   stats, err := warmup.WarmupWithAdapter(ctx, s.frames, duration, func(f Frame) warmup.Frame {
 //       return warmup.Frame{Seq: f.Seq, Timestamp: f.Timestamp}
-//   })
+//   }, warmup.Options{})
 func WarmupWithAdapter[T any](
 	ctx context.Context,
 	sourceFrames <-chan T,
 	duration time.Duration,
 	converter func(T) Frame,
+	opts Options,
 ) (*WarmupStats, error) {
+	clk := clock.OrSystem(opts.Clock)
+
 	// Create adapter channel
 	adaptedFrames := make(chan Frame, adapterBufferSize)
 
@@ -49,13 +55,14 @@ func WarmupWithAdapter[T any](
 	go func() {
 		defer close(adaptedFrames)
 
-		// Create timeout context for adapter goroutine
-		adapterCtx, cancel := context.WithTimeout(ctx, duration+adapterTimeout)
-		defer cancel()
+		// Adapter deadline (same clock as WarmupStream, plus safety margin)
+		deadline := clk.After(duration + adapterTimeout)
 
 		for {
 			select {
-			case <-adapterCtx.Done():
+			case <-ctx.Done():
+				return
+			case <-deadline:
 				return
 			case frame, ok := <-sourceFrames:
 				if !ok {
@@ -67,7 +74,9 @@ func WarmupWithAdapter[T any](
 				select {
 				case adaptedFrames <- warmupFrame:
 					// Sent successfully
-				case <-adapterCtx.Done():
+				case <-ctx.Done():
+					return
+				case <-deadline:
 					return
 				}
 			}
@@ -75,7 +84,7 @@ func WarmupWithAdapter[T any](
 	}()
 
 	// Delegate to warmup helper (includes fail-fast validation)
-	stats, err := WarmupStream(ctx, adaptedFrames, duration)
+	stats, err := WarmupStream(ctx, adaptedFrames, duration, opts)
 	if err != nil {
 		return nil, fmt.Errorf("warmup adapter failed: %w", err)
 	}
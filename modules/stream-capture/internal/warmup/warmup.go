@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log/slog"
 	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
 )
 
 // Frame is a minimal frame struct for internal use (avoids import cycle)
@@ -13,6 +15,22 @@ type Frame struct {
 	Timestamp time.Time
 }
 
+// Options carries injectable dependencies for warm-up
+//
+// The zero value uses the system clock and slog.Default().
+type Options struct {
+	Clock  clock.Clock  // Warm-up duration and elapsed time (tests use clock.Fake)
+	Logger *slog.Logger // Logger (e.g. tagged with camera ID)
+}
+
+// logger returns the configured logger, or slog.Default() if nil
+func (o Options) logger() *slog.Logger {
+	if o.Logger == nil {
+		return slog.Default()
+	}
+	return o.Logger
+}
+
 // WarmupStats contains statistics collected during warm-up phase
 type WarmupStats struct {
 	FramesReceived int           // Number of frames received during warm-up
@@ -46,28 +64,34 @@ func WarmupStream(
 	ctx context.Context,
 	frames <-chan Frame,
 	duration time.Duration,
+	opts Options,
 ) (*WarmupStats, error) {
-	slog.Info("warmup: starting stream warm-up",
+	log := opts.logger()
+	clk := clock.OrSystem(opts.Clock)
+
+	log.Info("warmup: starting stream warm-up",
 		"duration", duration,
 		"reason", "measure real FPS and stabilize pipeline",
 	)
 
-	startTime := time.Now()
+	startTime := clk.Now()
 
 	// Track frame timestamps
 	frameTimes := make([]time.Time, 0, 100) // Pre-allocate for ~30 FPS @ 3s
 
-	// Create timeout context
-	warmupCtx, cancel := context.WithTimeout(ctx, duration)
-	defer cancel()
+	// Warm-up deadline (clock-driven so tests can advance time)
+	deadline := clk.After(duration)
 
 	// Consume frames during warm-up
 	for {
 		select {
-		case <-warmupCtx.Done():
+		case <-deadline:
 			// Warm-up duration elapsed - analyze statistics
 			goto analyze
 
+		case <-ctx.Done():
+			return nil, fmt.Errorf("warmup: cancelled: %w", ctx.Err())
+
 		case frame, ok := <-frames:
 			if !ok {
 				return nil, fmt.Errorf("warmup: stream closed during warm-up")
@@ -76,7 +100,7 @@ func WarmupStream(
 			// Record frame timestamp
 			frameTimes = append(frameTimes, frame.Timestamp)
 
-			slog.Debug("warmup: frame received",
+			log.Debug("warmup: frame received",
 				"seq", frame.Seq,
 				"frames_collected", len(frameTimes),
 			)
@@ -84,7 +108,7 @@ func WarmupStream(
 	}
 
 analyze:
-	elapsed := time.Since(startTime)
+	elapsed := clk.Since(startTime)
 
 	// Validate minimum frames received
 	if len(frameTimes) < 2 {
@@ -97,7 +121,7 @@ analyze:
 	// Calculate FPS statistics (local function, no external dependencies)
 	stats := CalculateFPSStats(frameTimes, elapsed)
 
-	slog.Info("warmup: stream warm-up complete",
+	log.Info("warmup: stream warm-up complete",
 		"frames", stats.FramesReceived,
 		"duration", stats.Duration,
 		"fps_mean", fmt.Sprintf("%.2f", stats.FPSMean),
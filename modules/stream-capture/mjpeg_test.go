@@ -0,0 +1,546 @@
+package streamcapture
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/mjpeg"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsp"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
+)
+
+// newTestJPEG returns a solid-color JPEG of the given size
+func newTestJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i+0] = 0
+		img.Pix[i+1] = 128
+		img.Pix[i+2] = 255
+		img.Pix[i+3] = 255
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatalf("failed to encode test JPEG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// newMJPEGServer serves an endless MJPEG stream at ~cameraFPS
+func newMJPEGServer(t *testing.T, frame []byte, cameraFPS int) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=orion")
+		ticker := time.NewTicker(time.Second / time.Duration(cameraFPS))
+		defer ticker.Stop()
+
+		for {
+			fmt.Fprintf(w, "--orion\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(frame))
+			w.Write(frame)
+			fmt.Fprint(w, "\r\n")
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-ticker.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+}
+
+// TestMJPEGConfig_Validate verifies fail-fast validation for HTTP sources
+func TestMJPEGConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     MJPEGConfig
+		wantErr bool
+	}{
+		{"valid stream", MJPEGConfig{URL: "http://cam/video.mjpg", TargetFPS: 2}, false},
+		{"valid snapshot https", MJPEGConfig{URL: "https://cam/snap.jpg", Mode: MJPEGModeSnapshot, TargetFPS: 1}, false},
+		{"empty URL", MJPEGConfig{TargetFPS: 2}, true},
+		{"rtsp scheme", MJPEGConfig{URL: "rtsp://cam/stream", TargetFPS: 2}, true},
+		{"FPS too low", MJPEGConfig{URL: "http://cam/video.mjpg", TargetFPS: 0.05}, true},
+		{"FPS too high", MJPEGConfig{URL: "http://cam/video.mjpg", TargetFPS: 31}, true},
+		{"invalid mode", MJPEGConfig{URL: "http://cam/video.mjpg", TargetFPS: 2, Mode: MJPEGMode(9)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestMJPEGStream_StreamMode verifies end-to-end capture from a multipart/x-mixed-replace server
+func TestMJPEGStream_StreamMode(t *testing.T) {
+	server := newMJPEGServer(t, newTestJPEG(t, 64, 48), 20)
+	defer server.Close()
+
+	stream, err := NewMJPEGStream(MJPEGConfig{
+		URL:          server.URL,
+		Mode:         MJPEGModeStream,
+		TargetFPS:    10,
+		SourceStream: "test-mjpeg",
+	})
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	var lastSeq uint64
+	for i := 0; i < 3; i++ {
+		select {
+		case frame := <-frames:
+			if frame.Width != 64 || frame.Height != 48 {
+				t.Fatalf("expected 64x48 frame, got %dx%d", frame.Width, frame.Height)
+			}
+			if len(frame.Data) != 64*48*3 {
+				t.Fatalf("expected %d RGB bytes, got %d", 64*48*3, len(frame.Data))
+			}
+			if frame.SourceStream != "test-mjpeg" {
+				t.Errorf("expected SourceStream test-mjpeg, got %q", frame.SourceStream)
+			}
+			if frame.Seq <= lastSeq {
+				t.Errorf("sequence not monotonic: %d after %d", frame.Seq, lastSeq)
+			}
+			lastSeq = frame.Seq
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for frame %d", i)
+		}
+	}
+
+	stats := stream.Stats()
+	if !stats.IsConnected {
+		t.Error("expected IsConnected=true while receiving frames")
+	}
+	if stats.Resolution != "64x48" {
+		t.Errorf("expected Resolution 64x48, got %q", stats.Resolution)
+	}
+	if stats.BytesRead == 0 {
+		t.Error("expected BytesRead > 0")
+	}
+
+	// Hot-reload FPS (no pipeline, immediate)
+	if err := stream.SetTargetFPS(5); err != nil {
+		t.Errorf("SetTargetFPS failed: %v", err)
+	}
+	if got := stream.Stats().FPSTarget; got != 5 {
+		t.Errorf("expected FPSTarget 5, got %.2f", got)
+	}
+
+	if err := stream.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	// Channel must be closed after Stop (drain buffered frames first)
+	for range frames {
+	}
+
+	// Second Stop is a no-op
+	if err := stream.Stop(); err != nil {
+		t.Errorf("second Stop failed: %v", err)
+	}
+}
+
+// spanCollector is a trace.Exporter that keeps spans in memory
+type spanCollector struct {
+	mu    sync.Mutex
+	spans []trace.Span
+}
+
+func (c *spanCollector) ExportSpans(ctx context.Context, spans []trace.Span) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = append(c.spans, spans...)
+	return nil
+}
+
+func (c *spanCollector) Shutdown(ctx context.Context) error { return nil }
+
+// TestMJPEGStream_TraceContext verifies per-frame trace context and capture spans
+func TestMJPEGStream_TraceContext(t *testing.T) {
+	server := newMJPEGServer(t, newTestJPEG(t, 64, 48), 20)
+	defer server.Close()
+
+	collector := &spanCollector{}
+	tracer := trace.NewTracer(collector, trace.TracerConfig{})
+
+	stream, err := NewMJPEGStream(MJPEGConfig{
+		URL:          server.URL,
+		Mode:         MJPEGModeStream,
+		TargetFPS:    10,
+		SourceStream: "test-mjpeg",
+	}, WithTracer(tracer), WithClock(offsetClock{-24 * time.Hour}))
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	var got []Frame
+	for len(got) < 2 {
+		select {
+		case frame := <-frames:
+			got = append(got, frame)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for frame %d", len(got))
+		}
+	}
+	stream.Stop()
+	tracer.Shutdown(context.Background())
+
+	if got[0].TraceID == got[1].TraceID {
+		t.Error("frames share a trace ID, want one trace per frame")
+	}
+	for _, frame := range got {
+		sc, err := trace.ParseTraceparent(frame.TraceParent)
+		if err != nil {
+			t.Fatalf("frame %d TraceParent: %v", frame.Seq, err)
+		}
+		if sc.TraceID.String() != frame.TraceID {
+			t.Errorf("TraceParent %q does not match TraceID %q", frame.TraceParent, frame.TraceID)
+		}
+
+		var span *trace.Span
+		collector.mu.Lock()
+		for i := range collector.spans {
+			if collector.spans[i].Context == sc {
+				span = &collector.spans[i]
+			}
+		}
+		collector.mu.Unlock()
+		if span == nil {
+			t.Fatalf("no capture span for frame %d", frame.Seq)
+		}
+		if span.Name != "stream-capture.decode" || span.Parent.IsValid() || span.End.Before(span.Start) {
+			t.Errorf("capture span = %+v, want root decode span", span)
+		}
+		// Span timestamps follow the injected clock, like Frame.Timestamp
+		if dayAgo := time.Now().Add(-23 * time.Hour); span.Start.After(dayAgo) || span.End.After(dayAgo) {
+			t.Errorf("capture span %v..%v not on the injected clock (frame at %v)", span.Start, span.End, frame.Timestamp)
+		}
+	}
+}
+
+// offsetClock is the system clock shifted by a fixed offset
+type offsetClock struct {
+	offset time.Duration
+}
+
+func (c offsetClock) Now() time.Time                         { return time.Now().Add(c.offset) }
+func (c offsetClock) Since(t time.Time) time.Duration        { return c.Now().Sub(t) }
+func (c offsetClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (c offsetClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+// TestMJPEGStream_SnapshotMode verifies polling of a single-JPEG endpoint
+func TestMJPEGStream_SnapshotMode(t *testing.T) {
+	frame := newTestJPEG(t, 32, 32)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(frame)
+	}))
+	defer server.Close()
+
+	stream, err := NewMJPEGStream(MJPEGConfig{
+		URL:       server.URL + "/snapshot.jpg",
+		Mode:      MJPEGModeSnapshot,
+		TargetFPS: 20,
+	})
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		select {
+		case f := <-frames:
+			if f.Width != 32 || f.Height != 32 {
+				t.Fatalf("expected 32x32 frame, got %dx%d", f.Width, f.Height)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timeout waiting for snapshot %d", i)
+		}
+	}
+
+	stream.Stop()
+	if n := requests.Load(); n < 3 {
+		t.Errorf("expected at least 3 snapshot requests, got %d", n)
+	}
+}
+
+// TestMJPEGStream_AuthErrorCounted verifies 401 responses are classified as auth errors
+// and trigger the shared reconnection machinery
+func TestMJPEGStream_AuthErrorCounted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	stream, err := NewMJPEGStream(MJPEGConfig{
+		URL:                   server.URL,
+		TargetFPS:             2,
+		MaxReconnectAttempts:  2,
+		ReconnectInitialDelay: 10 * time.Millisecond,
+		ReconnectMaxDelay:     20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	if _, err := stream.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if stream.Stats().ErrorsAuth >= 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stats := stream.Stats()
+	if stats.ErrorsAuth < 3 {
+		t.Errorf("expected >= 3 auth errors (initial + 2 retries), got %d", stats.ErrorsAuth)
+	}
+	if stats.Reconnects == 0 {
+		t.Error("expected reconnect attempts to be counted")
+	}
+	if stats.IsConnected {
+		t.Error("expected IsConnected=false for rejected credentials")
+	}
+
+	// Error details are kept alongside the counters
+	if stats.LastError == nil {
+		t.Fatal("expected LastError to be set")
+	}
+	if stats.LastError.Category != ErrCategoryAuth || !strings.Contains(stats.LastError.Message, "401") {
+		t.Errorf("unexpected LastError: %+v", *stats.LastError)
+	}
+	history := stream.ErrorHistory()
+	if len(history) < 3 {
+		t.Fatalf("expected >= 3 errors in history, got %d", len(history))
+	}
+	if history[0].ReconnectAttempt != 0 || history[1].ReconnectAttempt != 1 {
+		t.Errorf("expected reconnect attempts 0,1 for first errors, got %d,%d",
+			history[0].ReconnectAttempt, history[1].ReconnectAttempt)
+	}
+}
+
+// TestMJPEGStream_PauseResume verifies privacy pause keeps the connection and channel alive
+func TestMJPEGStream_PauseResume(t *testing.T) {
+	var connections atomic.Int32
+	frame := newTestJPEG(t, 32, 32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connections.Add(1)
+		w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary=orion")
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			fmt.Fprintf(w, "--orion\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", len(frame))
+			w.Write(frame)
+			fmt.Fprint(w, "\r\n")
+			w.(http.Flusher).Flush()
+			select {
+			case <-ticker.C:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	stream, err := NewMJPEGStream(MJPEGConfig{URL: server.URL, TargetFPS: 30})
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	// Pause before Start is an error (nothing to pause)
+	if err := stream.Pause(); err == nil {
+		t.Error("expected error pausing a stream that is not running")
+	}
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case <-frames:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for first frame")
+	}
+
+	if err := stream.Pause(); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+	if err := stream.Pause(); err != nil {
+		t.Errorf("second Pause should be a no-op, got %v", err)
+	}
+
+	// No frame may be delivered while paused (buffered frames drained)
+	select {
+	case f := <-frames:
+		t.Fatalf("received frame seq=%d while paused", f.Seq)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	stats := stream.Stats()
+	if !stats.IsPaused {
+		t.Error("expected IsPaused=true")
+	}
+	if stats.PausedDuration < 250*time.Millisecond {
+		t.Errorf("expected PausedDuration >= 250ms, got %v", stats.PausedDuration)
+	}
+
+	if err := stream.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+
+	select {
+	case <-frames:
+	case <-time.After(time.Second):
+		t.Fatal("no frame after Resume")
+	}
+
+	if stream.Stats().IsPaused {
+		t.Error("expected IsPaused=false after Resume")
+	}
+	if n := connections.Load(); n != 1 {
+		t.Errorf("expected connection kept alive across pause (1 connection), got %d", n)
+	}
+}
+
+// TestMJPEGStream_ConcurrentStopStart verifies Stop owns the run it tears down
+//
+// A concurrent Stop must not close the channel of a later Start, and a Start
+// racing Stop must not have its channel closed under a live capture goroutine.
+func TestMJPEGStream_ConcurrentStopStart(t *testing.T) {
+	server := newMJPEGServer(t, newTestJPEG(t, 32, 32), 50)
+	defer server.Close()
+
+	stream, err := NewMJPEGStream(MJPEGConfig{URL: server.URL, TargetFPS: 30})
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	for i := 0; i < 20; i++ {
+		first, err := stream.Start(context.Background())
+		if err != nil {
+			t.Fatalf("iteration %d: Start failed: %v", i, err)
+		}
+		channels := []<-chan Frame{first}
+
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+		for j := 0; j < 2; j++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				stream.Stop()
+			}()
+			go func() {
+				defer wg.Done()
+				// Fails while stopping or already started; both are fine
+				if frames, err := stream.Start(context.Background()); err == nil {
+					mu.Lock()
+					channels = append(channels, frames)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if err := stream.Stop(); err != nil {
+			t.Fatalf("iteration %d: Stop failed: %v", i, err)
+		}
+
+		// Every channel handed out by Start is closed exactly once
+		for _, frames := range channels {
+			timeout := time.After(3 * time.Second)
+		drain:
+			for {
+				select {
+				case _, ok := <-frames:
+					if !ok {
+						break drain
+					}
+				case <-timeout:
+					t.Fatalf("iteration %d: frame channel not closed after Stop", i)
+				}
+			}
+		}
+	}
+}
+
+// TestClassifyMJPEGError verifies HTTP error → category mapping
+func TestClassifyMJPEGError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want rtsp.ErrorCategory
+	}{
+		{"401", &mjpeg.StatusError{Code: 401}, rtsp.ErrCategoryAuth},
+		{"403", &mjpeg.StatusError{Code: 403}, rtsp.ErrCategoryAuth},
+		{"404", &mjpeg.StatusError{Code: 404}, rtsp.ErrCategoryNetwork},
+		{"503", &mjpeg.StatusError{Code: 503}, rtsp.ErrCategoryNetwork},
+		{"400", &mjpeg.StatusError{Code: 400}, rtsp.ErrCategoryUnknown},
+		{"decode", &mjpeg.DecodeError{Err: io.ErrUnexpectedEOF}, rtsp.ErrCategoryCodec},
+		{"content type", &mjpeg.ContentTypeError{ContentType: "text/html"}, rtsp.ErrCategoryCodec},
+		{"stalled", mjpeg.ErrStalled, rtsp.ErrCategoryNetwork},
+		{"eof", io.EOF, rtsp.ErrCategoryNetwork},
+		{"wrapped eof", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), rtsp.ErrCategoryNetwork},
+		{"deadline", context.DeadlineExceeded, rtsp.ErrCategoryNetwork},
+		{"other", fmt.Errorf("something odd"), rtsp.ErrCategoryUnknown},
+		{"nil", nil, rtsp.ErrCategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyMJPEGError(tt.err); got != tt.want {
+				t.Errorf("classifyMJPEGError(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// Compile-time check: MJPEGStream satisfies StreamProvider
+var _ StreamProvider = (*MJPEGStream)(nil)
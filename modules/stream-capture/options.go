@@ -0,0 +1,119 @@
+package streamcapture
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
+)
+
+// Clock provides the current time and timers (see WithClock)
+//
+// Used for frame timestamps, FPS/latency statistics, warm-up duration,
+// reconnect backoff and shutdown timeouts. Implementations must be safe for
+// concurrent use.
+type Clock = clock.Clock
+
+// Option configures a stream provider at construction time
+//
+// Options are applied by NewRTSPStream and NewMJPEGStream:
+//
+//	logger := slog.Default().With("camera", "room-101")
+//	stream, err := streamcapture.NewRTSPStream(cfg,
+//	    streamcapture.WithLogger(logger),
+//	    streamcapture.WithFrameBufferSize(4),
+//	)
+type Option func(*streamOptions)
+
+// streamOptions holds the values configured through Option
+type streamOptions struct {
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+	shutdownTimeout time.Duration
+	tracer          *trace.Tracer
+}
+
+// WithLogger sets the logger used by the stream and its pipeline internals
+//
+// Pass a logger pre-tagged with the camera ID to separate multi-camera logs.
+// Default: slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *streamOptions) {
+		o.logger = logger
+	}
+}
+
+// WithClock sets the clock used for timestamps, statistics, warm-up,
+// reconnect backoff and shutdown timeouts
+//
+// Intended for tests (advance time without sleeping). Default: system clock.
+func WithClock(c Clock) Option {
+	return func(o *streamOptions) {
+		o.clock = c
+	}
+}
+
+// WithFrameBufferSize sets the capacity of the output frame channel
+//
+// Larger buffers absorb consumer hiccups at the cost of latency and memory
+// (frames are dropped, never queued, once the buffer is full). RTSP streams
+// size their internal appsink → forwarder channel the same way, so at most
+// 2n frames are queued end to end.
+// Must be >= 1. Default: 10.
+func WithFrameBufferSize(n int) Option {
+	return func(o *streamOptions) {
+		o.frameBufferSize = n
+	}
+}
+
+// WithShutdownTimeout sets how long Stop() waits for goroutines to exit
+//
+// Must be > 0. Default: 3s.
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(o *streamOptions) {
+		o.shutdownTimeout = d
+	}
+}
+
+// WithTracer records a capture span per frame on tracer
+//
+// Frames always carry a trace context (Frame.TraceID, Frame.TraceParent);
+// the tracer only controls whether spans are exported. Spans are recorded
+// without blocking the frame path. Default: nil (no spans).
+func WithTracer(tracer *trace.Tracer) Option {
+	return func(o *streamOptions) {
+		o.tracer = tracer
+	}
+}
+
+// applyOptions applies opts over the defaults and validates the result (fail-fast)
+func applyOptions(opts []Option) (streamOptions, error) {
+	o := streamOptions{
+		logger:          slog.Default(),
+		clock:           clock.System,
+		frameBufferSize: defaultFrameBufferSize,
+		shutdownTimeout: defaultShutdownTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.logger == nil {
+		o.logger = slog.Default()
+	}
+	if o.clock == nil {
+		o.clock = clock.System
+	}
+	if o.frameBufferSize < 1 {
+		return o, fmt.Errorf("frame buffer size must be >= 1, got %d", o.frameBufferSize)
+	}
+	if o.shutdownTimeout <= 0 {
+		return o, fmt.Errorf("shutdown timeout must be > 0, got %v", o.shutdownTimeout)
+	}
+
+	return o, nil
+}
@@ -0,0 +1,168 @@
+package streamcapture
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes (slog handler)
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestApplyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr bool
+	}{
+		{"defaults", nil, false},
+		{"custom", []Option{WithFrameBufferSize(1), WithShutdownTimeout(time.Second)}, false},
+		{"nil logger and clock fall back to defaults", []Option{WithLogger(nil), WithClock(nil)}, false},
+		{"zero buffer", []Option{WithFrameBufferSize(0)}, true},
+		{"negative timeout", []Option{WithShutdownTimeout(-time.Second)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, err := applyOptions(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("applyOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (o.logger == nil || o.clock == nil) {
+				t.Errorf("expected non-nil logger and clock, got %+v", o)
+			}
+		})
+	}
+
+	if _, err := NewMJPEGStream(MJPEGConfig{URL: "http://cam/video", TargetFPS: 1}, WithFrameBufferSize(0)); err == nil {
+		t.Error("expected NewMJPEGStream to reject invalid options")
+	}
+}
+
+// TestMJPEGStream_Options verifies logger, clock and buffer size are honoured
+//
+// With a fake clock, frame timestamps and FPS decimation follow the injected
+// time: a second frame is only emitted after the clock is advanced.
+func TestMJPEGStream_Options(t *testing.T) {
+	server := newMJPEGServer(t, newTestJPEG(t, 16, 16), 50)
+	defer server.Close()
+
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil)).With("camera", "room-101")
+	fake := clock.NewFake(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	stream, err := NewMJPEGStream(MJPEGConfig{
+		URL:       server.URL,
+		Mode:      MJPEGModeStream,
+		TargetFPS: 1,
+	}, WithLogger(logger), WithClock(fake), WithFrameBufferSize(3))
+	if err != nil {
+		t.Fatalf("NewMJPEGStream failed: %v", err)
+	}
+	defer stream.Stop()
+
+	frames, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if got := cap(frames); got != 3 {
+		t.Errorf("expected frame buffer size 3, got %d", got)
+	}
+
+	select {
+	case frame := <-frames:
+		if !frame.Timestamp.Equal(fake.Now()) {
+			t.Errorf("expected timestamp from fake clock %v, got %v", fake.Now(), frame.Timestamp)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for first frame")
+	}
+
+	// Camera pushes 50 fps, but fake time is frozen: decimation holds frames back
+	select {
+	case frame := <-frames:
+		t.Fatalf("unexpected frame %d before clock advanced", frame.Seq)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	fake.Advance(time.Second)
+	select {
+	case frame := <-frames:
+		if !frame.Timestamp.Equal(fake.Now()) {
+			t.Errorf("expected timestamp %v after Advance, got %v", fake.Now(), frame.Timestamp)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout waiting for frame after clock advanced")
+	}
+
+	if !strings.Contains(logs.String(), "camera=room-101") {
+		t.Errorf("expected logs tagged with camera ID, got:\n%s", logs.String())
+	}
+}
+
+// TestWarmupFrames_FakeClock verifies warm-up completes on clock time (no sleeping)
+func TestWarmupFrames_FakeClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	frames := make(chan Frame, 16)
+
+	start := fake.Now()
+	for i := 0; i < 10; i++ {
+		frames <- Frame{Seq: uint64(i + 1), Timestamp: start.Add(time.Duration(i) * 500 * time.Millisecond)}
+	}
+
+	done := make(chan struct{})
+	var stats *WarmupStats
+	var err error
+	go func() {
+		defer close(done)
+		stats, err = warmupFrames(context.Background(), frames, 5*time.Second, fake, slog.Default())
+	}()
+
+	// Wait until warm-up (and its adapter) are blocked on the clock, then jump ahead
+	deadline := time.Now().Add(2 * time.Second)
+	for fake.Waiters() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	for len(frames) > 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond) // let the adapter hand over the last frame
+	fake.Advance(5 * time.Second)
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("warm-up did not complete after advancing the fake clock")
+	}
+
+	if err != nil {
+		t.Fatalf("warmupFrames failed: %v", err)
+	}
+	if stats.FramesReceived != 10 || stats.Duration != 5*time.Second {
+		t.Errorf("expected 10 frames over 5s, got %d over %v", stats.FramesReceived, stats.Duration)
+	}
+	if stats.FPSMean < 1.9 || stats.FPSMean > 2.1 {
+		t.Errorf("expected ~2 fps, got %.2f", stats.FPSMean)
+	}
+}
@@ -0,0 +1,93 @@
+package streamcapture
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/clock"
+)
+
+// pauseState tracks Pause()/Resume() for a StreamProvider implementation
+//
+// The paused flag is read on the hot path (GStreamer callback, HTTP read loop)
+// so it is an atomic.Bool. Paused-time accounting is guarded by its own mutex
+// to keep Stats() independent of the provider lock ordering.
+type pauseState struct {
+	paused atomic.Bool
+	clock  Clock // nil = system clock
+
+	mu    sync.Mutex
+	since time.Time     // When the current pause started (zero if not paused)
+	total time.Duration // Accumulated paused time (excluding current pause)
+}
+
+// pause marks the stream as paused
+//
+// Returns false if the stream was already paused (idempotent).
+func (p *pauseState) pause() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused.CompareAndSwap(false, true) {
+		return false
+	}
+	p.since = clock.OrSystem(p.clock).Now()
+	return true
+}
+
+// resume clears the paused flag and accumulates the paused duration
+//
+// Returns false if the stream was not paused (idempotent).
+func (p *pauseState) resume() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused.CompareAndSwap(true, false) {
+		return false
+	}
+	p.total += clock.OrSystem(p.clock).Since(p.since)
+	p.since = time.Time{}
+	return true
+}
+
+// duration returns the total paused time, including the pause in progress
+func (p *pauseState) duration() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := p.total
+	if !p.since.IsZero() {
+		total += clock.OrSystem(p.clock).Since(p.since)
+	}
+	return total
+}
+
+// reset clears pause state (stream restart)
+func (p *pauseState) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.paused.Store(false)
+	p.since = time.Time{}
+	p.total = 0
+}
+
+// drainFrames discards frames already buffered in the output channel
+//
+// Called on Pause(): frames captured before the pause must not reach the
+// consumer afterwards (privacy mode). Non-blocking.
+func drainFrames(frames chan Frame) int {
+	drained := 0
+	for {
+		select {
+		case _, ok := <-frames:
+			if !ok {
+				return drained
+			}
+			drained++
+		default:
+			return drained
+		}
+	}
+}
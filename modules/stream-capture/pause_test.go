@@ -0,0 +1,66 @@
+package streamcapture
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPauseState_Accounting verifies paused time accumulation across pause/resume cycles
+func TestPauseState_Accounting(t *testing.T) {
+	var p pauseState
+
+	if p.resume() {
+		t.Error("resume on non-paused state should return false")
+	}
+
+	if !p.pause() {
+		t.Fatal("first pause should return true")
+	}
+	if p.pause() {
+		t.Error("second pause should return false (idempotent)")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if d := p.duration(); d < 20*time.Millisecond {
+		t.Errorf("duration should include current pause, got %v", d)
+	}
+
+	if !p.resume() {
+		t.Fatal("resume should return true")
+	}
+	first := p.duration()
+
+	// Duration is frozen while not paused
+	time.Sleep(10 * time.Millisecond)
+	if d := p.duration(); d != first {
+		t.Errorf("duration changed while not paused: %v -> %v", first, d)
+	}
+
+	// Second cycle accumulates
+	p.pause()
+	time.Sleep(10 * time.Millisecond)
+	p.resume()
+	if d := p.duration(); d < first+10*time.Millisecond {
+		t.Errorf("expected accumulated duration >= %v, got %v", first+10*time.Millisecond, d)
+	}
+
+	p.reset()
+	if p.paused.Load() || p.duration() != 0 {
+		t.Error("reset should clear paused flag and duration")
+	}
+}
+
+// TestDrainFrames verifies buffered frames are discarded without blocking
+func TestDrainFrames(t *testing.T) {
+	frames := make(chan Frame, 5)
+	for i := 0; i < 3; i++ {
+		frames <- Frame{Seq: uint64(i)}
+	}
+
+	if n := drainFrames(frames); n != 3 {
+		t.Errorf("expected 3 drained frames, got %d", n)
+	}
+	if n := drainFrames(frames); n != 0 {
+		t.Errorf("expected empty channel, drained %d", n)
+	}
+}
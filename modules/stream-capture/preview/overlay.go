@@ -0,0 +1,85 @@
+package preview
+
+import (
+	"image"
+)
+
+// glyphs is a minimal 3x5 bitmap font covering the overlay text (seq + timestamp).
+// Each glyph is 5 rows of 3 bits (MSB = leftmost pixel).
+//
+// A built-in font keeps the preview dependency-free (no x/image/font).
+var glyphs = map[rune][5]uint8{
+	'0': {0b111, 0b101, 0b101, 0b101, 0b111},
+	'1': {0b010, 0b110, 0b010, 0b010, 0b111},
+	'2': {0b111, 0b001, 0b111, 0b100, 0b111},
+	'3': {0b111, 0b001, 0b111, 0b001, 0b111},
+	'4': {0b101, 0b101, 0b111, 0b001, 0b001},
+	'5': {0b111, 0b100, 0b111, 0b001, 0b111},
+	'6': {0b111, 0b100, 0b111, 0b101, 0b111},
+	'7': {0b111, 0b001, 0b001, 0b001, 0b001},
+	'8': {0b111, 0b101, 0b111, 0b101, 0b111},
+	'9': {0b111, 0b101, 0b111, 0b001, 0b111},
+	'#': {0b101, 0b111, 0b101, 0b111, 0b101},
+	':': {0b000, 0b010, 0b000, 0b010, 0b000},
+	'.': {0b000, 0b000, 0b000, 0b000, 0b010},
+	'-': {0b000, 0b000, 0b111, 0b000, 0b000},
+	' ': {0b000, 0b000, 0b000, 0b000, 0b000},
+}
+
+const (
+	glyphWidth   = 3
+	glyphHeight  = 5
+	glyphSpacing = 1 // Columns between glyphs
+	overlayPad   = 2 // Background padding around text (in font pixels)
+)
+
+// drawOverlay renders white text on a black box in the top-left corner
+//
+// Scale adapts to frame height so the text stays readable from 480p to 1080p.
+// Characters missing from the font are rendered as blanks.
+func drawOverlay(img *image.RGBA, text string) {
+	bounds := img.Bounds()
+
+	scale := bounds.Dy() / 120
+	if scale < 1 {
+		scale = 1
+	}
+
+	runes := []rune(text)
+	boxW := (len(runes)*(glyphWidth+glyphSpacing) - glyphSpacing + 2*overlayPad) * scale
+	boxH := (glyphHeight + 2*overlayPad) * scale
+
+	// Background box
+	fillRect(img, 0, 0, boxW, boxH, 0, 0, 0)
+
+	// Glyphs
+	x := overlayPad * scale
+	y := overlayPad * scale
+	for _, r := range runes {
+		glyph := glyphs[r]
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if glyph[row]&(1<<uint(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				fillRect(img, x+col*scale, y+row*scale, scale, scale, 255, 255, 255)
+			}
+		}
+		x += (glyphWidth + glyphSpacing) * scale
+	}
+}
+
+// fillRect paints an opaque rectangle, clipped to the image bounds
+func fillRect(img *image.RGBA, x0, y0, w, h int, r, g, b uint8) {
+	rect := image.Rect(x0, y0, x0+w, y0+h).Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		i := img.PixOffset(rect.Min.X, y)
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.Pix[i+0] = r
+			img.Pix[i+1] = g
+			img.Pix[i+2] = b
+			img.Pix[i+3] = 255
+			i += 4
+		}
+	}
+}
@@ -0,0 +1,478 @@
+// Package preview provides an embeddable HTTP handler for live inspection of
+// captured frames during camera installation and debugging.
+//
+// The handler serves the latest frames of a StreamProvider as an MJPEG stream
+// (viewable in any browser), a single JPEG snapshot and a JSON stats endpoint:
+//
+//	GET /              Minimal HTML page (live image + stats)
+//	GET /stream.mjpeg  multipart/x-mixed-replace MJPEG stream
+//	GET /snapshot.jpg  Latest frame as JPEG
+//	GET /stats.json    StreamStats + preview telemetry
+//
+// Frames are fed by the application (StreamProvider channels have a single
+// consumer), either via Observe() from an existing frame loop or via Run()
+// on a dedicated channel. Preview output is rate-limited independently of
+// the stream TargetFPS, and JPEG encoding is shared between clients (one
+// encode per frame, regardless of the number of viewers).
+//
+// Example:
+//
+//	pv, _ := preview.New(stream, preview.Config{MaxFPS: 5, Overlay: true},
+//	    preview.WithLogger(logger))
+//	go http.ListenAndServe(":8080", pv)
+//
+//	for frame := range frameChan {
+//	    pv.Observe(frame)
+//	    process(frame)
+//	}
+//
+// Mount under a prefix with http.StripPrefix (the HTML page uses relative URLs):
+//
+//	mux.Handle("/preview/", http.StripPrefix("/preview", pv))
+package preview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
+)
+
+const (
+	// defaultMaxFPS is the default preview rate. Low on purpose: preview runs
+	// on the sensor next to inference, and 5 fps is enough to aim a camera.
+	defaultMaxFPS = 5.0
+
+	// defaultJPEGQuality balances browser image quality vs encode cost.
+	defaultJPEGQuality = 75
+
+	// defaultMaxClients bounds concurrent MJPEG viewers (each holds a connection).
+	defaultMaxClients = 4
+
+	// boundary is the multipart boundary used for /stream.mjpeg
+	boundary = "orionpreview"
+)
+
+// Config contains configuration for the preview handler
+type Config struct {
+	// MaxFPS is the maximum rate of frames sent to each MJPEG client (default: 5)
+	// Independent of the stream TargetFPS: preview never sends more frames than
+	// the stream produces, and never more than MaxFPS.
+	// Set to 0 to use default value
+	MaxFPS float64
+	// JPEGQuality is the JPEG encoding quality 1-100 (default: 75)
+	// Set to 0 to use default value
+	JPEGQuality int
+	// MaxClients is the maximum number of concurrent MJPEG stream clients (default: 4)
+	// Set to 0 to use default value
+	MaxClients int
+	// Overlay draws frame seq and capture timestamp in the top-left corner
+	Overlay bool
+}
+
+// Option configures a preview Server at construction time
+type Option func(*Server)
+
+// WithLogger sets the logger for client and encode events (default: slog.Default())
+//
+// A nil logger is ignored.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// frameKey identifies an observed frame
+//
+// Seq alone is not unique: it restarts with the stream (Restart, ResetStats),
+// repeats on replay loops and differs per source.
+type frameKey struct {
+	source    string
+	seq       uint64
+	timestamp int64 // UnixNano
+}
+
+func keyOf(frame *streamcapture.Frame) frameKey {
+	return frameKey{source: frame.SourceStream, seq: frame.Seq, timestamp: frame.Timestamp.UnixNano()}
+}
+
+// Stats contains preview telemetry (served in /stats.json)
+type Stats struct {
+	// Clients is the number of connected MJPEG stream clients
+	Clients int64 `json:"clients"`
+	// FramesObserved is the total number of frames passed to Observe()
+	FramesObserved uint64 `json:"frames_observed"`
+	// FramesEncoded is the number of JPEG encodes performed (shared between clients)
+	FramesEncoded uint64 `json:"frames_encoded"`
+	// LastSeq is the sequence number of the latest observed frame
+	LastSeq uint64 `json:"last_seq"`
+	// LastFrameAt is the capture timestamp of the latest observed frame
+	LastFrameAt time.Time `json:"last_frame_at"`
+	// MaxFPS is the configured preview rate limit
+	MaxFPS float64 `json:"max_fps"`
+}
+
+// Server is an http.Handler serving live preview of a stream
+//
+// Thread-safe: Observe() may be called from the frame loop while any number of
+// HTTP requests are served concurrently.
+type Server struct {
+	provider streamcapture.StreamProvider
+	cfg      Config
+	mux      *http.ServeMux
+	logger   *slog.Logger
+
+	// Latest frame (broadcast via updated channel, closed and replaced on Observe)
+	mu      sync.Mutex
+	latest  *streamcapture.Frame
+	updated chan struct{}
+
+	// Encoded JPEG cache (one encode per frame, shared by all clients)
+	encodeMu    sync.Mutex
+	encodedKey  frameKey
+	encodedJPEG []byte
+
+	// Telemetry (atomic for thread-safety)
+	clients        atomic.Int64
+	framesObserved atomic.Uint64
+	framesEncoded  atomic.Uint64
+}
+
+// New creates a preview handler with fail-fast validation
+//
+// provider is used for /stats.json only (may be nil to serve preview stats alone).
+//
+// Returns an error if:
+//   - MaxFPS is outside valid range (0.1-30.0)
+//   - JPEGQuality is outside valid range (1-100)
+//   - MaxClients is negative
+func New(provider streamcapture.StreamProvider, cfg Config, opts ...Option) (*Server, error) {
+	if cfg.MaxFPS == 0 {
+		cfg.MaxFPS = defaultMaxFPS
+	}
+	if cfg.JPEGQuality == 0 {
+		cfg.JPEGQuality = defaultJPEGQuality
+	}
+	if cfg.MaxClients == 0 {
+		cfg.MaxClients = defaultMaxClients
+	}
+
+	if cfg.MaxFPS < 0.1 || cfg.MaxFPS > 30 {
+		return nil, fmt.Errorf("preview: invalid MaxFPS %.2f (must be 0.1-30)", cfg.MaxFPS)
+	}
+	if cfg.JPEGQuality < 1 || cfg.JPEGQuality > 100 {
+		return nil, fmt.Errorf("preview: invalid JPEGQuality %d (must be 1-100)", cfg.JPEGQuality)
+	}
+	if cfg.MaxClients < 0 {
+		return nil, fmt.Errorf("preview: invalid MaxClients %d", cfg.MaxClients)
+	}
+
+	s := &Server{
+		provider: provider,
+		cfg:      cfg,
+		logger:   slog.Default(),
+		updated:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("/", s.handleIndex)
+	s.mux.HandleFunc("/stream.mjpeg", s.handleStream)
+	s.mux.HandleFunc("/snapshot.jpg", s.handleSnapshot)
+	s.mux.HandleFunc("/stats.json", s.handleStats)
+
+	return s, nil
+}
+
+// Observe records a frame as the latest available for preview
+//
+// Cheap and non-blocking (no encoding, no copy): safe to call from the
+// hot frame loop. Frame.Data must not be mutated after Observe().
+func (s *Server) Observe(frame streamcapture.Frame) {
+	s.framesObserved.Add(1)
+
+	s.mu.Lock()
+	s.latest = &frame
+	close(s.updated)
+	s.updated = make(chan struct{})
+	s.mu.Unlock()
+}
+
+// Run observes frames from a dedicated channel until it closes or ctx is cancelled
+//
+// Use this when the preview has its own frame source (e.g. a broadcaster
+// subscription). For a shared frame loop, call Observe() instead.
+func (s *Server) Run(ctx context.Context, frames <-chan streamcapture.Frame) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case frame, ok := <-frames:
+			if !ok {
+				return
+			}
+			s.Observe(frame)
+		}
+	}
+}
+
+// Stats returns current preview telemetry
+func (s *Server) Stats() Stats {
+	stats := Stats{
+		Clients:        s.clients.Load(),
+		FramesObserved: s.framesObserved.Load(),
+		FramesEncoded:  s.framesEncoded.Load(),
+		MaxFPS:         s.cfg.MaxFPS,
+	}
+
+	s.mu.Lock()
+	if s.latest != nil {
+		stats.LastSeq = s.latest.Seq
+		stats.LastFrameAt = s.latest.Timestamp
+	}
+	s.mu.Unlock()
+
+	return stats
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleStream serves multipart/x-mixed-replace MJPEG to a browser
+//
+// This handler:
+//  1. Rejects the client if MaxClients is reached (503)
+//  2. Waits for a frame newer than the last one sent
+//  3. Sends it as a JPEG part (shared encode; a frame that fails to encode
+//     is skipped)
+//  4. Sleeps until the next MaxFPS slot
+//
+// Runs until the client disconnects (or a write fails).
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	if n := s.clients.Add(1); n > int64(s.cfg.MaxClients) {
+		s.clients.Add(-1)
+		http.Error(w, "too many preview clients", http.StatusServiceUnavailable)
+		return
+	}
+	defer s.clients.Add(-1)
+
+	ctx := r.Context()
+	interval := time.Duration(float64(time.Second) / s.cfg.MaxFPS)
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+boundary)
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Connection", "close")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	s.logger.Debug("preview: client connected", "remote", r.RemoteAddr)
+	defer s.logger.Debug("preview: client disconnected", "remote", r.RemoteAddr)
+
+	var last frameKey
+	var next time.Time
+	for {
+		// Rate limit (independent of stream TargetFPS)
+		if wait := time.Until(next); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		frame, err := s.waitFrame(ctx, last)
+		if err != nil {
+			return
+		}
+		last = keyOf(frame)
+
+		data, err := s.encode(frame)
+		if err != nil {
+			s.logger.Warn("preview: skipping frame", "seq", frame.Seq, "error", err)
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", boundary, len(data)); err != nil {
+			return
+		}
+		if _, err := w.Write(data); err != nil {
+			return
+		}
+		if _, err := fmt.Fprint(w, "\r\n"); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		next = time.Now().Add(interval)
+	}
+}
+
+// handleSnapshot serves the latest frame as a single JPEG
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	frame := s.latest
+	s.mu.Unlock()
+
+	if frame == nil {
+		http.Error(w, "no frame available yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	data, err := s.encode(frame)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Content-Length", fmt.Sprint(len(data)))
+	w.Write(data)
+}
+
+// statsResponse is the /stats.json payload
+type statsResponse struct {
+	Stream  *streamcapture.StreamStats `json:"stream,omitempty"`
+	Preview Stats                      `json:"preview"`
+}
+
+// handleStats serves stream and preview statistics as JSON
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	resp := statsResponse{Preview: s.Stats()}
+	if s.provider != nil {
+		stats := s.provider.Stats()
+		resp.Stream = &stats
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(resp); err != nil {
+		s.logger.Debug("preview: failed to write stats", "error", err)
+	}
+}
+
+// handleIndex serves a minimal HTML page for installers
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, indexHTML)
+}
+
+// waitFrame blocks until a frame other than last is available
+//
+// Returns an error only if ctx is done (client gone).
+func (s *Server) waitFrame(ctx context.Context, last frameKey) (*streamcapture.Frame, error) {
+	for {
+		s.mu.Lock()
+		frame := s.latest
+		updated := s.updated
+		s.mu.Unlock()
+
+		if frame != nil && keyOf(frame) != last {
+			return frame, nil
+		}
+
+		select {
+		case <-updated:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// encode returns the JPEG for a frame, encoding at most once per frame
+// (keyed by source, seq and timestamp)
+func (s *Server) encode(frame *streamcapture.Frame) ([]byte, error) {
+	s.encodeMu.Lock()
+	defer s.encodeMu.Unlock()
+
+	key := keyOf(frame)
+	if s.encodedJPEG != nil && s.encodedKey == key {
+		return s.encodedJPEG, nil
+	}
+
+	if frame.Width <= 0 || frame.Height <= 0 || len(frame.Data) < frame.Width*frame.Height*3 {
+		return nil, fmt.Errorf("preview: invalid frame %dx%d (%d bytes)", frame.Width, frame.Height, len(frame.Data))
+	}
+
+	// RGB → RGBA (copy: overlay must not touch the shared frame buffer)
+	img := image.NewRGBA(image.Rect(0, 0, frame.Width, frame.Height))
+	for i, j := 0, 0; i < frame.Width*frame.Height*3; i, j = i+3, j+4 {
+		img.Pix[j+0] = frame.Data[i+0]
+		img.Pix[j+1] = frame.Data[i+1]
+		img.Pix[j+2] = frame.Data[i+2]
+		img.Pix[j+3] = 255
+	}
+
+	if s.cfg.Overlay {
+		drawOverlay(img, fmt.Sprintf("#%d %s", frame.Seq, frame.Timestamp.Format("15:04:05.000")))
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: s.cfg.JPEGQuality}); err != nil {
+		return nil, fmt.Errorf("preview: jpeg encode failed: %w", err)
+	}
+
+	s.encodedKey = key
+	s.encodedJPEG = buf.Bytes()
+	s.framesEncoded.Add(1)
+
+	return s.encodedJPEG, nil
+}
+
+// indexHTML is the installer page (relative URLs so it works under a prefix)
+const indexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Orion stream preview</title>
+<style>
+body { background: #111; color: #ddd; font-family: monospace; margin: 1em; }
+img { max-width: 100%; border: 1px solid #444; }
+pre { font-size: 12px; }
+</style>
+</head>
+<body>
+<img src="stream.mjpeg" alt="live preview">
+<pre id="stats">loading stats...</pre>
+<script>
+async function refresh() {
+  try {
+    const r = await fetch("stats.json", {cache: "no-store"});
+    document.getElementById("stats").textContent = JSON.stringify(await r.json(), null, 2);
+  } catch (e) {
+    document.getElementById("stats").textContent = "stats unavailable: " + e;
+  }
+}
+refresh();
+setInterval(refresh, 1000);
+</script>
+</body>
+</html>
+`
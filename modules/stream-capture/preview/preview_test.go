@@ -0,0 +1,384 @@
+package preview_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	streamcapture "github.com/e7canasta/orion-care-sensor/modules/stream-capture"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/preview"
+)
+
+// fakeProvider implements StreamProvider with fixed stats (no GStreamer needed)
+type fakeProvider struct {
+	stats streamcapture.StreamStats
+}
+
+func (f *fakeProvider) Start(ctx context.Context) (<-chan streamcapture.Frame, error) {
+	return nil, nil
+}
+func (f *fakeProvider) Stop() error                      { return nil }
+func (f *fakeProvider) Stats() streamcapture.StreamStats { return f.stats }
+func (f *fakeProvider) SetTargetFPS(fps float64) error   { return nil }
+func (f *fakeProvider) Pause() error                     { return nil }
+func (f *fakeProvider) Resume() error                    { return nil }
+func (f *fakeProvider) Warmup(ctx context.Context, d time.Duration) (*streamcapture.WarmupStats, error) {
+	return nil, nil
+}
+
+// grayFrame returns a mid-gray RGB frame
+func grayFrame(seq uint64, width, height int) streamcapture.Frame {
+	data := make([]byte, width*height*3)
+	for i := range data {
+		data[i] = 128
+	}
+	return streamcapture.Frame{
+		Seq:       seq,
+		Timestamp: time.Now(),
+		Width:     width,
+		Height:    height,
+		Data:      data,
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     preview.Config
+		wantErr bool
+	}{
+		{"defaults", preview.Config{}, false},
+		{"custom", preview.Config{MaxFPS: 1, JPEGQuality: 50, MaxClients: 2, Overlay: true}, false},
+		{"FPS too high", preview.Config{MaxFPS: 60}, true},
+		{"negative FPS", preview.Config{MaxFPS: -1}, true},
+		{"quality too high", preview.Config{JPEGQuality: 101}, true},
+		{"negative clients", preview.Config{MaxClients: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := preview.New(nil, tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_Snapshot(t *testing.T) {
+	pv, err := preview.New(nil, preview.Config{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	// No frame yet
+	resp, err := http.Get(server.URL + "/snapshot.jpg")
+	if err != nil {
+		t.Fatalf("GET snapshot failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before first frame, got %d", resp.StatusCode)
+	}
+
+	pv.Observe(grayFrame(1, 64, 48))
+
+	resp, err = http.Get(server.URL + "/snapshot.jpg")
+	if err != nil {
+		t.Fatalf("GET snapshot failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	img, err := jpeg.Decode(resp.Body)
+	if err != nil {
+		t.Fatalf("snapshot is not a valid JPEG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 64 || b.Dy() != 48 {
+		t.Errorf("expected 64x48 snapshot, got %dx%d", b.Dx(), b.Dy())
+	}
+}
+
+func TestServer_Overlay(t *testing.T) {
+	pv, err := preview.New(nil, preview.Config{Overlay: true, JPEGQuality: 100})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	pv.Observe(grayFrame(42, 320, 240))
+
+	resp, err := http.Get(server.URL + "/snapshot.jpg")
+	if err != nil {
+		t.Fatalf("GET snapshot failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	img, err := jpeg.Decode(resp.Body)
+	if err != nil {
+		t.Fatalf("snapshot is not a valid JPEG: %v", err)
+	}
+
+	// Top-left corner is the overlay background (black), far corner stays gray
+	r, _, _, _ := img.At(1, 1).RGBA()
+	if r>>8 > 40 {
+		t.Errorf("expected dark overlay background at (1,1), got R=%d", r>>8)
+	}
+	r, _, _, _ = img.At(300, 220).RGBA()
+	if v := r >> 8; v < 100 || v > 156 {
+		t.Errorf("expected untouched gray pixel at (300,220), got R=%d", v)
+	}
+}
+
+func TestServer_Stats(t *testing.T) {
+	provider := &fakeProvider{stats: streamcapture.StreamStats{FrameCount: 99, FPSTarget: 2}}
+	pv, err := preview.New(provider, preview.Config{MaxFPS: 3})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	pv.Observe(grayFrame(7, 8, 8))
+
+	resp, err := http.Get(server.URL + "/stats.json")
+	if err != nil {
+		t.Fatalf("GET stats failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Stream  streamcapture.StreamStats `json:"stream"`
+		Preview preview.Stats             `json:"preview"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid stats JSON: %v", err)
+	}
+
+	if body.Stream.FrameCount != 99 {
+		t.Errorf("expected stream FrameCount 99, got %d", body.Stream.FrameCount)
+	}
+	if body.Preview.LastSeq != 7 || body.Preview.FramesObserved != 1 {
+		t.Errorf("unexpected preview stats: %+v", body.Preview)
+	}
+	if body.Preview.MaxFPS != 3 {
+		t.Errorf("expected MaxFPS 3, got %.2f", body.Preview.MaxFPS)
+	}
+}
+
+// TestServer_StreamRateLimited verifies MJPEG output respects MaxFPS
+// even when frames are observed much faster
+func TestServer_StreamRateLimited(t *testing.T) {
+	pv, err := preview.New(nil, preview.Config{MaxFPS: 4})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Producer at ~100 fps
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for seq := uint64(1); ; seq++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pv.Observe(grayFrame(seq, 16, 16))
+			}
+		}
+	}()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream.mjpeg", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type: %v", err)
+	}
+	reader := multipart.NewReader(bufio.NewReader(resp.Body), params["boundary"])
+
+	start := time.Now()
+	parts := 0
+	for time.Since(start) < time.Second {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart failed after %d parts: %v", parts, err)
+		}
+		data, _ := io.ReadAll(part)
+		if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+			t.Fatalf("part %d is not a valid JPEG: %v", parts, err)
+		}
+		parts++
+	}
+
+	// 4 fps over ~1s: allow first frame + scheduling slack, but far below 100
+	if parts < 2 || parts > 7 {
+		t.Errorf("expected ~4-5 parts in 1s at MaxFPS=4, got %d", parts)
+	}
+}
+
+func TestServer_MaxClients(t *testing.T) {
+	pv, err := preview.New(nil, preview.Config{MaxClients: 1})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream.mjpeg", nil)
+	first, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("first client failed: %v", err)
+	}
+	defer first.Body.Close()
+
+	second, err := http.Get(server.URL + "/stream.mjpeg")
+	if err != nil {
+		t.Fatalf("second client failed: %v", err)
+	}
+	second.Body.Close()
+
+	if second.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 for client over limit, got %d", second.StatusCode)
+	}
+}
+
+// TestServer_SeqRepeats verifies the encode cache is not keyed on Seq alone
+// (replay loops and restarts reuse sequence numbers)
+func TestServer_SeqRepeats(t *testing.T) {
+	pv, err := preview.New(nil, preview.Config{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	snapshotRed := func() uint32 {
+		t.Helper()
+		resp, err := http.Get(server.URL + "/snapshot.jpg")
+		if err != nil {
+			t.Fatalf("GET snapshot failed: %v", err)
+		}
+		defer resp.Body.Close()
+		img, err := jpeg.Decode(resp.Body)
+		if err != nil {
+			t.Fatalf("snapshot is not a valid JPEG: %v", err)
+		}
+		r, _, _, _ := img.At(4, 4).RGBA()
+		return r >> 8
+	}
+
+	pv.Observe(grayFrame(1, 16, 16))
+	if v := snapshotRed(); v < 100 || v > 156 {
+		t.Fatalf("expected gray snapshot, got R=%d", v)
+	}
+
+	// Same Seq after a restart, different content
+	white := grayFrame(1, 16, 16)
+	white.Timestamp = white.Timestamp.Add(time.Second)
+	for i := range white.Data {
+		white.Data[i] = 255
+	}
+	pv.Observe(white)
+	if v := snapshotRed(); v < 240 {
+		t.Errorf("expected the new frame (white) for a repeated Seq, got stale R=%d", v)
+	}
+}
+
+// TestServer_StreamSkipsBadFrame verifies an encode failure skips the frame
+// instead of ending the client's stream, and is logged via WithLogger
+func TestServer_StreamSkipsBadFrame(t *testing.T) {
+	var logs syncBuffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	pv, err := preview.New(nil, preview.Config{MaxFPS: 30}, preview.WithLogger(logger))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	server := httptest.NewServer(pv)
+	defer server.Close()
+
+	// Truncated frame (fails validation in encode)
+	bad := grayFrame(1, 16, 16)
+	bad.Data = bad.Data[:10]
+	pv.Observe(bad)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/stream.mjpeg", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Good frame once the bad one was attempted
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(logs.String(), "preview: skipping frame") {
+		if time.Now().After(deadline) {
+			t.Fatalf("bad frame not skipped via injected logger; logs:\n%s", logs.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	pv.Observe(grayFrame(2, 16, 16))
+
+	_, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type: %v", err)
+	}
+	part, err := multipart.NewReader(bufio.NewReader(resp.Body), params["boundary"]).NextPart()
+	if err != nil {
+		t.Fatalf("stream ended after bad frame: %v", err)
+	}
+	data, _ := io.ReadAll(part)
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("part after bad frame is not a valid JPEG: %v", err)
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes (log handler)
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
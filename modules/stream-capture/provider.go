@@ -13,6 +13,7 @@ import (
 //   - Stop() is idempotent (safe to call multiple times)
 //   - Stats() is thread-safe (can be called from any goroutine)
 //   - SetTargetFPS() does not require restart (hot-reload)
+//   - Pause()/Resume() keep the camera session and channel alive
 //   - Warmup() measures FPS stability (optional but recommended)
 type StreamProvider interface {
 	// Start initializes the stream and returns a read-only channel of frames.
@@ -27,6 +28,9 @@ type StreamProvider interface {
 	// sent using a non-blocking pattern - if the channel buffer is full, frames
 	// are dropped rather than queued to maintain low latency.
 	//
+	// Start may be called again after Stop() (or after a failed Start()) and
+	// returns a fresh channel; statistics are preserved across restarts.
+	//
 	// Returns an error if:
 	//   - The stream cannot be established
 	//   - GStreamer is not available
@@ -92,6 +96,31 @@ type StreamProvider interface {
 	//   err := stream.SetTargetFPS(0.5)  // Change to 0.5 Hz (1 frame every 2 seconds)
 	SetTargetFPS(fps float64) error
 
+	// Pause stops frame delivery without tearing down the stream.
+	//
+	// The camera session (RTSP/HTTP connection) and the frame channel stay
+	// alive: frames are discarded at the source (appsink callback / HTTP
+	// reader) instead of being delivered. Frames already buffered in the
+	// channel are drained, so no pre-pause frame reaches the consumer after
+	// Pause() returns.
+	//
+	// Intended for privacy mode ("staff in the room, stop analysing"):
+	// Resume() delivers frames again immediately, without the 3-10 second
+	// reconnect cost of Stop()/Start().
+	//
+	// While paused, Stats().IsPaused is true and paused time is excluded
+	// from FPSReal. Calling Pause() on a paused stream is a no-op.
+	//
+	// Returns an error if the stream is not running.
+	Pause() error
+
+	// Resume restarts frame delivery after Pause().
+	//
+	// Calling Resume() on a stream that is not paused is a no-op.
+	//
+	// Returns an error if the stream is not running.
+	Resume() error
+
 	// Warmup measures stream FPS stability over a specified duration.
 	//
 	// This method should be called after Start() to measure the real FPS and
@@ -0,0 +1,251 @@
+package streamcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/framelog"
+)
+
+// Recorder taps a StreamProvider and appends every delivered frame to a frame log
+//
+// The Recorder is itself a StreamProvider: consumers read from the channel
+// returned by Recorder.Start and see exactly the frames that were recorded
+// (same Seq, Timestamp, TraceID and pixel data). Stats, SetTargetFPS and
+// Resume are forwarded to the wrapped provider.
+//
+// Frames are forwarded with a blocking send, so a slow consumer back-pressures
+// into the wrapped provider, which drops frames on its own (non-blocking)
+// channel. Dropped frames are therefore never recorded: the log holds what
+// the worker actually received.
+//
+// The Recorder does not own the framelog.Writer. Close it after Stop to write
+// the seek index:
+//
+//	w, _ := framelog.Create("incident.flog")
+//	rec, _ := streamcapture.NewRecorder(stream, w)
+//	frames, _ := rec.Start(ctx)
+//	...
+//	rec.Stop()
+//	w.Close()
+//
+// Write errors (disk full) are logged once and reported by Err(); frames keep
+// flowing to the consumer so recording never takes down the pipeline.
+type Recorder struct {
+	StreamProvider
+
+	w *framelog.Writer
+
+	// Injected dependencies (see Option)
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+
+	// Lifecycle
+	mu     sync.RWMutex
+	frames chan Frame
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	// Statistics (atomic for thread-safety)
+	recorded uint64
+
+	errMu sync.Mutex
+	err   error // First write error (nil if none)
+}
+
+// NewRecorder wraps provider so that delivered frames are written to w
+//
+// Options: WithLogger, WithClock (Received timestamps), WithFrameBufferSize
+// (output channel capacity).
+func NewRecorder(provider StreamProvider, w *framelog.Writer, opts ...Option) (*Recorder, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("stream-capture: recorder requires a stream provider")
+	}
+	if w == nil {
+		return nil, fmt.Errorf("stream-capture: recorder requires a frame log writer")
+	}
+
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	return &Recorder{
+		StreamProvider:  provider,
+		w:               w,
+		logger:          options.logger,
+		clock:           options.clock,
+		frameBufferSize: options.frameBufferSize,
+	}, nil
+}
+
+// Start starts the wrapped provider and begins recording
+//
+// The returned channel closes when the wrapped provider's channel closes
+// or after Stop().
+func (r *Recorder) Start(ctx context.Context) (<-chan Frame, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done != nil {
+		return nil, fmt.Errorf("stream-capture: stream already started")
+	}
+
+	in, err := r.StreamProvider.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.frames = make(chan Frame, r.frameBufferSize)
+	r.done = make(chan struct{})
+
+	r.logger.Info("stream-capture: recording started",
+		"records", r.w.Len(),
+	)
+
+	r.wg.Add(1)
+	go r.forward(in, r.frames, r.done)
+
+	return r.frames, nil
+}
+
+// forward records each frame and passes it on to the consumer
+func (r *Recorder) forward(in <-chan Frame, out chan<- Frame, done <-chan struct{}) {
+	defer r.wg.Done()
+	defer close(out)
+
+	for {
+		var frame Frame
+		var ok bool
+		select {
+		case <-done:
+			return
+		case frame, ok = <-in:
+			if !ok {
+				return
+			}
+		}
+
+		r.record(frame)
+
+		select {
+		case out <- frame:
+		case <-done:
+			return
+		}
+	}
+}
+
+// record appends a frame to the log (called from forward only)
+func (r *Recorder) record(frame Frame) {
+	err := r.w.Write(framelog.Record{
+		Seq:          frame.Seq,
+		Timestamp:    frame.Timestamp,
+		Received:     r.clock.Now(),
+		Width:        frame.Width,
+		Height:       frame.Height,
+		Format:       framelog.FormatRGB24,
+		SourceStream: frame.SourceStream,
+		TraceID:      frame.TraceID,
+		Data:         frame.Data,
+	})
+	if err == nil {
+		// Flush per frame: a crash loses at most the frame in flight
+		err = r.w.Flush()
+	}
+	if err != nil {
+		r.setErr(err)
+		return
+	}
+	atomic.AddUint64(&r.recorded, 1)
+}
+
+// setErr stores the first write error and logs it once
+func (r *Recorder) setErr(err error) {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	r.err = err
+	r.logger.Error("stream-capture: frame recording failed, frames still forwarded",
+		"error", err,
+		"recorded", atomic.LoadUint64(&r.recorded),
+	)
+}
+
+// Stop stops the wrapped provider and the recording goroutine
+//
+// Buffered records are flushed; the writer stays open (see Recorder).
+// Idempotent.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.done == nil {
+		return r.StreamProvider.Stop()
+	}
+
+	close(r.done)
+	err := r.StreamProvider.Stop()
+	r.wg.Wait()
+
+	if flushErr := r.w.Flush(); flushErr != nil {
+		r.setErr(flushErr)
+	}
+
+	r.logger.Info("stream-capture: recording stopped",
+		"recorded", atomic.LoadUint64(&r.recorded),
+		"records", r.w.Len(),
+	)
+
+	r.done = nil
+	return err
+}
+
+// Pause pauses the wrapped provider and drains frames already forwarded
+func (r *Recorder) Pause() error {
+	if err := r.StreamProvider.Pause(); err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.frames != nil {
+		drainFrames(r.frames)
+	}
+	return nil
+}
+
+// Warmup measures FPS stability on the recorded channel
+//
+// Frames consumed during warmup are recorded too.
+func (r *Recorder) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	r.mu.RLock()
+	if r.done == nil {
+		r.mu.RUnlock()
+		return nil, fmt.Errorf("stream-capture: stream not started")
+	}
+	frames := r.frames
+	r.mu.RUnlock()
+
+	return warmupFrames(ctx, frames, duration, r.clock, r.logger)
+}
+
+// Recorded returns the number of frames written to the log
+func (r *Recorder) Recorded() uint64 {
+	return atomic.LoadUint64(&r.recorded)
+}
+
+// Err returns the first write error, or nil
+func (r *Recorder) Err() error {
+	r.errMu.Lock()
+	defer r.errMu.Unlock()
+	return r.err
+}
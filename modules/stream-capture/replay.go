@@ -0,0 +1,543 @@
+package streamcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/framelog"
+)
+
+// replayPausePoll throttles an unpaced replay while paused
+const replayPausePoll = 10 * time.Millisecond
+
+// replaySendRetry is the back-off between sends to a full frame channel
+const replaySendRetry = 2 * time.Millisecond
+
+// ReplayStream implements StreamProvider by reading back a frame log
+//
+// Frames are delivered with their recorded Seq, Timestamp, TraceID and pixel
+// data, so a worker sees the same input as during the recorded incident.
+// Timing follows the recorded receive times (scaled by ReplayConfig.Speed),
+// or is dropped entirely with ReplayConfig.Unpaced for regression tests.
+//
+// Unlike live streams, frames are never dropped on a full channel: the send is
+// retried, so a slow consumer delays the replay instead of losing frames,
+// which keeps runs deterministic.
+// The channel closes at the end of the log (unless Loop is set) or on Stop().
+//
+// SetTargetFPS decimates on recorded time (a 10 FPS recording replayed at
+// target 5 FPS delivers every other frame). Pause discards frames while the
+// replay clock keeps running, like a live camera.
+type ReplayStream struct {
+	// Configuration
+	path     string
+	speed    float64
+	unpaced  bool
+	loop     bool
+	startSeq uint64
+
+	// Injected dependencies (see Option)
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+	shutdownTimeout time.Duration
+
+	// Frame output
+	frames chan Frame
+	mu     sync.RWMutex
+
+	// Lifecycle
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	playing  atomic.Bool // False once the end of the log is reached
+	stopping bool        // Stop is waiting for the replay goroutine (s.mu released)
+
+	// Statistics (atomic for thread-safety)
+	targetFPS     float64 // 0 = recorded rate
+	frameCount    uint64
+	framesAtStart uint64 // frameCount at the last Start (FPSReal covers the current run)
+	bytesRead     uint64
+	started       time.Time
+	lastFrameAt   time.Time
+	width         int // Dimensions of the last replayed frame
+	height        int
+	sourceStream  string
+
+	// Pause state (frames discarded, replay clock keeps running)
+	pause pauseState
+
+	// Shutdown protection (atomic flag to prevent double-close panic)
+	framesClosed atomic.Bool
+}
+
+// NewReplayStream creates a replay provider with fail-fast validation
+//
+// The frame log is opened once to check that it is readable and not empty
+// (and that StartSeq exists); it is reopened on every Start().
+func NewReplayStream(cfg ReplayConfig, opts ...Option) (*ReplayStream, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	speed := cfg.Speed
+	if speed == 0 {
+		speed = 1
+	}
+
+	s := &ReplayStream{
+		path:     cfg.Path,
+		speed:    speed,
+		unpaced:  cfg.Unpaced,
+		loop:     cfg.Loop,
+		startSeq: cfg.StartSeq,
+
+		logger:          options.logger,
+		clock:           options.clock,
+		frameBufferSize: options.frameBufferSize,
+		shutdownTimeout: options.shutdownTimeout,
+
+		frames: make(chan Frame, options.frameBufferSize),
+	}
+	s.pause.clock = options.clock
+
+	reader, first, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+	records := reader.Len()
+	recovered := reader.Recovered()
+	reader.Close()
+
+	s.logger.Info("stream-capture: replay stream created",
+		"path", s.path,
+		"records", records,
+		"recovered_index", recovered,
+		"first_record", first,
+		"speed", s.speed,
+		"unpaced", s.unpaced,
+		"loop", s.loop,
+	)
+
+	return s, nil
+}
+
+// open opens the frame log and locates the first record to replay
+func (s *ReplayStream) open() (*framelog.Reader, int, error) {
+	reader, err := framelog.Open(s.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	if reader.Len() == 0 {
+		reader.Close()
+		return nil, 0, fmt.Errorf("stream-capture: replay log %s has no frames", s.path)
+	}
+
+	first := 0
+	if s.startSeq > 0 {
+		i, ok := reader.Find(s.startSeq)
+		if !ok {
+			reader.Close()
+			return nil, 0, fmt.Errorf("stream-capture: replay start sequence %d not found in %s", s.startSeq, s.path)
+		}
+		first = i
+	}
+
+	return reader, first, nil
+}
+
+// Start opens the frame log and begins replaying it
+func (s *ReplayStream) Start(ctx context.Context) (<-chan Frame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel != nil {
+		return nil, fmt.Errorf("stream-capture: stream already started")
+	}
+	if s.stopping {
+		return nil, fmt.Errorf("stream-capture: stream is stopping")
+	}
+
+	reader, first, err := s.open()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create cancellable context
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.started = s.clock.Now()
+	atomic.StoreUint64(&s.framesAtStart, atomic.LoadUint64(&s.frameCount))
+	s.pause.reset()
+	s.playing.Store(true)
+
+	s.logger.Info("stream-capture: starting replay",
+		"path", s.path,
+		"first_record", first,
+		"speed", s.speed,
+	)
+
+	// Capture channel locally: Stop() replaces s.frames for restart
+	frames := s.frames
+	localCtx := s.ctx
+
+	s.wg.Add(1)
+	go s.play(localCtx, reader, first, frames)
+
+	return s.frames, nil
+}
+
+// play delivers records from first to the end of the log (wrapping if loop)
+func (s *ReplayStream) play(ctx context.Context, reader *framelog.Reader, first int, frames chan Frame) {
+	defer s.wg.Done()
+	defer reader.Close()
+
+	// Recorded receive times: previous record, last delivered frame, next due frame
+	var prev, last, next time.Time
+	for i := first; ; i++ {
+		if i == reader.Len() {
+			if !s.loop {
+				break
+			}
+			i = 0
+			prev, last, next = time.Time{}, time.Time{}, time.Time{}
+		}
+
+		entry := reader.Entry(i)
+
+		// Pace on recorded inter-frame time
+		if !s.unpaced && !prev.IsZero() {
+			if delay := time.Duration(float64(entry.Received.Sub(prev)) / s.speed); delay > 0 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.clock.After(delay):
+				}
+			}
+		}
+		prev = entry.Received
+
+		// Paused: replay clock keeps running, frames are discarded
+		if s.pause.paused.Load() {
+			if s.unpaced {
+				// No pacing to wait on: avoid spinning through the log
+				select {
+				case <-ctx.Done():
+					return
+				case <-s.clock.After(replayPausePoll):
+				}
+			}
+			continue
+		}
+
+		// Decimate to target FPS on recorded time (schedule-based, like MJPEGStream)
+		if interval := s.frameInterval(); interval > 0 {
+			// Target just set: schedule from the last delivered frame
+			if next.IsZero() && !last.IsZero() {
+				next = last.Add(interval)
+			}
+			if entry.Received.Before(next) {
+				continue
+			}
+			next = next.Add(interval)
+			if next.Before(entry.Received) {
+				next = entry.Received.Add(interval)
+			}
+		} else {
+			next = time.Time{}
+		}
+
+		rec, err := reader.Read(i)
+		if err != nil {
+			s.logger.Warn("stream-capture: skipping unreadable replay record",
+				"error", err,
+				"record", i,
+			)
+			continue
+		}
+
+		if err := s.emit(ctx, frames, rec); err != nil {
+			return
+		}
+		last = entry.Received
+	}
+
+	s.playing.Store(false)
+	s.logger.Info("stream-capture: replay finished",
+		"path", s.path,
+		"frames_replayed", atomic.LoadUint64(&s.frameCount),
+	)
+
+	// End of log: close the channel so consumers ranging over it terminate
+	if s.framesClosed.CompareAndSwap(false, true) {
+		close(frames)
+	}
+}
+
+// emit delivers a single record, waiting for room in the channel
+//
+// The send is attempted under s.mu so it cannot interleave with Pause():
+// once Pause has drained the channel, no frame of this run reaches it until
+// Resume. A frame pending while the stream is paused is discarded.
+func (s *ReplayStream) emit(ctx context.Context, frames chan<- Frame, rec framelog.Record) error {
+	frame := Frame{
+		Seq:          rec.Seq,
+		Timestamp:    rec.Timestamp,
+		Width:        rec.Width,
+		Height:       rec.Height,
+		Data:         rec.Data,
+		SourceStream: rec.SourceStream,
+		TraceID:      rec.TraceID,
+	}
+
+	for {
+		s.mu.Lock()
+		if s.pause.paused.Load() {
+			s.mu.Unlock()
+			return nil
+		}
+		select {
+		case frames <- frame:
+			atomic.AddUint64(&s.frameCount, 1)
+			atomic.AddUint64(&s.bytesRead, uint64(len(rec.Data)))
+			s.lastFrameAt = s.clock.Now()
+			s.width = rec.Width
+			s.height = rec.Height
+			s.sourceStream = rec.SourceStream
+			s.mu.Unlock()
+			return nil
+		default:
+		}
+		s.mu.Unlock()
+
+		// Channel full: slow consumer delays the replay
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.clock.After(replaySendRetry):
+		}
+	}
+}
+
+// frameInterval returns the current target frame period (0 = recorded rate)
+func (s *ReplayStream) frameInterval() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.targetFPS <= 0 {
+		return 0
+	}
+	return time.Duration(float64(time.Second) / s.targetFPS)
+}
+
+// Stop stops the replay and closes the frame channel
+//
+// Idempotent. The replay can be started again from the first record.
+func (s *ReplayStream) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		s.logger.Debug("stream-capture: stream not started, nothing to stop")
+		return nil
+	}
+
+	s.logger.Info("stream-capture: stopping replay")
+
+	// Cancel context to signal shutdown. This call owns the run from here:
+	// concurrent Stop calls see a stopped stream, Start waits for stopping.
+	s.cancel()
+	s.cancel = nil
+	s.ctx = nil
+	s.stopping = true
+	frames := s.frames
+
+	// Wait for the replay goroutine with timeout
+	// (play takes s.mu in emit, so release it while waiting)
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	s.mu.Unlock()
+	stopped := true
+	select {
+	case <-done:
+		s.logger.Debug("stream-capture: goroutines stopped cleanly")
+	case <-s.clock.After(s.shutdownTimeout):
+		stopped = false
+		s.logger.Warn("stream-capture: stop timeout exceeded, some goroutines may still be running")
+	}
+	s.mu.Lock()
+
+	// Close frame channel (protected against double-close, play may have closed it)
+	if stopped && s.framesClosed.CompareAndSwap(false, true) {
+		close(frames)
+	}
+
+	s.playing.Store(false)
+
+	s.logger.Info("stream-capture: replay stopped",
+		"frames_replayed", atomic.LoadUint64(&s.frameCount),
+		"uptime", s.clock.Since(s.started),
+	)
+
+	// Reset state for potential restart
+	s.stopping = false
+	s.frames = make(chan Frame, s.frameBufferSize)
+	s.framesClosed.Store(false)
+	s.pause.reset()
+
+	return nil
+}
+
+// Stats returns current replay statistics
+//
+// FPSTarget is 0 while frames are replayed at the recorded rate.
+// IsConnected is true while the replay is running and the end of the log
+// has not been reached.
+func (s *ReplayStream) Stats() StreamStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	frameCount := atomic.LoadUint64(&s.frameCount)
+
+	// Calculate real FPS (frames of the current run over its active time,
+	// paused time excluded; frameCount itself spans restarts)
+	pausedDuration := s.pause.duration()
+	var fpsReal float64
+	if !s.started.IsZero() {
+		activeTime := (s.clock.Since(s.started) - pausedDuration).Seconds()
+		if base := atomic.LoadUint64(&s.framesAtStart); activeTime > 0 && base <= frameCount {
+			fpsReal = float64(frameCount-base) / activeTime
+		}
+	}
+
+	// Calculate latency (time since last frame)
+	var latencyMS int64
+	if !s.lastFrameAt.IsZero() {
+		latencyMS = s.clock.Since(s.lastFrameAt).Milliseconds()
+	}
+
+	var resolution string
+	if s.width > 0 && s.height > 0 {
+		resolution = fmt.Sprintf("%dx%d", s.width, s.height)
+	}
+
+	return StreamStats{
+		FrameCount:     frameCount,
+		FPSTarget:      s.targetFPS,
+		FPSReal:        fpsReal,
+		LatencyMS:      latencyMS,
+		SourceStream:   s.sourceStream,
+		Resolution:     resolution,
+		BytesRead:      atomic.LoadUint64(&s.bytesRead),
+		IsConnected:    s.cancel != nil && s.playing.Load(),
+		IsPaused:       s.pause.paused.Load(),
+		PausedDuration: pausedDuration,
+	}
+}
+
+// SetTargetFPS decimates the replay to the given rate (recorded time)
+func (s *ReplayStream) SetTargetFPS(fps float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Validate FPS range
+	if fps < 0.1 || fps > 30 {
+		return fmt.Errorf(
+			"stream-capture: invalid FPS %.2f (must be 0.1-30)",
+			fps,
+		)
+	}
+
+	// Check if stream is running
+	if s.cancel == nil {
+		return fmt.Errorf("stream-capture: stream not running")
+	}
+
+	s.logger.Info("stream-capture: updating target FPS",
+		"old_fps", s.targetFPS,
+		"new_fps", fps,
+	)
+
+	s.targetFPS = fps
+
+	return nil
+}
+
+// Pause discards replayed frames until Resume()
+//
+// Frames already buffered in the output channel are drained.
+// Idempotent - calling Pause() on a paused stream is a no-op.
+func (s *ReplayStream) Pause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return fmt.Errorf("stream-capture: stream not running")
+	}
+
+	if !s.pause.pause() {
+		s.logger.Debug("stream-capture: stream already paused")
+		return nil
+	}
+
+	drained := drainFrames(s.frames)
+
+	s.logger.Info("stream-capture: replay paused",
+		"path", s.path,
+		"drained_frames", drained,
+	)
+
+	return nil
+}
+
+// Resume restarts frame delivery after Pause()
+//
+// Idempotent - calling Resume() on a stream that is not paused is a no-op.
+func (s *ReplayStream) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return fmt.Errorf("stream-capture: stream not running")
+	}
+
+	if !s.pause.resume() {
+		s.logger.Debug("stream-capture: stream not paused")
+		return nil
+	}
+
+	s.logger.Info("stream-capture: replay resumed",
+		"path", s.path,
+		"paused_total", s.pause.duration(),
+	)
+
+	return nil
+}
+
+// Warmup measures replay FPS stability over a specified duration
+//
+// Same semantics as RTSPStream.Warmup. With Unpaced replay the measured FPS
+// reflects consumer speed, not the recording.
+func (s *ReplayStream) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	s.mu.RLock()
+	if s.cancel == nil {
+		s.mu.RUnlock()
+		return nil, fmt.Errorf("stream-capture: stream not started")
+	}
+	frames := s.frames
+	s.mu.RUnlock()
+
+	return warmupFrames(ctx, frames, duration, s.clock, s.logger)
+}
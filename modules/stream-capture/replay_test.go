@@ -0,0 +1,488 @@
+package streamcapture
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/framelog"
+)
+
+// chanProvider is a minimal StreamProvider emitting frames from a channel
+type chanProvider struct {
+	frames  chan Frame
+	stopped bool
+	paused  bool
+}
+
+func (p *chanProvider) Start(ctx context.Context) (<-chan Frame, error) { return p.frames, nil }
+func (p *chanProvider) Stop() error                                     { p.stopped = true; return nil }
+func (p *chanProvider) Stats() StreamStats                              { return StreamStats{FrameCount: 42} }
+func (p *chanProvider) SetTargetFPS(fps float64) error                  { return nil }
+func (p *chanProvider) Pause() error                                    { p.paused = true; return nil }
+func (p *chanProvider) Resume() error                                   { p.paused = false; return nil }
+func (p *chanProvider) Warmup(ctx context.Context, d time.Duration) (*WarmupStats, error) {
+	return nil, nil
+}
+
+// testFrames returns n distinct 2x2 frames
+func testFrames(n int) []Frame {
+	frames := make([]Frame, n)
+	base := time.Unix(1700000000, 0)
+	for i := range frames {
+		frames[i] = Frame{
+			Seq:          uint64(i + 1),
+			Timestamp:    base.Add(time.Duration(i) * 100 * time.Millisecond),
+			Width:        2,
+			Height:       2,
+			Data:         bytes.Repeat([]byte{byte(i + 1)}, 2*2*3),
+			SourceStream: "LQ",
+			TraceID:      "trace-" + string(rune('a'+i)),
+		}
+	}
+	return frames
+}
+
+// recordFrames records frames through a Recorder (received 20ms apart)
+// and returns the log path
+func recordFrames(t *testing.T, frames []Frame) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "capture.flog")
+	w, err := framelog.Create(path)
+	if err != nil {
+		t.Fatalf("framelog.Create() error = %v", err)
+	}
+
+	clk := &stepClock{now: time.Unix(1700000000, 0), step: 20 * time.Millisecond}
+	provider := &chanProvider{frames: make(chan Frame, len(frames))}
+	rec, err := NewRecorder(provider, w, WithClock(clk))
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	out, err := rec.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := rec.Start(context.Background()); err == nil {
+		t.Error("second Start() succeeded, want error")
+	}
+
+	for _, f := range frames {
+		provider.frames <- f
+	}
+	close(provider.frames)
+
+	for i := range frames {
+		got, ok := <-out
+		if !ok {
+			t.Fatalf("recorder channel closed after %d frames, want %d", i, len(frames))
+		}
+		assertFrame(t, got, frames[i])
+	}
+	if _, ok := <-out; ok {
+		t.Error("recorder channel still open after provider closed")
+	}
+
+	if rec.Stats().FrameCount != 42 {
+		t.Error("Stats() not forwarded to wrapped provider")
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if !provider.stopped {
+		t.Error("Stop() not forwarded to wrapped provider")
+	}
+	if err := rec.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+	if rec.Recorded() != uint64(len(frames)) {
+		t.Errorf("Recorded() = %d, want %d", rec.Recorded(), len(frames))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Writer.Close() error = %v", err)
+	}
+
+	return path
+}
+
+// stepClock advances by step on every Now() call
+type stepClock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *stepClock) Now() time.Time {
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+func (c *stepClock) Since(t time.Time) time.Duration        { return c.now.Sub(t) }
+func (c *stepClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (c *stepClock) NewTicker(d time.Duration) *time.Ticker { return time.NewTicker(d) }
+
+func assertFrame(t *testing.T, got, want Frame) {
+	t.Helper()
+
+	if got.Seq != want.Seq || !got.Timestamp.Equal(want.Timestamp) || got.TraceID != want.TraceID {
+		t.Errorf("frame = seq %d ts %v trace %q, want seq %d ts %v trace %q",
+			got.Seq, got.Timestamp, got.TraceID, want.Seq, want.Timestamp, want.TraceID)
+	}
+	if got.Width != want.Width || got.Height != want.Height || got.SourceStream != want.SourceStream {
+		t.Errorf("frame %d = %dx%d %q, want %dx%d %q", want.Seq,
+			got.Width, got.Height, got.SourceStream, want.Width, want.Height, want.SourceStream)
+	}
+	if !bytes.Equal(got.Data, want.Data) {
+		t.Errorf("frame %d payload mismatch", want.Seq)
+	}
+}
+
+// collect reads frames until the channel closes or timeout
+func collect(t *testing.T, frames <-chan Frame, timeout time.Duration) []Frame {
+	t.Helper()
+
+	var got []Frame
+	deadline := time.After(timeout)
+	for {
+		select {
+		case f, ok := <-frames:
+			if !ok {
+				return got
+			}
+			got = append(got, f)
+		case <-deadline:
+			t.Fatalf("replay did not finish within %v (%d frames)", timeout, len(got))
+		}
+	}
+}
+
+func TestReplayConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     ReplayConfig
+		wantErr bool
+	}{
+		{"valid", ReplayConfig{Path: "x.flog"}, false},
+		{"valid speed", ReplayConfig{Path: "x.flog", Speed: 4}, false},
+		{"empty path", ReplayConfig{}, true},
+		{"negative speed", ReplayConfig{Path: "x.flog", Speed: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewReplayStream_FailFast(t *testing.T) {
+	path := recordFrames(t, testFrames(3))
+
+	if _, err := NewReplayStream(ReplayConfig{Path: filepath.Join(t.TempDir(), "missing.flog")}); err == nil {
+		t.Error("NewReplayStream() with missing file succeeded, want error")
+	}
+	if _, err := NewReplayStream(ReplayConfig{Path: path, StartSeq: 99}); err == nil {
+		t.Error("NewReplayStream() with unknown StartSeq succeeded, want error")
+	}
+}
+
+func TestRecorderReplay_BitExact(t *testing.T) {
+	frames := testFrames(5)
+	path := recordFrames(t, frames)
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Unpaced: true})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+
+	// Replay twice: restart must yield identical output
+	for run := 0; run < 2; run++ {
+		out, err := stream.Start(context.Background())
+		if err != nil {
+			t.Fatalf("Start() error = %v", err)
+		}
+
+		got := collect(t, out, 2*time.Second)
+		if len(got) != len(frames) {
+			t.Fatalf("run %d: replayed %d frames, want %d", run, len(got), len(frames))
+		}
+		for i := range frames {
+			assertFrame(t, got[i], frames[i])
+		}
+
+		stats := stream.Stats()
+		if stats.IsConnected {
+			t.Error("Stats().IsConnected = true after end of log")
+		}
+		if stats.Resolution != "2x2" || stats.SourceStream != "LQ" {
+			t.Errorf("Stats() resolution/source = %q %q, want 2x2 LQ", stats.Resolution, stats.SourceStream)
+		}
+
+		if err := stream.Stop(); err != nil {
+			t.Fatalf("Stop() error = %v", err)
+		}
+	}
+
+	if got := stream.Stats().FrameCount; got != uint64(2*len(frames)) {
+		t.Errorf("Stats().FrameCount = %d, want %d", got, 2*len(frames))
+	}
+}
+
+func TestReplayStream_Timing(t *testing.T) {
+	// 5 frames recorded 20ms apart = 80ms of recorded time
+	path := recordFrames(t, testFrames(5))
+
+	tests := []struct {
+		name     string
+		speed    float64
+		min, max time.Duration
+	}{
+		{"original", 1, 70 * time.Millisecond, time.Second},
+		{"accelerated", 4, 10 * time.Millisecond, 70 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := NewReplayStream(ReplayConfig{Path: path, Speed: tt.speed})
+			if err != nil {
+				t.Fatalf("NewReplayStream() error = %v", err)
+			}
+			defer stream.Stop()
+
+			start := time.Now()
+			out, err := stream.Start(context.Background())
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			got := collect(t, out, 2*time.Second)
+			elapsed := time.Since(start)
+
+			if len(got) != 5 {
+				t.Fatalf("replayed %d frames, want 5", len(got))
+			}
+			if elapsed < tt.min || elapsed > tt.max {
+				t.Errorf("replay took %v, want between %v and %v", elapsed, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestReplayStream_StartSeqAndLoop(t *testing.T) {
+	frames := testFrames(4)
+	path := recordFrames(t, frames)
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Unpaced: true, Loop: true, StartSeq: 3})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+	defer stream.Stop()
+
+	out, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// StartSeq 3, then wrap to the beginning
+	wantSeqs := []uint64{3, 4, 1, 2, 3, 4, 1}
+	for i, want := range wantSeqs {
+		select {
+		case f := <-out:
+			if f.Seq != want {
+				t.Fatalf("frame %d seq = %d, want %d", i, f.Seq, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for frame %d", i)
+		}
+	}
+
+	if !stream.Stats().IsConnected {
+		t.Error("Stats().IsConnected = false while looping")
+	}
+}
+
+func TestReplayStream_PauseAndTargetFPS(t *testing.T) {
+	path := recordFrames(t, testFrames(4))
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Unpaced: true})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+
+	if err := stream.SetTargetFPS(10); err == nil {
+		t.Error("SetTargetFPS() before Start succeeded, want error")
+	}
+	if err := stream.Pause(); err == nil {
+		t.Error("Pause() before Start succeeded, want error")
+	}
+
+	// Frames recorded 20ms apart (50 FPS): target 25 FPS keeps every other frame
+	out, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stream.SetTargetFPS(25); err != nil {
+		t.Fatalf("SetTargetFPS() error = %v", err)
+	}
+	if err := stream.SetTargetFPS(100); err == nil {
+		t.Error("SetTargetFPS(100) succeeded, want error")
+	}
+	if err := stream.Pause(); err != nil {
+		t.Fatalf("Pause() error = %v", err)
+	}
+	if !stream.Stats().IsPaused {
+		t.Error("Stats().IsPaused = false after Pause")
+	}
+	if err := stream.Resume(); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	stream.Stop()
+
+	// Decimation is applied from the first record when set before playback
+	stream, err = NewReplayStream(ReplayConfig{Path: path, Speed: 1})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+	defer stream.Stop()
+
+	out, err = stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := stream.SetTargetFPS(25); err != nil {
+		t.Fatalf("SetTargetFPS() error = %v", err)
+	}
+
+	got := collect(t, out, 2*time.Second)
+	if len(got) != 2 || got[0].Seq != 1 || got[1].Seq != 3 {
+		seqs := make([]uint64, len(got))
+		for i, f := range got {
+			seqs[i] = f.Seq
+		}
+		t.Errorf("replayed seqs = %v, want [1 3]", seqs)
+	}
+}
+
+// TestReplayStream_PauseStopsDelivery verifies a replay blocked on a full
+// channel delivers nothing after Pause returns
+func TestReplayStream_PauseStopsDelivery(t *testing.T) {
+	path := recordFrames(t, testFrames(4))
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Unpaced: true, Loop: true}, WithFrameBufferSize(2))
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+	defer stream.Stop()
+
+	out, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	// Pause while the replay waits on a full channel, many times over:
+	// a pending send must never land after the drain
+	for i := 0; i < 50; i++ {
+		deadline := time.Now().Add(2 * time.Second)
+		for len(out) < cap(out) {
+			if time.Now().After(deadline) {
+				t.Fatalf("cycle %d: replay did not fill the frame channel", i)
+			}
+			time.Sleep(100 * time.Microsecond)
+		}
+
+		if err := stream.Pause(); err != nil {
+			t.Fatalf("Pause() error = %v", err)
+		}
+		select {
+		case f := <-out:
+			t.Fatalf("cycle %d: frame seq %d delivered after Pause()", i, f.Seq)
+		case <-time.After(5 * time.Millisecond):
+		}
+		if err := stream.Resume(); err != nil {
+			t.Fatalf("Resume() error = %v", err)
+		}
+	}
+}
+
+// TestReplayStream_FPSRealAfterRestart verifies FPSReal covers the current
+// run, not frames replayed before the last Start
+func TestReplayStream_FPSRealAfterRestart(t *testing.T) {
+	path := recordFrames(t, testFrames(4))
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Speed: 1})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+	defer stream.Stop()
+
+	atomic.StoreUint64(&stream.frameCount, 10000) // long lifetime of earlier runs
+	out, err := stream.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	collect(t, out, 2*time.Second)
+	time.Sleep(200 * time.Millisecond)
+
+	// 4 frames over at least 200ms
+	if fps := stream.Stats().FPSReal; fps > 20 {
+		t.Errorf("FPSReal after restart = %.1f, want <= 20", fps)
+	}
+}
+
+// TestReplayStream_ConcurrentStopStart verifies Stop owns the run it tears down
+//
+// A concurrent Stop must not close the channel of a later Start, and a Start
+// racing Stop must not have its channel closed under a live replay goroutine.
+func TestReplayStream_ConcurrentStopStart(t *testing.T) {
+	path := recordFrames(t, testFrames(4))
+
+	stream, err := NewReplayStream(ReplayConfig{Path: path, Speed: 1, Loop: true})
+	if err != nil {
+		t.Fatalf("NewReplayStream() error = %v", err)
+	}
+	defer stream.Stop()
+
+	for i := 0; i < 20; i++ {
+		first, err := stream.Start(context.Background())
+		if err != nil {
+			t.Fatalf("iteration %d: Start() error = %v", i, err)
+		}
+		channels := []<-chan Frame{first}
+
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+		for j := 0; j < 2; j++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				stream.Stop()
+			}()
+			go func() {
+				defer wg.Done()
+				// Fails while stopping or already started; both are fine
+				if frames, err := stream.Start(context.Background()); err == nil {
+					mu.Lock()
+					channels = append(channels, frames)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if err := stream.Stop(); err != nil {
+			t.Fatalf("iteration %d: Stop() error = %v", i, err)
+		}
+
+		// Every channel handed out by Start is closed exactly once
+		for _, frames := range channels {
+			collect(t, frames, 3*time.Second)
+		}
+	}
+}
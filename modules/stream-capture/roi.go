@@ -0,0 +1,587 @@
+package streamcapture
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/roi"
+)
+
+// OverviewROI is the name of the built-in full-frame ROI returned by
+// ROIManager.Start
+const OverviewROI = "overview"
+
+// Rect is a region of a frame in normalized coordinates
+//
+// X and Y are the top-left corner, W and H the size, all as fractions of the
+// source frame (0-1), so a rectangle stays valid when the source resolution
+// changes (e.g. after a degradation step or failover to a substream).
+type Rect struct {
+	X, Y, W, H float64
+}
+
+// FullFrame covers the whole source frame
+var FullFrame = Rect{X: 0, Y: 0, W: 1, H: 1}
+
+// Validate checks that the rectangle is non-empty and inside the frame
+func (r Rect) Validate() error {
+	if r.X < 0 || r.Y < 0 || r.W <= 0 || r.H <= 0 || r.X+r.W > 1+1e-9 || r.Y+r.H > 1+1e-9 {
+		return fmt.Errorf("invalid rect %+v (must be non-empty and within 0-1)", r)
+	}
+	return nil
+}
+
+// ROIConfig configures one region-of-interest stream
+type ROIConfig struct {
+	// Name identifies the ROI in logs, stats and Frame.SourceStream (required, unique)
+	Name string
+	// Rect is the region of the source frame (zero value = FullFrame)
+	Rect Rect
+	// Width and Height are the output size in pixels. 0x0 keeps the crop at
+	// source resolution (digital zoom without scaling); if only one is set,
+	// the other follows the crop's aspect ratio
+	Width  int
+	Height int
+	// TargetFPS limits the ROI frame rate (0.1 - 30.0)
+	// Set to 0 to receive every source frame
+	TargetFPS float64
+	// BufferSize is the ROI channel capacity (default: WithFrameBufferSize, 10)
+	// Set to 0 to use default value
+	BufferSize int
+}
+
+// Validate checks if the ROI configuration is valid
+func (c ROIConfig) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("ROI name is required")
+	}
+	if c.Rect != (Rect{}) {
+		if err := c.Rect.Validate(); err != nil {
+			return fmt.Errorf("ROI %q: %w", c.Name, err)
+		}
+	}
+	if c.Width < 0 || c.Height < 0 {
+		return fmt.Errorf("ROI %q: invalid size %dx%d", c.Name, c.Width, c.Height)
+	}
+	if c.TargetFPS != 0 && (c.TargetFPS < 0.1 || c.TargetFPS > 30) {
+		return fmt.Errorf("ROI %q: invalid FPS %.2f (must be 0.1-30)", c.Name, c.TargetFPS)
+	}
+	if c.BufferSize < 0 {
+		return fmt.Errorf("ROI %q: invalid buffer size %d (must be >= 0)", c.Name, c.BufferSize)
+	}
+	return nil
+}
+
+// rect returns the configured rectangle (FullFrame if unset)
+func (c ROIConfig) rect() Rect {
+	if c.Rect == (Rect{}) {
+		return FullFrame
+	}
+	return c.Rect
+}
+
+// ROIStats contains per-ROI delivery statistics
+type ROIStats struct {
+	// Name is the ROI name
+	Name string
+	// Rect is the current region
+	Rect Rect
+	// Width and Height are the size of the last delivered frame
+	Width  int
+	Height int
+	// TargetFPS is the configured rate limit (0 = source rate)
+	TargetFPS float64
+	// Delivered is the number of frames placed in the channel
+	Delivered uint64
+	// Skipped is the number of source frames skipped by the rate limit
+	Skipped uint64
+	// Dropped is the number of frames lost because the channel was full
+	Dropped uint64
+}
+
+// ROIStream is one derived stream of an ROIManager
+//
+// Its configuration can be changed at any time with Update; the next source
+// frame uses the new region, size and rate.
+type ROIStream struct {
+	m      *ROIManager
+	name   string
+	cfg    atomic.Pointer[ROIConfig]
+	frames chan Frame
+	closed bool // Guarded by m.mu
+
+	// Fan-out state (only touched by the fan-out goroutine, under m.mu)
+	applied *ROIConfig // Config the schedule below was computed for
+	next    time.Time  // Earliest source timestamp of the next delivery
+
+	width     atomic.Int64
+	height    atomic.Int64
+	delivered uint64
+	skipped   uint64
+	dropped   uint64
+}
+
+// ROIManager derives region-of-interest streams from one StreamProvider
+//
+// Every ROI is cropped and scaled in Go from the frame the provider already
+// decoded, so a high-resolution crop of a bed area and a low-resolution
+// overview share one RTSP connection and one decoder. Configure the provider
+// at the highest resolution any ROI needs.
+//
+// ROIManager is itself a StreamProvider: Start returns the channel of the
+// built-in OverviewROI (full frame, source size, every frame), which can be
+// downscaled like any other ROI:
+//
+//	m, _ := streamcapture.NewROIManager(stream) // stream at 1080p
+//	overview, _ := m.Start(ctx)
+//	m.ROI(streamcapture.OverviewROI).Update(streamcapture.ROIConfig{Width: 640})
+//	bed, _ := m.AddROI(streamcapture.ROIConfig{
+//	    Name:      "bed-1",
+//	    Rect:      streamcapture.Rect{X: 0.5, Y: 0.3, W: 0.4, H: 0.5},
+//	    TargetFPS: 1,
+//	})
+//	go consume(bed.Frames())
+//
+// ROI frames keep the source Seq, Timestamp, TraceID and TraceParent; SourceStream is
+// "<source>/<roi name>". The overview at source size shares Frame.Data with
+// the provider (read-only). ROI channels close on Stop() or when the
+// provider's channel closes.
+type ROIManager struct {
+	StreamProvider
+
+	// Injected dependencies (see Option)
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+
+	// ROIs (fan-out holds mu while cropping and delivering a frame)
+	mu   sync.Mutex
+	rois []*ROIStream
+
+	// Lifecycle (serializes Start/Stop; done is guarded by lifecycle)
+	lifecycle sync.Mutex
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewROIManager wraps provider for region-of-interest streams
+//
+// Options: WithLogger, WithClock (warmup), WithFrameBufferSize (default
+// ROI channel capacity).
+func NewROIManager(provider StreamProvider, opts ...Option) (*ROIManager, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("stream-capture: ROI manager requires a stream provider")
+	}
+
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	return &ROIManager{
+		StreamProvider:  provider,
+		logger:          options.logger,
+		clock:           options.clock,
+		frameBufferSize: options.frameBufferSize,
+	}, nil
+}
+
+// Start starts the wrapped provider and returns the overview channel
+//
+// ROIs added before Start receive frames from the first one.
+func (m *ROIManager) Start(ctx context.Context) (<-chan Frame, error) {
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+
+	if m.done != nil {
+		return nil, fmt.Errorf("stream-capture: stream already started")
+	}
+
+	in, err := m.StreamProvider.Start(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	overview := m.findLocked(OverviewROI)
+	if overview == nil {
+		overview = m.addLocked(ROIConfig{Name: OverviewROI})
+	}
+	rois := len(m.rois)
+	m.mu.Unlock()
+
+	m.logger.Info("stream-capture: ROI manager started",
+		"rois", rois,
+	)
+
+	m.done = make(chan struct{})
+	m.wg.Add(1)
+	go m.fanOut(in, m.done)
+
+	return overview.frames, nil
+}
+
+// fanOut derives and delivers each provider frame to every ROI
+func (m *ROIManager) fanOut(in <-chan Frame, done <-chan struct{}) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-done:
+			return
+		case frame, ok := <-in:
+			if !ok {
+				// Provider closed its channel: propagate to ROIs
+				m.mu.Lock()
+				m.closeAllLocked()
+				m.mu.Unlock()
+				return
+			}
+
+			m.mu.Lock()
+			for _, r := range m.rois {
+				r.offer(frame)
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+// AddROI creates a new ROI stream
+//
+// May be called before or after Start. Names must be unique (OverviewROI
+// is reserved once Start has created it).
+func (m *ROIManager) AddROI(cfg ROIConfig) (*ROIStream, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.findLocked(cfg.Name) != nil {
+		return nil, fmt.Errorf("stream-capture: ROI %q already exists", cfg.Name)
+	}
+
+	r := m.addLocked(cfg)
+
+	rect := cfg.rect()
+	m.logger.Info("stream-capture: ROI added",
+		"roi", cfg.Name,
+		"rect", fmt.Sprintf("%.3f,%.3f %.3fx%.3f", rect.X, rect.Y, rect.W, rect.H),
+		"size", fmt.Sprintf("%dx%d", cfg.Width, cfg.Height),
+		"target_fps", cfg.TargetFPS,
+	)
+
+	return r, nil
+}
+
+// ROI returns the ROI with the given name
+func (m *ROIManager) ROI(name string) (*ROIStream, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.findLocked(name)
+	return r, r != nil
+}
+
+// findLocked returns the ROI with the given name, or nil (m.mu held)
+func (m *ROIManager) findLocked(name string) *ROIStream {
+	for _, r := range m.rois {
+		if r.name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// addLocked creates and registers an ROI (m.mu held, cfg validated)
+func (m *ROIManager) addLocked(cfg ROIConfig) *ROIStream {
+	size := cfg.BufferSize
+	if size == 0 {
+		size = m.frameBufferSize
+	}
+
+	r := &ROIStream{
+		m:      m,
+		name:   cfg.Name,
+		frames: make(chan Frame, size),
+	}
+	r.cfg.Store(&cfg)
+	m.rois = append(m.rois, r)
+	return r
+}
+
+// closeAllLocked closes every ROI channel (m.mu held)
+func (m *ROIManager) closeAllLocked() {
+	for _, r := range m.rois {
+		if !r.closed {
+			r.closed = true
+			close(r.frames)
+		}
+	}
+	m.rois = nil
+}
+
+// Stop stops the wrapped provider and closes all ROI channels
+//
+// Idempotent.
+func (m *ROIManager) Stop() error {
+	m.lifecycle.Lock()
+	defer m.lifecycle.Unlock()
+
+	if m.done == nil {
+		return m.StreamProvider.Stop()
+	}
+
+	close(m.done)
+	m.done = nil
+	err := m.StreamProvider.Stop()
+
+	// fanOut takes m.mu per frame, so wait without holding it
+	m.wg.Wait()
+
+	m.mu.Lock()
+	stats := m.roiStatsLocked()
+	m.closeAllLocked()
+	m.mu.Unlock()
+
+	for _, s := range stats {
+		m.logger.Info("stream-capture: ROI closed",
+			"roi", s.Name,
+			"delivered", s.Delivered,
+			"dropped", s.Dropped,
+		)
+	}
+
+	return err
+}
+
+// Pause pauses the wrapped provider and drains every ROI channel
+func (m *ROIManager) Pause() error {
+	if err := m.StreamProvider.Pause(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.rois {
+		drainFrames(r.frames)
+	}
+	return nil
+}
+
+// Warmup measures FPS stability on the overview channel
+func (m *ROIManager) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	m.mu.Lock()
+	overview := m.findLocked(OverviewROI)
+	m.mu.Unlock()
+
+	if overview == nil {
+		return nil, fmt.Errorf("stream-capture: stream not started")
+	}
+	return overview.Warmup(ctx, duration)
+}
+
+// ROIs returns delivery statistics for every active ROI
+func (m *ROIManager) ROIs() []ROIStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.roiStatsLocked()
+}
+
+// roiStatsLocked snapshots ROI stats (m.mu held)
+func (m *ROIManager) roiStatsLocked() []ROIStats {
+	stats := make([]ROIStats, 0, len(m.rois))
+	for _, r := range m.rois {
+		stats = append(stats, r.Stats())
+	}
+	return stats
+}
+
+// offer derives the ROI frame and delivers it without blocking (m.mu held)
+//
+// A full channel drops the frame (latency over completeness, ADR-001).
+func (r *ROIStream) offer(frame Frame) {
+	cfg := r.cfg.Load()
+
+	if !r.due(cfg, frame.Timestamp) {
+		atomic.AddUint64(&r.skipped, 1)
+		return
+	}
+
+	out, ok := r.derive(cfg, frame)
+	if !ok {
+		atomic.AddUint64(&r.dropped, 1)
+		return
+	}
+
+	select {
+	case r.frames <- out:
+		atomic.AddUint64(&r.delivered, 1)
+		r.width.Store(int64(out.Width))
+		r.height.Store(int64(out.Height))
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+// due applies the ROI rate limit to a source frame timestamp
+//
+// Deliveries are scheduled every 1/TargetFPS of source time; after a gap
+// longer than one interval the schedule restarts from the current frame
+// (no burst to catch up).
+func (r *ROIStream) due(cfg *ROIConfig, ts time.Time) bool {
+	if r.applied != cfg {
+		r.applied = cfg
+		r.next = time.Time{}
+	}
+	if cfg.TargetFPS == 0 {
+		return true
+	}
+
+	interval := time.Duration(float64(time.Second) / cfg.TargetFPS)
+	if !r.next.IsZero() && ts.Before(r.next) {
+		return false
+	}
+	if r.next.IsZero() || ts.Sub(r.next) >= interval {
+		r.next = ts
+	}
+	r.next = r.next.Add(interval)
+	return true
+}
+
+// derive crops and scales frame to the ROI (false if frame data is malformed)
+func (r *ROIStream) derive(cfg *ROIConfig, frame Frame) (Frame, bool) {
+	if frame.Width <= 0 || frame.Height <= 0 {
+		return Frame{}, false
+	}
+
+	rect := cfg.rect()
+	x0, y0, x1, y1 := roi.PixelRect(rect.X, rect.Y, rect.W, rect.H, frame.Width, frame.Height)
+	width, height := outputSize(cfg.Width, cfg.Height, x1-x0, y1-y0)
+
+	out := frame
+	out.Width, out.Height = width, height
+	out.SourceStream = frame.SourceStream + "/" + r.name
+
+	// Full frame at source size: share the data (no copy)
+	if x0 == 0 && y0 == 0 && x1 == frame.Width && y1 == frame.Height &&
+		width == frame.Width && height == frame.Height {
+		return out, true
+	}
+
+	out.Data = roi.Extract(frame.Data, frame.Width, frame.Height, x0, y0, x1, y1, width, height)
+	return out, out.Data != nil
+}
+
+// outputSize resolves the configured size against a crop of cropW x cropH
+func outputSize(width, height, cropW, cropH int) (int, int) {
+	switch {
+	case width == 0 && height == 0:
+		return cropW, cropH
+	case height == 0:
+		return width, max(1, int(float64(width)*float64(cropH)/float64(cropW)+0.5))
+	case width == 0:
+		return max(1, int(float64(height)*float64(cropW)/float64(cropH)+0.5)), height
+	default:
+		return width, height
+	}
+}
+
+// Frames returns the ROI channel
+func (r *ROIStream) Frames() <-chan Frame {
+	return r.frames
+}
+
+// Name returns the ROI name
+func (r *ROIStream) Name() string {
+	return r.name
+}
+
+// Config returns the current ROI configuration
+func (r *ROIStream) Config() ROIConfig {
+	return *r.cfg.Load()
+}
+
+// Update replaces the region, output size and rate of the ROI
+//
+// Takes effect from the next source frame. Name and BufferSize cannot
+// change (cfg.Name may be empty; BufferSize is ignored).
+func (r *ROIStream) Update(cfg ROIConfig) error {
+	if cfg.Name == "" {
+		cfg.Name = r.name
+	}
+	if cfg.Name != r.name {
+		return fmt.Errorf("stream-capture: cannot rename ROI %q to %q", r.name, cfg.Name)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("stream-capture: %w", err)
+	}
+	cfg.BufferSize = cap(r.frames)
+
+	r.cfg.Store(&cfg)
+
+	rect := cfg.rect()
+	r.m.logger.Info("stream-capture: ROI updated",
+		"roi", r.name,
+		"rect", fmt.Sprintf("%.3f,%.3f %.3fx%.3f", rect.X, rect.Y, rect.W, rect.H),
+		"size", fmt.Sprintf("%dx%d", cfg.Width, cfg.Height),
+		"target_fps", cfg.TargetFPS,
+	)
+	return nil
+}
+
+// Stats returns delivery statistics for this ROI
+//
+// Thread-safe - uses atomic operations for counters.
+func (r *ROIStream) Stats() ROIStats {
+	cfg := r.cfg.Load()
+	return ROIStats{
+		Name:      r.name,
+		Rect:      cfg.rect(),
+		Width:     int(r.width.Load()),
+		Height:    int(r.height.Load()),
+		TargetFPS: cfg.TargetFPS,
+		Delivered: atomic.LoadUint64(&r.delivered),
+		Skipped:   atomic.LoadUint64(&r.skipped),
+		Dropped:   atomic.LoadUint64(&r.dropped),
+	}
+}
+
+// Warmup measures FPS stability on this ROI only
+func (r *ROIStream) Warmup(ctx context.Context, duration time.Duration) (*WarmupStats, error) {
+	return warmupFrames(ctx, r.frames, duration, r.m.clock, r.m.logger)
+}
+
+// Close detaches the ROI and closes its channel
+//
+// Idempotent. Other ROIs are not affected.
+func (r *ROIStream) Close() {
+	m := r.m
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	r.closed = true
+	close(r.frames)
+
+	for i, other := range m.rois {
+		if other == r {
+			m.rois = append(m.rois[:i], m.rois[i+1:]...)
+			break
+		}
+	}
+
+	m.logger.Info("stream-capture: ROI removed",
+		"roi", r.name,
+		"delivered", atomic.LoadUint64(&r.delivered),
+		"dropped", atomic.LoadUint64(&r.dropped),
+	)
+}
@@ -0,0 +1,258 @@
+package streamcapture
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// gradientFrames returns n w x h frames 100ms apart where red = x, green = y
+func gradientFrames(n, w, h int) []Frame {
+	data := make([]byte, w*h*3)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			i := (y*w + x) * 3
+			data[i], data[i+1] = byte(x), byte(y)
+		}
+	}
+
+	frames := make([]Frame, n)
+	base := time.Unix(1700000000, 0)
+	for i := range frames {
+		frames[i] = Frame{
+			Seq:          uint64(i + 1),
+			Timestamp:    base.Add(time.Duration(i) * 100 * time.Millisecond),
+			Width:        w,
+			Height:       h,
+			Data:         data,
+			SourceStream: "HQ",
+			TraceID:      "trace",
+		}
+	}
+	return frames
+}
+
+// waitOffered waits until r has been offered n source frames
+func waitOffered(t *testing.T, r *ROIStream, n uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		s := r.Stats()
+		if s.Delivered+s.Skipped+s.Dropped >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("ROI %s not offered %d frames: %+v", r.Name(), n, r.Stats())
+}
+
+func TestROIManager_CropAndScale(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	m, err := NewROIManager(provider)
+	if err != nil {
+		t.Fatalf("NewROIManager() error = %v", err)
+	}
+
+	// Right half at source resolution (digital zoom) and scaled to width 2
+	zoom, err := m.AddROI(ROIConfig{Name: "bed", Rect: Rect{X: 0.5, Y: 0, W: 0.5, H: 1}})
+	if err != nil {
+		t.Fatalf("AddROI() error = %v", err)
+	}
+
+	overview, err := m.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer m.Stop()
+
+	small, err := m.AddROI(ROIConfig{Name: "small", Rect: Rect{X: 0.5, Y: 0, W: 0.5, H: 1}, Width: 2})
+	if err != nil {
+		t.Fatalf("AddROI() error = %v", err)
+	}
+
+	src := gradientFrames(1, 8, 4)[0]
+	provider.frames <- src
+
+	full := receive(t, overview)
+	if full.Width != 8 || full.Height != 4 || &full.Data[0] != &src.Data[0] {
+		t.Errorf("overview = %dx%d (shared data: %v), want source frame passed through",
+			full.Width, full.Height, &full.Data[0] == &src.Data[0])
+	}
+
+	crop := receive(t, zoom.Frames())
+	if crop.Width != 4 || crop.Height != 4 || len(crop.Data) != 4*4*3 {
+		t.Fatalf("crop = %dx%d (%d bytes), want 4x4", crop.Width, crop.Height, len(crop.Data))
+	}
+	if crop.Data[0] != 4 || crop.Data[(3*4+3)*3] != 7 || crop.Data[(3*4+3)*3+1] != 3 {
+		t.Errorf("crop pixels do not match the right half of the source")
+	}
+	if crop.Seq != src.Seq || crop.TraceID != src.TraceID || crop.SourceStream != "HQ/bed" {
+		t.Errorf("crop metadata = seq %d trace %q source %q", crop.Seq, crop.TraceID, crop.SourceStream)
+	}
+
+	scaled := receive(t, small.Frames())
+	if scaled.Width != 2 || scaled.Height != 2 {
+		t.Errorf("scaled = %dx%d, want 2x2 (aspect ratio kept)", scaled.Width, scaled.Height)
+	}
+
+	stats := m.ROIs()
+	if len(stats) != 3 {
+		t.Fatalf("ROIs() = %d entries, want 3", len(stats))
+	}
+	for _, s := range stats {
+		if s.Delivered != 1 || s.Dropped != 0 {
+			t.Errorf("ROI %s delivered/dropped = %d/%d, want 1/0", s.Name, s.Delivered, s.Dropped)
+		}
+	}
+}
+
+func TestROIManager_TargetFPS(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	m, _ := NewROIManager(provider)
+	if _, err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer m.Stop()
+
+	// Source at 10 FPS, ROI at 2.5 FPS
+	r, _ := m.AddROI(ROIConfig{Name: "slow", TargetFPS: 2.5, BufferSize: 20})
+	for _, f := range gradientFrames(10, 4, 4) {
+		provider.frames <- f
+	}
+	waitOffered(t, r, 10)
+
+	var seqs []uint64
+	for len(r.Frames()) > 0 {
+		seqs = append(seqs, (<-r.Frames()).Seq)
+	}
+	want := []uint64{1, 5, 9}
+	if len(seqs) != len(want) {
+		t.Fatalf("delivered seqs %v, want %v", seqs, want)
+	}
+	for i := range want {
+		if seqs[i] != want[i] {
+			t.Errorf("delivered seqs %v, want %v", seqs, want)
+			break
+		}
+	}
+	if s := r.Stats(); s.Skipped != 7 {
+		t.Errorf("Skipped = %d, want 7", s.Skipped)
+	}
+}
+
+func TestROIStream_Update(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	m, _ := NewROIManager(provider)
+	overview, err := m.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer m.Stop()
+
+	r, _ := m.AddROI(ROIConfig{Name: "bed", Rect: Rect{X: 0, Y: 0, W: 0.5, H: 0.5}})
+	frames := gradientFrames(2, 8, 4)
+
+	provider.frames <- frames[0]
+	if f := receive(t, r.Frames()); f.Width != 4 || f.Height != 2 {
+		t.Fatalf("ROI = %dx%d, want 4x2", f.Width, f.Height)
+	}
+	receive(t, overview)
+
+	// Move the region and downscale the overview at runtime
+	if err := r.Update(ROIConfig{Rect: Rect{X: 0.75, Y: 0.5, W: 0.25, H: 0.5}, Width: 4, Height: 4}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	ov, _ := m.ROI(OverviewROI)
+	if err := ov.Update(ROIConfig{Width: 4}); err != nil {
+		t.Fatalf("overview Update() error = %v", err)
+	}
+
+	provider.frames <- frames[1]
+	f := receive(t, r.Frames())
+	if f.Width != 4 || f.Height != 4 || f.Data[0] < 6 || f.Data[1] < 2 {
+		t.Errorf("updated ROI = %dx%d first pixel %v, want 4x4 from the bottom-right corner",
+			f.Width, f.Height, f.Data[:3])
+	}
+	if f := receive(t, overview); f.Width != 4 || f.Height != 2 {
+		t.Errorf("overview = %dx%d after Update, want 4x2", f.Width, f.Height)
+	}
+
+	if got := r.Config(); got.Width != 4 || got.Name != "bed" {
+		t.Errorf("Config() = %+v", got)
+	}
+	if err := r.Update(ROIConfig{Name: "other"}); err == nil {
+		t.Error("Update() renaming the ROI succeeded, want error")
+	}
+	if err := r.Update(ROIConfig{Rect: Rect{X: 0.8, W: 0.5, H: 1}}); err == nil {
+		t.Error("Update() with rect outside the frame succeeded, want error")
+	}
+
+	r.Close()
+	r.Close() // idempotent
+	if _, ok := <-r.Frames(); ok {
+		t.Error("closed ROI channel still open")
+	}
+	if _, ok := m.ROI("bed"); ok {
+		t.Error("ROI() found closed ROI")
+	}
+}
+
+func TestROIManager_Invalid(t *testing.T) {
+	if _, err := NewROIManager(nil); err == nil {
+		t.Error("NewROIManager(nil) succeeded, want error")
+	}
+
+	m, _ := NewROIManager(&chanProvider{frames: make(chan Frame)})
+	tests := []struct {
+		name string
+		cfg  ROIConfig
+	}{
+		{"missing name", ROIConfig{}},
+		{"empty rect", ROIConfig{Name: "a", Rect: Rect{X: 0.1, Y: 0.1}}},
+		{"negative size", ROIConfig{Name: "a", Width: -1}},
+		{"invalid fps", ROIConfig{Name: "a", TargetFPS: 60}},
+		{"negative buffer", ROIConfig{Name: "a", BufferSize: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := m.AddROI(tt.cfg); err == nil {
+				t.Errorf("AddROI(%+v) succeeded, want error", tt.cfg)
+			}
+		})
+	}
+
+	if _, err := m.AddROI(ROIConfig{Name: "a"}); err != nil {
+		t.Fatalf("AddROI() error = %v", err)
+	}
+	if _, err := m.AddROI(ROIConfig{Name: "a"}); err == nil {
+		t.Error("AddROI() with duplicate name succeeded, want error")
+	}
+}
+
+func TestROIManager_ProviderChannelClosed(t *testing.T) {
+	provider := &chanProvider{frames: make(chan Frame)}
+	m, _ := NewROIManager(provider)
+	overview, err := m.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	r, _ := m.AddROI(ROIConfig{Name: "bed"})
+
+	close(provider.frames)
+
+	for _, ch := range []<-chan Frame{overview, r.Frames()} {
+		select {
+		case _, ok := <-ch:
+			if ok {
+				t.Error("received frame, want closed channel")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("ROI channel not closed after provider channel closed")
+		}
+	}
+
+	if err := m.Stop(); err != nil {
+		t.Errorf("Stop() error = %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package streamcapture
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sync"
@@ -9,7 +10,7 @@ import (
 	"time"
 
 	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsp"
-	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/warmup"
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
 	"github.com/tinyzimmer/go-gst/gst"
 	"github.com/tinyzimmer/go-gst/gst/app"
 )
@@ -18,23 +19,42 @@ const (
 	// defaultFrameBufferSize is the number of frames buffered in the output channel.
 	// This provides a small buffer to handle temporary processing delays without blocking
 	// the GStreamer pipeline, while keeping memory usage bounded.
+	// Override with WithFrameBufferSize.
 	defaultFrameBufferSize = 10
 
-	// shutdownTimeout is the maximum time to wait for graceful pipeline shutdown.
+	// defaultShutdownTimeout is the maximum time to wait for graceful pipeline shutdown.
 	// After this timeout, the pipeline is forcefully stopped to prevent hangs.
-	shutdownTimeout = 3 * time.Second
+	// Override with WithShutdownTimeout.
+	defaultShutdownTimeout = 3 * time.Second
+
+	// defaultProbeInterval is the time between primary URL probes while on a
+	// fallback URL. Override with FailoverConfig.ProbeInterval.
+	defaultProbeInterval = 30 * time.Second
+
+	// probeTimeout bounds a single primary URL probe (connect + OPTIONS)
+	probeTimeout = 5 * time.Second
 )
 
 // RTSPStream implements StreamProvider using GStreamer for RTSP streaming
 type RTSPStream struct {
 	// Configuration
-	rtspURL      string
+	rtspURL      string              // Active URL (primary or a fallback, see failover)
+	logURL       string              // rtspURL with credentials redacted (see RedactURL)
+	redact       func(string) string // Scrubs credentials from GStreamer messages
+	resolution   Resolution
 	width        int
 	height       int
 	targetFPS    float64
 	sourceStream string
 	acceleration HardwareAccel
 
+	// Injected dependencies (see Option)
+	logger          *slog.Logger
+	clock           Clock
+	frameBufferSize int
+	shutdownTimeout time.Duration
+	tracer          *trace.Tracer
+
 	// GStreamer pipeline elements (for hot-reload)
 	elements *rtsp.PipelineElements
 
@@ -43,9 +63,10 @@ type RTSPStream struct {
 	mu     sync.RWMutex
 
 	// Lifecycle
-	ctx    context.Context
-	cancel context.CancelFunc
-	wg     sync.WaitGroup
+	parentCtx context.Context // Context passed to Start (pipeline rebuilds, see Reconfigure)
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
 
 	// Statistics (atomic for thread-safety)
 	frameCount    uint64
@@ -53,7 +74,8 @@ type RTSPStream struct {
 	bytesRead     uint64
 	reconnects    uint32
 	started       time.Time
-	lastFrameAt   time.Time
+	framesAtStart uint64       // frameCount at the last Start/Restart (FPSReal covers the current run)
+	lastFrameAt   atomic.Int64 // UnixNano of last delivered frame (0 = none)
 
 	// Error telemetry (atomic for thread-safety)
 	errorsNetwork uint64 // Network-related errors (connection, timeout)
@@ -61,6 +83,9 @@ type RTSPStream struct {
 	errorsAuth    uint64 // Authentication/authorization errors
 	errorsUnknown uint64 // Unclassified errors
 
+	// Error details (bounded ring, see ErrorHistory)
+	errorHistory *rtsp.ErrorHistory
+
 	// VAAPI telemetry
 	usingVAAPI      bool                               // True if VAAPI pipeline is active
 	decodeLatencies atomic.Pointer[rtsp.LatencyWindow] // Lock-free latency tracking
@@ -69,6 +94,13 @@ type RTSPStream struct {
 	reconnectState *rtsp.ReconnectState
 	reconnectCfg   rtsp.ReconnectConfig
 
+	// URL failover (primary + FallbackURLs)
+	failover      *rtsp.Failover
+	probeInterval time.Duration
+
+	// Pause state (privacy mode: frames discarded at appsink, session kept alive)
+	pause pauseState
+
 	// Shutdown protection (atomic flag to prevent double-close panic)
 	framesClosed atomic.Bool
 }
@@ -79,14 +111,24 @@ type RTSPStream struct {
 //   - RTSP URL must not be empty
 //   - Target FPS must be between 0.1 and 30.0
 //   - Resolution must be valid (512p, 720p, 1080p)
+//   - Fallback URLs must not be empty, failover settings must be >= 0
+//   - Options must be valid (buffer size >= 1, shutdown timeout > 0)
+//
+// Options inject a logger, clock, frame buffer size and shutdown timeout
+// (see WithLogger, WithClock, WithFrameBufferSize, WithShutdownTimeout).
 //
 // Returns an error if validation fails or GStreamer is not available.
-func NewRTSPStream(cfg RTSPConfig) (*RTSPStream, error) {
+func NewRTSPStream(cfg RTSPConfig, opts ...Option) (*RTSPStream, error) {
 	// Fail-fast validation: Delegate to config (SRP)
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("stream-capture: %w", err)
 	}
 
+	options, err := applyOptions(opts)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: %w", err)
+	}
+
 	// Fail-fast validation: GStreamer availability
 	if err := checkGStreamerAvailable(); err != nil {
 		return nil, fmt.Errorf("stream-capture: GStreamer not available: %w", err)
@@ -113,27 +155,54 @@ func NewRTSPStream(cfg RTSPConfig) (*RTSPStream, error) {
 	if cfg.ReconnectMaxDelay > 0 {
 		reconnectCfg.MaxRetryDelay = cfg.ReconnectMaxDelay
 	}
+	reconnectCfg.Logger = options.logger
+	reconnectCfg.Clock = options.clock
+
+	probeInterval := cfg.Failover.ProbeInterval
+	if probeInterval == 0 {
+		probeInterval = defaultProbeInterval
+	}
+	failover := rtsp.NewFailover(append([]string{cfg.URL}, cfg.FallbackURLs...), rtsp.FailoverThresholds{
+		Network: cfg.Failover.NetworkFailures,
+		Codec:   cfg.Failover.CodecFailures,
+		Auth:    cfg.Failover.AuthFailures,
+		Unknown: cfg.Failover.UnknownFailures,
+	})
 
 	s := &RTSPStream{
 		rtspURL:      cfg.URL,
+		logURL:       RedactURL(cfg.URL),
+		redact:       newRedactor(cfg.URL),
+		resolution:   cfg.Resolution,
 		width:        width,
 		height:       height,
 		targetFPS:    cfg.TargetFPS,
 		sourceStream: cfg.SourceStream,
 		acceleration: cfg.Acceleration,
-		frames:       make(chan Frame, defaultFrameBufferSize),
+		frames:       make(chan Frame, options.frameBufferSize),
 		reconnectCfg: reconnectCfg,
 		reconnectState: &rtsp.ReconnectState{
 			Reconnects: new(uint32),
 		},
+		errorHistory:  rtsp.NewErrorHistory(rtsp.DefaultErrorHistorySize),
+		failover:      failover,
+		probeInterval: probeInterval,
+
+		logger:          options.logger,
+		clock:           options.clock,
+		frameBufferSize: options.frameBufferSize,
+		shutdownTimeout: options.shutdownTimeout,
+		tracer:          options.tracer,
 	}
+	s.pause.clock = options.clock
 
-	slog.Info("stream-capture: RTSP stream created",
-		"url", cfg.URL,
+	s.logger.Info("stream-capture: RTSP stream created",
+		"url", s.logURL,
 		"resolution", fmt.Sprintf("%dx%d", width, height),
 		"target_fps", cfg.TargetFPS,
 		"source_stream", cfg.SourceStream,
 		"acceleration", cfg.Acceleration.String(),
+		"fallback_urls", len(cfg.FallbackURLs),
 	)
 
 	return s, nil
@@ -152,6 +221,10 @@ func NewRTSPStream(cfg RTSPConfig) (*RTSPStream, error) {
 //
 // For production use, call WarmupStream() separately after Start() to
 // measure FPS stability before processing frames.
+//
+// Restartable: after Stop() (or a failed Start()), Start() can be called again
+// and returns a fresh channel. Statistics are preserved across restarts
+// (see ResetStats).
 func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -160,12 +233,50 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 		return nil, fmt.Errorf("stream-capture: stream already started")
 	}
 
-	// Create cancellable context
-	s.ctx, s.cancel = context.WithCancel(ctx)
-	s.started = time.Now()
+	s.started = s.clock.Now()
+	atomic.StoreUint64(&s.framesAtStart, atomic.LoadUint64(&s.frameCount))
+	s.pause.reset()
+
+	return s.startLocked(ctx)
+}
+
+// Restart stops the stream (if running) and starts it again
+//
+// Returns a fresh frame channel; the previous channel is closed. Statistics
+// are preserved (call ResetStats() first for a clean slate). The stop/start
+// sequence is atomic with respect to other lifecycle calls.
+//
+// Use this to recover from a fatal error (e.g. max reconnect attempts
+// exceeded) without constructing a new RTSPStream and re-wiring consumers.
+func (s *RTSPStream) Restart(ctx context.Context) (<-chan Frame, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.logger.Info("stream-capture: restarting RTSP stream", "url", s.logURL)
+
+	if s.cancel != nil {
+		s.stopLocked()
+	}
+
+	s.started = s.clock.Now()
+	atomic.StoreUint64(&s.framesAtStart, atomic.LoadUint64(&s.frameCount))
+	s.pause.reset()
+
+	return s.startLocked(ctx)
+}
 
-	slog.Info("stream-capture: starting RTSP stream",
-		"url", s.rtspURL,
+// startLocked builds the pipeline and launches the stream goroutines
+//
+// Caller must hold s.mu. On any failure, everything created so far is torn
+// down and the stream is left in the stopped state (Start can be retried).
+// Frames are sent to s.frames, so a rebuild (Reconfigure) keeps the
+// consumer's channel.
+func (s *RTSPStream) startLocked(ctx context.Context) (<-chan Frame, error) {
+	// Create cancellable context (only published to s on success)
+	runCtx, cancel := context.WithCancel(ctx)
+
+	s.logger.Info("stream-capture: starting RTSP stream",
+		"url", s.logURL,
 		"resolution", fmt.Sprintf("%dx%d", s.width, s.height),
 		"target_fps", s.targetFPS,
 	)
@@ -177,13 +288,14 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 		Height:       s.height,
 		TargetFPS:    s.targetFPS,
 		Acceleration: int(s.acceleration), // Pass acceleration mode
+		Logger:       s.logger,
 	}
 
 	elements, err := rtsp.CreatePipeline(pipelineCfg)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("stream-capture: failed to create pipeline: %w", err)
 	}
-	s.elements = elements
 
 	// Set VAAPI flag from pipeline detection
 	s.usingVAAPI = elements.UsingVAAPI
@@ -192,12 +304,17 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 	if s.usingVAAPI {
 		initialWindow := &rtsp.LatencyWindow{}
 		s.decodeLatencies.Store(initialWindow)
-		slog.Info("stream-capture: VAAPI hardware acceleration active, latency tracking enabled")
+		s.logger.Info("stream-capture: VAAPI hardware acceleration active, latency tracking enabled")
 	}
 
 	// Create internal frame channel for callbacks
 	// (avoids import cycle by using rtsp.Frame instead of streamcapture.Frame)
-	internalFrames := make(chan rtsp.Frame, 10)
+	//
+	// Never closed: the appsink callback (writer) may still fire until the
+	// pipeline reaches NULL, and the reader exits on context cancellation.
+	// Sized like the output channel: WithFrameBufferSize bounds the total
+	// frames queued between the appsink and the consumer.
+	internalFrames := make(chan rtsp.Frame, s.frameBufferSize)
 
 	// Set up callbacks
 	callbackCtx := &rtsp.CallbackContext{
@@ -208,6 +325,9 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 		Width:         s.width,
 		Height:        s.height,
 		SourceStream:  s.sourceStream,
+		Paused:        &s.pause.paused,
+		Logger:        s.logger,
+		Clock:         s.clock,
 	}
 
 	// Enable latency tracking if VAAPI is active
@@ -215,49 +335,7 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 		callbackCtx.DecodeLatencies = &s.decodeLatencies
 	}
 
-	// Launch goroutine to convert internal frames to public frames
-	// Capture ctx locally to avoid nil dereference during shutdown
-	localCtx := s.ctx
-	s.wg.Add(1)
-	go func() {
-		defer s.wg.Done()
-		defer close(internalFrames) // Ensure internal channel is closed on exit
-
-		for internalFrame := range internalFrames {
-			// Convert rtsp.Frame to streamcapture.Frame
-			publicFrame := Frame{
-				Seq:          internalFrame.Seq,
-				Timestamp:    internalFrame.Timestamp,
-				Width:        internalFrame.Width,
-				Height:       internalFrame.Height,
-				Data:         internalFrame.Data,
-				SourceStream: internalFrame.SourceStream,
-				TraceID:      internalFrame.TraceID,
-			}
-
-			// Update lastFrameAt timestamp (for latency metric)
-			s.mu.Lock()
-			s.lastFrameAt = time.Now()
-			s.mu.Unlock()
-
-			// Send to public channel (non-blocking with drop tracking)
-			select {
-			case s.frames <- publicFrame:
-				// Frame sent successfully
-			case <-localCtx.Done():
-				return
-			default:
-				// Channel full - drop frame and track metric
-				atomic.AddUint64(&s.framesDropped, 1)
-				slog.Debug("stream-capture: dropping frame, channel full",
-					"seq", publicFrame.Seq,
-					"trace_id", publicFrame.TraceID,
-				)
-			}
-		}
-	}()
-
-	s.elements.AppSink.SetCallbacks(&app.SinkCallbacks{
+	elements.AppSink.SetCallbacks(&app.SinkCallbacks{
 		NewSampleFunc: func(sink *app.Sink) gst.FlowReturn {
 			return rtsp.OnNewSample(sink, callbackCtx)
 		},
@@ -266,7 +344,7 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 	// Connect pad-added signal for rtspsrc dynamic pads
 	// We need to find the rtph264depay element to link to
 	var depayElement *gst.Element
-	pipelineElements, _ := s.elements.Pipeline.GetElements()
+	pipelineElements, _ := elements.Pipeline.GetElements()
 	for _, elem := range pipelineElements {
 		if elem.GetFactory() != nil && elem.GetFactory().GetName() == "rtph264depay" {
 			depayElement = elem
@@ -275,81 +353,255 @@ func (s *RTSPStream) Start(ctx context.Context) (<-chan Frame, error) {
 	}
 
 	if depayElement != nil {
-		s.elements.RTSPSrc.Connect("pad-added", func(self *gst.Element, srcPad *gst.Pad) {
-			rtsp.OnPadAdded(self, srcPad, depayElement)
+		elements.RTSPSrc.Connect("pad-added", func(self *gst.Element, srcPad *gst.Pad) {
+			rtsp.OnPadAdded(self, srcPad, depayElement, s.logger)
 		})
 	} else {
-		slog.Warn("stream-capture: rtph264depay element not found, pad-added callback not connected")
+		s.logger.Warn("stream-capture: rtph264depay element not found, pad-added callback not connected")
 	}
 
 	// Start pipeline
-	if err := s.elements.Pipeline.SetState(gst.StatePlaying); err != nil {
+	if err := elements.Pipeline.SetState(gst.StatePlaying); err != nil {
+		cancel()
+		if destroyErr := rtsp.DestroyPipeline(elements); destroyErr != nil {
+			s.logger.Error("stream-capture: failed to destroy pipeline after start failure", "error", destroyErr)
+		}
 		return nil, fmt.Errorf("stream-capture: failed to start pipeline: %w", err)
 	}
 
 	// Wait for pipeline to reach PLAYING state
-	bus := s.elements.Pipeline.GetPipelineBus()
+	bus := elements.Pipeline.GetPipelineBus()
 	msg := bus.TimedPop(5 * time.Second)
 	if msg != nil && msg.Type() == gst.MessageStateChanged {
 		_, newState := msg.ParseStateChanged()
 		if newState == gst.StatePlaying {
-			slog.Info("stream-capture: pipeline reached PLAYING state")
+			s.logger.Info("stream-capture: pipeline reached PLAYING state")
 		}
 	}
 
-	// Launch background goroutine for pipeline bus monitoring
-	s.wg.Add(1)
-	go s.runPipeline()
+	// Pipeline running: publish lifecycle state
+	s.parentCtx = ctx
+	s.ctx, s.cancel = runCtx, cancel
+	s.elements = elements
 
-	slog.Info("stream-capture: RTSP stream started",
-		"url", s.rtspURL,
+	// Goroutines receive ctx, channel and pipeline as parameters (not via s)
+	// so a later Stop()/Start() cycle cannot swap them underneath.
+	s.wg.Add(2)
+	go s.forwardFrames(runCtx, internalFrames, s.frames)
+	go s.runPipeline(runCtx, elements)
+
+	// On a fallback URL: watch for the primary to come back
+	if active, _ := s.failover.Active(); active != 0 {
+		s.wg.Add(1)
+		go s.probePrimary(runCtx)
+	}
+
+	s.logger.Info("stream-capture: RTSP stream started",
+		"url", s.logURL,
 		"note", "frames will arrive asynchronously once pipeline reaches PLAYING state",
 	)
 
 	return s.frames, nil
 }
 
+// forwardFrames converts internal frames to public frames until ctx is cancelled
+//
+// Sends are non-blocking: if the consumer is slow, frames are dropped and
+// counted (latency over completeness, ADR-001).
+func (s *RTSPStream) forwardFrames(ctx context.Context, internalFrames <-chan rtsp.Frame, frames chan<- Frame) {
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case internalFrame := <-internalFrames:
+			// Discard frames that crossed the appsink before Pause()
+			if s.pause.paused.Load() {
+				continue
+			}
+
+			// Convert rtsp.Frame to streamcapture.Frame
+			publicFrame := Frame{
+				Seq:          internalFrame.Seq,
+				Timestamp:    internalFrame.Timestamp,
+				Width:        internalFrame.Width,
+				Height:       internalFrame.Height,
+				Data:         internalFrame.Data,
+				SourceStream: internalFrame.SourceStream,
+				TraceID:      internalFrame.Trace.TraceID.String(),
+				TraceParent:  internalFrame.Trace.Traceparent(),
+			}
+
+			// Update lastFrameAt timestamp (for latency metric)
+			// Atomic: this goroutine must never contend on s.mu (Stop holds it while waiting)
+			s.lastFrameAt.Store(s.clock.Now().UnixNano())
+
+			// Send to public channel (non-blocking with drop tracking)
+			select {
+			case frames <- publicFrame:
+				recordCaptureSpan(s.tracer, s.clock, internalFrame.Trace, publicFrame, internalFrame.DecodedAt, false)
+			case <-ctx.Done():
+				return
+			default:
+				// Channel full - drop frame and track metric
+				atomic.AddUint64(&s.framesDropped, 1)
+				recordCaptureSpan(s.tracer, s.clock, internalFrame.Trace, publicFrame, internalFrame.DecodedAt, true)
+				s.logger.Debug("stream-capture: dropping frame, channel full",
+					"seq", publicFrame.Seq,
+					"trace_id", publicFrame.TraceID,
+				)
+			}
+		}
+	}
+}
+
 // runPipeline monitors the GStreamer pipeline bus for messages with reconnection
 //
 // This goroutine runs in the background and:
 //  1. Monitors pipeline bus for errors
 //  2. On error: triggers reconnection with exponential backoff
 //  3. On success: resets retry counter and continues
+//  4. With fallback URLs: hands over to switchURL once the active URL hits
+//     its failover threshold or exhausts its reconnect budget
 //
-// Runs until context is cancelled or max retries exceeded.
-func (s *RTSPStream) runPipeline() {
+// Runs until context is cancelled, max retries exceeded or a failover.
+func (s *RTSPStream) runPipeline(ctx context.Context, elements *rtsp.PipelineElements) {
 	defer s.wg.Done()
 
+	// A failover decision cancels the reconnect loop only (not the stream)
+	reconnectCtx, stopReconnect := context.WithCancel(ctx)
+	defer stopReconnect()
+
+	switchTo := -1
+	var switchReason string
+	lastFrames := atomic.LoadUint64(&s.frameCount)
+
 	// Use RunWithReconnect for automatic reconnection with exponential backoff
 	connectFn := func(ctx context.Context) error {
-		return s.monitorPipeline(ctx)
+		err := s.monitorPipeline(ctx, elements)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		// Frames delivered since the last failure restart the failure streak
+		frames := atomic.LoadUint64(&s.frameCount)
+		if next := s.failover.RecordFailure(failureCategory(err), frames != lastFrames); next >= 0 {
+			switchTo, switchReason = next, err.Error()
+			stopReconnect()
+		}
+		lastFrames = frames
+		return err
 	}
 
 	err := rtsp.RunWithReconnect(
-		s.ctx,
+		reconnectCtx,
 		connectFn,
 		s.reconnectCfg,
 		s.reconnectState,
 	)
 
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Reconnect budget exhausted before any failover threshold
+	if switchTo < 0 && err != nil {
+		if switchTo = s.failover.Next(); switchTo >= 0 {
+			switchReason = err.Error()
+		}
+	}
+	if switchTo >= 0 {
+		go s.switchURL(ctx, switchTo, switchReason)
+		return
+	}
+
 	if err != nil {
-		slog.Error("stream-capture: pipeline stopped after reconnection failure",
+		s.logger.Error("stream-capture: pipeline stopped after reconnection failure",
 			"error", err,
-			"rtsp_url", s.rtspURL,
+			"rtsp_url", s.logURL,
 			"resolution", fmt.Sprintf("%dx%d", s.width, s.height),
-			"uptime", time.Since(s.started),
+			"uptime", s.clock.Since(s.started),
 			"frames_processed", atomic.LoadUint64(&s.frameCount),
 			"reconnects", atomic.LoadUint32(s.reconnectState.Reconnects),
+			"hint", "call Restart() to recover",
 		)
 	}
 }
 
+// failureCategory extracts the error category from a monitor error
+//
+// End of stream and other non-bus errors count as unknown.
+func failureCategory(err error) rtsp.ErrorCategory {
+	var pipelineErr *rtsp.PipelineError
+	if errors.As(err, &pipelineErr) {
+		return pipelineErr.Category
+	}
+	return rtsp.ErrCategoryUnknown
+}
+
+// switchURL rebuilds the pipeline on another URL (failover or fail-back)
+//
+// Runs on its own goroutine, outside s.wg: the rebuild waits for the stream
+// goroutines, including the one that requested the switch. runCtx identifies
+// the pipeline generation; if the stream was stopped, restarted or rebuilt
+// in the meantime, the request is stale and ignored.
+func (s *RTSPStream) switchURL(runCtx context.Context, index int, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil || s.ctx != runCtx {
+		return
+	}
+
+	fromURL := s.logURL
+	s.setURLLocked(s.failover.Switch(index))
+
+	s.logger.Warn("stream-capture: switching RTSP URL",
+		"from", fromURL,
+		"to", s.logURL,
+		"primary", index == 0,
+		"reason", reason,
+		"failovers", s.failover.Failovers(),
+	)
+
+	// Error already logged; the stream is stopped (call Restart to recover)
+	_ = s.rebuildLocked()
+}
+
+// probePrimary checks the primary URL every probeInterval while a fallback
+// is active, and switches back once it answers
+func (s *RTSPStream) probePrimary(ctx context.Context) {
+	defer s.wg.Done()
+
+	primary := s.failover.Primary()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.clock.After(s.probeInterval):
+		}
+
+		if err := rtsp.ProbeURL(ctx, primary, probeTimeout); err != nil {
+			s.logger.Debug("stream-capture: primary URL still unavailable",
+				"url", RedactURL(primary),
+				"error", err,
+			)
+			continue
+		}
+
+		go s.switchURL(ctx, 0, "primary URL reachable again")
+		return
+	}
+}
+
 // monitorPipeline monitors the GStreamer pipeline bus for messages
 //
 // Returns an error if the pipeline encounters an error (triggers reconnection).
 // Returns nil if context is cancelled (graceful shutdown).
-func (s *RTSPStream) monitorPipeline(ctx context.Context) error {
-	if s.elements == nil || s.elements.Pipeline == nil {
+func (s *RTSPStream) monitorPipeline(ctx context.Context, elements *rtsp.PipelineElements) error {
+	if elements == nil || elements.Pipeline == nil {
 		return fmt.Errorf("pipeline not initialized")
 	}
 
@@ -359,21 +611,25 @@ func (s *RTSPStream) monitorPipeline(ctx context.Context) error {
 		Codec:   &s.errorsCodec,
 		Auth:    &s.errorsAuth,
 		Unknown: &s.errorsUnknown,
+		History: s.errorHistory,
 	}
 
 	// Prepare metrics
 	metrics := &rtsp.MonitorMetrics{
-		RTSPURL:        s.rtspURL,
+		RTSPURL:        s.logURL,
 		Resolution:     fmt.Sprintf("%dx%d", s.width, s.height),
 		FrameCount:     &s.frameCount,
 		ReconnectCount: s.reconnectState.Reconnects,
 		StartedAt:      s.started,
+		Logger:         s.logger,
+		Clock:          s.clock,
+		Redact:         s.redact,
 	}
 
 	// Delegate to monitor module
 	return rtsp.MonitorPipelineBus(
 		ctx,
-		s.elements.Pipeline,
+		elements.Pipeline,
 		errorCounters,
 		s.reconnectState,
 		metrics,
@@ -395,12 +651,44 @@ func (s *RTSPStream) Stop() error {
 	defer s.mu.Unlock()
 
 	if s.cancel == nil {
-		slog.Debug("stream-capture: stream not started, nothing to stop")
+		s.logger.Debug("stream-capture: stream not started, nothing to stop")
 		return nil
 	}
 
-	slog.Info("stream-capture: stopping RTSP stream")
+	s.stopLocked()
+	return nil
+}
+
+// stopLocked tears down a running stream (caller holds s.mu, s.cancel != nil)
+//
+// Stream goroutines never take s.mu, so holding it while waiting is safe.
+func (s *RTSPStream) stopLocked() {
+	s.logger.Info("stream-capture: stopping RTSP stream")
+
+	stopped := s.teardownLocked()
+
+	s.closeFramesLocked(stopped)
 
+	// Log statistics
+	frameCount := atomic.LoadUint64(&s.frameCount)
+	reconnects := atomic.LoadUint32(s.reconnectState.Reconnects)
+	uptime := s.clock.Since(s.started)
+
+	s.logger.Info("stream-capture: RTSP stream stopped",
+		"frames_captured", frameCount,
+		"reconnects", reconnects,
+		"uptime", uptime,
+	)
+
+	s.resetLocked()
+}
+
+// teardownLocked stops the stream goroutines and destroys the pipeline
+// (caller holds s.mu, s.cancel != nil)
+//
+// The frame channel is left open. Returns false if the goroutines did not
+// exit within the shutdown timeout.
+func (s *RTSPStream) teardownLocked() bool {
 	// Cancel context to signal shutdown
 	s.cancel()
 
@@ -411,48 +699,89 @@ func (s *RTSPStream) Stop() error {
 		close(done)
 	}()
 
+	stopped := true
 	select {
 	case <-done:
-		slog.Debug("stream-capture: goroutines stopped cleanly")
-	case <-time.After(shutdownTimeout):
-		slog.Warn("stream-capture: stop timeout exceeded, some goroutines may still be running")
+		s.logger.Debug("stream-capture: goroutines stopped cleanly")
+	case <-s.clock.After(s.shutdownTimeout):
+		stopped = false
+		s.logger.Warn("stream-capture: stop timeout exceeded, some goroutines may still be running")
 	}
 
 	// Destroy GStreamer pipeline
 	if s.elements != nil {
 		if err := rtsp.DestroyPipeline(s.elements); err != nil {
-			slog.Error("stream-capture: failed to destroy pipeline", "error", err)
+			s.logger.Error("stream-capture: failed to destroy pipeline", "error", err)
 		}
 		s.elements = nil
 	}
 
+	return stopped
+}
+
+// closeFramesLocked closes the frame channel (caller holds s.mu)
+func (s *RTSPStream) closeFramesLocked(stopped bool) {
 	// Close frame channel (protected against double-close)
-	// Use atomic CompareAndSwap to ensure channel is closed exactly once
-	if s.framesClosed.CompareAndSwap(false, true) {
+	// Use atomic CompareAndSwap to ensure channel is closed exactly once.
+	// Skipped if the forwarder is still running: a late send would panic.
+	if stopped && s.framesClosed.CompareAndSwap(false, true) {
 		close(s.frames)
-		slog.Debug("stream-capture: frame channel closed")
+		s.logger.Debug("stream-capture: frame channel closed")
 	} else {
-		slog.Debug("stream-capture: frame channel already closed, skipping")
+		s.logger.Debug("stream-capture: frame channel not closed (already closed or forwarder still running)")
 	}
+}
 
-	// Log statistics
-	frameCount := atomic.LoadUint64(&s.frameCount)
-	reconnects := atomic.LoadUint32(s.reconnectState.Reconnects)
-	uptime := time.Since(s.started)
-
-	slog.Info("stream-capture: RTSP stream stopped",
-		"frames_captured", frameCount,
-		"reconnects", reconnects,
-		"uptime", uptime,
-	)
-
-	// Reset state for potential restart
+// resetLocked resets lifecycle state for a later Start (caller holds s.mu)
+func (s *RTSPStream) resetLocked() {
 	s.cancel = nil
+	s.parentCtx = nil
 	s.ctx = nil
-	s.frames = make(chan Frame, defaultFrameBufferSize)
+	s.frames = make(chan Frame, s.frameBufferSize)
 	s.framesClosed.Store(false) // Reset flag for restart
+	s.pause.reset()
+	s.reconnectState.CurrentRetries = 0
+}
 
-	return nil
+// ResetStats clears all counters (frames, drops, bytes, reconnects, errors, latency,
+// failovers) and the error history
+//
+// Statistics are preserved across Stop()/Start() and Restart() by default so
+// that long-running telemetry is not lost on recovery. Call ResetStats() for a
+// clean slate (e.g. before a qualification run).
+//
+// Note: FrameCount also drives Frame.Seq, so sequence numbers restart from 1.
+// Safe to call at any time (atomic stores).
+func (s *RTSPStream) ResetStats() {
+	atomic.StoreUint64(&s.frameCount, 0)
+	atomic.StoreUint64(&s.framesAtStart, 0)
+	atomic.StoreUint64(&s.framesDropped, 0)
+	atomic.StoreUint64(&s.bytesRead, 0)
+	atomic.StoreUint32(s.reconnectState.Reconnects, 0)
+	atomic.StoreUint64(&s.errorsNetwork, 0)
+	atomic.StoreUint64(&s.errorsCodec, 0)
+	atomic.StoreUint64(&s.errorsAuth, 0)
+	atomic.StoreUint64(&s.errorsUnknown, 0)
+	s.lastFrameAt.Store(0)
+	s.errorHistory.Reset()
+	s.failover.ResetFailovers()
+
+	if s.decodeLatencies.Load() != nil {
+		s.decodeLatencies.Store(&rtsp.LatencyWindow{})
+	}
+
+	s.logger.Info("stream-capture: statistics reset", "url", s.logURL)
+}
+
+// ErrorHistory returns the most recent errors, oldest first
+//
+// Bounded to the last 16 errors (rtsp.DefaultErrorHistorySize); older
+// entries are overwritten. Each record carries the GStreamer element,
+// GError domain/code, message, debug string and reconnect attempt.
+// The same data is available in Stats().RecentErrors.
+func (s *RTSPStream) ErrorHistory() []ErrorRecord {
+	recent, _ := errorHistoryStats(s.errorHistory)
+	return recent
 }
 
 // Stats returns current stream statistics
@@ -467,12 +796,18 @@ func (s *RTSPStream) Stats() StreamStats {
 	bytesRead := atomic.LoadUint64(&s.bytesRead)
 	reconnects := atomic.LoadUint32(s.reconnectState.Reconnects)
 
-	// Calculate real FPS
+	// Calculate real FPS (frames of the current run over its active time,
+	// paused time excluded; frameCount itself spans restarts)
+	pausedDuration := s.pause.duration()
 	var fpsReal float64
 	if !s.started.IsZero() {
-		uptime := time.Since(s.started).Seconds()
-		if uptime > 0 {
-			fpsReal = float64(frameCount) / uptime
+		runFrames := frameCount
+		if base := atomic.LoadUint64(&s.framesAtStart); base <= frameCount {
+			runFrames -= base
+		}
+		activeTime := (s.clock.Since(s.started) - pausedDuration).Seconds()
+		if activeTime > 0 {
+			fpsReal = float64(runFrames) / activeTime
 		}
 	}
 
@@ -485,8 +820,8 @@ func (s *RTSPStream) Stats() StreamStats {
 
 	// Calculate latency (time since last frame)
 	var latencyMS int64
-	if !s.lastFrameAt.IsZero() {
-		latencyMS = time.Since(s.lastFrameAt).Milliseconds()
+	if lastFrameAt := s.lastFrameAt.Load(); lastFrameAt != 0 {
+		latencyMS = s.clock.Since(time.Unix(0, lastFrameAt)).Milliseconds()
 	}
 
 	// Determine connection status
@@ -497,6 +832,7 @@ func (s *RTSPStream) Stats() StreamStats {
 	errorsCodec := atomic.LoadUint64(&s.errorsCodec)
 	errorsAuth := atomic.LoadUint64(&s.errorsAuth)
 	errorsUnknown := atomic.LoadUint64(&s.errorsUnknown)
+	recentErrors, lastError := errorHistoryStats(s.errorHistory)
 
 	// Calculate VAAPI decode latency stats (lock-free read)
 	var decodeMean, decodeP95, decodeMax float64
@@ -508,17 +844,19 @@ func (s *RTSPStream) Stats() StreamStats {
 	}
 
 	return StreamStats{
-		FrameCount:    frameCount,
-		FramesDropped: framesDropped,
-		DropRate:      dropRate,
-		FPSTarget:     s.targetFPS,
-		FPSReal:       fpsReal,
-		LatencyMS:     latencyMS,
-		SourceStream:  s.sourceStream,
-		Resolution:    fmt.Sprintf("%dx%d", s.width, s.height),
-		Reconnects:    reconnects,
-		BytesRead:     bytesRead,
-		IsConnected:   isConnected,
+		FrameCount:          frameCount,
+		FramesDropped:       framesDropped,
+		DropRate:            dropRate,
+		FPSTarget:           s.targetFPS,
+		FPSReal:             fpsReal,
+		LatencyMS:           latencyMS,
+		SourceStream:        s.sourceStream,
+		Resolution:          fmt.Sprintf("%dx%d", s.width, s.height),
+		Reconnects:          reconnects,
+		BytesRead:           bytesRead,
+		IsConnected:         isConnected,
+		IsPaused:            s.pause.paused.Load(),
+		PausedDuration:      pausedDuration,
 		ErrorsNetwork:       errorsNetwork,
 		ErrorsCodec:         errorsCodec,
 		ErrorsAuth:          errorsAuth,
@@ -527,6 +865,10 @@ func (s *RTSPStream) Stats() StreamStats {
 		DecodeLatencyP95MS:  decodeP95,
 		DecodeLatencyMaxMS:  decodeMax,
 		UsingVAAPI:          s.usingVAAPI,
+		LastError:           lastError,
+		RecentErrors:        recentErrors,
+		ActiveURL:           s.logURL,
+		Failovers:           s.failover.Failovers(),
 	}
 }
 
@@ -551,9 +893,15 @@ func (s *RTSPStream) SetTargetFPS(fps float64) error {
 		return fmt.Errorf("stream-capture: stream not running")
 	}
 
+	return s.setTargetFPSLocked(fps)
+}
+
+// setTargetFPSLocked hot-reloads the framerate caps (caller holds s.mu,
+// pipeline running, fps validated)
+func (s *RTSPStream) setTargetFPSLocked(fps float64) error {
 	oldFPS := s.targetFPS
 
-	slog.Info("stream-capture: updating target FPS",
+	s.logger.Info("stream-capture: updating target FPS",
 		"old_fps", oldFPS,
 		"new_fps", fps,
 	)
@@ -572,7 +920,7 @@ func (s *RTSPStream) SetTargetFPS(fps float64) error {
 	case err := <-errChan:
 		if err != nil {
 			// Explicit rollback to previous FPS
-			slog.Warn("stream-capture: FPS update failed, attempting rollback",
+			s.logger.Warn("stream-capture: FPS update failed, attempting rollback",
 				"error", err,
 				"old_fps", oldFPS,
 				"failed_fps", fps,
@@ -580,7 +928,7 @@ func (s *RTSPStream) SetTargetFPS(fps float64) error {
 
 			rollbackErr := rtsp.UpdateFramerateCaps(s.elements.CapsFilter, oldFPS, s.width, s.height)
 			if rollbackErr != nil {
-				slog.Error("stream-capture: rollback failed, pipeline may be in inconsistent state",
+				s.logger.Error("stream-capture: rollback failed, pipeline may be in inconsistent state",
 					"rollback_error", rollbackErr,
 					"original_error", err,
 				)
@@ -591,14 +939,14 @@ func (s *RTSPStream) SetTargetFPS(fps float64) error {
 
 	case <-updateCtx.Done():
 		// Timeout exceeded - attempt rollback
-		slog.Error("stream-capture: FPS update timeout (>5s), attempting rollback",
+		s.logger.Error("stream-capture: FPS update timeout (>5s), attempting rollback",
 			"old_fps", oldFPS,
 			"failed_fps", fps,
 		)
 
 		rollbackErr := rtsp.UpdateFramerateCaps(s.elements.CapsFilter, oldFPS, s.width, s.height)
 		if rollbackErr != nil {
-			slog.Error("stream-capture: rollback failed after timeout",
+			s.logger.Error("stream-capture: rollback failed after timeout",
 				"rollback_error", rollbackErr,
 			)
 		}
@@ -609,13 +957,198 @@ func (s *RTSPStream) SetTargetFPS(fps float64) error {
 	// Update internal state
 	s.targetFPS = fps
 
-	slog.Info("stream-capture: target FPS updated successfully",
+	s.logger.Info("stream-capture: target FPS updated successfully",
 		"new_fps", fps,
 	)
 
 	return nil
 }
 
+// Settings returns the current source URL, resolution and target FPS
+//
+// Reflects the last successful Reconfigure/SetTargetFPS call. URL is the
+// primary URL even while a fallback is active (see Stats().ActiveURL).
+func (s *RTSPStream) Settings() StreamSettings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return StreamSettings{
+		URL:        s.failover.Primary(),
+		Resolution: s.resolution,
+		TargetFPS:  s.targetFPS,
+	}
+}
+
+// SetResolution changes the output resolution (pipeline rebuild)
+//
+// Shorthand for Reconfigure with only the resolution changed.
+func (s *RTSPStream) SetResolution(res Resolution) error {
+	settings := s.Settings()
+	settings.Resolution = res
+	return s.Reconfigure(settings)
+}
+
+// SetSourceURL switches the camera URL, e.g. to a substream (pipeline rebuild)
+//
+// Shorthand for Reconfigure with only the URL changed.
+func (s *RTSPStream) SetSourceURL(url string) error {
+	settings := s.Settings()
+	settings.URL = url
+	return s.Reconfigure(settings)
+}
+
+// Reconfigure applies a new source URL, resolution and target FPS
+//
+// This method:
+//  1. Validates the settings (same rules as RTSPConfig)
+//  2. If only the FPS changed: hot-reloads the capsfilter (see SetTargetFPS)
+//  3. Otherwise: tears down the pipeline and builds a new one, keeping the
+//     frame channel, statistics and pause state (~3s interruption)
+//
+// If the stream is not running, the settings are stored and used by the
+// next Start(). If the rebuild fails, the stream is stopped and its frame
+// channel closed (same as a fatal error; call Restart to recover).
+func (s *RTSPStream) Reconfigure(settings StreamSettings) error {
+	if err := settings.Validate(); err != nil {
+		return fmt.Errorf("stream-capture: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	primary := s.failover.Primary()
+	sourceChanged := settings.URL != primary || settings.Resolution != s.resolution
+	if !sourceChanged && settings.TargetFPS == s.targetFPS {
+		return nil
+	}
+
+	// Running and only FPS changed: no rebuild needed
+	if s.cancel != nil && !sourceChanged {
+		return s.setTargetFPSLocked(settings.TargetFPS)
+	}
+
+	// A new URL becomes the primary (fallbacks are kept); a resolution-only
+	// change stays on the active URL
+	oldLogURL := s.logURL
+	if settings.URL != primary {
+		s.failover.SetPrimary(settings.URL)
+		s.setURLLocked(settings.URL)
+	}
+	s.resolution = settings.Resolution
+	s.width, s.height = settings.Resolution.Dimensions()
+	s.targetFPS = settings.TargetFPS
+
+	if s.cancel == nil {
+		s.logger.Info("stream-capture: settings updated, applied on next start",
+			"url", s.logURL,
+			"resolution", fmt.Sprintf("%dx%d", s.width, s.height),
+			"target_fps", s.targetFPS,
+		)
+		return nil
+	}
+
+	s.logger.Info("stream-capture: rebuilding pipeline",
+		"old_url", oldLogURL,
+		"url", s.logURL,
+		"resolution", fmt.Sprintf("%dx%d", s.width, s.height),
+		"target_fps", s.targetFPS,
+	)
+
+	return s.rebuildLocked()
+}
+
+// rebuildLocked replaces the running pipeline with one built from the
+// current settings, keeping the frame channel (caller holds s.mu, running)
+//
+// On failure the stream is stopped and its frame channel closed.
+func (s *RTSPStream) rebuildLocked() error {
+	parent := s.parentCtx
+	stopped := s.teardownLocked()
+	s.cancel = nil
+	s.ctx = nil
+	s.reconnectState.CurrentRetries = 0 // Fresh reconnect budget for the new pipeline
+
+	if _, err := s.startLocked(parent); err != nil {
+		s.logger.Error("stream-capture: pipeline rebuild failed, stream stopped",
+			"error", err,
+			"url", s.logURL,
+		)
+		s.closeFramesLocked(stopped)
+		s.resetLocked()
+		return err
+	}
+
+	return nil
+}
+
+// setURLLocked makes url the active source URL (caller holds s.mu)
+func (s *RTSPStream) setURLLocked(url string) {
+	s.rtspURL = url
+	s.logURL = RedactURL(url)
+	s.redact = newRedactor(url)
+}
+
+// Pause stops frame delivery while keeping the RTSP session alive
+//
+// This method:
+//  1. Sets the paused flag read by the appsink callback (samples discarded)
+//  2. Drains frames already buffered in the output channel
+//
+// The pipeline stays in PLAYING state on purpose: putting a live rtspsrc into
+// PAUSED sends RTSP PAUSE, which many IP cameras handle poorly (session
+// timeouts, no resume), forcing the 3-10s reconnect this method exists to avoid.
+// Decode cost is still paid while paused; inference cost is not.
+//
+// Idempotent - calling Pause() on a paused stream is a no-op.
+func (s *RTSPStream) Pause() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return fmt.Errorf("stream-capture: stream not running")
+	}
+
+	if !s.pause.pause() {
+		s.logger.Debug("stream-capture: stream already paused")
+		return nil
+	}
+
+	drained := drainFrames(s.frames)
+
+	s.logger.Info("stream-capture: RTSP stream paused",
+		"url", s.logURL,
+		"drained_frames", drained,
+	)
+
+	return nil
+}
+
+// Resume restarts frame delivery after Pause()
+//
+// Frames flow again from the next decoded sample (no reconnect, no warmup needed).
+//
+// Idempotent - calling Resume() on a stream that is not paused is a no-op.
+func (s *RTSPStream) Resume() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return fmt.Errorf("stream-capture: stream not running")
+	}
+
+	if !s.pause.resume() {
+		s.logger.Debug("stream-capture: stream not paused")
+		return nil
+	}
+
+	s.logger.Info("stream-capture: RTSP stream resumed",
+		"url", s.logURL,
+		"paused_total", s.pause.duration(),
+	)
+
+	return nil
+}
+
 // Warmup measures stream FPS stability over a specified duration
 //
 // This method should be called after Start() to measure the real FPS and
@@ -652,35 +1185,7 @@ func (s *RTSPStream) Warmup(ctx context.Context, duration time.Duration) (*Warmu
 	}
 	s.mu.RUnlock()
 
-	// Delegate to warmup adapter (handles frame conversion and statistics)
-	internalStats, err := warmup.WarmupWithAdapter(
-		ctx,
-		s.frames,
-		duration,
-		func(f Frame) warmup.Frame {
-			return warmup.Frame{
-				Seq:       f.Seq,
-				Timestamp: f.Timestamp,
-			}
-		},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("stream-capture: warmup failed: %w", err)
-	}
-
-	// Convert internal stats to public stats (identical structure)
-	return &WarmupStats{
-		FramesReceived: internalStats.FramesReceived,
-		Duration:       internalStats.Duration,
-		FPSMean:        internalStats.FPSMean,
-		FPSStdDev:      internalStats.FPSStdDev,
-		FPSMin:         internalStats.FPSMin,
-		FPSMax:         internalStats.FPSMax,
-		IsStable:       internalStats.IsStable,
-		JitterMean:     internalStats.JitterMean,
-		JitterStdDev:   internalStats.JitterStdDev,
-		JitterMax:      internalStats.JitterMax,
-	}, nil
+	return warmupFrames(ctx, s.frames, duration, s.clock, s.logger)
 }
 
 // checkGStreamerAvailable checks if GStreamer is available
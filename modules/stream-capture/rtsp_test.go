@@ -2,8 +2,13 @@ package streamcapture
 
 import (
 	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/rtsp"
 )
 
 // TestRTSPStream_Stop_Idempotent verifies that Stop() can be called multiple times safely
@@ -99,6 +104,132 @@ func TestRTSPStream_Stop_AfterStart(t *testing.T) {
 	t.Log("✅ Multiple Stop() calls after Start() successful (no panic)")
 }
 
+// TestRTSPStream_StartStopCycles verifies the stream can be started and stopped
+// repeatedly without leaking goroutines or GStreamer pipelines
+//
+// The URL points at a closed port: with GStreamer available, Start() succeeds
+// (connection is asynchronous) and no frame ever arrives, which exercises the
+// shutdown path of an idle frame forwarder. Without a usable pipeline, Start()
+// fails and the test validates error-path cleanup instead.
+func TestRTSPStream_StartStopCycles(t *testing.T) {
+	cfg := RTSPConfig{
+		URL:          "rtsp://127.0.0.1:1/cycle",
+		Resolution:   Res512p,
+		TargetFPS:    2.0,
+		SourceStream: "test",
+		Acceleration: AccelSoftware,
+	}
+
+	stream, err := NewRTSPStream(cfg)
+	if err != nil {
+		t.Skipf("Skipping test: GStreamer not available: %v", err)
+	}
+
+	baselineGoroutines := runtime.NumGoroutine()
+	baselinePipelines := rtsp.LivePipelines()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const cycles = 3
+	for i := 0; i < cycles; i++ {
+		frames, err := stream.Start(ctx)
+		if err != nil {
+			if strings.Contains(err.Error(), "already started") {
+				t.Fatalf("cycle %d: Start() after failed start/stop reported %v", i, err)
+			}
+			continue
+		}
+
+		stopStart := time.Now()
+		if err := stream.Stop(); err != nil {
+			t.Fatalf("cycle %d: Stop() failed: %v", i, err)
+		}
+		if elapsed := time.Since(stopStart); elapsed >= defaultShutdownTimeout {
+			t.Errorf("cycle %d: Stop() hit shutdown timeout (%v), goroutines did not exit", i, elapsed)
+		}
+
+		// Channel from this cycle must be closed
+		select {
+		case _, ok := <-frames:
+			for ok {
+				_, ok = <-frames
+			}
+		case <-time.After(time.Second):
+			t.Errorf("cycle %d: frame channel not closed after Stop()", i)
+		}
+	}
+
+	// Restart works both from stopped and (when possible) running state.
+	// FPSReal covers the current run only: a long lifetime frame count from
+	// earlier runs must not inflate it.
+	atomic.StoreUint64(&stream.frameCount, 10000)
+	_, restartErr := stream.Restart(ctx)
+	if restartErr != nil && strings.Contains(restartErr.Error(), "already started") {
+		t.Fatalf("Restart() reported %v", restartErr)
+	}
+	time.Sleep(500 * time.Millisecond)
+	atomic.AddUint64(&stream.frameCount, 1) // 2 fps over 0.5s
+	if fps := stream.Stats().FPSReal; fps > 2*cfg.TargetFPS {
+		t.Errorf("FPSReal after Restart() = %.1f, want near target %.1f", fps, cfg.TargetFPS)
+	}
+
+	if restartErr == nil {
+		if _, err := stream.Restart(ctx); err != nil {
+			t.Errorf("Restart() of running stream failed: %v", err)
+		}
+		stream.Stop()
+	}
+
+	if got := rtsp.LivePipelines(); got != baselinePipelines {
+		t.Errorf("leaked GStreamer pipelines: got %d live, baseline %d", got, baselinePipelines)
+	}
+
+	// Goroutines may take a moment to be reaped by the scheduler
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > baselineGoroutines && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := runtime.NumGoroutine(); got > baselineGoroutines {
+		t.Errorf("leaked goroutines: got %d, baseline %d", got, baselineGoroutines)
+	}
+}
+
+// TestRTSPStream_ResetStats verifies counters are cleared explicitly
+// (they are preserved across Stop()/Start() and Restart() otherwise)
+func TestRTSPStream_ResetStats(t *testing.T) {
+	stream, err := NewRTSPStream(RTSPConfig{
+		URL:          "rtsp://test.invalid/stream",
+		Resolution:   Res720p,
+		TargetFPS:    2.0,
+		SourceStream: "test",
+		Acceleration: AccelSoftware,
+	})
+	if err != nil {
+		t.Skipf("Skipping test: GStreamer not available: %v", err)
+	}
+
+	atomic.StoreUint64(&stream.frameCount, 10)
+	atomic.StoreUint64(&stream.framesDropped, 3)
+	atomic.StoreUint64(&stream.errorsNetwork, 2)
+	atomic.StoreUint32(stream.reconnectState.Reconnects, 1)
+	stream.lastFrameAt.Store(time.Now().UnixNano())
+
+	// Preserved across Stop()
+	stream.Stop()
+	if stats := stream.Stats(); stats.FrameCount != 10 || stats.Reconnects != 1 {
+		t.Fatalf("expected counters preserved after Stop(), got %+v", stats)
+	}
+
+	stream.ResetStats()
+
+	stats := stream.Stats()
+	if stats.FrameCount != 0 || stats.FramesDropped != 0 || stats.Reconnects != 0 ||
+		stats.ErrorsNetwork != 0 || stats.LatencyMS != 0 {
+		t.Errorf("expected zeroed stats after ResetStats(), got %+v", stats)
+	}
+}
+
 // TestRTSPStream_FailFast_InvalidFPS validates fail-fast FPS validation
 func TestRTSPStream_FailFast_InvalidFPS(t *testing.T) {
 	testCases := []struct {
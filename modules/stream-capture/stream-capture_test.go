@@ -101,6 +101,29 @@ func TestNewRTSPStream_FailFast(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid FPS",
 		},
+		{
+			name: "empty fallback URL",
+			cfg: streamcapture.RTSPConfig{
+				URL:          "rtsp://test.local/stream",
+				TargetFPS:    2.0,
+				Resolution:   streamcapture.Res720p,
+				FallbackURLs: []string{"rtsp://test.local/sub", ""},
+			},
+			wantErr: true,
+			errMsg:  "fallback URL 1 is empty",
+		},
+		{
+			name: "negative failover probe interval",
+			cfg: streamcapture.RTSPConfig{
+				URL:          "rtsp://test.local/stream",
+				TargetFPS:    2.0,
+				Resolution:   streamcapture.Res720p,
+				FallbackURLs: []string{"rtsp://test.local/sub"},
+				Failover:     streamcapture.FailoverConfig{ProbeInterval: -time.Second},
+			},
+			wantErr: true,
+			errMsg:  "invalid failover probe interval",
+		},
 		{
 			name: "valid FPS - minimum boundary",
 			cfg: streamcapture.RTSPConfig{
@@ -0,0 +1,90 @@
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileExporter writes spans as OTLP JSON, one export request per line
+//
+// The output can be replayed into a collector with the otlpjsonfile
+// receiver, or inspected with jq. Useful on edge devices without a
+// collector.
+type FileExporter struct {
+	serviceName string
+
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closer io.Closer
+}
+
+// NewFileExporter creates (or truncates) path and writes spans to it
+func NewFileExporter(path, serviceName string) (*FileExporter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("trace: failed to create span file: %w", err)
+	}
+	return NewWriterExporter(f, serviceName), nil
+}
+
+// NewWriterExporter writes spans to w
+//
+// If w is an io.Closer, Shutdown closes it. serviceName "" uses
+// "stream-capture".
+func NewWriterExporter(w io.Writer, serviceName string) *FileExporter {
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	e := &FileExporter{
+		serviceName: serviceName,
+		w:           bufio.NewWriter(w),
+	}
+	if c, ok := w.(io.Closer); ok {
+		e.closer = c
+	}
+	return e
+}
+
+// ExportSpans writes spans as one JSON line
+func (e *FileExporter) ExportSpans(ctx context.Context, spans []Span) error {
+	line, err := json.Marshal(encodeRequest(e.serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("trace: failed to encode spans: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.w == nil {
+		return fmt.Errorf("trace: exporter is shut down")
+	}
+	e.w.Write(line)
+	e.w.WriteByte('\n')
+	if err := e.w.Flush(); err != nil {
+		return fmt.Errorf("trace: failed to write spans: %w", err)
+	}
+	return nil
+}
+
+// Shutdown flushes and closes the output
+func (e *FileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.w == nil {
+		return nil
+	}
+	err := e.w.Flush()
+	e.w = nil
+	if e.closer != nil {
+		if cerr := e.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,225 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+	defaultServiceName  = "stream-capture"
+	instrumentationName = "github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
+)
+
+// OTLPConfig configures the OTLP/HTTP exporter
+type OTLPConfig struct {
+	// Endpoint is the collector traces URL (default: http://localhost:4318/v1/traces)
+	// Set to "" to use default value
+	Endpoint string
+	// ServiceName is reported as the service.name resource attribute (default: "stream-capture")
+	// Set to "" to use default value
+	ServiceName string
+	// Headers are added to every request (e.g. authentication)
+	Headers map[string]string
+	// Timeout bounds a single request (default: 10s)
+	// Set to 0 to use default value
+	Timeout time.Duration
+}
+
+// OTLPExporter posts spans to an OpenTelemetry collector as OTLP/HTTP JSON
+type OTLPExporter struct {
+	endpoint    string
+	serviceName string
+	headers     map[string]string
+	client      *http.Client
+}
+
+// NewOTLPExporter creates an exporter for an OTLP/HTTP collector
+func NewOTLPExporter(cfg OTLPConfig) (*OTLPExporter, error) {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultOTLPEndpoint
+	}
+	if cfg.ServiceName == "" {
+		cfg.ServiceName = defaultServiceName
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultExportTimeout
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("trace: invalid OTLP endpoint %q (want http(s)://host:port/v1/traces)", cfg.Endpoint)
+	}
+
+	return &OTLPExporter{
+		endpoint:    cfg.Endpoint,
+		serviceName: cfg.ServiceName,
+		headers:     cfg.Headers,
+		client:      &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+// ExportSpans posts spans as one ExportTraceServiceRequest
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []Span) error {
+	body, err := json.Marshal(encodeRequest(e.serviceName, spans))
+	if err != nil {
+		return fmt.Errorf("trace: failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("trace: invalid OTLP endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("trace: OTLP export failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("trace: OTLP collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Shutdown releases idle connections
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.client.CloseIdleConnections()
+	return nil
+}
+
+// OTLP JSON encoding (opentelemetry-proto, JSON mapping).
+// Trace and span IDs are hex strings; 64-bit integers are decimal strings.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusError      = 2
+)
+
+// encodeRequest converts spans to an OTLP ExportTraceServiceRequest
+func encodeRequest(serviceName string, spans []Span) otlpRequest {
+	out := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		span := otlpSpan{
+			TraceID:           s.Context.TraceID.String(),
+			SpanID:            s.Context.SpanID.String(),
+			Name:              s.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        encodeAttributes(s.Attributes),
+		}
+		if s.Parent.IsValid() {
+			span.ParentSpanID = s.Parent.String()
+		}
+		if s.Error != "" {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+		out = append(out, span)
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: encodeAttributes([]Attribute{String("service.name", serviceName)}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: instrumentationName},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+func encodeAttributes(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v otlpValue
+		switch val := a.Value.(type) {
+		case string:
+			v.StringValue = &val
+		case bool:
+			v.BoolValue = &val
+		case int64:
+			s := strconv.FormatInt(val, 10)
+			v.IntValue = &s
+		case int:
+			s := strconv.Itoa(val)
+			v.IntValue = &s
+		case float64:
+			v.DoubleValue = &val
+		default:
+			s := fmt.Sprint(val)
+			v.StringValue = &s
+		}
+		out = append(out, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+// Package trace implements OpenTelemetry-compatible frame tracing without
+// the OpenTelemetry SDK.
+//
+// Every captured frame gets a W3C trace context (16-byte trace ID, 8-byte
+// span ID) that travels with it as a "traceparent" string
+// (Frame.TraceParent). Each stage that handles the frame - decode in
+// stream-capture, distribution in framesupplier, inference in a worker -
+// records a child span, so one trace shows the whole life of a frame.
+//
+// Spans are recorded through a Tracer, which batches them in the background
+// and hands them to an Exporter:
+//
+//	exp, _ := trace.NewOTLPExporter(trace.OTLPConfig{ServiceName: "orion"}) // local collector
+//	tracer := trace.NewTracer(exp, trace.TracerConfig{})
+//	defer tracer.Shutdown(ctx)
+//
+//	stream, _ := streamcapture.NewRTSPStream(cfg, streamcapture.WithTracer(tracer))
+//
+// OTLPExporter posts OTLP/HTTP JSON to a collector (default
+// http://localhost:4318/v1/traces); FileExporter writes the same JSON, one
+// export request per line, readable by the collector's otlpjsonfile
+// receiver.
+//
+// Recording never blocks the frame path: when the Tracer queue is full,
+// spans are dropped and counted (latency over completeness).
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TraceID identifies a trace (one captured frame)
+type TraceID [16]byte
+
+// SpanID identifies a span within a trace
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid reports whether the ID is non-zero (W3C: all zeros is invalid)
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String returns the lowercase hex encoding
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid reports whether the ID is non-zero (W3C: all zeros is invalid)
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// SpanContext is the propagated part of a span (W3C trace context)
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// NewRoot creates the context of a new trace (random trace and span IDs, sampled)
+func NewRoot() SpanContext {
+	var sc SpanContext
+	randomBytes(sc.TraceID[:])
+	randomBytes(sc.SpanID[:])
+	sc.Sampled = true
+	return sc
+}
+
+// Child creates the context of a child span (same trace, new span ID)
+func (sc SpanContext) Child() SpanContext {
+	child := sc
+	randomBytes(child.SpanID[:])
+	return child
+}
+
+// IsValid reports whether both IDs are set
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// Traceparent returns the W3C traceparent header value
+//
+// Format: "00-<trace-id>-<span-id>-<flags>", e.g.
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+// Returns "" for an invalid context.
+func (sc SpanContext) Traceparent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses a W3C traceparent header value
+//
+// Only version 00 is accepted. Returns an error for malformed values and
+// all-zero IDs.
+func ParseTraceparent(s string) (SpanContext, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, fmt.Errorf("trace: malformed traceparent %q", s)
+	}
+
+	var sc SpanContext
+	var flags [1]byte
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, fmt.Errorf("trace: malformed trace ID in %q", s)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, fmt.Errorf("trace: malformed span ID in %q", s)
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return SpanContext{}, fmt.Errorf("trace: malformed flags in %q", s)
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("trace: all-zero ID in %q", s)
+	}
+	sc.Sampled = flags[0]&0x01 != 0
+	return sc, nil
+}
+
+// randomBytes fills b from crypto/rand (never fails on supported platforms)
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("trace: crypto/rand failed: %v", err))
+	}
+}
+
+// Attribute is a span attribute (string, bool, int64 or float64 value)
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute
+func Int(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Float returns a floating-point attribute
+func Float(key string, value float64) Attribute { return Attribute{Key: key, Value: value} }
+
+// Bool returns a boolean attribute
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Span is a finished operation on a frame
+type Span struct {
+	// Name is the operation (e.g. "stream-capture.decode")
+	Name string
+	// Context identifies this span
+	Context SpanContext
+	// Parent is the parent span ID (zero for a root span)
+	Parent SpanID
+	// Start and End delimit the operation
+	Start time.Time
+	End   time.Time
+	// Attributes describe the frame (seq, source stream, worker ID, ...)
+	Attributes []Attribute
+	// Error marks the operation as failed (status message; "" = ok)
+	Error string
+}
@@ -0,0 +1,315 @@
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	_ Exporter = (*OTLPExporter)(nil)
+	_ Exporter = (*FileExporter)(nil)
+)
+
+func TestTraceparent_RoundTrip(t *testing.T) {
+	root := NewRoot()
+	if !root.IsValid() || !root.Sampled {
+		t.Fatalf("NewRoot() = %+v, want valid sampled context", root)
+	}
+
+	tp := root.Traceparent()
+	if len(tp) != 55 || !strings.HasPrefix(tp, "00-") || !strings.HasSuffix(tp, "-01") {
+		t.Fatalf("Traceparent() = %q, want 00-<32hex>-<16hex>-01", tp)
+	}
+
+	parsed, err := ParseTraceparent(tp)
+	if err != nil {
+		t.Fatalf("ParseTraceparent() error = %v", err)
+	}
+	if parsed != root {
+		t.Errorf("ParseTraceparent() = %+v, want %+v", parsed, root)
+	}
+
+	child := root.Child()
+	if child.TraceID != root.TraceID || child.SpanID == root.SpanID {
+		t.Errorf("Child() = %+v, want same trace ID and new span ID", child)
+	}
+}
+
+func TestParseTraceparent_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+	}
+	for _, tp := range tests {
+		if _, err := ParseTraceparent(tp); err == nil {
+			t.Errorf("ParseTraceparent(%q) succeeded, want error", tp)
+		}
+	}
+
+	sc, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	if err != nil {
+		t.Fatalf("ParseTraceparent() error = %v", err)
+	}
+	if sc.Sampled {
+		t.Error("Sampled = true for flags 00")
+	}
+}
+
+// memExporter collects exported spans
+type memExporter struct {
+	mu      sync.Mutex
+	batches [][]Span
+	err     error
+	closed  bool
+}
+
+func (e *memExporter) ExportSpans(ctx context.Context, spans []Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err != nil {
+		return e.err
+	}
+	e.batches = append(e.batches, append([]Span(nil), spans...))
+	return nil
+}
+
+func (e *memExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	return nil
+}
+
+func (e *memExporter) spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var all []Span
+	for _, b := range e.batches {
+		all = append(all, b...)
+	}
+	return all
+}
+
+func testSpan(name string) Span {
+	root := NewRoot()
+	child := root.Child()
+	start := time.Unix(1700000000, 0)
+	return Span{
+		Name:       name,
+		Context:    child,
+		Parent:     root.SpanID,
+		Start:      start,
+		End:        start.Add(5 * time.Millisecond),
+		Attributes: []Attribute{Int("seq", 7), String("source_stream", "LQ")},
+	}
+}
+
+func TestTracer_BatchesAndFlushes(t *testing.T) {
+	exp := &memExporter{}
+	tracer := NewTracer(exp, TracerConfig{BatchSize: 2, FlushInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		tracer.Record(testSpan("decode"))
+	}
+	// Invalid and unsampled spans are ignored
+	tracer.Record(Span{Name: "invalid"})
+	unsampled := testSpan("unsampled")
+	unsampled.Context.Sampled = false
+	tracer.Record(unsampled)
+
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if got := len(exp.spans()); got != 5 {
+		t.Fatalf("exported %d spans, want 5", got)
+	}
+	for _, b := range exp.batches {
+		if len(b) > 2 {
+			t.Errorf("batch of %d spans, want at most BatchSize 2", len(b))
+		}
+	}
+
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if !exp.closed {
+		t.Error("exporter not shut down")
+	}
+
+	tracer.Record(testSpan("late"))
+	stats := tracer.Stats()
+	if stats.Recorded != 5 || stats.Exported != 5 || stats.Dropped != 1 {
+		t.Errorf("Stats() = %+v, want 5 recorded, 5 exported, 1 dropped", stats)
+	}
+}
+
+func TestTracer_DropsWhenFull(t *testing.T) {
+	exp := &memExporter{}
+	tracer := NewTracer(exp, TracerConfig{QueueSize: 1, BatchSize: 100, FlushInterval: time.Hour})
+	defer tracer.Shutdown(context.Background())
+
+	// The exporter loop may take one span off the queue; the rest must drop
+	for i := 0; i < 10; i++ {
+		tracer.Record(testSpan("decode"))
+	}
+	if stats := tracer.Stats(); stats.Dropped == 0 || stats.Recorded+stats.Dropped != 10 {
+		t.Errorf("Stats() = %+v, want drops without blocking", stats)
+	}
+}
+
+func TestTracer_ExportErrorsCounted(t *testing.T) {
+	exp := &memExporter{err: errors.New("collector down")}
+	tracer := NewTracer(exp, TracerConfig{})
+	tracer.Record(testSpan("decode"))
+	tracer.Flush(context.Background())
+	tracer.Shutdown(context.Background())
+
+	if stats := tracer.Stats(); stats.ExportErrors != 1 || stats.Exported != 0 {
+		t.Errorf("Stats() = %+v, want 1 export error", stats)
+	}
+}
+
+func TestTracer_Nil(t *testing.T) {
+	var tracer *Tracer
+	tracer.Record(testSpan("decode"))
+	if err := tracer.Flush(context.Background()); err != nil {
+		t.Errorf("Flush() on nil tracer error = %v", err)
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() on nil tracer error = %v", err)
+	}
+}
+
+func TestFileExporter_WritesOTLPJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	exp := NewWriterExporter(&buf, "orion")
+
+	span := testSpan("framesupplier.distribute")
+	span.Error = "no workers"
+	if err := exp.ExportSpans(context.Background(), []Span{span}); err != nil {
+		t.Fatalf("ExportSpans() error = %v", err)
+	}
+	exp.ExportSpans(context.Background(), []Span{testSpan("decode")})
+	exp.Shutdown(context.Background())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("wrote %d lines, want 2", len(lines))
+	}
+
+	var req otlpRequest
+	if err := json.Unmarshal([]byte(lines[0]), &req); err != nil {
+		t.Fatalf("line is not JSON: %v", err)
+	}
+	rs := req.ResourceSpans[0]
+	if got := *rs.Resource.Attributes[0].Value.StringValue; got != "orion" {
+		t.Errorf("service.name = %q, want orion", got)
+	}
+	got := rs.ScopeSpans[0].Spans[0]
+	if got.TraceID != span.Context.TraceID.String() || got.SpanID != span.Context.SpanID.String() || got.ParentSpanID != span.Parent.String() {
+		t.Errorf("span IDs = %s/%s/%s, want %s/%s/%s", got.TraceID, got.SpanID, got.ParentSpanID,
+			span.Context.TraceID, span.Context.SpanID, span.Parent)
+	}
+	if got.StartTimeUnixNano != "1700000000000000000" || got.EndTimeUnixNano != "1700000000005000000" {
+		t.Errorf("times = %s..%s", got.StartTimeUnixNano, got.EndTimeUnixNano)
+	}
+	if got.Status.Code != otlpStatusError || got.Status.Message != "no workers" {
+		t.Errorf("Status = %+v, want error status", got.Status)
+	}
+	if len(got.Attributes) != 2 || got.Attributes[0].Key != "seq" || *got.Attributes[0].Value.IntValue != "7" {
+		t.Errorf("Attributes = %+v, want seq=7 first", got.Attributes)
+	}
+
+	if err := exp.ExportSpans(context.Background(), []Span{span}); err == nil {
+		t.Error("ExportSpans() after Shutdown succeeded, want error")
+	}
+}
+
+func TestOTLPExporter_PostsJSON(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies [][]byte
+		header http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		header = r.Header.Clone()
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	exp, err := NewOTLPExporter(OTLPConfig{
+		Endpoint: server.URL + "/v1/traces",
+		Headers:  map[string]string{"Authorization": "Bearer token"},
+	})
+	if err != nil {
+		t.Fatalf("NewOTLPExporter() error = %v", err)
+	}
+
+	tracer := NewTracer(exp, TracerConfig{})
+	tracer.Record(testSpan("decode"))
+	tracer.Record(testSpan("worker.process"))
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("collector received %d requests, want 1", len(bodies))
+	}
+	if ct := header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if auth := header.Get("Authorization"); auth != "Bearer token" {
+		t.Errorf("Authorization = %q, want configured header", auth)
+	}
+	var req otlpRequest
+	if err := json.Unmarshal(bodies[0], &req); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	if n := len(req.ResourceSpans[0].ScopeSpans[0].Spans); n != 2 {
+		t.Errorf("request has %d spans, want 2", n)
+	}
+	if stats := tracer.Stats(); stats.Exported != 2 {
+		t.Errorf("Stats().Exported = %d, want 2", stats.Exported)
+	}
+
+}
+
+func TestOTLPExporter_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	exp, _ := NewOTLPExporter(OTLPConfig{Endpoint: server.URL + "/v1/traces"})
+	if err := exp.ExportSpans(context.Background(), []Span{testSpan("decode")}); err == nil {
+		t.Error("ExportSpans() on 404 succeeded, want error")
+	}
+}
+
+func TestNewOTLPExporter_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"localhost:4318", "grpc://localhost:4317", "http://"} {
+		if _, err := NewOTLPExporter(OTLPConfig{Endpoint: endpoint}); err == nil {
+			t.Errorf("NewOTLPExporter(%q) succeeded, want error", endpoint)
+		}
+	}
+	if _, err := NewOTLPExporter(OTLPConfig{}); err != nil {
+		t.Errorf("NewOTLPExporter() with defaults error = %v", err)
+	}
+}
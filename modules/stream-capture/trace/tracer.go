@@ -0,0 +1,255 @@
+package trace
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 2048
+	defaultBatchSize     = 256
+	defaultFlushInterval = time.Second
+	defaultExportTimeout = 10 * time.Second
+)
+
+// Exporter sends finished spans to a backend
+//
+// ExportSpans is called from a single goroutine (the Tracer's exporter loop).
+type Exporter interface {
+	ExportSpans(ctx context.Context, spans []Span) error
+	Shutdown(ctx context.Context) error
+}
+
+// TracerConfig tunes span batching
+type TracerConfig struct {
+	// QueueSize is the number of spans buffered before dropping (default: 2048)
+	// Set to 0 to use default value
+	QueueSize int
+	// BatchSize is the maximum number of spans per export (default: 256)
+	// Set to 0 to use default value
+	BatchSize int
+	// FlushInterval is the maximum time a span waits before export (default: 1s)
+	// Set to 0 to use default value
+	FlushInterval time.Duration
+	// ExportTimeout bounds a single export call (default: 10s)
+	// Set to 0 to use default value
+	ExportTimeout time.Duration
+	// Logger reports export failures (nil = slog.Default())
+	Logger *slog.Logger
+}
+
+// TracerStats contains span counters
+type TracerStats struct {
+	// Recorded is the number of spans accepted into the queue
+	Recorded uint64
+	// Dropped is the number of spans lost because the queue was full
+	Dropped uint64
+	// Exported is the number of spans successfully exported
+	Exported uint64
+	// ExportErrors is the number of failed export calls
+	ExportErrors uint64
+}
+
+// Tracer batches spans and exports them in the background
+//
+// Record never blocks. A nil *Tracer is valid and discards everything, so
+// callers can hold an optional tracer without nil checks. Thread-safe.
+type Tracer struct {
+	exporter Exporter
+	cfg      TracerConfig
+	logger   *slog.Logger
+
+	queue chan Span
+	flush chan chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	closeOnce sync.Once
+
+	recorded     atomic.Uint64
+	dropped      atomic.Uint64
+	exported     atomic.Uint64
+	exportErrors atomic.Uint64
+}
+
+// NewTracer starts a tracer exporting to exporter
+//
+// Call Shutdown to flush pending spans and release the exporter.
+func NewTracer(exporter Exporter, cfg TracerConfig) *Tracer {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = defaultFlushInterval
+	}
+	if cfg.ExportTimeout <= 0 {
+		cfg.ExportTimeout = defaultExportTimeout
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	t := &Tracer{
+		exporter: exporter,
+		cfg:      cfg,
+		logger:   logger,
+		queue:    make(chan Span, cfg.QueueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	t.wg.Add(1)
+	go t.run()
+
+	return t
+}
+
+// Record queues a finished span for export (non-blocking)
+//
+// Spans with an invalid context or not sampled are ignored.
+func (t *Tracer) Record(span Span) {
+	if t == nil || !span.Context.IsValid() || !span.Context.Sampled {
+		return
+	}
+
+	select {
+	case <-t.done:
+		t.dropped.Add(1)
+		return
+	default:
+	}
+
+	select {
+	case t.queue <- span:
+		t.recorded.Add(1)
+	default:
+		t.dropped.Add(1)
+	}
+}
+
+// Flush exports all queued spans and waits for the export to finish
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	ack := make(chan struct{})
+	select {
+	case t.flush <- ack:
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown flushes pending spans, stops the tracer and shuts down the exporter
+//
+// Idempotent. Spans recorded afterwards are dropped.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+
+	var err error
+	t.closeOnce.Do(func() {
+		close(t.done)
+		t.wg.Wait()
+		err = t.exporter.Shutdown(ctx)
+	})
+	return err
+}
+
+// Stats returns span counters
+func (t *Tracer) Stats() TracerStats {
+	if t == nil {
+		return TracerStats{}
+	}
+	return TracerStats{
+		Recorded:     t.recorded.Load(),
+		Dropped:      t.dropped.Load(),
+		Exported:     t.exported.Load(),
+		ExportErrors: t.exportErrors.Load(),
+	}
+}
+
+// run batches queued spans until Shutdown
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Span, 0, t.cfg.BatchSize)
+	for {
+		select {
+		case span := <-t.queue:
+			batch = append(batch, span)
+			if len(batch) >= t.cfg.BatchSize {
+				batch = t.export(batch)
+			}
+
+		case <-ticker.C:
+			batch = t.export(batch)
+
+		case ack := <-t.flush:
+			batch = t.export(t.drain(batch))
+			close(ack)
+
+		case <-t.done:
+			t.export(t.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves every queued span into batch, exporting full batches
+func (t *Tracer) drain(batch []Span) []Span {
+	for {
+		select {
+		case span := <-t.queue:
+			batch = append(batch, span)
+			if len(batch) >= t.cfg.BatchSize {
+				batch = t.export(batch)
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// export sends batch and returns it emptied for reuse
+func (t *Tracer) export(batch []Span) []Span {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.ExportTimeout)
+	defer cancel()
+
+	if err := t.exporter.ExportSpans(ctx, batch); err != nil {
+		t.exportErrors.Add(1)
+		t.logger.Warn("trace: span export failed",
+			"spans", len(batch),
+			"error", err,
+		)
+	} else {
+		t.exported.Add(uint64(len(batch)))
+	}
+
+	return batch[:0]
+}
@@ -0,0 +1,37 @@
+package streamcapture
+
+import (
+	"time"
+
+	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/trace"
+)
+
+// captureSpanName is the root span of every frame trace
+const captureSpanName = "stream-capture.decode"
+
+// recordCaptureSpan records the root span of a frame trace
+//
+// The span runs from the decoded frame leaving the decoder (MJPEG: start of
+// JPEG decode; RTSP: arrival at the appsink) until the frame is handed to
+// the output channel, or dropped because the channel was full.
+//
+// start and the end timestamp come from the stream's injected Clock, like
+// Frame.Timestamp.
+func recordCaptureSpan(tracer *trace.Tracer, clk Clock, sc trace.SpanContext, frame Frame, start time.Time, dropped bool) {
+	if tracer == nil {
+		return
+	}
+	tracer.Record(trace.Span{
+		Name:    captureSpanName,
+		Context: sc,
+		Start:   start,
+		End:     clk.Now(),
+		Attributes: []trace.Attribute{
+			trace.Int("frame.seq", int64(frame.Seq)),
+			trace.String("frame.source_stream", frame.SourceStream),
+			trace.Int("frame.width", int64(frame.Width)),
+			trace.Int("frame.height", int64(frame.Height)),
+			trace.Bool("frame.dropped", dropped),
+		},
+	})
+}
@@ -1,6 +1,9 @@
 package streamcapture
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/e7canasta/orion-care-sensor/modules/stream-capture/internal/warmup"
@@ -42,3 +45,41 @@ func CalculateFPSStats(frameTimes []time.Time, totalDuration time.Duration) *War
 		JitterMax:      internalStats.JitterMax,
 	}
 }
+
+// warmupFrames runs warm-up over a provider's frame channel
+//
+// Shared by every StreamProvider implementation (RTSPStream, MJPEGStream):
+// delegates to internal/warmup (frame conversion + statistics) and converts
+// the result to the public WarmupStats type.
+func warmupFrames(ctx context.Context, frames <-chan Frame, duration time.Duration, clk Clock, logger *slog.Logger) (*WarmupStats, error) {
+	// Delegate to warmup adapter (handles frame conversion and statistics)
+	internalStats, err := warmup.WarmupWithAdapter(
+		ctx,
+		frames,
+		duration,
+		func(f Frame) warmup.Frame {
+			return warmup.Frame{
+				Seq:       f.Seq,
+				Timestamp: f.Timestamp,
+			}
+		},
+		warmup.Options{Clock: clk, Logger: logger},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("stream-capture: warmup failed: %w", err)
+	}
+
+	// Convert internal stats to public stats (identical structure)
+	return &WarmupStats{
+		FramesReceived: internalStats.FramesReceived,
+		Duration:       internalStats.Duration,
+		FPSMean:        internalStats.FPSMean,
+		FPSStdDev:      internalStats.FPSStdDev,
+		FPSMin:         internalStats.FPSMin,
+		FPSMax:         internalStats.FPSMax,
+		IsStable:       internalStats.IsStable,
+		JitterMean:     internalStats.JitterMean,
+		JitterStdDev:   internalStats.JitterStdDev,
+		JitterMax:      internalStats.JitterMax,
+	}, nil
+}